@@ -0,0 +1,64 @@
+package main
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/internal/acl"
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+)
+
+// noAuthCommands are the commands a client can run before authenticating.
+var noAuthCommands = map[string]bool{
+	"AUTH":  true,
+	"HELLO": true,
+	"QUIT":  true,
+}
+
+var wrongPassReply = payload.Error("WRONGPASS invalid username-password pair or user is disabled.")
+
+// auth runs AUTH [username] password
+func (c *client) auth(args []string) payload.Reply {
+	if len(args) == 0 || len(args) > 2 {
+		return payload.Error("ERR wrong number of arguments for 'auth' command")
+	}
+
+	if len(args) == 1 && accessControl.NoPass(acl.DefaultUser) {
+		return payload.Error("ERR AUTH <password> called without any password configured for the default user. Are you sure your configuration is correct?")
+	}
+
+	username := acl.DefaultUser
+	if len(args) == 2 {
+		username = args[0]
+	}
+
+	if !c.authenticate(username, args[len(args)-1]) {
+		return wrongPassReply
+	}
+
+	c.user = username
+	c.authenticated = true
+
+	return payload.SimpleString("OK")
+}
+
+// authenticate checks the credentials of the user, logging the failures in
+// the ACL LOG.
+func (c *client) authenticate(username, password string) bool {
+	if accessControl.Authenticate(username, password) {
+		return true
+	}
+
+	accessControl.Log().Add(acl.ReasonAuth, "toplevel", "AUTH", username, c.info())
+
+	return false
+}
+
+// setDefaultPassword makes requirepass the password of the default user, no
+// password being required when it's empty.
+func setDefaultPassword(password string) {
+	rules := []string{"nopass"}
+	if password != "" {
+		rules = []string{"resetpass", ">" + password}
+	}
+
+	// the rules are valid whatever the password
+	_ = accessControl.SetUser(acl.DefaultUser, rules)
+}
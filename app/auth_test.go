@@ -0,0 +1,129 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const noAuthReply = "-NOAUTH Authentication required.\r\n"
+
+func TestAuth_RequiredBeforeCommands(t *testing.T) {
+	requireAuth(t, "secret")
+	c := newTestClient(t)
+
+	for _, table := range []map[string]*command{commandTable, clientCommands} {
+		for name := range table {
+			if noAuthCommands[name] {
+				continue
+			}
+
+			assert.Equal(t, noAuthReply, run(c, name, "key", "value"), name)
+		}
+	}
+
+	assert.Equal(t, noAuthReply, run(c, "UNKNOWN"))
+	assert.False(t, c.authenticated)
+
+	assert.Equal(t, "+OK\r\n", run(c, "QUIT"))
+}
+
+func TestAuth(t *testing.T) {
+	testCases := map[string]struct {
+		args                  []string
+		expected              string
+		expectedAuthenticated bool
+	}{
+		"when no argument given": {
+			args:     []string{"AUTH"},
+			expected: "-ERR wrong number of arguments for 'auth' command\r\n",
+		},
+		"when too many arguments given": {
+			args:     []string{"AUTH", "default", "secret", "extra"},
+			expected: "-ERR wrong number of arguments for 'auth' command\r\n",
+		},
+		"when password is wrong": {
+			args:     []string{"AUTH", "wrong"},
+			expected: "-WRONGPASS invalid username-password pair or user is disabled.\r\n",
+		},
+		"when user is unknown": {
+			args:     []string{"AUTH", "unknown", "secret"},
+			expected: "-WRONGPASS invalid username-password pair or user is disabled.\r\n",
+		},
+		"when password is right": {
+			args:                  []string{"AUTH", "secret"},
+			expected:              "+OK\r\n",
+			expectedAuthenticated: true,
+		},
+		"when user and password are right": {
+			args:                  []string{"AUTH", "default", "secret"},
+			expected:              "+OK\r\n",
+			expectedAuthenticated: true,
+		},
+	}
+
+	requireAuth(t, "secret")
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			c := newTestClient(t)
+
+			assert.Equal(t, tc.expected, run(c, tc.args...))
+			assert.Equal(t, tc.expectedAuthenticated, c.authenticated)
+
+			if tc.expectedAuthenticated {
+				assert.Equal(t, "+OK\r\n", run(c, "SET", "k", "v"))
+				assert.Equal(t, "$1\r\nv\r\n", run(c, "GET", "k"))
+			} else {
+				assert.Equal(t, noAuthReply, run(c, "GET", "k"))
+			}
+		})
+	}
+}
+
+func TestAuth_WithoutPassword(t *testing.T) {
+	c := newTestClient(t)
+
+	assert.True(t, c.authenticated)
+	assert.Equal(t, "-ERR AUTH <password> called without any password configured for the default user. Are you sure your configuration is correct?\r\n", run(c, "AUTH", "secret"))
+}
+
+func TestHello_Auth(t *testing.T) {
+	testCases := map[string]struct {
+		args                  []string
+		expectedPrefix        string
+		expectedProtocol      int
+		expectedAuthenticated bool
+	}{
+		"when credentials are right": {
+			args:                  []string{"HELLO", "3", "AUTH", "default", "secret"},
+			expectedPrefix:        "%7\r\n$6\r\nserver\r\n",
+			expectedProtocol:      3,
+			expectedAuthenticated: true,
+		},
+		"when password is wrong": {
+			args:             []string{"HELLO", "3", "AUTH", "default", "wrong"},
+			expectedPrefix:   "-WRONGPASS invalid username-password pair or user is disabled.\r\n",
+			expectedProtocol: 2,
+		},
+		"when AUTH is missing": {
+			args:             []string{"HELLO", "3"},
+			expectedPrefix:   "-NOAUTH HELLO must be called with the client already authenticated",
+			expectedProtocol: 2,
+		},
+	}
+
+	requireAuth(t, "secret")
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			c := newTestClient(t)
+
+			reply := run(c, tc.args...)
+			assert.True(t, strings.HasPrefix(reply, tc.expectedPrefix), reply)
+			assert.Equal(t, tc.expectedProtocol, c.protocol)
+			assert.Equal(t, tc.expectedAuthenticated, c.authenticated)
+		})
+	}
+}
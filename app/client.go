@@ -0,0 +1,427 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/acl"
+	"github.com/codecrafters-io/redis-starter-go/internal/blocking"
+	"github.com/codecrafters-io/redis-starter-go/internal/commands"
+	"github.com/codecrafters-io/redis-starter-go/internal/parser"
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+	"github.com/codecrafters-io/redis-starter-go/internal/pubsub"
+)
+
+// client is a connection to the server. Its requests are read in a separate
+// goroutine, so that a disconnect is noticed while the client is blocked.
+type client struct {
+	id   int
+	conn net.Conn
+
+	requests chan *parser.RedisRequest
+	// closed is closed once no more requests can be read, readErr holding
+	// the reason.
+	closed  chan struct{}
+	readErr error
+	// done is closed once the client stops serving requests.
+	done chan struct{}
+	// handled receives a value once a request has been handled, which the
+	// reader waits for until the client authenticated.
+	handled chan struct{}
+
+	// multi holds the commands queued since MULTI, nil outside of
+	// transactions.
+	multi *transaction
+
+	// db is the index of the selected database.
+	db int
+	// protocol is the RESP version set by HELLO, 2 until then. It's only
+	// changed while holding execMu, as messages are encoded with it.
+	protocol int
+	// name is the name set by HELLO SETNAME.
+	name string
+	// authenticated is set once the client authenticated with AUTH or
+	// HELLO, or right away if the default user requires no password.
+	authenticated bool
+	// user is the ACL user the client runs commands as.
+	user string
+
+	// messages holds the Pub/Sub messages until they're written, after the
+	// reply of the command being run.
+	messages *pubsub.Queue
+	// quit is set by QUIT, the connection is closed once the reply is sent.
+	quit bool
+}
+
+// subscribeModeCommands are the commands allowed while the client is
+// subscribed to channels or patterns.
+var subscribeModeCommands = map[string]bool{
+	"SUBSCRIBE":    true,
+	"PSUBSCRIBE":   true,
+	"UNSUBSCRIBE":  true,
+	"PUNSUBSCRIBE": true,
+	"SSUBSCRIBE":   true,
+	"SUNSUBSCRIBE": true,
+	"PING":         true,
+	"QUIT":         true,
+}
+
+type transaction struct {
+	queued []queuedCommand
+	// dirty is set when a command couldn't be queued, EXEC aborts then.
+	dirty bool
+}
+
+type queuedCommand struct {
+	name string
+	args []string
+}
+
+func newClient(id int, conn net.Conn) *client {
+	return &client{
+		id:       id,
+		conn:     conn,
+		requests: make(chan *parser.RedisRequest),
+		closed:   make(chan struct{}),
+		done:     make(chan struct{}),
+		handled:  make(chan struct{}, 1),
+		messages: pubsub.NewQueue(pubsubLimits),
+		protocol: 2,
+
+		authenticated: accessControl.NoPass(acl.DefaultUser),
+		user:          acl.DefaultUser,
+	}
+}
+
+// pubsubLimits are the output buffer limits of the subscribers.
+func pubsubLimits() pubsub.Limits {
+	hard, soft, seconds := cfg.OutputBufferLimit("pubsub")
+
+	return pubsub.Limits{Hard: hard, Soft: soft, SoftDuration: time.Duration(seconds) * time.Second}
+}
+
+// Send queues a Pub/Sub message, as a push message over RESP3. A client
+// reading too slowly to keep up with its messages is disconnected.
+func (c *client) Send(msg payload.Push) {
+	if !c.messages.Push(payload.Encode(msg, c.protocol)) {
+		log.Println("Closing connection exceeding its pubsub output buffer limit, ID:", c.id)
+		c.conn.Close()
+	}
+}
+
+// serve handles the requests of the client until the connection is closed.
+func (c *client) serve() error {
+	defer c.conn.Close()
+	defer close(c.done)
+	defer func() {
+		execMu.Lock()
+		pubsubHub.Remove(c)
+		execMu.Unlock()
+	}()
+
+	go c.readRequests()
+
+	// the pending messages and the reply are sent at once
+	out := bufio.NewWriter(c.conn)
+
+	for {
+		select {
+		case req := <-c.requests:
+			// the messages sent before the command ran come before its reply
+			if err := c.messages.Flush(out); err != nil {
+				return fmt.Errorf("Failed to write to connection %d: %w", c.id, err)
+			}
+
+			c.handle(req).WriteRESP(out, c.protocol)
+
+			select {
+			case c.handled <- struct{}{}:
+			default:
+			}
+
+			if err := out.Flush(); err != nil {
+				return fmt.Errorf("Failed to write to connection %d: %w", c.id, err)
+			}
+
+			if c.quit {
+				return nil
+			}
+		case <-c.messages.Ready():
+			if err := c.messages.Flush(out); err != nil {
+				return fmt.Errorf("Failed to write to connection %d: %w", c.id, err)
+			}
+
+			if err := out.Flush(); err != nil {
+				return fmt.Errorf("Failed to write to connection %d: %w", c.id, err)
+			}
+		case <-c.closed:
+			if errors.Is(c.readErr, io.EOF) {
+				log.Println("Breaking due to EOF..., ID:", c.id)
+				return nil
+			}
+
+			// the client is told why its connection is closed
+			var protocolErr *parser.ProtocolError
+			if errors.As(c.readErr, &protocolErr) {
+				payload.Error("ERR "+protocolErr.Error()).WriteRESP(out, c.protocol)
+				out.Flush()
+			}
+
+			return fmt.Errorf("Failed to read from connection %d: %w", c.id, c.readErr)
+		}
+	}
+}
+
+func (c *client) readRequests() {
+	defer close(c.closed)
+
+	reader := parser.NewReader(c.conn)
+	authenticated := c.authenticated
+
+	for {
+		reader.SetAuthenticated(authenticated)
+
+		parsed, err := reader.ReadRequest()
+		if err != nil {
+			c.readErr = fmt.Errorf("Failed to parse redis request: %w", err)
+			return
+		}
+
+		select {
+		case c.requests <- parsed:
+		case <-c.done:
+			return
+		}
+
+		if authenticated {
+			continue
+		}
+
+		// the request may authenticate the client, lifting the limits on
+		// the next ones, so it has to run before they're read
+		select {
+		case <-c.handled:
+			authenticated = c.authenticated
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// handle runs a request and returns its reply, waiting for it if the command
+// blocked the client.
+func (c *client) handle(req *parser.RedisRequest) payload.Reply {
+	if !c.authenticated && !noAuthCommands[req.Command] {
+		return payload.Error("NOAUTH Authentication required.")
+	}
+
+	args := req.Args()
+
+	// over RESP3, messages are told apart from replies by their push type,
+	// so subscribed clients can run any command
+	if c.protocol == 2 && !subscribeModeCommands[req.Command] && c.subscribed() {
+		return payload.Error(fmt.Sprintf("ERR Can't execute '%s': only (P|S)SUBSCRIBE / (P|S)UNSUBSCRIBE / PING / QUIT are allowed in this context", strings.ToLower(req.Command)))
+	}
+
+	// AUTH, HELLO and QUIT are always allowed, so that a client can switch
+	// to another user
+	if cmd := clientCommands[req.Command]; cmd != nil && !noAuthCommands[req.Command] {
+		if errReply := c.checkPermission(cmd, args, "toplevel"); errReply != nil {
+			return errReply
+		}
+	}
+
+	switch req.Command {
+	case "QUIT":
+		c.quit = true
+		return payload.SimpleString("OK")
+	case "MULTI":
+		return c.startTransaction(args)
+	case "EXEC":
+		return c.exec(args)
+	case "DISCARD":
+		return c.discard(args)
+	case "HELLO":
+		return c.hello(args)
+	case "AUTH":
+		return c.auth(args)
+	}
+
+	if c.multi != nil {
+		return c.queue(req.Command, args)
+	}
+
+	cmd, errReply := lookupCommand(req.Command, args)
+	if errReply == nil {
+		errReply = c.checkPermission(cmd, args, "toplevel")
+	}
+
+	if errReply != nil {
+		return errReply
+	}
+
+	ctx := &commands.Context{DB: c.db, Subscriber: c, Protocol: c.protocol, User: c.user}
+
+	execMu.Lock()
+	reply := executeCommand(ctx, req.Command, args)
+	handleReadyKeys()
+	execMu.Unlock()
+
+	c.db = ctx.DB
+
+	if w, timeout, timeoutReply := ctx.Blocked(); w != nil {
+		return c.waitUnblocked(w, timeout, timeoutReply)
+	}
+
+	return reply
+}
+
+// waitUnblocked waits until the blocked client is served, its timeout expires
+// or it disconnects.
+func (c *client) waitUnblocked(w *blocking.Waiter, timeout time.Duration, timeoutReply payload.Reply) payload.Reply {
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+
+		expired = timer.C
+	}
+
+	select {
+	case reply := <-w.Reply():
+		return reply
+	case <-expired:
+	case <-c.closed:
+	}
+
+	execMu.Lock()
+	unblocked := databases[c.db].blocking.Unblock(w)
+	execMu.Unlock()
+
+	// the client has been served right before giving up on it
+	if !unblocked {
+		return <-w.Reply()
+	}
+
+	return timeoutReply
+}
+
+// subscribed returns true if the client is subscribed to channels, patterns
+// or shard channels.
+func (c *client) subscribed() bool {
+	execMu.Lock()
+	defer execMu.Unlock()
+
+	return pubsubHub.Subscribed(c)
+}
+
+func (c *client) startTransaction(args []string) payload.Reply {
+	if len(args) != 0 {
+		return payload.Error("ERR wrong number of arguments for 'multi' command")
+	}
+
+	if c.multi != nil {
+		return payload.Error("ERR MULTI calls can not be nested")
+	}
+
+	c.multi = &transaction{}
+
+	return payload.SimpleString("OK")
+}
+
+func (c *client) queue(name string, args []string) payload.Reply {
+	cmd, errReply := lookupCommand(name, args)
+	if errReply == nil {
+		errReply = c.checkPermission(cmd, args, "toplevel")
+	}
+
+	if errReply != nil {
+		c.multi.dirty = true
+		return errReply
+	}
+
+	c.multi.queued = append(c.multi.queued, queuedCommand{name: name, args: args})
+
+	return payload.SimpleString("QUEUED")
+}
+
+// exec runs the queued commands at once. Clients blocked on keys the
+// transaction pushed to are only served after all the commands ran.
+func (c *client) exec(args []string) payload.Reply {
+	if len(args) != 0 {
+		return payload.Error("ERR wrong number of arguments for 'exec' command")
+	}
+
+	if c.multi == nil {
+		return payload.Error("ERR EXEC without MULTI")
+	}
+
+	multi := c.multi
+	c.multi = nil
+
+	if multi.dirty {
+		return payload.Error("EXECABORT Transaction discarded because of previous errors.")
+	}
+
+	replies := make(payload.Array, 0, len(multi.queued))
+
+	execMu.Lock()
+	for _, queued := range multi.queued {
+		// the permissions of the user may have changed since the command
+		// was queued
+		if errReply := c.checkPermission(commandTable[queued.name], queued.args, "multi"); errReply != nil {
+			replies = append(replies, errReply)
+			continue
+		}
+
+		ctx := &commands.Context{InMulti: true, DB: c.db, Subscriber: c, Protocol: c.protocol, User: c.user}
+		replies = append(replies, executeCommand(ctx, queued.name, queued.args))
+		c.db = ctx.DB
+	}
+	handleReadyKeys()
+	execMu.Unlock()
+
+	return replies
+}
+
+func (c *client) discard(args []string) payload.Reply {
+	if len(args) != 0 {
+		return payload.Error("ERR wrong number of arguments for 'discard' command")
+	}
+
+	if c.multi == nil {
+		return payload.Error("ERR DISCARD without MULTI")
+	}
+
+	c.multi = nil
+
+	return payload.SimpleString("OK")
+}
+
+// checkPermission returns the error reply to send back if the user of the
+// client can't run the command, logging the denial in the ACL LOG. The
+// context is toplevel, or multi for the commands run by EXEC.
+func (c *client) checkPermission(cmd *command, args []string, context string) payload.Reply {
+	err := accessControl.Check(c.user, cmd.request(args))
+	if err == nil {
+		return nil
+	}
+
+	var denied *acl.DeniedError
+	if errors.As(err, &denied) {
+		accessControl.Log().Add(denied.Reason, context, denied.Object, c.user, c.info())
+	}
+
+	return payload.Error(err.Error())
+}
+
+// info describes the client, as the ACL LOG has it.
+func (c *client) info() string {
+	return fmt.Sprintf("id=%d addr=%s laddr=%s name=%s db=%d user=%s resp=%d", c.id, c.conn.RemoteAddr(), c.conn.LocalAddr(), c.name, c.db, c.user, c.protocol)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser"
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMain(m *testing.M) {
+	databases = newDatabases(16)
+	for _, db := range databases {
+		registerCommands(db)
+	}
+
+	os.Exit(m.Run())
+}
+
+// newTestClient returns a client of an emptied server, whose requests are
+// run by calling run.
+func newTestClient(t *testing.T) *client {
+	conn, peer := net.Pipe()
+	t.Cleanup(func() {
+		conn.Close()
+		peer.Close()
+	})
+
+	for _, db := range databases {
+		db.kvStore.Flush()
+	}
+
+	return newClient(1, conn)
+}
+
+// run handles the request and returns its reply, encoded in the protocol of
+// the client.
+func run(c *client, args ...string) string {
+	req := &parser.RedisRequest{Command: strings.ToUpper(args[0])}
+	for _, arg := range args[1:] {
+		req.Payload = append(req.Payload, []byte(arg))
+	}
+
+	return string(payload.Encode(c.handle(req), c.protocol))
+}
+
+// serveTestClient serves a client over a pipe, and returns the other end of
+// the pipe.
+func serveTestClient(t *testing.T) net.Conn {
+	conn, peer := net.Pipe()
+	t.Cleanup(func() { peer.Close() })
+
+	go newClient(1, conn).serve()
+
+	return peer
+}
+
+// requireAuth makes the default user require the password for the duration
+// of the test.
+func requireAuth(t *testing.T, password string) {
+	setDefaultPassword(password)
+	t.Cleanup(func() { setDefaultPassword("") })
+}
+
+func TestClient_UnauthenticatedLimits(t *testing.T) {
+	requireAuth(t, "secret")
+
+	value := strings.Repeat("x", 20000)
+
+	t.Run("when the client sends a large argument before authenticating", func(t *testing.T) {
+		conn := serveTestClient(t)
+		go io.WriteString(conn, "*3\r\n$3\r\nSET\r\n$1\r\nk\r\n$20000\r\n"+value+"\r\n")
+
+		reply, err := bufio.NewReader(conn).ReadString('\n')
+		require.NoError(t, err)
+		assert.Equal(t, "-ERR Protocol error: unauthenticated bulk length\r\n", reply)
+	})
+
+	t.Run("when the large argument is pipelined after AUTH", func(t *testing.T) {
+		conn := serveTestClient(t)
+		go io.WriteString(conn, "*2\r\n$4\r\nAUTH\r\n$6\r\nsecret\r\n*3\r\n$3\r\nSET\r\n$1\r\nk\r\n$20000\r\n"+value+"\r\n")
+
+		rd := bufio.NewReader(conn)
+		for i := 0; i < 2; i++ {
+			reply, err := rd.ReadString('\n')
+			require.NoError(t, err)
+			assert.Equal(t, "+OK\r\n", reply)
+		}
+	})
+}
+
+func TestClient_NullReplies(t *testing.T) {
+	testCases := map[string]struct {
+		args  []string
+		resp2 string
+		resp3 string
+	}{
+		"when string is missing": {
+			args:  []string{"GET", "missing"},
+			resp2: "$-1\r\n",
+			resp3: "_\r\n",
+		},
+		"when sorted set member is missing": {
+			args:  []string{"ZSCORE", "zset", "missing"},
+			resp2: "$-1\r\n",
+			resp3: "_\r\n",
+		},
+		"when ranking missing member": {
+			args:  []string{"ZRANK", "zset", "missing"},
+			resp2: "$-1\r\n",
+			resp3: "_\r\n",
+		},
+		"when popping from missing list": {
+			args:  []string{"LPOP", "missing"},
+			resp2: "$-1\r\n",
+			resp3: "_\r\n",
+		},
+		"when blocking pop times out": {
+			args:  []string{"BLPOP", "missing", "0.01"},
+			resp2: "*-1\r\n",
+			resp3: "_\r\n",
+		},
+		"when scores of missing members given": {
+			args:  []string{"ZMSCORE", "zset", "a", "missing"},
+			resp2: "*2\r\n$1\r\n1\r\n$-1\r\n",
+			resp3: "*2\r\n,1\r\n_\r\n",
+		},
+		"when positions of missing members given": {
+			args:  []string{"GEOPOS", "geo", "missing"},
+			resp2: "*1\r\n*-1\r\n",
+			resp3: "*1\r\n_\r\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			c := newTestClient(t)
+			run(c, "ZADD", "zset", "1", "a")
+			run(c, "GEOADD", "geo", "13.361389", "38.115556", "Palermo")
+
+			assert.Equal(t, tc.resp2, run(c, tc.args...))
+
+			run(c, "HELLO", "3")
+			assert.Equal(t, tc.resp3, run(c, tc.args...))
+		})
+	}
+}
+
+func TestClient_Transaction(t *testing.T) {
+	c := newTestClient(t)
+
+	assert.Equal(t, "+OK\r\n", run(c, "MULTI"))
+	assert.Equal(t, "+QUEUED\r\n", run(c, "SET", "k", "a\r\nb"))
+	assert.Equal(t, "+QUEUED\r\n", run(c, "GET", "k"))
+	assert.Equal(t, "*2\r\n+OK\r\n$4\r\na\r\nb\r\n", run(c, "EXEC"))
+}
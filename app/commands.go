@@ -0,0 +1,386 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/acl"
+	"github.com/codecrafters-io/redis-starter-go/internal/commands"
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+)
+
+type commandHandler func(ctx *commands.Context, args []string) payload.Reply
+
+type command struct {
+	name string
+	// arity is the number of arguments including the command name. A negative
+	// arity means at least -arity arguments.
+	arity int
+	// categories are the ACL categories the command belongs to.
+	categories acl.Category
+	// keySpecs find the keys and channels the command accesses.
+	keySpecs []keySpec
+	// subcommands is set for the commands whose first argument is a
+	// subcommand, which ACL rules can allow on its own.
+	subcommands bool
+	// handlers holds the handler of the command for every database, by index.
+	handlers []commandHandler
+}
+
+func (c *command) checkArity(argc int) bool {
+	if c.arity < 0 {
+		return argc >= -c.arity
+	}
+
+	return argc == c.arity
+}
+
+// request returns what running the command with the arguments needs the
+// permission of.
+func (c *command) request(args []string) *acl.Request {
+	req := &acl.Request{Command: c.name, Categories: c.categories}
+	if c.subcommands && len(args) > 0 {
+		req.Subcommand = strings.ToLower(args[0])
+
+		if categories, exists := subcommandCategories[c.name+"|"+req.Subcommand]; exists {
+			req.Categories = categories
+		}
+	}
+
+	for _, spec := range c.keySpecs {
+		spec(req, args)
+	}
+
+	return req
+}
+
+var commandTable = map[string]*command{}
+
+// clientCommands are the commands run by the client itself, as they change
+// its state. They're only listed for their ACL categories.
+var clientCommands = map[string]*command{
+	"AUTH":    newCommand("AUTH", -2, "fast connection"),
+	"HELLO":   newCommand("HELLO", -1, "fast connection"),
+	"QUIT":    newCommand("QUIT", -1, "fast connection"),
+	"MULTI":   newCommand("MULTI", 1, "fast transaction"),
+	"EXEC":    newCommand("EXEC", 1, "slow transaction"),
+	"DISCARD": newCommand("DISCARD", 1, "fast transaction"),
+}
+
+// subcommandCommands are the commands having subcommands.
+var subcommandCommands = map[string]bool{
+	"CONFIG": true,
+	"PUBSUB": true,
+	"ACL":    true,
+	"XINFO":  true,
+}
+
+// subcommandCategories are the ACL categories of the subcommands not
+// belonging to the categories of their command, e.g. anyone can run ACL
+// WHOAMI.
+var subcommandCategories = map[string]acl.Category{
+	"acl|whoami":  mustParseCategories("acl|whoami", "slow"),
+	"acl|cat":     mustParseCategories("acl|cat", "slow"),
+	"acl|genpass": mustParseCategories("acl|genpass", "slow"),
+}
+
+func newCommand(name string, arity int, categories string, specs ...keySpec) *command {
+	return &command{
+		name:        strings.ToLower(name),
+		arity:       arity,
+		categories:  mustParseCategories(name, categories),
+		keySpecs:    specs,
+		subcommands: subcommandCommands[name],
+	}
+}
+
+// mustParseCategories parses the categories of a command, panicking if they're
+// invalid since they can only come from a programming error.
+func mustParseCategories(name, categories string) acl.Category {
+	parsed, err := acl.ParseCategories(categories)
+	if err != nil {
+		panic(fmt.Sprintf("command %s: %s", name, err))
+	}
+
+	return parsed
+}
+
+// registerCommand adds the handler of the command for the next database. The
+// categories are space separated ACL category names, commands which aren't
+// fast being slow.
+func registerCommand(name string, arity int, categories string, handler commandHandler, specs ...keySpec) {
+	cmd, exists := commandTable[name]
+	if !exists {
+		cmd = newCommand(name, arity, categories, specs...)
+		commandTable[name] = cmd
+	}
+
+	cmd.handlers = append(cmd.handlers, handler)
+}
+
+// isCommand returns true if the command exists, for ACL rules.
+func isCommand(name string) bool {
+	name = strings.ToUpper(name)
+	return commandTable[name] != nil || clientCommands[name] != nil
+}
+
+// commandsInCategory returns the sorted names of the commands belonging to
+// the ACL category.
+func commandsInCategory(category acl.Category) []string {
+	names := []string{}
+	for _, table := range []map[string]*command{commandTable, clientCommands} {
+		for _, cmd := range table {
+			if cmd.categories&category != 0 {
+				names = append(names, cmd.name)
+			}
+		}
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// registerCommands registers the commands running against the database. The
+// databases must be registered in the order of their index.
+func registerCommands(db *database) {
+	registerCommand("PING", -1, "fast connection", ping)
+	registerCommand("ECHO", 2, "fast connection", echo)
+	registerCommand("TYPE", 2, "keyspace read fast", func(ctx *commands.Context, args []string) payload.Reply { return db.typeCommand.GetType(args[0]) }, readKeys(1, 1, 1))
+	registerCommand("CONFIG", -2, "admin slow dangerous", configCommand.Handle)
+	registerCommand("ACL", -2, "admin slow dangerous", aclCommand.Handle)
+
+	registerCommand("SUBSCRIBE", -2, "pubsub slow", pubSubCommands.Subscribe, channels(1, -1))
+	registerCommand("PSUBSCRIBE", -2, "pubsub slow", pubSubCommands.PSubscribe, patterns(1, -1))
+	registerCommand("UNSUBSCRIBE", -1, "pubsub slow", pubSubCommands.Unsubscribe)
+	registerCommand("PUNSUBSCRIBE", -1, "pubsub slow", pubSubCommands.PUnsubscribe)
+	registerCommand("SSUBSCRIBE", -2, "pubsub slow", pubSubCommands.SSubscribe, channels(1, -1))
+	registerCommand("SUNSUBSCRIBE", -1, "pubsub slow", pubSubCommands.SUnsubscribe)
+	registerCommand("PUBLISH", 3, "pubsub fast", pubSubCommands.Publish, channels(1, 1))
+	registerCommand("SPUBLISH", 3, "pubsub fast", pubSubCommands.SPublish, channels(1, 1))
+	registerCommand("PUBSUB", -2, "pubsub slow", pubSubCommands.PubSub)
+
+	registerCommand("DEL", -2, "keyspace write slow", db.keyCommands.Del, writeKeys(1, -1, 1))
+	registerCommand("UNLINK", -2, "keyspace write fast", db.keyCommands.Unlink, writeKeys(1, -1, 1))
+	registerCommand("EXISTS", -2, "keyspace read fast", db.keyCommands.Exists, readKeys(1, -1, 1))
+	registerCommand("KEYS", 2, "keyspace read slow dangerous", db.keyCommands.Keys)
+	registerCommand("RENAME", 3, "keyspace write slow", db.keyCommands.Rename, rwKeys(1, 1, 1), writeKeys(2, 2, 1))
+	registerCommand("RENAMENX", 3, "keyspace write fast", db.keyCommands.RenameNX, rwKeys(1, 1, 1), writeKeys(2, 2, 1))
+	registerCommand("COPY", -3, "keyspace write slow", db.keyCommands.Copy, readKeys(1, 1, 1), writeKeys(2, 2, 1))
+	registerCommand("TOUCH", -2, "keyspace read fast", db.keyCommands.Touch, readKeys(1, -1, 1))
+	registerCommand("RANDOMKEY", 1, "keyspace read slow", db.keyCommands.RandomKey)
+	registerCommand("DBSIZE", 1, "keyspace read fast", db.keyCommands.DBSize)
+	registerCommand("SELECT", 2, "fast connection", db.keyCommands.Select)
+	registerCommand("MOVE", 3, "keyspace write fast", db.keyCommands.Move, rwKeys(1, 1, 1))
+	registerCommand("SWAPDB", 3, "keyspace write fast dangerous", db.keyCommands.SwapDB)
+	registerCommand("FLUSHDB", -1, "keyspace write slow dangerous", db.keyCommands.FlushDB)
+	registerCommand("FLUSHALL", -1, "keyspace write slow dangerous", db.keyCommands.FlushAll)
+	registerCommand("SCAN", -2, "keyspace read slow", db.keyCommands.Scan)
+
+	registerCommand("SET", -3, "write string slow", db.stringCommands.Set, writeKeys(1, 1, 1))
+	registerCommand("SETNX", 3, "write string fast", db.stringCommands.SetNX, writeKeys(1, 1, 1))
+	registerCommand("SETEX", 4, "write string slow", db.stringCommands.SetEx, writeKeys(1, 1, 1))
+	registerCommand("PSETEX", 4, "write string slow", db.stringCommands.PSetEx, writeKeys(1, 1, 1))
+	registerCommand("GETSET", 3, "write string fast", db.stringCommands.GetSet, rwKeys(1, 1, 1))
+	registerCommand("MSET", -3, "write string slow", db.stringCommands.MSet, writeKeys(1, -1, 2))
+	registerCommand("MSETNX", -3, "write string slow", db.stringCommands.MSetNX, writeKeys(1, -1, 2))
+	registerCommand("GET", 2, "read string fast", db.stringCommands.Get, readKeys(1, 1, 1))
+	registerCommand("MGET", -2, "read string fast", db.stringCommands.MGet, readKeys(1, -1, 1))
+	registerCommand("GETDEL", 2, "write string fast", db.stringCommands.GetDel, rwKeys(1, 1, 1))
+	registerCommand("GETEX", -2, "write string fast", db.stringCommands.GetEx, rwKeys(1, 1, 1))
+	registerCommand("APPEND", 3, "write string fast", db.stringCommands.Append, rwKeys(1, 1, 1))
+	registerCommand("STRLEN", 2, "read string fast", db.stringCommands.StrLen, readKeys(1, 1, 1))
+	registerCommand("GETRANGE", 4, "read string slow", db.stringCommands.GetRange, readKeys(1, 1, 1))
+	registerCommand("SETRANGE", 4, "write string slow", db.stringCommands.SetRange, rwKeys(1, 1, 1))
+	registerCommand("LCS", -3, "read string slow", db.stringCommands.LCS, readKeys(1, 2, 1))
+	registerCommand("INCR", 2, "write string fast", db.stringCommands.Incr, rwKeys(1, 1, 1))
+	registerCommand("DECR", 2, "write string fast", db.stringCommands.Decr, rwKeys(1, 1, 1))
+	registerCommand("INCRBY", 3, "write string fast", db.stringCommands.IncrBy, rwKeys(1, 1, 1))
+	registerCommand("DECRBY", 3, "write string fast", db.stringCommands.DecrBy, rwKeys(1, 1, 1))
+	registerCommand("INCRBYFLOAT", 3, "write string fast", db.stringCommands.IncrByFloat, rwKeys(1, 1, 1))
+
+	registerCommand("SETBIT", 4, "write bitmap slow", db.bitmapCommands.SetBit, rwKeys(1, 1, 1))
+	registerCommand("GETBIT", 3, "read bitmap fast", db.bitmapCommands.GetBit, readKeys(1, 1, 1))
+	registerCommand("BITCOUNT", -2, "read bitmap slow", db.bitmapCommands.BitCount, readKeys(1, 1, 1))
+	registerCommand("BITPOS", -3, "read bitmap slow", db.bitmapCommands.BitPos, readKeys(1, 1, 1))
+	registerCommand("BITOP", -4, "write bitmap slow", db.bitmapCommands.BitOp, writeKeys(2, 2, 1), readKeys(3, -1, 1))
+	registerCommand("BITFIELD", -2, "write bitmap slow", db.bitmapCommands.BitField, rwKeys(1, 1, 1))
+	registerCommand("BITFIELD_RO", -2, "read bitmap fast", db.bitmapCommands.BitFieldRO, readKeys(1, 1, 1))
+
+	registerCommand("PFADD", -2, "write hyperloglog fast", db.hllCommands.PFAdd, rwKeys(1, 1, 1))
+	registerCommand("PFCOUNT", -2, "read hyperloglog slow", db.hllCommands.PFCount, readKeys(1, -1, 1))
+	registerCommand("PFMERGE", -2, "write hyperloglog slow", db.hllCommands.PFMerge, rwKeys(1, 1, 1), readKeys(2, -1, 1))
+	registerCommand("PFDEBUG", 3, "write hyperloglog admin slow dangerous", db.hllCommands.PFDebug, rwKeys(2, 2, 1))
+	registerCommand("PFSELFTEST", 1, "hyperloglog admin slow dangerous", db.hllCommands.PFSelfTest)
+
+	registerCommand("XADD", -5, "write stream fast", db.streamCommands.XAdd, rwKeys(1, 1, 1))
+	registerCommand("XRANGE", 4, "read stream slow", db.streamCommands.XRange, readKeys(1, 1, 1))
+	registerCommand("XREAD", -4, "read stream slow blocking", db.streamCommands.XRead, streamKeys)
+	registerCommand("XINFO", -2, "read stream slow", db.streamCommands.XInfo, readKeys(2, 2, 1))
+	registerCommand("XLEN", 2, "read stream fast", db.streamCommands.XLen, readKeys(1, 1, 1))
+
+	registerCommand("LPUSH", -3, "write list fast", db.listCommands.LPush, writeKeys(1, 1, 1))
+	registerCommand("RPUSH", -3, "write list fast", db.listCommands.RPush, writeKeys(1, 1, 1))
+	registerCommand("LPUSHX", -3, "write list fast", db.listCommands.LPushX, writeKeys(1, 1, 1))
+	registerCommand("RPUSHX", -3, "write list fast", db.listCommands.RPushX, writeKeys(1, 1, 1))
+	registerCommand("LPOP", -2, "write list fast", db.listCommands.LPop, rwKeys(1, 1, 1))
+	registerCommand("RPOP", -2, "write list fast", db.listCommands.RPop, rwKeys(1, 1, 1))
+	registerCommand("LLEN", 2, "read list fast", db.listCommands.LLen, readKeys(1, 1, 1))
+	registerCommand("LRANGE", 4, "read list slow", db.listCommands.LRange, readKeys(1, 1, 1))
+	registerCommand("LINDEX", 3, "read list slow", db.listCommands.LIndex, readKeys(1, 1, 1))
+	registerCommand("LSET", 4, "write list slow", db.listCommands.LSet, rwKeys(1, 1, 1))
+	registerCommand("LREM", 4, "write list slow", db.listCommands.LRem, rwKeys(1, 1, 1))
+	registerCommand("LTRIM", 4, "write list slow", db.listCommands.LTrim, rwKeys(1, 1, 1))
+	registerCommand("LINSERT", 5, "write list slow", db.listCommands.LInsert, rwKeys(1, 1, 1))
+	registerCommand("LPOS", -3, "read list slow", db.listCommands.LPos, readKeys(1, 1, 1))
+	registerCommand("LMOVE", 5, "write list slow", db.listCommands.LMove, rwKeys(1, 2, 1))
+	registerCommand("LMPOP", -4, "write list slow", db.listCommands.LMPop, numKeys(acl.ReadWriteAccess, 1))
+	registerCommand("BLPOP", -3, "write list slow blocking", db.listCommands.BLPop, rwKeys(1, -2, 1))
+	registerCommand("BRPOP", -3, "write list slow blocking", db.listCommands.BRPop, rwKeys(1, -2, 1))
+	registerCommand("BLMOVE", 6, "write list slow blocking", db.listCommands.BLMove, rwKeys(1, 2, 1))
+	registerCommand("BLMPOP", -5, "write list slow blocking", db.listCommands.BLMPop, numKeys(acl.ReadWriteAccess, 2))
+
+	registerCommand("HSET", -4, "write hash fast", db.hashCommands.HSet, writeKeys(1, 1, 1))
+	registerCommand("HMSET", -4, "write hash fast", db.hashCommands.HMSet, writeKeys(1, 1, 1))
+	registerCommand("HSETNX", 4, "write hash fast", db.hashCommands.HSetNX, writeKeys(1, 1, 1))
+	registerCommand("HGET", 3, "read hash fast", db.hashCommands.HGet, readKeys(1, 1, 1))
+	registerCommand("HMGET", -3, "read hash fast", db.hashCommands.HMGet, readKeys(1, 1, 1))
+	registerCommand("HDEL", -3, "write hash fast", db.hashCommands.HDel, rwKeys(1, 1, 1))
+	registerCommand("HLEN", 2, "read hash fast", db.hashCommands.HLen, readKeys(1, 1, 1))
+	registerCommand("HEXISTS", 3, "read hash fast", db.hashCommands.HExists, readKeys(1, 1, 1))
+	registerCommand("HSTRLEN", 3, "read hash fast", db.hashCommands.HStrLen, readKeys(1, 1, 1))
+	registerCommand("HGETALL", 2, "read hash slow", db.hashCommands.HGetAll, readKeys(1, 1, 1))
+	registerCommand("HKEYS", 2, "read hash slow", db.hashCommands.HKeys, readKeys(1, 1, 1))
+	registerCommand("HVALS", 2, "read hash slow", db.hashCommands.HVals, readKeys(1, 1, 1))
+	registerCommand("HINCRBY", 4, "write hash fast", db.hashCommands.HIncrBy, rwKeys(1, 1, 1))
+	registerCommand("HINCRBYFLOAT", 4, "write hash fast", db.hashCommands.HIncrByFloat, rwKeys(1, 1, 1))
+	registerCommand("HRANDFIELD", -2, "read hash slow", db.hashCommands.HRandField, readKeys(1, 1, 1))
+	registerCommand("HSCAN", -3, "read hash slow", db.hashCommands.HScan, readKeys(1, 1, 1))
+	registerCommand("HEXPIRE", -6, "write hash fast", db.hashCommands.HExpire, rwKeys(1, 1, 1))
+	registerCommand("HPEXPIRE", -6, "write hash fast", db.hashCommands.HPExpire, rwKeys(1, 1, 1))
+	registerCommand("HEXPIREAT", -6, "write hash fast", db.hashCommands.HExpireAt, rwKeys(1, 1, 1))
+	registerCommand("HPEXPIREAT", -6, "write hash fast", db.hashCommands.HPExpireAt, rwKeys(1, 1, 1))
+	registerCommand("HTTL", -5, "read hash fast", db.hashCommands.HTTL, readKeys(1, 1, 1))
+	registerCommand("HPTTL", -5, "read hash fast", db.hashCommands.HPTTL, readKeys(1, 1, 1))
+	registerCommand("HEXPIRETIME", -5, "read hash fast", db.hashCommands.HExpireTime, readKeys(1, 1, 1))
+	registerCommand("HPEXPIRETIME", -5, "read hash fast", db.hashCommands.HPExpireTime, readKeys(1, 1, 1))
+	registerCommand("HPERSIST", -5, "write hash fast", db.hashCommands.HPersist, rwKeys(1, 1, 1))
+
+	registerCommand("SADD", -3, "write set fast", db.setCommands.SAdd, writeKeys(1, 1, 1))
+	registerCommand("SREM", -3, "write set fast", db.setCommands.SRem, rwKeys(1, 1, 1))
+	registerCommand("SISMEMBER", 3, "read set fast", db.setCommands.SIsMember, readKeys(1, 1, 1))
+	registerCommand("SMISMEMBER", -3, "read set fast", db.setCommands.SMIsMember, readKeys(1, 1, 1))
+	registerCommand("SMEMBERS", 2, "read set slow", db.setCommands.SMembers, readKeys(1, 1, 1))
+	registerCommand("SCARD", 2, "read set fast", db.setCommands.SCard, readKeys(1, 1, 1))
+	registerCommand("SSCAN", -3, "read set slow", db.setCommands.SScan, readKeys(1, 1, 1))
+	registerCommand("SPOP", -2, "write set fast", db.setCommands.SPop, rwKeys(1, 1, 1))
+	registerCommand("SRANDMEMBER", -2, "read set slow", db.setCommands.SRandMember, readKeys(1, 1, 1))
+	registerCommand("SINTER", -2, "read set slow", db.setCommands.SInter, readKeys(1, -1, 1))
+	registerCommand("SUNION", -2, "read set slow", db.setCommands.SUnion, readKeys(1, -1, 1))
+	registerCommand("SDIFF", -2, "read set slow", db.setCommands.SDiff, readKeys(1, -1, 1))
+	registerCommand("SINTERSTORE", -3, "write set slow", db.setCommands.SInterStore, writeKeys(1, 1, 1), readKeys(2, -1, 1))
+	registerCommand("SUNIONSTORE", -3, "write set slow", db.setCommands.SUnionStore, writeKeys(1, 1, 1), readKeys(2, -1, 1))
+	registerCommand("SDIFFSTORE", -3, "write set slow", db.setCommands.SDiffStore, writeKeys(1, 1, 1), readKeys(2, -1, 1))
+	registerCommand("SINTERCARD", -3, "read set slow", db.setCommands.SInterCard, numKeys(acl.ReadAccess, 1))
+
+	registerCommand("ZADD", -4, "write sortedset fast", db.zsetCommands.ZAdd, rwKeys(1, 1, 1))
+	registerCommand("ZINCRBY", 4, "write sortedset fast", db.zsetCommands.ZIncrBy, rwKeys(1, 1, 1))
+	registerCommand("ZSCORE", 3, "read sortedset fast", db.zsetCommands.ZScore, readKeys(1, 1, 1))
+	registerCommand("ZMSCORE", -3, "read sortedset fast", db.zsetCommands.ZMScore, readKeys(1, 1, 1))
+	registerCommand("ZCARD", 2, "read sortedset fast", db.zsetCommands.ZCard, readKeys(1, 1, 1))
+	registerCommand("ZREM", -3, "write sortedset fast", db.zsetCommands.ZRem, rwKeys(1, 1, 1))
+	registerCommand("ZSCAN", -3, "read sortedset slow", db.zsetCommands.ZScan, readKeys(1, 1, 1))
+	registerCommand("ZRANGE", -4, "read sortedset slow", db.zsetCommands.ZRange, readKeys(1, 1, 1))
+	registerCommand("ZREVRANGE", -4, "read sortedset slow", db.zsetCommands.ZRevRange, readKeys(1, 1, 1))
+	registerCommand("ZRANGEBYSCORE", -4, "read sortedset slow", db.zsetCommands.ZRangeByScore, readKeys(1, 1, 1))
+	registerCommand("ZREVRANGEBYSCORE", -4, "read sortedset slow", db.zsetCommands.ZRevRangeByScore, readKeys(1, 1, 1))
+	registerCommand("ZRANGEBYLEX", -4, "read sortedset slow", db.zsetCommands.ZRangeByLex, readKeys(1, 1, 1))
+	registerCommand("ZREVRANGEBYLEX", -4, "read sortedset slow", db.zsetCommands.ZRevRangeByLex, readKeys(1, 1, 1))
+	registerCommand("ZRANK", -3, "read sortedset fast", db.zsetCommands.ZRank, readKeys(1, 1, 1))
+	registerCommand("ZREVRANK", -3, "read sortedset fast", db.zsetCommands.ZRevRank, readKeys(1, 1, 1))
+	registerCommand("ZCOUNT", 4, "read sortedset fast", db.zsetCommands.ZCount, readKeys(1, 1, 1))
+	registerCommand("ZLEXCOUNT", 4, "read sortedset fast", db.zsetCommands.ZLexCount, readKeys(1, 1, 1))
+	registerCommand("ZREMRANGEBYRANK", 4, "write sortedset slow", db.zsetCommands.ZRemRangeByRank, rwKeys(1, 1, 1))
+	registerCommand("ZREMRANGEBYSCORE", 4, "write sortedset slow", db.zsetCommands.ZRemRangeByScore, rwKeys(1, 1, 1))
+	registerCommand("ZREMRANGEBYLEX", 4, "write sortedset slow", db.zsetCommands.ZRemRangeByLex, rwKeys(1, 1, 1))
+	registerCommand("ZRANGESTORE", -5, "write sortedset slow", db.zsetCommands.ZRangeStore, writeKeys(1, 1, 1), readKeys(2, 2, 1))
+	registerCommand("ZUNION", -3, "read sortedset slow", db.zsetCommands.ZUnion, numKeys(acl.ReadAccess, 1))
+	registerCommand("ZINTER", -3, "read sortedset slow", db.zsetCommands.ZInter, numKeys(acl.ReadAccess, 1))
+	registerCommand("ZDIFF", -3, "read sortedset slow", db.zsetCommands.ZDiff, numKeys(acl.ReadAccess, 1))
+	registerCommand("ZUNIONSTORE", -4, "write sortedset slow", db.zsetCommands.ZUnionStore, writeKeys(1, 1, 1), numKeys(acl.ReadAccess, 2))
+	registerCommand("ZINTERSTORE", -4, "write sortedset slow", db.zsetCommands.ZInterStore, writeKeys(1, 1, 1), numKeys(acl.ReadAccess, 2))
+	registerCommand("ZDIFFSTORE", -4, "write sortedset slow", db.zsetCommands.ZDiffStore, writeKeys(1, 1, 1), numKeys(acl.ReadAccess, 2))
+	registerCommand("ZPOPMIN", -2, "write sortedset fast", db.zsetCommands.ZPopMin, rwKeys(1, 1, 1))
+	registerCommand("ZPOPMAX", -2, "write sortedset fast", db.zsetCommands.ZPopMax, rwKeys(1, 1, 1))
+	registerCommand("ZMPOP", -4, "write sortedset slow", db.zsetCommands.ZMPop, numKeys(acl.ReadWriteAccess, 1))
+	registerCommand("BZPOPMIN", -3, "write sortedset fast blocking", db.zsetCommands.BZPopMin, rwKeys(1, -2, 1))
+	registerCommand("BZPOPMAX", -3, "write sortedset fast blocking", db.zsetCommands.BZPopMax, rwKeys(1, -2, 1))
+	registerCommand("BZMPOP", -5, "write sortedset slow blocking", db.zsetCommands.BZMPop, numKeys(acl.ReadWriteAccess, 2))
+
+	registerCommand("GEOADD", -5, "write geo slow", db.geoCommands.GeoAdd, rwKeys(1, 1, 1))
+	registerCommand("GEODIST", -4, "read geo slow", db.geoCommands.GeoDist, readKeys(1, 1, 1))
+	registerCommand("GEOHASH", -2, "read geo slow", db.geoCommands.GeoHash, readKeys(1, 1, 1))
+	registerCommand("GEOPOS", -2, "read geo slow", db.geoCommands.GeoPos, readKeys(1, 1, 1))
+	registerCommand("GEOSEARCH", -7, "read geo slow", db.geoCommands.GeoSearch, readKeys(1, 1, 1))
+	registerCommand("GEOSEARCHSTORE", -8, "write geo slow", db.geoCommands.GeoSearchStore, writeKeys(1, 1, 1), readKeys(2, 2, 1))
+}
+
+// lookupCommand finds the command and checks its arity, returning the error
+// reply to send back to the client if it can't be run.
+func lookupCommand(name string, args []string) (*command, payload.Reply) {
+	cmd, exists := commandTable[name]
+	if !exists {
+		quotedArgs := ""
+		for _, arg := range args {
+			quotedArgs += fmt.Sprintf("'%s' ", arg)
+		}
+
+		return nil, payload.Error(fmt.Sprintf("ERR unknown command '%s', with args beginning with: %s", strings.ToLower(name), quotedArgs))
+	}
+
+	if !cmd.checkArity(len(args) + 1) {
+		return nil, payload.Error(fmt.Sprintf("ERR wrong number of arguments for '%s' command", cmd.name))
+	}
+
+	return cmd, nil
+}
+
+// executeCommand looks the command up and runs it, returning the reply to
+// send back to the client. The reply is nil if the command blocked the
+// client, see commands.Context.Blocked.
+func executeCommand(ctx *commands.Context, name string, args []string) payload.Reply {
+	cmd, errReply := lookupCommand(name, args)
+	if errReply != nil {
+		return errReply
+	}
+
+	return cmd.handlers[ctx.DB](ctx, args)
+}
+
+func ping(ctx *commands.Context, args []string) payload.Reply {
+	if len(args) > 1 {
+		return payload.Error("ERR wrong number of arguments for 'ping' command")
+	}
+
+	// in subscribe mode, PING replies as a message would be pushed, unless
+	// pushed messages can be told apart from replies as over RESP3
+	if !ctx.RESP3() && pubsubHub.Subscribed(ctx.Subscriber) {
+		message := ""
+		if len(args) == 1 {
+			message = args[0]
+		}
+
+		return payload.BulkStrings([]string{"pong", message})
+	}
+
+	if len(args) == 1 {
+		return payload.BulkString(args[0])
+	}
+
+	return payload.SimpleString("PONG")
+}
+
+func echo(ctx *commands.Context, args []string) payload.Reply {
+	return payload.BulkString(args[0])
+}
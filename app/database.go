@@ -0,0 +1,70 @@
+package main
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/internal/blocking"
+	"github.com/codecrafters-io/redis-starter-go/internal/commands"
+	"github.com/codecrafters-io/redis-starter-go/internal/store"
+)
+
+// database is a logical database: its keyspace, the stores of every type
+// built on it, the clients blocked on its keys, and the commands running
+// against it.
+type database struct {
+	kvStore  *store.KVStore
+	blocking *blocking.Manager
+
+	typeCommand    *commands.TypeCommand
+	keyCommands    *commands.KeyCommands
+	stringCommands *commands.StringCommands
+	bitmapCommands *commands.BitmapCommands
+	streamCommands *commands.StreamCommands
+	listCommands   *commands.ListCommands
+	hashCommands   *commands.HashCommands
+	setCommands    *commands.SetCommands
+	zsetCommands   *commands.ZSetCommands
+	geoCommands    *commands.GeoCommands
+	hllCommands    *commands.HyperLogLogCommands
+}
+
+// newDatabases returns n empty databases.
+func newDatabases(n int) []*database {
+	kvStores := store.NewDatabases(n, notifier)
+
+	blockingManagers := make([]*blocking.Manager, n)
+	for i := range blockingManagers {
+		blockingManagers[i] = blocking.NewManager()
+	}
+
+	dbs := make([]*database, n)
+	for i, kvStore := range kvStores {
+		blockingManager := blockingManagers[i]
+		zsetStore := store.NewZSet(kvStore, cfg)
+
+		dbs[i] = &database{
+			kvStore:  kvStore,
+			blocking: blockingManager,
+
+			typeCommand:    commands.NewTypeCommand(kvStore),
+			keyCommands:    commands.NewKeyCommands(kvStores, blockingManagers, i),
+			stringCommands: commands.NewStringCommands(kvStore),
+			bitmapCommands: commands.NewBitmapCommands(kvStore),
+			streamCommands: commands.NewStreamCommands(store.NewStream(kvStore, cfg), blockingManager),
+			listCommands:   commands.NewListCommands(store.NewList(kvStore, cfg), blockingManager),
+			hashCommands:   commands.NewHashCommands(store.NewHash(kvStore, cfg)),
+			setCommands:    commands.NewSetCommands(store.NewSet(kvStore, cfg)),
+			zsetCommands:   commands.NewZSetCommands(zsetStore, blockingManager),
+			geoCommands:    commands.NewGeoCommands(zsetStore, blockingManager),
+			hllCommands:    commands.NewHyperLogLogCommands(store.NewHyperLogLog(kvStore, cfg)),
+		}
+	}
+
+	return dbs
+}
+
+// handleReadyKeys serves the clients blocked on the keys signaled as ready,
+// in every database.
+func handleReadyKeys() {
+	for _, db := range databases {
+		db.blocking.HandleReadyKeys()
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+)
+
+// hello runs HELLO [protover [AUTH username password] [SETNAME clientname]],
+// switching the connection to protover, and replies with the server and
+// connection properties in the protocol now in use. Nothing changes if any
+// argument is invalid.
+func (c *client) hello(args []string) payload.Reply {
+	protocol := c.protocol
+	name := c.name
+	authenticated := c.authenticated
+	user := c.user
+
+	if len(args) > 0 {
+		version, err := strconv.Atoi(args[0])
+		if err != nil {
+			return payload.Error("ERR Protocol version is not an integer or out of range")
+		}
+
+		if version != 2 && version != 3 {
+			return payload.Error("NOPROTO unsupported protocol version")
+		}
+
+		protocol = version
+	}
+
+	for i := 1; i < len(args); i++ {
+		switch option := strings.ToUpper(args[i]); {
+		case option == "AUTH" && i+2 < len(args):
+			if !c.authenticate(args[i+1], args[i+2]) {
+				return wrongPassReply
+			}
+
+			authenticated = true
+			user = args[i+1]
+			i += 2
+		case option == "SETNAME" && i+1 < len(args):
+			if !validClientName(args[i+1]) {
+				return payload.Error("ERR Client names cannot contain spaces, newlines or special characters.")
+			}
+
+			name = args[i+1]
+			i++
+		default:
+			return payload.Error(fmt.Sprintf("ERR Syntax error in HELLO option '%s'", args[i]))
+		}
+	}
+
+	if !authenticated {
+		return payload.Error("NOAUTH HELLO must be called with the client already authenticated, otherwise the HELLO <proto> AUTH <user> <pass> option can be used to authenticate the client and select the RESP protocol version at the same time")
+	}
+
+	execMu.Lock()
+	c.protocol = protocol
+	execMu.Unlock()
+
+	c.name = name
+	c.user = user
+	c.authenticated = true
+
+	return payload.Map{
+		{Key: payload.BulkString("server"), Value: payload.BulkString("redis")},
+		{Key: payload.BulkString("version"), Value: payload.BulkString(serverVersion)},
+		{Key: payload.BulkString("proto"), Value: payload.Integer(c.protocol)},
+		{Key: payload.BulkString("id"), Value: payload.Integer(c.id)},
+		{Key: payload.BulkString("mode"), Value: payload.BulkString("standalone")},
+		{Key: payload.BulkString("role"), Value: payload.BulkString("master")},
+		{Key: payload.BulkString("modules"), Value: payload.Array{}},
+	}
+}
+
+// validClientName returns true if the name only has printable characters
+// other than spaces, so that it can be listed.
+func validClientName(name string) bool {
+	for i := 0; i < len(name); i++ {
+		if name[i] <= ' ' || name[i] > '~' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHello(t *testing.T) {
+	testCases := map[string]struct {
+		args             []string
+		expectedPrefix   string
+		expectedProtocol int
+	}{
+		"when no version given": {
+			args:             []string{"HELLO"},
+			expectedPrefix:   "*14\r\n$6\r\nserver\r\n",
+			expectedProtocol: 2,
+		},
+		"when switching to RESP3": {
+			args:             []string{"HELLO", "3"},
+			expectedPrefix:   "%7\r\n$6\r\nserver\r\n",
+			expectedProtocol: 3,
+		},
+		"when version is unsupported": {
+			args:             []string{"HELLO", "4"},
+			expectedPrefix:   "-NOPROTO unsupported protocol version\r\n",
+			expectedProtocol: 2,
+		},
+		"when option is invalid": {
+			args:             []string{"HELLO", "3", "SETNAME"},
+			expectedPrefix:   "-ERR Syntax error in HELLO option 'SETNAME'\r\n",
+			expectedProtocol: 2,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			c := newTestClient(t)
+
+			reply := run(c, tc.args...)
+			assert.True(t, strings.HasPrefix(reply, tc.expectedPrefix), reply)
+			assert.Equal(t, tc.expectedProtocol, c.protocol)
+		})
+	}
+}
+
+func TestHello_RESP3Replies(t *testing.T) {
+	testCases := map[string]struct {
+		setup [][]string
+		args  []string
+		resp2 string
+		resp3 string
+	}{
+		"when hash given": {
+			setup: [][]string{{"HSET", "hash", "a", "1", "b", "2"}},
+			args:  []string{"HGETALL", "hash"},
+			resp2: "*4\r\n$1\r\na\r\n$1\r\n1\r\n$1\r\nb\r\n$1\r\n2\r\n",
+			resp3: "%2\r\n$1\r\na\r\n$1\r\n1\r\n$1\r\nb\r\n$1\r\n2\r\n",
+		},
+		"when score given": {
+			setup: [][]string{{"ZADD", "zset", "1.5", "a"}},
+			args:  []string{"ZSCORE", "zset", "a"},
+			resp2: "$3\r\n1.5\r\n",
+			resp3: ",1.5\r\n",
+		},
+		"when range with scores given": {
+			setup: [][]string{{"ZADD", "zset", "1", "a", "2", "b"}},
+			args:  []string{"ZRANGE", "zset", "0", "-1", "WITHSCORES"},
+			resp2: "*4\r\n$1\r\na\r\n$1\r\n1\r\n$1\r\nb\r\n$1\r\n2\r\n",
+			resp3: "*2\r\n*2\r\n$1\r\na\r\n,1\r\n*2\r\n$1\r\nb\r\n,2\r\n",
+		},
+		"when stream info given": {
+			setup: [][]string{{"XADD", "stream", "1-1", "a", "1"}},
+			args:  []string{"XINFO", "STREAM", "stream"},
+			resp2: "*18\r\n" +
+				"$6\r\nlength\r\n:1\r\n" +
+				"$15\r\nradix-tree-keys\r\n:1\r\n" +
+				"$17\r\nlast-generated-id\r\n$3\r\n1-1\r\n" +
+				"$20\r\nmax-deleted-entry-id\r\n$3\r\n0-0\r\n" +
+				"$13\r\nentries-added\r\n:1\r\n" +
+				"$23\r\nrecorded-first-entry-id\r\n$3\r\n1-1\r\n" +
+				"$6\r\ngroups\r\n:0\r\n" +
+				"$11\r\nfirst-entry\r\n*2\r\n$3\r\n1-1\r\n*2\r\n$1\r\na\r\n$1\r\n1\r\n" +
+				"$10\r\nlast-entry\r\n*2\r\n$3\r\n1-1\r\n*2\r\n$1\r\na\r\n$1\r\n1\r\n",
+			resp3: "%9\r\n" +
+				"$6\r\nlength\r\n:1\r\n" +
+				"$15\r\nradix-tree-keys\r\n:1\r\n" +
+				"$17\r\nlast-generated-id\r\n$3\r\n1-1\r\n" +
+				"$20\r\nmax-deleted-entry-id\r\n$3\r\n0-0\r\n" +
+				"$13\r\nentries-added\r\n:1\r\n" +
+				"$23\r\nrecorded-first-entry-id\r\n$3\r\n1-1\r\n" +
+				"$6\r\ngroups\r\n:0\r\n" +
+				"$11\r\nfirst-entry\r\n*2\r\n$3\r\n1-1\r\n*2\r\n$1\r\na\r\n$1\r\n1\r\n" +
+				"$10\r\nlast-entry\r\n*2\r\n$3\r\n1-1\r\n*2\r\n$1\r\na\r\n$1\r\n1\r\n",
+		},
+		"when stream is missing": {
+			args:  []string{"XINFO", "STREAM", "missing"},
+			resp2: "-ERR no such key\r\n",
+			resp3: "-ERR no such key\r\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			c := newTestClient(t)
+			for _, args := range tc.setup {
+				run(c, args...)
+			}
+
+			assert.Equal(t, tc.resp2, run(c, tc.args...))
+
+			run(c, "HELLO", "3")
+			assert.Equal(t, tc.resp3, run(c, tc.args...))
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/acl"
+)
+
+// keySpec adds the keys or channels a command accesses to the ACL request,
+// finding them in the arguments following the command name.
+type keySpec func(req *acl.Request, args []string)
+
+// keyRange finds the keys from the first to the last position, every step
+// positions. Positions start at 1 for the argument following the command
+// name as in COMMAND INFO, and count from the end when negative.
+func keyRange(access acl.Access, first, last, step int) keySpec {
+	return func(req *acl.Request, args []string) {
+		end := last - 1
+		if last < 0 {
+			end = len(args) + last
+		}
+
+		for i := first - 1; i <= end && i < len(args); i += step {
+			req.Keys = append(req.Keys, acl.Key{Name: args[i], Access: access})
+		}
+	}
+}
+
+func readKeys(first, last, step int) keySpec {
+	return keyRange(acl.ReadAccess, first, last, step)
+}
+
+// writeKeys are keys the command writes without reading them first, or
+// returning their content, which %W~ patterns allow.
+func writeKeys(first, last, step int) keySpec {
+	return keyRange(acl.WriteAccess, first, last, step)
+}
+
+func rwKeys(first, last, step int) keySpec {
+	return keyRange(acl.ReadWriteAccess, first, last, step)
+}
+
+// numKeys finds the keys following their count, given at the position.
+func numKeys(access acl.Access, pos int) keySpec {
+	return func(req *acl.Request, args []string) {
+		if pos > len(args) {
+			return
+		}
+
+		// an invalid count is an error of the command itself
+		count, err := strconv.Atoi(args[pos-1])
+		if err != nil || count < 0 || pos+count > len(args) {
+			return
+		}
+
+		for _, key := range args[pos : pos+count] {
+			req.Keys = append(req.Keys, acl.Key{Name: key, Access: access})
+		}
+	}
+}
+
+// streamKeys finds the keys of XREAD, the first half of the arguments
+// following STREAMS.
+func streamKeys(req *acl.Request, args []string) {
+	for i, arg := range args {
+		if strings.ToUpper(arg) != "STREAMS" {
+			continue
+		}
+
+		streams := args[i+1:]
+		for _, key := range streams[:len(streams)/2] {
+			req.Keys = append(req.Keys, acl.Key{Name: key, Access: acl.ReadAccess})
+		}
+
+		return
+	}
+}
+
+// channels finds the channels from the first to the last position.
+func channels(first, last int) keySpec {
+	return channelRange(first, last, false)
+}
+
+// patterns finds the patterns subscribed to from the first to the last
+// position.
+func patterns(first, last int) keySpec {
+	return channelRange(first, last, true)
+}
+
+func channelRange(first, last int, pattern bool) keySpec {
+	return func(req *acl.Request, args []string) {
+		end := last - 1
+		if last < 0 {
+			end = len(args) + last
+		}
+
+		for i := first - 1; i <= end && i < len(args); i++ {
+			req.Channels = append(req.Channels, acl.Channel{Name: args[i], Pattern: pattern})
+		}
+	}
+}
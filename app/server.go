@@ -1,33 +1,74 @@
 package main
 
 import (
-	"errors"
 	"fmt"
-	"io"
 	"log"
 	"net"
 	"os"
-	"strconv"
-	"strings"
+	"sync"
+	"time"
 
+	"github.com/codecrafters-io/redis-starter-go/internal/acl"
 	"github.com/codecrafters-io/redis-starter-go/internal/commands"
-	"github.com/codecrafters-io/redis-starter-go/internal/parser"
-	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/streamparser"
-	"github.com/codecrafters-io/redis-starter-go/internal/payload"
-	"github.com/codecrafters-io/redis-starter-go/internal/store"
+	"github.com/codecrafters-io/redis-starter-go/internal/config"
+	"github.com/codecrafters-io/redis-starter-go/internal/notify"
+	"github.com/codecrafters-io/redis-starter-go/internal/pubsub"
 )
 
+// serverVersion is the version of Redis whose behavior is implemented, as
+// HELLO replies.
+const serverVersion = "7.4.0"
+
+// activeExpireInterval is the time between two active expire cycles, 10 per
+// second as with the default hz of Redis.
+const activeExpireInterval = 100 * time.Millisecond
+
 var (
-	kvStore     = store.NewKVStore()
-	streamStore = store.NewStream()
-	typeCommand = commands.NewTypeCommand(kvStore, streamStore)
+	cfg           = config.New()
+	configCommand = commands.NewConfigCommand(cfg)
+
+	// accessControl holds the ACL users, clients running commands as one of
+	// them.
+	accessControl = acl.New(isCommand, func() int { return cfg.Int(config.ACLLogMaxLen) })
+	aclCommand    = commands.NewACLCommand(accessControl, cfg, commandsInCategory)
+	// databases are created once the configuration is known.
+	databases []*database
+
+	// pubsubHub holds the subscriptions of every client.
+	pubsubHub      = pubsub.NewHub()
+	pubSubCommands = commands.NewPubSubCommands(pubsubHub)
+	// notifier publishes the keyspace notifications to pubsubHub.
+	notifier = notify.NewNotifier(pubsubHub, keyspaceEventClasses)
+
+	// execMu serializes the execution of commands, so that every command,
+	// transaction, and serving of blocked clients is atomic.
+	execMu sync.Mutex
 )
 
 func main() {
 	// You can use print statements as follows for debugging, they'll be visible when running tests.
 	fmt.Println("Logs from your program will appear here!")
 
-	// Uncomment this block to pass the first stage
+	cfg.OnChange(config.RequirePass, setDefaultPassword)
+
+	if err := cfg.ParseArgs(os.Args[1:]); err != nil {
+		fmt.Println("Failed to parse arguments:", err.Error())
+		os.Exit(1)
+	}
+
+	databases = newDatabases(cfg.Int(config.Databases))
+	for _, db := range databases {
+		registerCommands(db)
+	}
+
+	// the users of the ACL file replace the default user set by requirepass,
+	// as the rules are checked against the registered commands
+	if path, _ := cfg.Get(config.ACLFile); path != "" {
+		if err := accessControl.LoadFile(path); err != nil {
+			fmt.Println("Failed to load the ACL file:", err.Error())
+			os.Exit(1)
+		}
+	}
 
 	l, err := net.Listen("tcp", "0.0.0.0:6379")
 	if err != nil {
@@ -35,157 +76,54 @@ func main() {
 		os.Exit(1)
 	}
 
-	connCh := make(chan net.Conn)
 	errCh := make(chan error)
-
-	// starting 10 workers...
-	startWorkers(10, connCh, errCh)
 	go logger(errCh)
+	go activeExpire()
 
-	for {
+	for id := 1; ; id++ {
 		conn, err := l.Accept()
 		if err != nil {
 			fmt.Println("Error accepting connection: ", err.Error())
 			os.Exit(1)
 		}
 
-		// send the connection to a worker
-		connCh <- conn
+		// blocked clients hold their goroutine, so every client gets its own
+		go func(c *client) {
+			if err := c.serve(); err != nil {
+				errCh <- fmt.Errorf("Failed to handle connection: %w", err)
+			}
+		}(newClient(id, conn))
 	}
 }
 
-func startWorkers(quantitiy int, connCh <-chan net.Conn, errCh chan<- error) {
-	for i := 1; i <= quantitiy; i++ {
-		go worker(i, connCh, errCh)
-	}
-}
+// activeExpire removes expired keys and hash fields in the background, so
+// that they don't use memory until they're accessed again.
+func activeExpire() {
+	ticker := time.NewTicker(activeExpireInterval)
+	defer ticker.Stop()
 
-func logger(errCh <-chan error) {
-	for err := range errCh {
-		log.Println("Error happened:", err.Error())
-	}
-}
-
-// worker will work until the connection is closed
-func worker(id int, connCh <-chan net.Conn, errCh chan<- error) {
-	for conn := range connCh {
-		err := handleConnection(id, conn)
-		if err != nil {
-			errCh <- fmt.Errorf("Failed to handle connection: %w", err)
+	for range ticker.C {
+		execMu.Lock()
+		for _, db := range databases {
+			db.kvStore.ActiveExpireCycle()
 		}
+		execMu.Unlock()
 	}
 }
 
-func handleConnection(connID int, conn net.Conn) error {
-	defer conn.Close()
-
-	for {
-		content, err := readFromConnection(conn)
-		if err != nil {
-			if errors.Is(err, io.EOF) {
-				log.Println("Breaking due to EOF..., ID:", connID)
-				break
-			}
-
-			return fmt.Errorf("Failed to read from connection %d: %w", connID, err)
-		}
-
-		parsed, err := parser.ParseRequest(content)
-		if err != nil {
-			return fmt.Errorf("Failed to parse redis request: %w", err)
-		}
-
-		var writeContent []byte
-
-		if parsed.Command == "ECHO" && len(parsed.Payload) != 0 {
-			writeContent = payload.GenerateBulkString([]byte(parsed.Payload[0]))
-		} else if parsed.Command == "SET" && len(parsed.Payload) > 1 {
-			expirationMs := 0
-			if len(parsed.Payload) > 3 {
-				if strings.EqualFold(parsed.Payload[2], "PX") {
-					expirationMs, err = strconv.Atoi(parsed.Payload[3])
-					if err != nil {
-						return fmt.Errorf("Failed to convert expiration payload to int: %w", err)
-					}
-				}
-			}
-
-			kvStore.Set(parsed.Payload[0], parsed.Payload[1], int64(expirationMs))
-
-			writeContent = payload.GenerateBasicString([]byte("OK"))
-		} else if parsed.Command == "GET" && len(parsed.Payload) != 0 {
-			val, found := kvStore.Get(parsed.Payload[0])
-
-			if !found {
-				writeContent = payload.GenerateNullString()
-			} else {
-				writeContent = payload.GenerateBulkString([]byte(val))
-			}
-		} else if parsed.Command == "XADD" && len(parsed.Payload) > 2 {
-			kvPairs := []string{}
-
-			key := parsed.Payload[0]
-			id := parsed.Payload[1]
-
-			for i := 3; i < len(parsed.Payload); i += 2 {
-				kvPairs = append(kvPairs, parsed.Payload[i-1], parsed.Payload[i])
-			}
+// keyspaceEventClasses returns the classes of keyspace notifications
+// enabled by notify-keyspace-events.
+func keyspaceEventClasses() notify.Class {
+	value, _ := cfg.Get(config.NotifyKeyspaceEvents)
 
-			res, err := streamStore.XAdd(key, id, kvPairs)
-			if err != nil {
-				writeContent = payload.GenerateSimpleErrorString([]byte(err.Error()))
-			} else {
-				writeContent = payload.GenerateBasicString([]byte(res))
-			}
-
-		} else if parsed.Command == "XRANGE" && len(parsed.Payload) > 2 {
-			key := parsed.Payload[0]
-			begin := parsed.Payload[1]
-			end := parsed.Payload[2]
-
-			res, err := streamStore.XRange(key, begin, end)
-			if err != nil {
-				return fmt.Errorf("Failed during XRange: %w", err)
-			}
-
-			writeContent = []byte(res)
-
-		} else if parsed.Command == "XREAD" && len(parsed.Payload) > 2 {
-
-			keys, ids, err := streamparser.ParseXReadCommand(parsed.Payload)
-			if err != nil {
-				return fmt.Errorf("Failed to parse: %w", err)
-			}
+	// the value has been validated when set
+	classes, _ := notify.ParseClasses(value)
 
-			res, err := streamStore.XRead(keys, ids)
-			if err != nil {
-				return fmt.Errorf("Failed during XRead: %w", err)
-			}
-
-			writeContent = []byte(res)
-
-		} else if parsed.Command == "TYPE" && len(parsed.Payload) != 0 {
-			writeContent = typeCommand.GetType(parsed.Payload[0])
-		} else {
-			writeContent = payload.GenerateBasicString([]byte("PONG"))
-		}
-
-		_, err = conn.Write(writeContent)
-		if err != nil {
-			return fmt.Errorf("Failed to write to connection %d: %w", connID, err)
-		}
-	}
-
-	return nil
+	return classes
 }
 
-func readFromConnection(conn net.Conn) ([]byte, error) {
-	buf := make([]byte, 4096)
-
-	n, err := conn.Read(buf)
-	if err != nil {
-		return nil, fmt.Errorf("Failed to read from the connection: %w", err)
+func logger(errCh <-chan error) {
+	for err := range errCh {
+		log.Println("Error happened:", err.Error())
 	}
-
-	return buf[:n], nil
 }
@@ -0,0 +1,323 @@
+package acl
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultUser is the user clients are authenticated as when they connect.
+const DefaultUser = "default"
+
+// defaultRules are the rules of the default user when it isn't configured,
+// which can run every command.
+var defaultRules = []string{"on", "nopass", "~*", "&*", "+@all"}
+
+// Reasons a command is denied, as the ACL LOG has them.
+const (
+	ReasonCommand = "command"
+	ReasonKey     = "key"
+	ReasonChannel = "channel"
+	ReasonAuth    = "auth"
+)
+
+// Key is a key a command accesses, and how.
+type Key struct {
+	Name   string
+	Access Access
+}
+
+// Channel is a channel a command publishes or subscribes to, or a pattern
+// subscribed to.
+type Channel struct {
+	Name    string
+	Pattern bool
+}
+
+// Request is what a command needs the permission of.
+type Request struct {
+	// Command is the lowercase name of the command.
+	Command string
+	// Subcommand is the lowercase first argument, for the commands having
+	// subcommands.
+	Subcommand string
+	Categories Category
+	Keys       []Key
+	Channels   []Channel
+}
+
+// DeniedError is returned when a user has no permission to run a command.
+type DeniedError struct {
+	// Reason is ReasonCommand, ReasonKey or ReasonChannel.
+	Reason string
+	// Object is the command, key or channel denied.
+	Object   string
+	Username string
+}
+
+func (e *DeniedError) Error() string {
+	switch e.Reason {
+	case ReasonKey:
+		return "NOPERM No permissions to access a key"
+	case ReasonChannel:
+		return "NOPERM No permissions to access a channel"
+	}
+
+	return fmt.Sprintf("NOPERM User %s has no permissions to run the '%s' command", e.Username, e.Object)
+}
+
+// UserInfo describes a user, as ACL GETUSER lists it.
+type UserInfo struct {
+	Flags     []string
+	Passwords []string
+	Commands  string
+	Keys      string
+	Channels  string
+}
+
+// ACL holds the users, their credentials and permissions.
+type ACL struct {
+	mu    sync.RWMutex
+	users map[string]*User
+	// isCommand tells the commands which can be allowed or denied.
+	isCommand func(name string) bool
+
+	log *Log
+}
+
+// New returns an ACL with only the default user, which can run every
+// command. logMaxLen returns the maximum number of ACL LOG entries.
+func New(isCommand func(name string) bool, logMaxLen func() int) *ACL {
+	a := &ACL{
+		users:     map[string]*User{},
+		isCommand: isCommand,
+		log:       NewLog(logMaxLen),
+	}
+
+	a.users[DefaultUser] = a.defaultUser()
+
+	return a
+}
+
+func (a *ACL) defaultUser() *User {
+	u := newUser(DefaultUser)
+	for _, rule := range defaultRules {
+		// the default rules are valid
+		_ = u.setRule(rule, a.isCommand)
+	}
+
+	return u
+}
+
+// Log returns the log of the denied commands and failed authentications.
+func (a *ACL) Log() *Log {
+	return a.log
+}
+
+// SetUser creates the user if it doesn't exist and applies the rules in
+// order. Either every rule is applied, or none if one is invalid.
+func (a *ACL) SetUser(name string, rules []string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	u, exists := a.users[name]
+	if exists {
+		u = u.clone()
+	} else {
+		u = newUser(name)
+	}
+
+	for _, rule := range rules {
+		if err := u.setRule(rule, a.isCommand); err != nil {
+			return fmt.Errorf("Error in ACL SETUSER modifier '%s': %w", rule, err)
+		}
+	}
+
+	a.users[name] = u
+
+	return nil
+}
+
+// DelUser removes the users, returning how many existed. The default user
+// can't be removed.
+func (a *ACL) DelUser(names []string) (int, error) {
+	for _, name := range names {
+		if name == DefaultUser {
+			return 0, fmt.Errorf("The '%s' user cannot be removed", DefaultUser)
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	deleted := 0
+	for _, name := range names {
+		if _, exists := a.users[name]; exists {
+			delete(a.users, name)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+// Exists returns true if the user exists.
+func (a *ACL) Exists(name string) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	_, exists := a.users[name]
+	return exists
+}
+
+// Users returns the names of the users, sorted.
+func (a *ACL) Users() []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.sortedNames()
+}
+
+func (a *ACL) sortedNames() []string {
+	names := make([]string, 0, len(a.users))
+	for name := range a.users {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// List describes every user with the rules giving its current state, sorted
+// by name.
+func (a *ACL) List() []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	list := make([]string, 0, len(a.users))
+	for _, name := range a.sortedNames() {
+		list = append(list, "user "+name+" "+a.users[name].describe())
+	}
+
+	return list
+}
+
+// GetUser describes the user, returning false if it doesn't exist.
+func (a *ACL) GetUser(name string) (*UserInfo, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	u, exists := a.users[name]
+	if !exists {
+		return nil, false
+	}
+
+	channels := u.channelRules()
+	if channels == "" {
+		channels = "resetchannels"
+	}
+
+	return &UserInfo{
+		Flags:     u.flags(),
+		Passwords: append([]string{}, u.passwords...),
+		Commands:  u.commandRules(),
+		Keys:      u.keyRules(),
+		Channels:  channels,
+	}, true
+}
+
+// Authenticate returns true if the user exists, is enabled and the password
+// is one of its passwords, or if it doesn't need any.
+func (a *ACL) Authenticate(username, password string) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	u, exists := a.users[username]
+	if !exists {
+		// a password is hashed anyway, so that the time taken doesn't tell
+		// whether the user exists
+		hashPassword(password)
+		return false
+	}
+
+	return u.authenticate(password)
+}
+
+// NoPass returns true if the user is enabled and doesn't need a password, in
+// which case clients are authenticated as the user right away.
+func (a *ACL) NoPass(username string) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	u, exists := a.users[username]
+	return exists && u.enabled && u.nopass
+}
+
+// Check returns a *DeniedError if the user can't run the command, access one
+// of its keys or one of its channels.
+func (a *ACL) Check(username string, req *Request) error {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	u, exists := a.users[username]
+	if !exists || !u.canRun(req.Command, req.Categories, req.Subcommand) {
+		object := req.Command
+		if req.Subcommand != "" {
+			object += "|" + req.Subcommand
+		}
+
+		return &DeniedError{Reason: ReasonCommand, Object: object, Username: username}
+	}
+
+	for _, key := range req.Keys {
+		if !u.canAccessKey(key.Name, key.Access) {
+			return &DeniedError{Reason: ReasonKey, Object: key.Name, Username: username}
+		}
+	}
+
+	for _, channel := range req.Channels {
+		if !u.canAccessChannel(channel.Name, channel.Pattern) {
+			return &DeniedError{Reason: ReasonChannel, Object: channel.Name, Username: username}
+		}
+	}
+
+	return nil
+}
+
+// parseUsers parses `user name rule...` lines, numbered from 1 in errors.
+func (a *ACL) parseUsers(lines []string) (map[string]*User, error) {
+	users := map[string]*User{}
+
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		if fields[0] != "user" || len(fields) < 2 {
+			return nil, fmt.Errorf("%d: line should start with user keyword", i+1)
+		}
+
+		name := fields[1]
+		if _, exists := users[name]; exists {
+			return nil, fmt.Errorf("%d: Duplicate user '%s' found", i+1, name)
+		}
+
+		u := newUser(name)
+		for _, rule := range fields[2:] {
+			if err := u.setRule(rule, a.isCommand); err != nil {
+				return nil, fmt.Errorf("%d: Error in applying operation '%s': %w", i+1, rule, err)
+			}
+		}
+
+		users[name] = u
+	}
+
+	if _, exists := users[DefaultUser]; !exists {
+		users[DefaultUser] = a.defaultUser()
+	}
+
+	return users, nil
+}
@@ -0,0 +1,349 @@
+package acl_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/acl"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func isCommand(name string) bool {
+	return name == "get" || name == "set" || name == "config" || name == "flushall" || name == "subscribe"
+}
+
+func newACL() *acl.ACL {
+	return acl.New(isCommand, func() int { return 128 })
+}
+
+func TestParseCategories(t *testing.T) {
+	categories, err := acl.ParseCategories("read string fast")
+	require.NoError(t, err)
+	assert.Equal(t, acl.Read|acl.String|acl.Fast, categories)
+
+	categories, err = acl.ParseCategories("write list")
+	require.NoError(t, err)
+	assert.Equal(t, acl.Write|acl.List|acl.Slow, categories)
+
+	_, err = acl.ParseCategories("read unknown")
+	assert.Error(t, err)
+}
+
+func TestACL_Check(t *testing.T) {
+	get := func(key string) *acl.Request {
+		return &acl.Request{Command: "get", Categories: acl.Read | acl.String | acl.Fast, Keys: []acl.Key{{Name: key, Access: acl.ReadAccess}}}
+	}
+	set := func(key string) *acl.Request {
+		return &acl.Request{Command: "set", Categories: acl.Write | acl.String | acl.Slow, Keys: []acl.Key{{Name: key, Access: acl.WriteAccess}}}
+	}
+	flushAll := &acl.Request{Command: "flushall", Categories: acl.Keyspace | acl.Write | acl.Slow | acl.Dangerous}
+	configGet := &acl.Request{Command: "config", Subcommand: "get", Categories: acl.Admin | acl.Slow | acl.Dangerous}
+	subscribe := func(channel string, pattern bool) *acl.Request {
+		return &acl.Request{Command: "subscribe", Categories: acl.PubSub | acl.Slow, Channels: []acl.Channel{{Name: channel, Pattern: pattern}}}
+	}
+
+	testCases := map[string]struct {
+		rules   []string
+		request *acl.Request
+		reason  string
+	}{
+		"when the user has every permission": {
+			rules:   []string{"on", "~*", "&*", "+@all"},
+			request: flushAll,
+		},
+		"when the user has no permission": {
+			rules:   []string{"on", "~*"},
+			request: get("a"),
+			reason:  acl.ReasonCommand,
+		},
+		"when a category is allowed": {
+			rules:   []string{"on", "~*", "+@read"},
+			request: get("a"),
+		},
+		"when a category is denied after being allowed": {
+			rules:   []string{"on", "~*", "+@all", "-@dangerous"},
+			request: flushAll,
+			reason:  acl.ReasonCommand,
+		},
+		"when a command is allowed after its category is denied": {
+			rules:   []string{"on", "~*", "-@dangerous", "+flushall"},
+			request: flushAll,
+		},
+		"when a subcommand is allowed": {
+			rules:   []string{"on", "+config|get"},
+			request: configGet,
+		},
+		"when the command of an allowed subcommand is denied": {
+			rules:   []string{"on", "+config|get", "-config"},
+			request: configGet,
+			reason:  acl.ReasonCommand,
+		},
+		"when +@all drops the rules before": {
+			rules:   []string{"on", "~*", "-get", "+@all"},
+			request: get("a"),
+		},
+		"when the key matches a pattern": {
+			rules:   []string{"on", "~cache:*", "+@all"},
+			request: get("cache:1"),
+		},
+		"when the key doesn't match any pattern": {
+			rules:   []string{"on", "~cache:*", "+@all"},
+			request: get("session:1"),
+			reason:  acl.ReasonKey,
+		},
+		"when the key can only be read": {
+			rules:   []string{"on", "%R~*", "+@all"},
+			request: set("a"),
+			reason:  acl.ReasonKey,
+		},
+		"when the key can be written": {
+			rules:   []string{"on", "%R~*", "%W~a*", "+@all"},
+			request: set("a"),
+		},
+		"when the keys are reset": {
+			rules:   []string{"on", "allkeys", "resetkeys", "+@all"},
+			request: get("a"),
+			reason:  acl.ReasonKey,
+		},
+		"when the channel matches a pattern": {
+			rules:   []string{"on", "&news.*", "+@all"},
+			request: subscribe("news.sport", false),
+		},
+		"when the channel doesn't match any pattern": {
+			rules:   []string{"on", "&news.*", "+@all"},
+			request: subscribe("chat", false),
+			reason:  acl.ReasonChannel,
+		},
+		"when a pattern subscribed to is an allowed pattern": {
+			rules:   []string{"on", "&news.*", "+@all"},
+			request: subscribe("news.*", true),
+		},
+		"when a pattern subscribed to matches an allowed pattern": {
+			rules:   []string{"on", "&news.*", "+@all"},
+			request: subscribe("news.s*", true),
+			reason:  acl.ReasonChannel,
+		},
+		"when every channel is allowed": {
+			rules:   []string{"on", "allchannels", "+@all"},
+			request: subscribe("*", true),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			a := newACL()
+			require.NoError(t, a.SetUser("alice", tc.rules))
+
+			err := a.Check("alice", tc.request)
+			if tc.reason == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			var denied *acl.DeniedError
+			require.ErrorAs(t, err, &denied)
+			assert.Equal(t, tc.reason, denied.Reason)
+		})
+	}
+}
+
+func TestACL_SetUser(t *testing.T) {
+	testCases := map[string]struct {
+		rules    []string
+		expected string
+		error    string
+	}{
+		"when the user is new": {
+			rules:    []string{},
+			expected: "user alice off resetchannels -@all",
+		},
+		"when rules are given": {
+			rules:    []string{"on", "nopass", "~cache:*", "%R~app:*", "&news", "+@read", "-@dangerous", "+config|get"},
+			expected: "user alice on nopass ~cache:* %R~app:* resetchannels &news -@all +@read -@dangerous +config|get",
+		},
+		"when a password is added": {
+			rules:    []string{"on", ">secret", "allcommands"},
+			expected: "user alice on #2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b resetchannels +@all",
+		},
+		"when the user is reset": {
+			rules:    []string{"on", ">secret", "allkeys", "allchannels", "+@all", "reset"},
+			expected: "user alice off resetchannels -@all",
+		},
+		"when a rule is invalid": {
+			rules: []string{"on", "bogus"},
+			error: "Error in ACL SETUSER modifier 'bogus': Syntax error",
+		},
+		"when a category is unknown": {
+			rules: []string{"+@bogus"},
+			error: "Error in ACL SETUSER modifier '+@bogus': Unknown command or category name in ACL",
+		},
+		"when a command is unknown": {
+			rules: []string{"-bogus"},
+			error: "Error in ACL SETUSER modifier '-bogus': Unknown command or category name in ACL",
+		},
+		"when a key permission is invalid": {
+			rules: []string{"%X~*"},
+			error: "Error in ACL SETUSER modifier '%X~*': Syntax error",
+		},
+		"when a password hash is invalid": {
+			rules: []string{"#abc"},
+			error: "Error in ACL SETUSER modifier '#abc': The password hash must be exactly 64 characters and contain only lowercase hexadecimal characters",
+		},
+		"when a removed password doesn't exist": {
+			rules: []string{"<secret"},
+			error: "Error in ACL SETUSER modifier '<secret': The password you are trying to remove from the user does not exist",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			a := newACL()
+
+			err := a.SetUser("alice", tc.rules)
+			if tc.error != "" {
+				require.EqualError(t, err, tc.error)
+				assert.Equal(t, []string{"default"}, a.Users())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, []string{
+				tc.expected,
+				"user default on nopass ~* resetchannels &* +@all",
+			}, a.List())
+		})
+	}
+}
+
+func TestACL_Authenticate(t *testing.T) {
+	a := newACL()
+	assert.True(t, a.Authenticate("default", "anything"))
+	assert.True(t, a.NoPass("default"))
+
+	require.NoError(t, a.SetUser("alice", []string{"on", ">secret", ">other"}))
+	assert.True(t, a.Authenticate("alice", "secret"))
+	assert.True(t, a.Authenticate("alice", "other"))
+	assert.False(t, a.Authenticate("alice", "wrong"))
+	assert.False(t, a.NoPass("alice"))
+	assert.False(t, a.Authenticate("bob", "secret"))
+
+	require.NoError(t, a.SetUser("alice", []string{"<other"}))
+	assert.False(t, a.Authenticate("alice", "other"))
+
+	require.NoError(t, a.SetUser("alice", []string{"off"}))
+	assert.False(t, a.Authenticate("alice", "secret"))
+}
+
+func TestACL_DelUser(t *testing.T) {
+	a := newACL()
+	require.NoError(t, a.SetUser("alice", nil))
+
+	_, err := a.DelUser([]string{"alice", "default"})
+	require.Error(t, err)
+
+	deleted, err := a.DelUser([]string{"alice", "bob"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	assert.Equal(t, []string{"default"}, a.Users())
+}
+
+func TestACL_LoadFile(t *testing.T) {
+	testCases := map[string]struct {
+		content  string
+		expected []string
+		error    string
+	}{
+		"when the file defines users": {
+			content: "# services\nuser cache on >secret ~cache:* +@read\n\nuser default off\n",
+			expected: []string{
+				"user cache on #2bb80d537b1da3e38bd30361aa855686bde0eacd7162fef6a25fe97bf527a25b ~cache:* resetchannels -@all +@read",
+				"user default off resetchannels -@all",
+			},
+		},
+		"when the file doesn't define the default user": {
+			content: "user cache on nopass\n",
+			expected: []string{
+				"user cache on nopass resetchannels -@all",
+				"user default on nopass ~* resetchannels &* +@all",
+			},
+		},
+		"when a line doesn't define a user": {
+			content: "user cache on\nrequirepass secret\n",
+			error:   ":2: line should start with user keyword",
+		},
+		"when a rule is invalid": {
+			content: "user cache on +@bogus\n",
+			error:   ":1: Error in applying operation '+@bogus': Unknown command or category name in ACL",
+		},
+		"when a user is duplicated": {
+			content: "user cache on\nuser cache off\n",
+			error:   ":2: Duplicate user 'cache' found",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "users.acl")
+			require.NoError(t, os.WriteFile(path, []byte(tc.content), 0o644))
+
+			a := newACL()
+			require.NoError(t, a.SetUser("alice", nil))
+
+			err := a.LoadFile(path)
+			if tc.error != "" {
+				require.EqualError(t, err, path+tc.error)
+				assert.Equal(t, []string{"alice", "default"}, a.Users())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, a.List())
+		})
+	}
+}
+
+func TestACL_SaveFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "users.acl")
+
+	a := newACL()
+	require.NoError(t, a.SetUser("cache", []string{"on", ">secret", "%R~cache:*", "&news", "+@read", "-get"}))
+	require.NoError(t, a.SaveFile(path))
+
+	loaded := newACL()
+	require.NoError(t, loaded.LoadFile(path))
+	assert.Equal(t, a.List(), loaded.List())
+}
+
+func TestLog(t *testing.T) {
+	maxLen := 2
+	log := acl.NewLog(func() int { return maxLen })
+
+	log.Add(acl.ReasonCommand, "toplevel", "flushall", "alice", "id=1")
+	log.Add(acl.ReasonKey, "toplevel", "secret", "alice", "id=1")
+	log.Add(acl.ReasonCommand, "toplevel", "flushall", "alice", "id=2")
+
+	entries := log.Entries(-1)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "flushall", entries[0].Object)
+	assert.Equal(t, 2, entries[0].Count)
+	assert.Equal(t, "id=2", entries[0].ClientInfo)
+	assert.Equal(t, "secret", entries[1].Object)
+	assert.Equal(t, 1, entries[1].Count)
+
+	log.Add(acl.ReasonAuth, "toplevel", "AUTH", "bob", "id=3")
+	entries = log.Entries(-1)
+	require.Len(t, entries, 2)
+	assert.Equal(t, []string{"AUTH", "flushall"}, []string{entries[0].Object, entries[1].Object})
+	assert.Equal(t, int64(2), entries[0].ID)
+
+	assert.Len(t, log.Entries(1), 1)
+
+	maxLen = 1
+	assert.Len(t, log.Entries(-1), 1)
+
+	log.Reset()
+	assert.Empty(t, log.Entries(-1))
+}
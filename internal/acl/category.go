@@ -0,0 +1,114 @@
+package acl
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Category is a set of ACL command categories, as bit flags. A command
+// belongs to several categories, e.g. GET is @read, @string and @fast.
+type Category uint32
+
+const (
+	Keyspace Category = 1 << iota
+	Read
+	Write
+	Set
+	SortedSet
+	List
+	Hash
+	String
+	Bitmap
+	HyperLogLog
+	Geo
+	Stream
+	PubSub
+	Admin
+	Fast
+	Slow
+	Blocking
+	Dangerous
+	Connection
+	Transaction
+	Scripting
+
+	// All is every category, as +@all allows every command.
+	All Category = 1<<iota - 1
+)
+
+// categoryNames are the names of the categories, in the order ACL CAT lists
+// them.
+var categoryNames = []struct {
+	name     string
+	category Category
+}{
+	{"keyspace", Keyspace},
+	{"read", Read},
+	{"write", Write},
+	{"set", Set},
+	{"sortedset", SortedSet},
+	{"list", List},
+	{"hash", Hash},
+	{"string", String},
+	{"bitmap", Bitmap},
+	{"hyperloglog", HyperLogLog},
+	{"geo", Geo},
+	{"stream", Stream},
+	{"pubsub", PubSub},
+	{"admin", Admin},
+	{"fast", Fast},
+	{"slow", Slow},
+	{"blocking", Blocking},
+	{"dangerous", Dangerous},
+	{"connection", Connection},
+	{"transaction", Transaction},
+	{"scripting", Scripting},
+}
+
+// CategoryNames returns the names of every category.
+func CategoryNames() []string {
+	names := make([]string, len(categoryNames))
+	for i, c := range categoryNames {
+		names[i] = c.name
+	}
+
+	return names
+}
+
+// LookupCategory returns the category of the given name, "all" standing for
+// every category.
+func LookupCategory(name string) (Category, bool) {
+	name = strings.ToLower(name)
+	if name == "all" {
+		return All, true
+	}
+
+	for _, c := range categoryNames {
+		if c.name == name {
+			return c.category, true
+		}
+	}
+
+	return 0, false
+}
+
+// ParseCategories parses space separated category names. Commands which
+// aren't @fast are @slow.
+func ParseCategories(names string) (Category, error) {
+	categories := Category(0)
+
+	for _, name := range strings.Fields(names) {
+		category, found := LookupCategory(name)
+		if !found || category == All {
+			return 0, fmt.Errorf("unknown ACL category '%s'", name)
+		}
+
+		categories |= category
+	}
+
+	if categories&Fast == 0 {
+		categories |= Slow
+	}
+
+	return categories, nil
+}
@@ -0,0 +1,57 @@
+package acl
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LoadFile replaces the users with the ones of the ACL file, one
+// `user name rule...` line per user. The users are left as they were if the
+// file is invalid. The default user is created with every permission if the
+// file doesn't define it.
+func (a *ACL) LoadFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("Error loading ACLs, opening file '%s': %w", path, err)
+	}
+
+	users, err := a.parseUsers(strings.Split(string(content), "\n"))
+	if err != nil {
+		return fmt.Errorf("%s:%w", path, err)
+	}
+
+	a.mu.Lock()
+	a.users = users
+	a.mu.Unlock()
+
+	return nil
+}
+
+// SaveFile writes the users to the ACL file. The file is replaced at once,
+// so that it's never left half written.
+func (a *ACL) SaveFile(path string) error {
+	content := strings.Join(a.List(), "\n") + "\n"
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("Opening temp ACL file for ACL SAVE: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(content); err != nil {
+		tmp.Close()
+		return fmt.Errorf("Writing ACL file for ACL SAVE: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("Writing ACL file for ACL SAVE: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("Renaming ACL file for ACL SAVE: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package acl
+
+import (
+	"sync"
+	"time"
+)
+
+// groupingMaxDelta is how long after its last update an entry is counted
+// again instead of a new entry being added for the same denial.
+const groupingMaxDelta = 60 * time.Second
+
+// LogEntry is a denied command or failed authentication.
+type LogEntry struct {
+	// Count is the number of times it happened.
+	Count int
+	// Reason is ReasonCommand, ReasonKey, ReasonChannel or ReasonAuth.
+	Reason string
+	// Context is toplevel, or multi for a command of a transaction.
+	Context    string
+	Object     string
+	Username   string
+	ClientInfo string
+	ID         int64
+	Created    time.Time
+	Updated    time.Time
+}
+
+// Log holds the latest entries of ACL LOG, the oldest ones being dropped
+// beyond the maximum length.
+type Log struct {
+	mu sync.Mutex
+	// entries are sorted from the newest to the oldest.
+	entries []*LogEntry
+	nextID  int64
+	maxLen  func() int
+	now     func() time.Time
+}
+
+func NewLog(maxLen func() int) *Log {
+	return &Log{maxLen: maxLen, now: time.Now}
+}
+
+// Add logs an entry, or counts it again if the same user was denied the same
+// object in the same context lately.
+func (l *Log) Add(reason, context, object, username, clientInfo string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+
+	for i, e := range l.entries {
+		if e.Reason == reason && e.Context == context && e.Object == object && e.Username == username && now.Sub(e.Updated) < groupingMaxDelta {
+			e.Count++
+			e.Updated = now
+			e.ClientInfo = clientInfo
+
+			// the entry becomes the newest
+			copy(l.entries[1:i+1], l.entries[:i])
+			l.entries[0] = e
+
+			return
+		}
+	}
+
+	e := &LogEntry{
+		Count:      1,
+		Reason:     reason,
+		Context:    context,
+		Object:     object,
+		Username:   username,
+		ClientInfo: clientInfo,
+		ID:         l.nextID,
+		Created:    now,
+		Updated:    now,
+	}
+	l.nextID++
+
+	l.entries = append([]*LogEntry{e}, l.entries...)
+	l.trim()
+}
+
+func (l *Log) trim() {
+	if maxLen := l.maxLen(); len(l.entries) > maxLen {
+		l.entries = l.entries[:maxLen]
+	}
+}
+
+// Entries returns up to count entries, newest first. A negative count
+// returns every entry.
+func (l *Log) Entries(count int) []LogEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// the maximum length may have been lowered since the last entry
+	l.trim()
+
+	if count < 0 || count > len(l.entries) {
+		count = len(l.entries)
+	}
+
+	entries := make([]LogEntry, count)
+	for i := range entries {
+		entries[i] = *l.entries[i]
+	}
+
+	return entries
+}
+
+// Reset removes every entry.
+func (l *Log) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.entries = nil
+}
@@ -0,0 +1,397 @@
+package acl
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/glob"
+)
+
+var (
+	errSyntax          = errors.New("Syntax error")
+	errUnknownCommand  = errors.New("Unknown command or category name in ACL")
+	errNoSuchPassword  = errors.New("The password you are trying to remove from the user does not exist")
+	errInvalidPassHash = errors.New("The password hash must be exactly 64 characters and contain only lowercase hexadecimal characters")
+)
+
+// Access is the access a command needs to a key, or a key pattern grants.
+type Access int
+
+const (
+	ReadAccess Access = 1 << iota
+	WriteAccess
+
+	ReadWriteAccess = ReadAccess | WriteAccess
+)
+
+type keyPattern struct {
+	pattern string
+	access  Access
+}
+
+// commandRule allows or denies either the commands of some categories, or a
+// command, optionally only with a given subcommand.
+type commandRule struct {
+	allow      bool
+	categories Category
+	command    string
+	subcommand string
+}
+
+func (r commandRule) matches(command string, categories Category, subcommand string) bool {
+	if r.categories != 0 {
+		return categories&r.categories != 0
+	}
+
+	return r.command == command && (r.subcommand == "" || r.subcommand == subcommand)
+}
+
+func (r commandRule) String() string {
+	sign := "-"
+	if r.allow {
+		sign = "+"
+	}
+
+	switch {
+	case r.categories == All:
+		return sign + "@all"
+	case r.categories != 0:
+		for _, c := range categoryNames {
+			if c.category == r.categories {
+				return sign + "@" + c.name
+			}
+		}
+	case r.subcommand != "":
+		return sign + r.command + "|" + r.subcommand
+	}
+
+	return sign + r.command
+}
+
+// User is a set of credentials and of permissions: the commands the user can
+// run, and the keys and channels it can access. A new user is disabled and
+// has no permissions.
+type User struct {
+	name    string
+	enabled bool
+	// nopass users authenticate with any password
+	nopass bool
+	// passwords are SHA-256 hashes, in hexadecimal
+	passwords []string
+	// commands are applied in order, the last rule matching a command
+	// deciding whether it can run
+	commands []commandRule
+	keys     []keyPattern
+	channels []string
+}
+
+func newUser(name string) *User {
+	return &User{name: name}
+}
+
+func (u *User) clone() *User {
+	clone := *u
+	clone.passwords = append([]string{}, u.passwords...)
+	clone.commands = append([]commandRule{}, u.commands...)
+	clone.keys = append([]keyPattern{}, u.keys...)
+	clone.channels = append([]string{}, u.channels...)
+
+	return &clone
+}
+
+// setRule applies a rule of ACL SETUSER. isCommand tells the commands which
+// can be allowed or denied.
+func (u *User) setRule(rule string, isCommand func(name string) bool) error {
+	if rule == "" {
+		return errSyntax
+	}
+
+	switch rule[0] {
+	case '>':
+		u.addPassword(hashPassword(rule[1:]))
+		return nil
+	case '<':
+		return u.removePassword(hashPassword(rule[1:]))
+	case '#':
+		if !validHash(rule[1:]) {
+			return errInvalidPassHash
+		}
+
+		u.addPassword(rule[1:])
+		return nil
+	case '!':
+		if !validHash(rule[1:]) {
+			return errInvalidPassHash
+		}
+
+		return u.removePassword(rule[1:])
+	case '~':
+		u.keys = append(u.keys, keyPattern{pattern: rule[1:], access: ReadWriteAccess})
+		return nil
+	case '%':
+		return u.addKeyPattern(rule[1:])
+	case '&':
+		u.channels = append(u.channels, rule[1:])
+		return nil
+	case '+', '-':
+		return u.addCommandRule(rule, isCommand)
+	}
+
+	switch strings.ToLower(rule) {
+	case "on":
+		u.enabled = true
+	case "off":
+		u.enabled = false
+	case "nopass":
+		u.passwords = nil
+		u.nopass = true
+	case "resetpass":
+		u.passwords = nil
+		u.nopass = false
+	case "allkeys":
+		u.keys = []keyPattern{{pattern: "*", access: ReadWriteAccess}}
+	case "resetkeys":
+		u.keys = nil
+	case "allchannels":
+		u.channels = []string{"*"}
+	case "resetchannels":
+		u.channels = nil
+	case "allcommands":
+		return u.addCommandRule("+@all", isCommand)
+	case "nocommands":
+		return u.addCommandRule("-@all", isCommand)
+	case "reset":
+		*u = User{name: u.name}
+	default:
+		return errSyntax
+	}
+
+	return nil
+}
+
+func (u *User) addPassword(hash string) {
+	for _, password := range u.passwords {
+		if password == hash {
+			return
+		}
+	}
+
+	u.passwords = append(u.passwords, hash)
+	u.nopass = false
+}
+
+func (u *User) removePassword(hash string) error {
+	for i, password := range u.passwords {
+		if password == hash {
+			u.passwords = append(u.passwords[:i], u.passwords[i+1:]...)
+			return nil
+		}
+	}
+
+	return errNoSuchPassword
+}
+
+// addKeyPattern adds a pattern given as R~pattern, W~pattern or RW~pattern.
+func (u *User) addKeyPattern(rule string) error {
+	end := strings.IndexByte(rule, '~')
+	if end <= 0 {
+		return errSyntax
+	}
+
+	access := Access(0)
+	for _, c := range strings.ToUpper(rule[:end]) {
+		switch c {
+		case 'R':
+			access |= ReadAccess
+		case 'W':
+			access |= WriteAccess
+		default:
+			return errSyntax
+		}
+	}
+
+	u.keys = append(u.keys, keyPattern{pattern: rule[end+1:], access: access})
+
+	return nil
+}
+
+func (u *User) addCommandRule(rule string, isCommand func(name string) bool) error {
+	r := commandRule{allow: rule[0] == '+'}
+	name := strings.ToLower(rule[1:])
+
+	if strings.HasPrefix(name, "@") {
+		categories, found := LookupCategory(name[1:])
+		if !found {
+			return errUnknownCommand
+		}
+
+		r.categories = categories
+
+		// the rules before are overridden, so they're dropped
+		if categories == All {
+			u.commands = nil
+		}
+	} else {
+		r.command, r.subcommand, _ = strings.Cut(name, "|")
+		if !isCommand(r.command) {
+			return errUnknownCommand
+		}
+	}
+
+	u.commands = append(u.commands, r)
+
+	return nil
+}
+
+// authenticate checks the password in constant time, the same for every
+// stored hash.
+func (u *User) authenticate(password string) bool {
+	if !u.enabled {
+		return false
+	}
+
+	if u.nopass {
+		return true
+	}
+
+	hash := []byte(hashPassword(password))
+
+	matched := false
+	for _, stored := range u.passwords {
+		if subtle.ConstantTimeCompare(hash, []byte(stored)) == 1 {
+			matched = true
+		}
+	}
+
+	return matched
+}
+
+func (u *User) canRun(command string, categories Category, subcommand string) bool {
+	allowed := false
+	for _, r := range u.commands {
+		if r.matches(command, categories, subcommand) {
+			allowed = r.allow
+		}
+	}
+
+	return allowed
+}
+
+// canAccessKey returns true if a single pattern both matches the key and
+// grants the access.
+func (u *User) canAccessKey(key string, access Access) bool {
+	for _, p := range u.keys {
+		if p.access&access == access && glob.Match(p.pattern, key, false) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// canAccessChannel returns true if the channel matches one of the patterns.
+// A pattern subscribed to must be one of the patterns itself, since it could
+// match any channel otherwise.
+func (u *User) canAccessChannel(channel string, isPattern bool) bool {
+	for _, p := range u.channels {
+		if p == "*" || p == channel || (!isPattern && glob.Match(p, channel, false)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (u *User) flags() []string {
+	flags := []string{"off"}
+	if u.enabled {
+		flags[0] = "on"
+	}
+
+	if u.nopass {
+		flags = append(flags, "nopass")
+	}
+
+	return flags
+}
+
+func (u *User) commandRules() string {
+	rules := make([]string, 0, len(u.commands)+1)
+	if len(u.commands) == 0 || u.commands[0].categories != All {
+		rules = append(rules, "-@all")
+	}
+
+	for _, r := range u.commands {
+		rules = append(rules, r.String())
+	}
+
+	return strings.Join(rules, " ")
+}
+
+func (u *User) keyRules() string {
+	rules := make([]string, len(u.keys))
+	for i, p := range u.keys {
+		switch p.access {
+		case ReadAccess:
+			rules[i] = "%R~" + p.pattern
+		case WriteAccess:
+			rules[i] = "%W~" + p.pattern
+		default:
+			rules[i] = "~" + p.pattern
+		}
+	}
+
+	return strings.Join(rules, " ")
+}
+
+func (u *User) channelRules() string {
+	rules := make([]string, len(u.channels))
+	for i, channel := range u.channels {
+		rules[i] = "&" + channel
+	}
+
+	return strings.Join(rules, " ")
+}
+
+// describe returns the rules giving the user its current state, as ACL LIST
+// and the ACL file have them.
+func (u *User) describe() string {
+	rules := u.flags()
+
+	for _, password := range u.passwords {
+		rules = append(rules, "#"+password)
+	}
+
+	if keys := u.keyRules(); keys != "" {
+		rules = append(rules, keys)
+	}
+
+	rules = append(rules, "resetchannels")
+	if channels := u.channelRules(); channels != "" {
+		rules = append(rules, channels)
+	}
+
+	return strings.Join(append(rules, u.commandRules()), " ")
+}
+
+func hashPassword(password string) string {
+	hash := sha256.Sum256([]byte(password))
+	return hex.EncodeToString(hash[:])
+}
+
+func validHash(hash string) bool {
+	if len(hash) != 2*sha256.Size {
+		return false
+	}
+
+	for i := 0; i < len(hash); i++ {
+		if !(hash[i] >= '0' && hash[i] <= '9') && !(hash[i] >= 'a' && hash[i] <= 'f') {
+			return false
+		}
+	}
+
+	return true
+}
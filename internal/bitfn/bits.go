@@ -0,0 +1,72 @@
+package bitfn
+
+import "math/bits"
+
+// Bits are numbered from the most significant bit of the first byte, as
+// SETBIT and friends expect.
+
+// GetBit returns the bit at offset, 0 if it's past the end of b.
+func GetBit(b []byte, offset uint64) int {
+	if offset>>3 >= uint64(len(b)) {
+		return 0
+	}
+
+	return int(b[offset>>3]>>(7-offset&7)) & 1
+}
+
+// SetBit sets the bit at offset, which must be within b, and returns its
+// previous value.
+func SetBit(b []byte, offset uint64, bit int) int {
+	previous := GetBit(b, offset)
+
+	mask := byte(1) << (7 - offset&7)
+	if bit == 1 {
+		b[offset>>3] |= mask
+	} else {
+		b[offset>>3] &^= mask
+	}
+
+	return previous
+}
+
+// Count returns the number of bits set between the bits start and end, both
+// inclusive and within b.
+func Count(b []byte, start, end int) int {
+	first, last := start>>3, end>>3
+
+	count := 0
+	for _, c := range b[first : last+1] {
+		count += bits.OnesCount8(c)
+	}
+
+	// the bits of the first and last bytes which are out of the range
+	count -= bits.OnesCount8(b[first] >> (8 - start&7))
+	count -= bits.OnesCount8(b[last] << (1 + end&7))
+
+	return count
+}
+
+// Pos returns the position of the first bit set to bit between the bits
+// start and end, both inclusive and within b, or -1 if there is none.
+func Pos(b []byte, bit int, start, end int) int {
+	// bytes without the bit looked for are skipped at once
+	skip := byte(0)
+	if bit == 0 {
+		skip = 0xff
+	}
+
+	for pos := start; pos <= end; {
+		if pos&7 == 0 && pos+7 <= end && b[pos>>3] == skip {
+			pos += 8
+			continue
+		}
+
+		if GetBit(b, uint64(pos)) == bit {
+			return pos
+		}
+
+		pos++
+	}
+
+	return -1
+}
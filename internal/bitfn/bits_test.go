@@ -0,0 +1,86 @@
+package bitfn_test
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/bitfn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetBit(t *testing.T) {
+	b := make([]byte, 2)
+
+	assert.Equal(t, 0, bitfn.SetBit(b, 1, 1))
+	assert.Equal(t, 0, bitfn.SetBit(b, 15, 1))
+	assert.Equal(t, []byte{0x40, 0x01}, b)
+
+	assert.Equal(t, 1, bitfn.SetBit(b, 1, 0))
+	assert.Equal(t, []byte{0x00, 0x01}, b)
+
+	assert.Equal(t, 1, bitfn.GetBit(b, 15))
+	assert.Equal(t, 0, bitfn.GetBit(b, 100))
+}
+
+func TestCount(t *testing.T) {
+	b := []byte("foobar")
+
+	testCases := map[string]struct {
+		start, end int
+		expected   int
+	}{
+		"when whole string counted":      {start: 0, end: 47, expected: 26},
+		"when a single byte counted":     {start: 8, end: 15, expected: 6},
+		"when range is inside a byte":    {start: 5, end: 6, expected: 2},
+		"when range spans partial bytes": {start: 5, end: 30, expected: 17},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, bitfn.Count(b, tc.start, tc.end))
+		})
+	}
+}
+
+func TestPos(t *testing.T) {
+	testCases := map[string]struct {
+		b          []byte
+		bit        int
+		start, end int
+		expected   int
+	}{
+		"when first set bit looked for":   {b: []byte{0x00, 0x0f}, bit: 1, start: 0, end: 15, expected: 12},
+		"when first clear bit looked for": {b: []byte{0xff, 0xf0}, bit: 0, start: 0, end: 15, expected: 12},
+		"when bit is out of the range":    {b: []byte{0x00, 0x0f}, bit: 1, start: 0, end: 11, expected: -1},
+		"when range starts inside a byte": {b: []byte{0x81}, bit: 1, start: 1, end: 7, expected: 7},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, bitfn.Pos(tc.b, tc.bit, tc.start, tc.end))
+		})
+	}
+}
+
+func TestOp(t *testing.T) {
+	a, b, c := []byte{0xf0, 0xff}, []byte{0x3c}, []byte{0x0f}
+
+	testCases := map[string]struct {
+		op       bitfn.Operation
+		sources  [][]byte
+		expected []byte
+	}{
+		"when AND":   {op: bitfn.OpAnd, sources: [][]byte{a, b}, expected: []byte{0x30, 0x00}},
+		"when OR":    {op: bitfn.OpOr, sources: [][]byte{a, b, c}, expected: []byte{0xff, 0xff}},
+		"when XOR":   {op: bitfn.OpXor, sources: [][]byte{a, b}, expected: []byte{0xcc, 0xff}},
+		"when NOT":   {op: bitfn.OpNot, sources: [][]byte{b}, expected: []byte{0xc3}},
+		"when DIFF":  {op: bitfn.OpDiff, sources: [][]byte{a, b, c}, expected: []byte{0xc0, 0xff}},
+		"when ANDOR": {op: bitfn.OpAndOr, sources: [][]byte{a, b, c}, expected: []byte{0x30, 0x00}},
+		"when ONE":   {op: bitfn.OpOne, sources: [][]byte{a, b, c}, expected: []byte{0xc3, 0xff}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, bitfn.Op(tc.op, tc.sources))
+		})
+	}
+}
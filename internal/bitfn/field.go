@@ -0,0 +1,136 @@
+package bitfn
+
+import "math"
+
+// Overflow is the way BITFIELD handles the overflows of SET and INCRBY.
+type Overflow int
+
+const (
+	OverflowWrap Overflow = iota
+	OverflowSat
+	OverflowFail
+)
+
+type FieldOpKind int
+
+const (
+	FieldGet FieldOpKind = iota
+	FieldSet
+	FieldIncrBy
+)
+
+// FieldOp is a GET, SET or INCRBY subcommand of BITFIELD, with the overflow
+// handling in effect when it runs.
+type FieldOp struct {
+	Kind     FieldOpKind
+	Encoding Encoding
+	Offset   uint64
+	Value    int64
+	Overflow Overflow
+}
+
+// Encoding is the type of a BITFIELD integer, from i1 to i64 and from u1 to
+// u63.
+type Encoding struct {
+	Signed bool
+	Bits   uint
+}
+
+// Get reads the integer at the bit offset, the bits past the end of b being
+// zeros.
+func (e Encoding) Get(b []byte, offset uint64) int64 {
+	var value uint64
+	for i := uint64(0); i < uint64(e.Bits); i++ {
+		value = value<<1 | uint64(GetBit(b, offset+i))
+	}
+
+	// sign extension
+	if e.Signed && e.Bits < 64 && value&(1<<(e.Bits-1)) != 0 {
+		value |= math.MaxUint64 << e.Bits
+	}
+
+	return int64(value)
+}
+
+// Set writes the integer at the bit offset, which must be within b.
+func (e Encoding) Set(b []byte, offset uint64, value int64) {
+	for i := uint64(0); i < uint64(e.Bits); i++ {
+		SetBit(b, offset+i, int(uint64(value)>>(uint64(e.Bits)-1-i))&1)
+	}
+}
+
+// Add returns value+incr as an integer of the encoding, handling an overflow
+// as requested. It returns false if the operation must fail. SET checks the
+// range of its value with an increment of 0.
+func (e Encoding) Add(value, incr int64, overflow Overflow) (int64, bool) {
+	var res int64
+	var overflowed bool
+
+	if e.Signed {
+		res, overflowed = e.addSigned(value, incr, overflow)
+	} else {
+		res, overflowed = e.addUnsigned(uint64(value), incr, overflow)
+	}
+
+	if overflowed && overflow == OverflowFail {
+		return 0, false
+	}
+
+	return res, true
+}
+
+func (e Encoding) addSigned(value, incr int64, overflow Overflow) (int64, bool) {
+	maxValue := int64(math.MaxInt64)
+	if e.Bits < 64 {
+		maxValue = 1<<(e.Bits-1) - 1
+	}
+
+	minValue := -maxValue - 1
+
+	// the increments which don't overflow, as long as they don't overflow
+	// themselves
+	maxIncr, minIncr := maxValue-value, minValue-value
+
+	switch {
+	case value > maxValue || (e.Bits != 64 && incr > maxIncr) || (value >= 0 && incr > 0 && incr > maxIncr):
+		if overflow == OverflowSat {
+			return maxValue, true
+		}
+	case value < minValue || (e.Bits != 64 && incr < minIncr) || (value < 0 && incr < 0 && incr < minIncr):
+		if overflow == OverflowSat {
+			return minValue, true
+		}
+	default:
+		return value + incr, false
+	}
+
+	res := uint64(value) + uint64(incr)
+	if e.Bits < 64 {
+		if res&(1<<(e.Bits-1)) != 0 {
+			res |= math.MaxUint64 << e.Bits
+		} else {
+			res &^= math.MaxUint64 << e.Bits
+		}
+	}
+
+	return int64(res), true
+}
+
+func (e Encoding) addUnsigned(value uint64, incr int64, overflow Overflow) (int64, bool) {
+	maxValue := uint64(1)<<e.Bits - 1
+
+	switch {
+	case value > maxValue || (incr > 0 && uint64(incr) > maxValue-value):
+		if overflow == OverflowSat {
+			return int64(maxValue), true
+		}
+	case incr < 0 && uint64(-incr) > value:
+		if overflow == OverflowSat {
+			return 0, true
+		}
+	default:
+		return int64(value + uint64(incr)), false
+	}
+
+	return int64((value + uint64(incr)) & maxValue), true
+}
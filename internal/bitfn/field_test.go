@@ -0,0 +1,62 @@
+package bitfn_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/bitfn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncoding_GetSet(t *testing.T) {
+	b := make([]byte, 3)
+
+	i5 := bitfn.Encoding{Signed: true, Bits: 5}
+	i5.Set(b, 3, -3)
+	assert.Equal(t, int64(-3), i5.Get(b, 3))
+	assert.Equal(t, []byte{0x1d, 0x00, 0x00}, b)
+
+	u8 := bitfn.Encoding{Bits: 8}
+	assert.Equal(t, int64(0x1d), u8.Get(b, 0))
+	// past the end of the string
+	assert.Equal(t, int64(0), u8.Get(b, 100))
+
+	i64 := bitfn.Encoding{Signed: true, Bits: 64}
+	big := make([]byte, 8)
+	i64.Set(big, 0, math.MinInt64)
+	assert.Equal(t, int64(math.MinInt64), i64.Get(big, 0))
+}
+
+func TestEncoding_Add(t *testing.T) {
+	u2 := bitfn.Encoding{Bits: 2}
+	i8 := bitfn.Encoding{Signed: true, Bits: 8}
+	i64 := bitfn.Encoding{Signed: true, Bits: 64}
+
+	testCases := map[string]struct {
+		encoding    bitfn.Encoding
+		value, incr int64
+		overflow    bitfn.Overflow
+		expected    int64
+		expectedOK  bool
+	}{
+		"when no overflow":                  {encoding: u2, value: 1, incr: 2, expected: 3, expectedOK: true},
+		"when unsigned wraps":               {encoding: u2, value: 3, incr: 2, expected: 1, expectedOK: true},
+		"when unsigned saturates":           {encoding: u2, value: 3, incr: 2, overflow: bitfn.OverflowSat, expected: 3, expectedOK: true},
+		"when unsigned saturates below":     {encoding: u2, value: 1, incr: -2, overflow: bitfn.OverflowSat, expected: 0, expectedOK: true},
+		"when unsigned fails":               {encoding: u2, value: 1, incr: -2, overflow: bitfn.OverflowFail},
+		"when unsigned set is out of range": {encoding: u2, value: -1, overflow: bitfn.OverflowWrap, expected: 3, expectedOK: true},
+		"when signed wraps":                 {encoding: i8, value: 127, incr: 1, expected: -128, expectedOK: true},
+		"when signed saturates":             {encoding: i8, value: -100, incr: -100, overflow: bitfn.OverflowSat, expected: -128, expectedOK: true},
+		"when signed fails":                 {encoding: i8, value: 100, incr: 100, overflow: bitfn.OverflowFail},
+		"when i64 wraps":                    {encoding: i64, value: math.MaxInt64, incr: 1, expected: math.MinInt64, expectedOK: true},
+		"when i64 saturates":                {encoding: i64, value: math.MaxInt64, incr: 1, overflow: bitfn.OverflowSat, expected: math.MaxInt64, expectedOK: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			res, ok := tc.encoding.Add(tc.value, tc.incr, tc.overflow)
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package bitfn
+
+// Operation is a BITOP operation.
+type Operation int
+
+const (
+	OpAnd Operation = iota
+	OpOr
+	OpXor
+	OpNot
+	// OpDiff keeps the bits of the first source which are set in none of the
+	// others.
+	OpDiff
+	// OpAndOr keeps the bits of the first source which are set in at least
+	// one of the others.
+	OpAndOr
+	// OpOne keeps the bits set in exactly one source.
+	OpOne
+)
+
+// Op applies the operation to the sources, the shorter ones being padded
+// with zero bytes. The result is as long as the longest source.
+func Op(op Operation, sources [][]byte) []byte {
+	length := 0
+	for _, src := range sources {
+		if len(src) > length {
+			length = len(src)
+		}
+	}
+
+	res := make([]byte, length)
+	for i := range res {
+		res[i] = opByte(op, sources, i)
+	}
+
+	return res
+}
+
+func opByte(op Operation, sources [][]byte, i int) byte {
+	at := func(src []byte) byte {
+		if i < len(src) {
+			return src[i]
+		}
+
+		return 0
+	}
+
+	switch op {
+	case OpNot:
+		return ^at(sources[0])
+	case OpDiff, OpAndOr:
+		others := byte(0)
+		for _, src := range sources[1:] {
+			others |= at(src)
+		}
+
+		if op == OpDiff {
+			return at(sources[0]) &^ others
+		}
+
+		return at(sources[0]) & others
+	case OpOne:
+		seen, multiple := byte(0), byte(0)
+		for _, src := range sources {
+			multiple |= seen & at(src)
+			seen |= at(src)
+		}
+
+		return seen &^ multiple
+	}
+
+	res := at(sources[0])
+	for _, src := range sources[1:] {
+		switch op {
+		case OpAnd:
+			res &= at(src)
+		case OpOr:
+			res |= at(src)
+		case OpXor:
+			res ^= at(src)
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,126 @@
+package blocking
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+)
+
+// Waiter is a client blocked until one of its keys can serve it.
+type Waiter struct {
+	keys  []string
+	serve func(key string) (payload.Reply, bool)
+	reply chan payload.Reply
+}
+
+// Reply delivers the reply of the client once it has been served.
+func (w *Waiter) Reply() <-chan payload.Reply {
+	return w.reply
+}
+
+// Manager keeps track of the clients blocked on keys and serves them, in the
+// order they blocked, when the keys get new data.
+//
+// Manager isn't safe for concurrent use: it's only accessed while holding the
+// lock which serializes command execution, so that serving a client is
+// atomic with the commands around it.
+type Manager struct {
+	waiting  map[string][]*Waiter
+	ready    []string
+	readySet map[string]bool
+}
+
+func NewManager() *Manager {
+	return &Manager{
+		waiting:  map[string][]*Waiter{},
+		readySet: map[string]bool{},
+	}
+}
+
+// Block registers a client waiting on the given keys. serve is called with
+// the key which has been signaled as ready, and should return false if the
+// client still can't be served from it.
+func (m *Manager) Block(keys []string, serve func(key string) (payload.Reply, bool)) *Waiter {
+	w := &Waiter{
+		keys:  keys,
+		serve: serve,
+		reply: make(chan payload.Reply, 1),
+	}
+
+	for _, key := range keys {
+		m.waiting[key] = append(m.waiting[key], w)
+	}
+
+	return w
+}
+
+// Unblock removes a waiter, e.g. after its timeout. It returns false if the
+// waiter has already been served, in which case its reply is available.
+func (m *Manager) Unblock(w *Waiter) bool {
+	registered := false
+
+	for _, key := range w.keys {
+		queue := m.waiting[key]
+
+		for i, waiter := range queue {
+			if waiter != w {
+				continue
+			}
+
+			registered = true
+			queue = append(queue[:i], queue[i+1:]...)
+			break
+		}
+
+		if len(queue) == 0 {
+			delete(m.waiting, key)
+		} else {
+			m.waiting[key] = queue
+		}
+	}
+
+	return registered
+}
+
+// SignalKeyAsReady marks a key as having received new data. It's cheap to
+// call when nobody is blocked on the key.
+func (m *Manager) SignalKeyAsReady(key string) {
+	if _, exists := m.waiting[key]; !exists || m.readySet[key] {
+		return
+	}
+
+	m.readySet[key] = true
+	m.ready = append(m.ready, key)
+}
+
+// SignalAllKeysAsReady marks every key clients are blocked on as ready, for
+// when the whole keyspace changed at once (e.g. SWAPDB).
+func (m *Manager) SignalAllKeysAsReady() {
+	for key := range m.waiting {
+		m.SignalKeyAsReady(key)
+	}
+}
+
+// HandleReadyKeys serves the clients blocked on the keys signaled as ready,
+// in FIFO order per key. Serving a client may signal other keys (e.g. the
+// destination of BLMOVE), so it loops until no key is ready anymore.
+func (m *Manager) HandleReadyKeys() {
+	for len(m.ready) > 0 {
+		keys := m.ready
+		m.ready = nil
+		m.readySet = map[string]bool{}
+
+		for _, key := range keys {
+			// serving unblocks waiters, so iterate over a copy of the queue
+			queue := append([]*Waiter{}, m.waiting[key]...)
+
+			for _, w := range queue {
+				reply, ok := w.serve(key)
+				if !ok {
+					continue
+				}
+
+				m.Unblock(w)
+				w.reply <- reply
+			}
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package blocking_test
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/blocking"
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+	"github.com/stretchr/testify/assert"
+)
+
+// queue is a tiny data source where every element can serve one waiter
+type queue struct {
+	items []string
+}
+
+func (q *queue) serve(key string) (payload.Reply, bool) {
+	if len(q.items) == 0 {
+		return nil, false
+	}
+
+	item := q.items[0]
+	q.items = q.items[1:]
+
+	return payload.BulkString(item), true
+}
+
+func receive(w *blocking.Waiter) string {
+	select {
+	case reply := <-w.Reply():
+		return string(reply.(payload.BulkString))
+	default:
+		return ""
+	}
+}
+
+func TestHandleReadyKeys(t *testing.T) {
+	t.Run("when waiters are served in FIFO order", func(t *testing.T) {
+		m := blocking.NewManager()
+		q := &queue{}
+
+		first := m.Block([]string{"key"}, q.serve)
+		second := m.Block([]string{"key"}, q.serve)
+		third := m.Block([]string{"key"}, q.serve)
+
+		q.items = []string{"a", "b"}
+		m.SignalKeyAsReady("key")
+		m.HandleReadyKeys()
+
+		assert.Equal(t, "a", receive(first))
+		assert.Equal(t, "b", receive(second))
+		assert.Equal(t, "", receive(third))
+
+		assert.True(t, m.Unblock(third))
+		assert.False(t, m.Unblock(first))
+	})
+
+	t.Run("when waiter is blocked on multiple keys", func(t *testing.T) {
+		m := blocking.NewManager()
+		q := &queue{}
+
+		w := m.Block([]string{"key-1", "key-2"}, q.serve)
+
+		q.items = []string{"a", "b"}
+		m.SignalKeyAsReady("key-2")
+		m.SignalKeyAsReady("key-1")
+		m.HandleReadyKeys()
+
+		assert.Equal(t, "a", receive(w))
+		assert.Equal(t, []string{"b"}, q.items)
+	})
+
+	t.Run("when keys are not signaled", func(t *testing.T) {
+		m := blocking.NewManager()
+		q := &queue{items: []string{"a"}}
+
+		w := m.Block([]string{"key"}, q.serve)
+
+		m.SignalKeyAsReady("other-key")
+		m.HandleReadyKeys()
+
+		assert.Equal(t, "", receive(w))
+	})
+
+	t.Run("when serving a waiter signals another key", func(t *testing.T) {
+		m := blocking.NewManager()
+		source := &queue{}
+		destination := &queue{}
+
+		m.Block([]string{"source"}, func(key string) (payload.Reply, bool) {
+			reply, ok := source.serve(key)
+			if ok {
+				destination.items = append(destination.items, string(reply.(payload.BulkString)))
+				m.SignalKeyAsReady("destination")
+			}
+
+			return reply, ok
+		})
+		w := m.Block([]string{"destination"}, destination.serve)
+
+		source.items = []string{"a"}
+		m.SignalKeyAsReady("source")
+		m.HandleReadyKeys()
+
+		assert.Equal(t, "a", receive(w))
+	})
+
+	t.Run("when every key is signaled", func(t *testing.T) {
+		m := blocking.NewManager()
+		q := &queue{items: []string{"a", "b"}}
+
+		first := m.Block([]string{"key-1"}, q.serve)
+		second := m.Block([]string{"key-2"}, q.serve)
+
+		m.SignalAllKeysAsReady()
+		m.HandleReadyKeys()
+
+		assert.NotEmpty(t, receive(first))
+		assert.NotEmpty(t, receive(second))
+	})
+}
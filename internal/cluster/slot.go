@@ -0,0 +1,37 @@
+package cluster
+
+import "strings"
+
+// Slots is the number of hash slots the keyspace is split into.
+const Slots = 16384
+
+// KeySlot returns the hash slot of a key, or of a sharded channel. Only the
+// hash tag is hashed when the key has one, i.e. the part between the first
+// { and the next }, if not empty, so that related keys can be put in the
+// same slot.
+func KeySlot(key string) int {
+	if start := strings.IndexByte(key, '{'); start != -1 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+
+	return int(crc16(key) & (Slots - 1))
+}
+
+// crc16 is the CRC-16/XMODEM checksum: polynomial 0x1021, initial value 0.
+func crc16(s string) uint16 {
+	crc := uint16(0)
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+
+	return crc
+}
@@ -0,0 +1,52 @@
+package cluster
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCRC16(t *testing.T) {
+	assert.Equal(t, uint16(0x31c3), crc16("123456789"))
+}
+
+func TestKeySlot(t *testing.T) {
+	testCases := map[string]struct {
+		key      string
+		expected int
+	}{
+		"when key has no hash tag": {
+			key:      "foo",
+			expected: 12182,
+		},
+		"when key has a hash tag": {
+			key:      "{foo}.bar",
+			expected: 12182,
+		},
+		"when only the first hash tag counts": {
+			key:      "x{foo}{bar}",
+			expected: 12182,
+		},
+		"when hash tag is empty, the whole key is hashed": {
+			key:      "foo{}{bar}",
+			expected: int(crc16("foo{}{bar}") & (Slots - 1)),
+		},
+		"when braces aren't closed": {
+			key:      "{foo",
+			expected: int(crc16("{foo") & (Slots - 1)),
+		},
+		"when key is empty": {
+			key:      "",
+			expected: 0,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, KeySlot(tc.key))
+		})
+	}
+
+	assert.NotEqual(t, KeySlot("foo"), KeySlot("foo{}{bar}"))
+	assert.Equal(t, KeySlot("{user1000}.following"), KeySlot("{user1000}.followers"))
+}
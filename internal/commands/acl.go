@@ -0,0 +1,259 @@
+package commands
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/acl"
+	"github.com/codecrafters-io/redis-starter-go/internal/config"
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+)
+
+// defaultLogCount is the number of entries ACL LOG lists without a count.
+const defaultLogCount = 10
+
+// maxGenPassBits is the maximum number of bits ACL GENPASS generates.
+const maxGenPassBits = 4096
+
+var errNoACLFile = errors.New("ERR This Redis instance is not configured to use an ACL file. You may want to specify users via the ACL SETUSER command and then issue a CONFIG REWRITE (assuming you have a Redis configuration file set) in order to store users in the Redis configuration.")
+
+type ACLCommand struct {
+	acl *acl.ACL
+	cfg *config.Config
+	// commandsIn returns the names of the commands of a category.
+	commandsIn func(category acl.Category) []string
+}
+
+func NewACLCommand(a *acl.ACL, cfg *config.Config, commandsIn func(category acl.Category) []string) *ACLCommand {
+	return &ACLCommand{
+		acl:        a,
+		cfg:        cfg,
+		commandsIn: commandsIn,
+	}
+}
+
+// Handle runs the ACL subcommands managing the users and their permissions.
+func (c *ACLCommand) Handle(ctx *Context, args []string) payload.Reply {
+	subcommand := strings.ToLower(args[0])
+	args = args[1:]
+
+	switch subcommand {
+	case "setuser":
+		if len(args) < 1 {
+			break
+		}
+
+		return c.setUser(args[0], args[1:])
+	case "getuser":
+		if len(args) != 1 {
+			break
+		}
+
+		return c.getUser(args[0])
+	case "deluser":
+		if len(args) < 1 {
+			break
+		}
+
+		return c.delUser(args)
+	case "users":
+		if len(args) != 0 {
+			break
+		}
+
+		return payload.BulkStrings(c.acl.Users())
+	case "list":
+		if len(args) != 0 {
+			break
+		}
+
+		return payload.BulkStrings(c.acl.List())
+	case "whoami":
+		if len(args) != 0 {
+			break
+		}
+
+		return payload.BulkString(ctx.User)
+	case "cat":
+		if len(args) > 1 {
+			break
+		}
+
+		return c.cat(args)
+	case "log":
+		if len(args) > 1 {
+			break
+		}
+
+		return c.log(args)
+	case "load":
+		if len(args) != 0 {
+			break
+		}
+
+		return c.load()
+	case "save":
+		if len(args) != 0 {
+			break
+		}
+
+		return c.save()
+	case "genpass":
+		if len(args) > 1 {
+			break
+		}
+
+		return c.genPass(args)
+	default:
+		return payload.Error(fmt.Sprintf("ERR unknown subcommand '%s'. Try ACL HELP.", subcommand))
+	}
+
+	return payload.Error(fmt.Sprintf("ERR wrong number of arguments for 'acl|%s' command", subcommand))
+}
+
+// setUser runs ACL SETUSER username [rule [rule ...]]
+func (c *ACLCommand) setUser(name string, rules []string) payload.Reply {
+	if err := c.acl.SetUser(name, rules); err != nil {
+		return payload.Error("ERR " + err.Error())
+	}
+
+	return payload.SimpleString("OK")
+}
+
+// getUser runs ACL GETUSER username
+func (c *ACLCommand) getUser(name string) payload.Reply {
+	info, exists := c.acl.GetUser(name)
+	if !exists {
+		return payload.Null{}
+	}
+
+	return payload.Map{
+		{Key: payload.BulkString("flags"), Value: payload.BulkStrings(info.Flags)},
+		{Key: payload.BulkString("passwords"), Value: payload.BulkStrings(info.Passwords)},
+		{Key: payload.BulkString("commands"), Value: payload.BulkString(info.Commands)},
+		{Key: payload.BulkString("keys"), Value: payload.BulkString(info.Keys)},
+		{Key: payload.BulkString("channels"), Value: payload.BulkString(info.Channels)},
+		{Key: payload.BulkString("selectors"), Value: payload.Array{}},
+	}
+}
+
+// delUser runs ACL DELUSER username [username ...]
+func (c *ACLCommand) delUser(names []string) payload.Reply {
+	deleted, err := c.acl.DelUser(names)
+	if err != nil {
+		return payload.Error("ERR " + err.Error())
+	}
+
+	return payload.Integer(int64(deleted))
+}
+
+// cat runs ACL CAT [category]
+func (c *ACLCommand) cat(args []string) payload.Reply {
+	if len(args) == 0 {
+		return payload.BulkStrings(acl.CategoryNames())
+	}
+
+	category, found := acl.LookupCategory(args[0])
+	if !found || category == acl.All {
+		return payload.Error(fmt.Sprintf("ERR Unknown category '%s'", args[0]))
+	}
+
+	return payload.BulkStrings(c.commandsIn(category))
+}
+
+// log runs ACL LOG [count | RESET]
+func (c *ACLCommand) log(args []string) payload.Reply {
+	count := defaultLogCount
+
+	if len(args) == 1 {
+		if strings.ToUpper(args[0]) == "RESET" {
+			c.acl.Log().Reset()
+			return payload.SimpleString("OK")
+		}
+
+		parsed, err := strconv.Atoi(args[0])
+		if err != nil || parsed < 0 {
+			return payload.Error("ERR value is out of range, must be positive")
+		}
+
+		count = parsed
+	}
+
+	now := time.Now()
+
+	res := payload.Array{}
+	for _, e := range c.acl.Log().Entries(count) {
+		res = append(res, payload.Map{
+			{Key: payload.BulkString("count"), Value: payload.Integer(e.Count)},
+			{Key: payload.BulkString("reason"), Value: payload.BulkString(e.Reason)},
+			{Key: payload.BulkString("context"), Value: payload.BulkString(e.Context)},
+			{Key: payload.BulkString("object"), Value: payload.BulkString(e.Object)},
+			{Key: payload.BulkString("username"), Value: payload.BulkString(e.Username)},
+			{Key: payload.BulkString("age-seconds"), Value: payload.Double(now.Sub(e.Created).Seconds())},
+			{Key: payload.BulkString("client-info"), Value: payload.BulkString(e.ClientInfo)},
+			{Key: payload.BulkString("entry-id"), Value: payload.Integer(e.ID)},
+			{Key: payload.BulkString("timestamp-created"), Value: payload.Integer(e.Created.UnixMilli())},
+			{Key: payload.BulkString("timestamp-last-updated"), Value: payload.Integer(e.Updated.UnixMilli())},
+		})
+	}
+
+	return res
+}
+
+// load runs ACL LOAD, replacing the users with those of the aclfile.
+func (c *ACLCommand) load() payload.Reply {
+	path, _ := c.cfg.Get(config.ACLFile)
+	if path == "" {
+		return errorReply(errNoACLFile)
+	}
+
+	if err := c.acl.LoadFile(path); err != nil {
+		return payload.Error("ERR " + err.Error())
+	}
+
+	return payload.SimpleString("OK")
+}
+
+// save runs ACL SAVE, writing the users to the aclfile.
+func (c *ACLCommand) save() payload.Reply {
+	path, _ := c.cfg.Get(config.ACLFile)
+	if path == "" {
+		return errorReply(errNoACLFile)
+	}
+
+	if err := c.acl.SaveFile(path); err != nil {
+		return payload.Error("ERR " + err.Error())
+	}
+
+	return payload.SimpleString("OK")
+}
+
+// genPass runs ACL GENPASS [bits], generating a random password of 256 bits
+// by default, in hexadecimal.
+func (c *ACLCommand) genPass(args []string) payload.Reply {
+	bits := 256
+
+	if len(args) == 1 {
+		parsed, err := strconv.Atoi(args[0])
+		if err != nil || parsed <= 0 || parsed > maxGenPassBits {
+			return payload.Error(fmt.Sprintf("ERR ACL GENPASS argument must be the number of bits for the output password, a positive number up to %d", maxGenPassBits))
+		}
+
+		bits = parsed
+	}
+
+	// every hexadecimal character holds 4 bits
+	chars := (bits + 3) / 4
+
+	random := make([]byte, (chars+1)/2)
+	if _, err := rand.Read(random); err != nil {
+		return payload.Error("ERR Failed to generate a password")
+	}
+
+	return payload.BulkString(hex.EncodeToString(random)[:chars])
+}
@@ -0,0 +1,213 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/internal/bitfn"
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/bitmapparser"
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+	"github.com/codecrafters-io/redis-starter-go/internal/store"
+)
+
+// BitmapCommands are the bit-level commands, which work on string values.
+type BitmapCommands struct {
+	kvStore *store.KVStore
+}
+
+func NewBitmapCommands(kvStore *store.KVStore) *BitmapCommands {
+	return &BitmapCommands{
+		kvStore: kvStore,
+	}
+}
+
+// SetBit runs SETBIT key offset value
+func (c *BitmapCommands) SetBit(ctx *Context, args []string) payload.Reply {
+	offset, err := bitmapparser.ParseBitOffset(args[1])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	bit, err := bitmapparser.ParseBit(args[2])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	previous, err := c.kvStore.SetBit(args[0], offset, bit)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(int64(previous))
+}
+
+// GetBit runs GETBIT key offset
+func (c *BitmapCommands) GetBit(ctx *Context, args []string) payload.Reply {
+	offset, err := bitmapparser.ParseBitOffset(args[1])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	str, _, err := c.kvStore.GetString(args[0])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(int64(bitfn.GetBit(str, offset)))
+}
+
+// BitCount runs BITCOUNT key [start end [BYTE|BIT]]
+func (c *BitmapCommands) BitCount(ctx *Context, args []string) payload.Reply {
+	bitRange, err := bitmapparser.ParseBitRange(args[1:], false)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	str, _, err := c.kvStore.GetString(args[0])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	if bitRange.HasStart && bitRange.Start < 0 && bitRange.End < 0 && bitRange.Start > bitRange.End {
+		return payload.Integer(0)
+	}
+
+	start, end, ok := bitPositions(bitRange, len(str))
+	if !ok {
+		return payload.Integer(0)
+	}
+
+	return payload.Integer(int64(bitfn.Count(str, start, end)))
+}
+
+// BitPos runs BITPOS key bit [start [end [BYTE|BIT]]]
+func (c *BitmapCommands) BitPos(ctx *Context, args []string) payload.Reply {
+	bit, err := bitmapparser.ParseBitPosBit(args[1])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	bitRange, err := bitmapparser.ParseBitRange(args[2:], true)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	str, found, err := c.kvStore.GetString(args[0])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	// a missing key is an endless string of zeros
+	if !found {
+		return payload.Integer(int64(-bit))
+	}
+
+	start, end, ok := bitPositions(bitRange, len(str))
+	if !ok {
+		return payload.Integer(-1)
+	}
+
+	pos := bitfn.Pos(str, bit, start, end)
+
+	// without an end, the string is padded with zeros on the right
+	if pos == -1 && bit == 0 && !bitRange.HasEnd {
+		pos = end + 1
+	}
+
+	return payload.Integer(int64(pos))
+}
+
+// BitOp runs BITOP AND|OR|XOR|NOT|DIFF|ANDOR|ONE destkey key [key ...]
+func (c *BitmapCommands) BitOp(ctx *Context, args []string) payload.Reply {
+	bitopArgs, err := bitmapparser.ParseBitOpArgs(args)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	length, err := c.kvStore.BitOp(bitopArgs.Op, bitopArgs.Dest, bitopArgs.Keys)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(int64(length))
+}
+
+// BitField runs BITFIELD key [GET encoding offset | [OVERFLOW WRAP|SAT|FAIL]
+// SET encoding offset value | INCRBY encoding offset increment ...]
+func (c *BitmapCommands) BitField(ctx *Context, args []string) payload.Reply {
+	return c.bitField(args, false)
+}
+
+// BitFieldRO runs BITFIELD_RO key [GET encoding offset ...]
+func (c *BitmapCommands) BitFieldRO(ctx *Context, args []string) payload.Reply {
+	return c.bitField(args, true)
+}
+
+func (c *BitmapCommands) bitField(args []string, readOnly bool) payload.Reply {
+	ops, err := bitmapparser.ParseBitFieldArgs(args[1:], readOnly)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	results, ok, err := c.kvStore.BitField(args[0], ops)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	elements := make([]payload.Reply, len(results))
+	for i, res := range results {
+		if ok[i] {
+			elements[i] = payload.Integer(res)
+		} else {
+			elements[i] = payload.Null{}
+		}
+	}
+
+	return payload.Array(elements)
+}
+
+// bitPositions returns the positions of the first and last bits of the
+// range within a string of length bytes, and false if the range is empty.
+// Negative offsets count from the end of the string.
+func bitPositions(bitRange *bitmapparser.BitRange, length int) (int, int, bool) {
+	unitLength := length
+	if bitRange.BitUnit {
+		unitLength = length * 8
+	}
+
+	start, end := 0, unitLength-1
+	if bitRange.HasStart {
+		start = bitRange.Start
+	}
+
+	if bitRange.HasEnd {
+		end = bitRange.End
+	}
+
+	if start < 0 {
+		start += unitLength
+	}
+
+	if end < 0 {
+		end += unitLength
+	}
+
+	if start < 0 {
+		start = 0
+	}
+
+	if end < 0 {
+		end = 0
+	}
+
+	if end >= unitLength {
+		end = unitLength - 1
+	}
+
+	if start > end {
+		return 0, 0, false
+	}
+
+	if bitRange.BitUnit {
+		return start, end, true
+	}
+
+	return start * 8, end*8 + 7, true
+}
@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/config"
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+)
+
+type ConfigCommand struct {
+	cfg *config.Config
+}
+
+func NewConfigCommand(cfg *config.Config) *ConfigCommand {
+	return &ConfigCommand{
+		cfg: cfg,
+	}
+}
+
+// Handle runs CONFIG GET name [name ...] and CONFIG SET name value [name value ...]
+func (c *ConfigCommand) Handle(ctx *Context, args []string) payload.Reply {
+	if len(args) == 0 {
+		return payload.Error("ERR wrong number of arguments for 'config' command")
+	}
+
+	switch strings.ToUpper(args[0]) {
+	case "GET":
+		return c.get(args[1:])
+	case "SET":
+		return c.set(args[1:])
+	}
+
+	return payload.Error(fmt.Sprintf("ERR unknown subcommand '%s'. Try CONFIG HELP.", args[0]))
+}
+
+func (c *ConfigCommand) get(names []string) payload.Reply {
+	if len(names) == 0 {
+		return payload.Error("ERR wrong number of arguments for 'config|get' command")
+	}
+
+	res := payload.Map{}
+
+	for _, name := range names {
+		if value, exists := c.cfg.Get(name); exists {
+			res = append(res, payload.MapEntry{Key: payload.BulkString(strings.ToLower(name)), Value: payload.BulkString(value)})
+		}
+	}
+
+	return res
+}
+
+func (c *ConfigCommand) set(args []string) payload.Reply {
+	if len(args) == 0 || len(args)%2 != 0 {
+		return payload.Error("ERR wrong number of arguments for 'config|set' command")
+	}
+
+	for i := 0; i < len(args); i += 2 {
+		if err := c.cfg.Set(args[i], args[i+1]); err != nil {
+			return payload.Error("ERR " + err.Error())
+		}
+	}
+
+	return payload.SimpleString("OK")
+}
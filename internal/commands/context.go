@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/blocking"
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+	"github.com/codecrafters-io/redis-starter-go/internal/pubsub"
+)
+
+type Handler func(ctx *Context, args []string) payload.Reply
+
+// Context is the state of the client running a command.
+type Context struct {
+	// InMulti is set while running the commands of a transaction, where
+	// blocking commands reply right away instead of blocking.
+	InMulti bool
+	// DB is the index of the database selected by the client, which SELECT
+	// changes.
+	DB int
+	// Subscriber is the client itself, for the Pub/Sub commands.
+	Subscriber pubsub.Subscriber
+	// Protocol is the RESP version the client switched to with HELLO, zero
+	// standing for the default RESP2.
+	Protocol int
+	// User is the name of the ACL user the client is authenticated as.
+	User string
+
+	waiter       *blocking.Waiter
+	timeout      time.Duration
+	timeoutReply payload.Reply
+}
+
+// Blocked returns the waiter registered by a blocking command, nil if the
+// command didn't block. The command's reply is only available once the waiter
+// is served, or else the timeout reply is sent when the timeout expires. A
+// zero timeout means blocking forever.
+func (ctx *Context) Blocked() (*blocking.Waiter, time.Duration, payload.Reply) {
+	return ctx.waiter, ctx.timeout, ctx.timeoutReply
+}
+
+// RESP3 returns true if the client speaks RESP3, in which case replies use
+// its native types, e.g. maps instead of flat arrays of keys and values.
+func (ctx *Context) RESP3() bool {
+	return ctx.Protocol == 3
+}
+
+func (ctx *Context) block(waiter *blocking.Waiter, timeout time.Duration, timeoutReply payload.Reply) {
+	ctx.waiter = waiter
+	ctx.timeout = timeout
+	ctx.timeoutReply = timeoutReply
+}
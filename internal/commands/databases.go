@@ -0,0 +1,128 @@
+package commands
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+)
+
+var (
+	errDBIndexOutOfRange    = errors.New("ERR DB index is out of range")
+	errInvalidFirstDBIndex  = errors.New("ERR invalid first DB index")
+	errInvalidSecondDBIndex = errors.New("ERR invalid second DB index")
+)
+
+// Select runs SELECT index
+func (c *KeyCommands) Select(ctx *Context, args []string) payload.Reply {
+	db, err := c.parseDB(args[0], argparser.ErrNotInteger)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	ctx.DB = db
+
+	return payload.SimpleString("OK")
+}
+
+// Move runs MOVE key db
+func (c *KeyCommands) Move(ctx *Context, args []string) payload.Reply {
+	db, err := c.parseDB(args[1], argparser.ErrNotInteger)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	moved, err := c.kvStore.Move(args[0], c.dbs[db])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	if moved {
+		c.blockingManagers[db].SignalKeyAsReady(args[0])
+	}
+
+	return payload.Integer(boolToInt(moved))
+}
+
+// SwapDB runs SWAPDB index1 index2
+func (c *KeyCommands) SwapDB(ctx *Context, args []string) payload.Reply {
+	first, err := c.parseDB(args[0], errInvalidFirstDBIndex)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	second, err := c.parseDB(args[1], errInvalidSecondDBIndex)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	if first == second {
+		return payload.SimpleString("OK")
+	}
+
+	c.dbs[first].Swap(c.dbs[second])
+
+	// the clients blocked in either database may be served by the keys they
+	// see now
+	c.blockingManagers[first].SignalAllKeysAsReady()
+	c.blockingManagers[second].SignalAllKeysAsReady()
+
+	return payload.SimpleString("OK")
+}
+
+// FlushDB runs FLUSHDB [ASYNC|SYNC]
+func (c *KeyCommands) FlushDB(ctx *Context, args []string) payload.Reply {
+	if err := checkFlushMode(args); err != nil {
+		return errorReply(err)
+	}
+
+	c.kvStore.Flush()
+
+	return payload.SimpleString("OK")
+}
+
+// FlushAll runs FLUSHALL [ASYNC|SYNC]
+func (c *KeyCommands) FlushAll(ctx *Context, args []string) payload.Reply {
+	if err := checkFlushMode(args); err != nil {
+		return errorReply(err)
+	}
+
+	for _, db := range c.dbs {
+		db.Flush()
+	}
+
+	return payload.SimpleString("OK")
+}
+
+// parseDB parses the index of a database, replying with notInteger if it's
+// not an integer.
+func (c *KeyCommands) parseDB(arg string, notInteger error) (int, error) {
+	db, err := argparser.ParseInt(arg)
+	if err != nil {
+		return 0, notInteger
+	}
+
+	if db < 0 || db >= len(c.dbs) {
+		return 0, errDBIndexOutOfRange
+	}
+
+	return db, nil
+}
+
+// checkFlushMode checks the [ASYNC|SYNC] option of FLUSHDB and FLUSHALL.
+// Both modes are the same, the values being freed by the garbage collector.
+func checkFlushMode(args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+
+	if len(args) == 1 {
+		switch strings.ToUpper(args[0]) {
+		case "ASYNC", "SYNC":
+			return nil
+		}
+	}
+
+	return argparser.ErrSyntax
+}
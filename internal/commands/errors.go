@@ -0,0 +1,9 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+)
+
+func errorReply(err error) payload.Reply {
+	return payload.Error(err.Error())
+}
@@ -0,0 +1,278 @@
+package commands
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/blocking"
+	"github.com/codecrafters-io/redis-starter-go/internal/floatfn"
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/geoparser"
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+	"github.com/codecrafters-io/redis-starter-go/internal/store"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/geo"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/zset"
+)
+
+var errMemberNotFound = errors.New("ERR could not decode requested zset member")
+
+// GeoCommands are the GEO commands, which store the locations in sorted sets
+// with their geohash as score.
+type GeoCommands struct {
+	zsetStore *store.ZSet
+	blocking  *blocking.Manager
+}
+
+func NewGeoCommands(zsetStore *store.ZSet, blockingManager *blocking.Manager) *GeoCommands {
+	return &GeoCommands{
+		zsetStore: zsetStore,
+		blocking:  blockingManager,
+	}
+}
+
+// GeoAdd runs GEOADD key [NX|XX] [CH] longitude latitude member [longitude
+// latitude member ...]
+func (c *GeoCommands) GeoAdd(ctx *Context, args []string) payload.Reply {
+	geoaddArgs, err := geoparser.ParseGeoAddArgs(args[1:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	entries := make([]zset.Entry, len(geoaddArgs.Locations))
+	for i, location := range geoaddArgs.Locations {
+		entries[i] = zset.Entry{
+			Member: location.Member,
+			Score:  float64(geo.Encode(location.Lon, location.Lat)),
+		}
+	}
+
+	flags := zset.AddFlags{NX: geoaddArgs.NX, XX: geoaddArgs.XX}
+
+	added, updated, err := c.zsetStore.Add(args[0], entries, flags)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	if added > 0 {
+		c.blocking.SignalKeyAsReady(args[0])
+	}
+
+	if geoaddArgs.CH {
+		added += updated
+	}
+
+	return payload.Integer(int64(added))
+}
+
+// GeoDist runs GEODIST key member1 member2 [M|KM|FT|MI]
+func (c *GeoCommands) GeoDist(ctx *Context, args []string) payload.Reply {
+	if len(args) > 4 {
+		return errorReply(argparser.ErrSyntax)
+	}
+
+	conversion := 1.0
+	if len(args) == 4 {
+		var err error
+
+		conversion, err = geoparser.ParseUnit(args[3])
+		if err != nil {
+			return errorReply(err)
+		}
+	}
+
+	scores, found, err := c.zsetStore.MScore(args[0], args[1:3])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	if !found[0] || !found[1] {
+		return payload.Null{}
+	}
+
+	lon1, lat1 := geo.Decode(uint64(scores[0]))
+	lon2, lat2 := geo.Decode(uint64(scores[1]))
+
+	return distanceReply(geo.Distance(lon1, lat1, lon2, lat2) / conversion)
+}
+
+// GeoHash runs GEOHASH key [member [member ...]]
+func (c *GeoCommands) GeoHash(ctx *Context, args []string) payload.Reply {
+	scores, found, err := c.zsetStore.MScore(args[0], args[1:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	elements := make([]payload.Reply, len(scores))
+	for i, score := range scores {
+		if found[i] {
+			elements[i] = payload.BulkString(geo.String(uint64(score)))
+		} else {
+			elements[i] = payload.Null{}
+		}
+	}
+
+	return payload.Array(elements)
+}
+
+// GeoPos runs GEOPOS key [member [member ...]]
+func (c *GeoCommands) GeoPos(ctx *Context, args []string) payload.Reply {
+	scores, found, err := c.zsetStore.MScore(args[0], args[1:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	elements := make([]payload.Reply, len(scores))
+	for i, score := range scores {
+		if found[i] {
+			elements[i] = coordinatesReply(geo.Decode(uint64(score)))
+		} else {
+			elements[i] = payload.NullArray{}
+		}
+	}
+
+	return payload.Array(elements)
+}
+
+// GeoSearch runs GEOSEARCH key FROMMEMBER member | FROMLONLAT longitude
+// latitude BYRADIUS radius unit | BYBOX width height unit [ASC|DESC] [COUNT
+// count [ANY]] [WITHCOORD] [WITHDIST] [WITHHASH]
+func (c *GeoCommands) GeoSearch(ctx *Context, args []string) payload.Reply {
+	searchArgs, err := geoparser.ParseGeoSearchArgs("GEOSEARCH", args[1:], false)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	matches, err := c.search(args[0], searchArgs)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	elements := make([]payload.Reply, len(matches))
+	for i, match := range matches {
+		if !searchArgs.WithDist && !searchArgs.WithHash && !searchArgs.WithCoord {
+			elements[i] = payload.BulkString(match.Member)
+			continue
+		}
+
+		fields := []payload.Reply{payload.BulkString(match.Member)}
+
+		if searchArgs.WithDist {
+			fields = append(fields, distanceReply(match.Distance/searchArgs.Unit))
+		}
+
+		if searchArgs.WithHash {
+			fields = append(fields, payload.Integer(int64(match.Score)))
+		}
+
+		if searchArgs.WithCoord {
+			fields = append(fields, coordinatesReply(match.Lon, match.Lat))
+		}
+
+		elements[i] = payload.Array(fields)
+	}
+
+	return payload.Array(elements)
+}
+
+// GeoSearchStore runs GEOSEARCHSTORE destination source FROMMEMBER member |
+// FROMLONLAT longitude latitude BYRADIUS radius unit | BYBOX width height
+// unit [ASC|DESC] [COUNT count [ANY]] [STOREDIST]
+func (c *GeoCommands) GeoSearchStore(ctx *Context, args []string) payload.Reply {
+	searchArgs, err := geoparser.ParseGeoSearchArgs("GEOSEARCHSTORE", args[2:], true)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	matches, err := c.search(args[1], searchArgs)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	entries := make([]zset.Entry, len(matches))
+	for i, match := range matches {
+		entries[i] = match.Entry
+
+		if searchArgs.StoreDist {
+			entries[i].Score = match.Distance / searchArgs.Unit
+		}
+	}
+
+	count := c.zsetStore.Store(args[0], entries, "geosearchstore")
+	if count > 0 {
+		c.blocking.SignalKeyAsReady(args[0])
+	}
+
+	return payload.Integer(int64(count))
+}
+
+// search returns the members found by GEOSEARCH and GEOSEARCHSTORE, sorted
+// and limited as requested.
+func (c *GeoCommands) search(key string, args *geoparser.GeoSearchArgs) ([]store.GeoMatch, error) {
+	shape := &geo.Shape{
+		Lon:    args.Lon,
+		Lat:    args.Lat,
+		Box:    args.Box,
+		Radius: args.Radius * args.Unit,
+		Width:  args.Width * args.Unit,
+		Height: args.Height * args.Unit,
+	}
+
+	if args.HasFromMember {
+		// a missing key is an empty result, not a missing member
+		count, err := c.zsetStore.Card(key)
+		if err != nil || count == 0 {
+			return []store.GeoMatch{}, err
+		}
+
+		score, found, err := c.zsetStore.Score(key, args.FromMember)
+		if err != nil {
+			return nil, err
+		}
+
+		if !found {
+			return nil, errMemberNotFound
+		}
+
+		shape.Lon, shape.Lat = geo.Decode(uint64(score))
+	}
+
+	limit := 0
+	if args.Any {
+		limit = args.Count
+	}
+
+	matches, err := c.zsetStore.GeoSearch(key, shape, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	switch args.Sort {
+	case geoparser.SortAsc:
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].Distance < matches[j].Distance
+		})
+	case geoparser.SortDesc:
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].Distance > matches[j].Distance
+		})
+	}
+
+	if args.Count > 0 && len(matches) > args.Count {
+		matches = matches[:args.Count]
+	}
+
+	return matches, nil
+}
+
+// distanceReply replies with a distance, with the 4 decimals of Redis.
+func distanceReply(distance float64) payload.Reply {
+	return payload.BulkString(strconv.FormatFloat(distance, 'f', 4, 64))
+}
+
+func coordinatesReply(lon, lat float64) payload.Reply {
+	return payload.BulkStrings([]string{
+		floatfn.FormatPrecise(lon),
+		floatfn.FormatPrecise(lat),
+	})
+}
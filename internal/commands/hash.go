@@ -0,0 +1,443 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/hashparser"
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/scanparser"
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+	"github.com/codecrafters-io/redis-starter-go/internal/store"
+)
+
+// maxExpireTime is the latest expiration time a hash field can have, in unix
+// milliseconds.
+const maxExpireTime = 1<<48 - 1
+
+type HashCommands struct {
+	hashStore *store.Hash
+}
+
+func NewHashCommands(hashStore *store.Hash) *HashCommands {
+	return &HashCommands{
+		hashStore: hashStore,
+	}
+}
+
+// HSet runs HSET key field value [field value ...]
+func (c *HashCommands) HSet(ctx *Context, args []string) payload.Reply {
+	pairs, errReply := fieldValuePairs("hset", args[1:])
+	if errReply != nil {
+		return errReply
+	}
+
+	added, err := c.hashStore.Set(args[0], pairs)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(int64(added))
+}
+
+// HMSet runs HMSET key field value [field value ...]
+func (c *HashCommands) HMSet(ctx *Context, args []string) payload.Reply {
+	pairs, errReply := fieldValuePairs("hmset", args[1:])
+	if errReply != nil {
+		return errReply
+	}
+
+	if _, err := c.hashStore.Set(args[0], pairs); err != nil {
+		return errorReply(err)
+	}
+
+	return payload.SimpleString("OK")
+}
+
+// HSetNX runs HSETNX key field value
+func (c *HashCommands) HSetNX(ctx *Context, args []string) payload.Reply {
+	set, err := c.hashStore.SetNX(args[0], args[1], args[2])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(boolToInt(set))
+}
+
+// HGet runs HGET key field
+func (c *HashCommands) HGet(ctx *Context, args []string) payload.Reply {
+	value, found, err := c.hashStore.Get(args[0], args[1])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	if !found {
+		return payload.Null{}
+	}
+
+	return payload.BulkString(value)
+}
+
+// HMGet runs HMGET key field [field ...]
+func (c *HashCommands) HMGet(ctx *Context, args []string) payload.Reply {
+	values, err := c.hashStore.MGet(args[0], args[1:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	elements := make([]payload.Reply, len(values))
+	for i, value := range values {
+		if value == nil {
+			elements[i] = payload.Null{}
+		} else {
+			elements[i] = payload.BulkString(value)
+		}
+	}
+
+	return payload.Array(elements)
+}
+
+// HDel runs HDEL key field [field ...]
+func (c *HashCommands) HDel(ctx *Context, args []string) payload.Reply {
+	removed, err := c.hashStore.Del(args[0], args[1:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(int64(removed))
+}
+
+// HLen runs HLEN key
+func (c *HashCommands) HLen(ctx *Context, args []string) payload.Reply {
+	length, err := c.hashStore.Len(args[0])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(int64(length))
+}
+
+// HExists runs HEXISTS key field
+func (c *HashCommands) HExists(ctx *Context, args []string) payload.Reply {
+	_, found, err := c.hashStore.Get(args[0], args[1])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(boolToInt(found))
+}
+
+// HStrLen runs HSTRLEN key field
+func (c *HashCommands) HStrLen(ctx *Context, args []string) payload.Reply {
+	value, _, err := c.hashStore.Get(args[0], args[1])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(int64(len(value)))
+}
+
+// HGetAll runs HGETALL key
+func (c *HashCommands) HGetAll(ctx *Context, args []string) payload.Reply {
+	fields, err := c.hashStore.GetAll(args[0])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	res := make(payload.Map, len(fields))
+	for i, field := range fields {
+		res[i] = payload.MapEntry{Key: payload.BulkString(field.Field), Value: payload.BulkString(field.Value)}
+	}
+
+	return res
+}
+
+// HKeys runs HKEYS key
+func (c *HashCommands) HKeys(ctx *Context, args []string) payload.Reply {
+	return c.getAll(args[0], true, false)
+}
+
+// HVals runs HVALS key
+func (c *HashCommands) HVals(ctx *Context, args []string) payload.Reply {
+	return c.getAll(args[0], false, true)
+}
+
+// HIncrBy runs HINCRBY key field increment
+func (c *HashCommands) HIncrBy(ctx *Context, args []string) payload.Reply {
+	increment, err := argparser.ParseInt(args[2])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	value, err := c.hashStore.IncrBy(args[0], args[1], int64(increment))
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(value)
+}
+
+// HIncrByFloat runs HINCRBYFLOAT key field increment
+func (c *HashCommands) HIncrByFloat(ctx *Context, args []string) payload.Reply {
+	increment, err := argparser.ParseFloat(args[2])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	value, err := c.hashStore.IncrByFloat(args[0], args[1], increment)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.BulkString(value)
+}
+
+// HRandField runs HRANDFIELD key [count [WITHVALUES]]
+func (c *HashCommands) HRandField(ctx *Context, args []string) payload.Reply {
+	if len(args) == 1 {
+		fields, err := c.hashStore.RandFields(args[0], 1, false)
+		if err != nil {
+			return errorReply(err)
+		}
+
+		if len(fields) == 0 {
+			return payload.Null{}
+		}
+
+		return payload.BulkString(fields[0].Field)
+	}
+
+	if len(args) > 3 || (len(args) == 3 && strings.ToUpper(args[2]) != "WITHVALUES") {
+		return errorReply(argparser.ErrSyntax)
+	}
+
+	count, err := argparser.ParseInt(args[1])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	// a negative count allows returning the same field many times
+	allowRepeat := count < 0
+	if allowRepeat {
+		count = -count
+	}
+
+	fields, err := c.hashStore.RandFields(args[0], count, allowRepeat)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	// over RESP3, every field comes with its value in a pair of its own
+	if len(args) == 3 && ctx.RESP3() {
+		pairs := make([]payload.Reply, len(fields))
+		for i, field := range fields {
+			pairs[i] = payload.BulkStrings([]string{field.Field, field.Value})
+		}
+
+		return payload.Array(pairs)
+	}
+
+	return fieldValueArray(fields, true, len(args) == 3)
+}
+
+// HScan runs HSCAN key cursor [MATCH pattern] [COUNT count] [NOVALUES]
+func (c *HashCommands) HScan(ctx *Context, args []string) payload.Reply {
+	options, err := scanparser.ParseScanOptions(args[1:], "NOVALUES")
+	if err != nil {
+		return errorReply(err)
+	}
+
+	cursor, fields, err := c.hashStore.Scan(args[0], options.Cursor, options.Match, options.Count)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return scanReply(cursor, fieldValueArray(fields, true, !options.NoValues))
+}
+
+// HExpire runs HEXPIRE key seconds [NX | XX | GT | LT] FIELDS numfields field [field ...]
+func (c *HashCommands) HExpire(ctx *Context, args []string) payload.Reply {
+	return c.expire("hexpire", args, time.Second, false)
+}
+
+// HPExpire runs HPEXPIRE key milliseconds [NX | XX | GT | LT] FIELDS numfields field [field ...]
+func (c *HashCommands) HPExpire(ctx *Context, args []string) payload.Reply {
+	return c.expire("hpexpire", args, time.Millisecond, false)
+}
+
+// HExpireAt runs HEXPIREAT key unix-time-seconds [NX | XX | GT | LT] FIELDS numfields field [field ...]
+func (c *HashCommands) HExpireAt(ctx *Context, args []string) payload.Reply {
+	return c.expire("hexpireat", args, time.Second, true)
+}
+
+// HPExpireAt runs HPEXPIREAT key unix-time-milliseconds [NX | XX | GT | LT] FIELDS numfields field [field ...]
+func (c *HashCommands) HPExpireAt(ctx *Context, args []string) payload.Reply {
+	return c.expire("hpexpireat", args, time.Millisecond, true)
+}
+
+// HTTL runs HTTL key FIELDS numfields field [field ...]
+func (c *HashCommands) HTTL(ctx *Context, args []string) payload.Reply {
+	return c.expireTimes(args, time.Second, false)
+}
+
+// HPTTL runs HPTTL key FIELDS numfields field [field ...]
+func (c *HashCommands) HPTTL(ctx *Context, args []string) payload.Reply {
+	return c.expireTimes(args, time.Millisecond, false)
+}
+
+// HExpireTime runs HEXPIRETIME key FIELDS numfields field [field ...]
+func (c *HashCommands) HExpireTime(ctx *Context, args []string) payload.Reply {
+	return c.expireTimes(args, time.Second, true)
+}
+
+// HPExpireTime runs HPEXPIRETIME key FIELDS numfields field [field ...]
+func (c *HashCommands) HPExpireTime(ctx *Context, args []string) payload.Reply {
+	return c.expireTimes(args, time.Millisecond, true)
+}
+
+// HPersist runs HPERSIST key FIELDS numfields field [field ...]
+func (c *HashCommands) HPersist(ctx *Context, args []string) payload.Reply {
+	fields, err := hashparser.ParseFields(args[1:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	res, err := c.hashStore.Persist(args[0], fields)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return integerArray(res)
+}
+
+// expire sets the expiration time of fields, given either relative to now or
+// as a unix time, in the given unit.
+func (c *HashCommands) expire(command string, args []string, unit time.Duration, absolute bool) payload.Reply {
+	value, err := argparser.ParseInt(args[1])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	if value < 0 {
+		return errorReply(errors.New("ERR invalid expire time, must be >= 0"))
+	}
+
+	condition, fields, err := hashparser.ParseExpireFields(args[2:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	perMs := int64(unit / time.Millisecond)
+	at := int64(value) * perMs
+	if !absolute {
+		at += time.Now().UnixMilli()
+	}
+
+	if int64(value) > (math.MaxInt64-time.Now().UnixMilli())/perMs || at > maxExpireTime {
+		return errorReply(fmt.Errorf("ERR invalid expire time in '%s' command", command))
+	}
+
+	res, err := c.hashStore.Expire(args[0], fields, at, store.ExpireCondition(condition))
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return integerArray(res)
+}
+
+// expireTimes replies with the expiration times of fields, either as the time
+// to live or as a unix time, in the given unit.
+func (c *HashCommands) expireTimes(args []string, unit time.Duration, absolute bool) payload.Reply {
+	fields, err := hashparser.ParseFields(args[1:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	res, err := c.hashStore.ExpireTimes(args[0], fields)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	perMs := int64(unit / time.Millisecond)
+	now := time.Now().UnixMilli()
+
+	for i, at := range res {
+		if at < 0 {
+			continue
+		}
+
+		if absolute {
+			res[i] = at / perMs
+			continue
+		}
+
+		ttl := at - now
+		if ttl < 0 {
+			ttl = 0
+		}
+
+		// round up, so that a field about to expire has a TTL of 1 second
+		res[i] = (ttl + perMs - 1) / perMs
+	}
+
+	return integerArray(res)
+}
+
+func (c *HashCommands) getAll(key string, withFields, withValues bool) payload.Reply {
+	fields, err := c.hashStore.GetAll(key)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return fieldValueArray(fields, withFields, withValues)
+}
+
+// fieldValuePairs groups the field value arguments of HSET and HMSET.
+func fieldValuePairs(command string, args []string) ([]store.FieldValue, payload.Reply) {
+	if len(args) == 0 || len(args)%2 != 0 {
+		return nil, payload.Error(fmt.Sprintf("ERR wrong number of arguments for '%s' command", command))
+	}
+
+	pairs := make([]store.FieldValue, 0, len(args)/2)
+	for i := 0; i < len(args); i += 2 {
+		pairs = append(pairs, store.FieldValue{Field: args[i], Value: args[i+1]})
+	}
+
+	return pairs, nil
+}
+
+func fieldValueArray(fields []store.FieldValue, withFields, withValues bool) payload.Reply {
+	values := make([]string, 0, 2*len(fields))
+
+	for _, field := range fields {
+		if withFields {
+			values = append(values, field.Field)
+		}
+
+		if withValues {
+			values = append(values, field.Value)
+		}
+	}
+
+	return payload.BulkStrings(values)
+}
+
+func integerArray(values []int64) payload.Reply {
+	elements := make([]payload.Reply, len(values))
+	for i, value := range values {
+		elements[i] = payload.Integer(value)
+	}
+
+	return payload.Array(elements)
+}
+
+func boolToInt(b bool) int64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
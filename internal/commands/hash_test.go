@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/config"
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+	"github.com/codecrafters-io/redis-starter-go/internal/store"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashCommands_ExpireTimes(t *testing.T) {
+	c := NewHashCommands(store.NewHash(store.NewKVStore(), config.New()))
+	ctx := &Context{}
+
+	c.HSet(ctx, []string{"hash", "a", "1", "b", "2"})
+
+	// 2100-01-01T00:00:00.500Z
+	assert.Equal(t, payload.Array{payload.Integer(1)}, c.HPExpireAt(ctx, []string{"hash", "4102444800500", "FIELDS", "1", "a"}))
+
+	testCases := map[string]struct {
+		handler  Handler
+		expected payload.Reply
+	}{
+		"when absolute seconds are asked": {
+			handler:  c.HExpireTime,
+			expected: payload.Array{payload.Integer(4102444800), payload.Integer(-1)},
+		},
+		"when absolute milliseconds are asked": {
+			handler:  c.HPExpireTime,
+			expected: payload.Array{payload.Integer(4102444800500), payload.Integer(-1)},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.handler(ctx, []string{"hash", "FIELDS", "2", "a", "b"}))
+		})
+	}
+
+	t.Run("when the TTL is less than a second", func(t *testing.T) {
+		c.HPExpire(ctx, []string{"hash", "500", "FIELDS", "1", "b"})
+
+		assert.Equal(t, payload.Array{payload.Integer(1)}, c.HTTL(ctx, []string{"hash", "FIELDS", "1", "b"}))
+	})
+}
@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+	"github.com/codecrafters-io/redis-starter-go/internal/store"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/hll"
+)
+
+type HyperLogLogCommands struct {
+	hllStore *store.HyperLogLog
+}
+
+func NewHyperLogLogCommands(hllStore *store.HyperLogLog) *HyperLogLogCommands {
+	return &HyperLogLogCommands{
+		hllStore: hllStore,
+	}
+}
+
+// PFAdd runs PFADD key [element [element ...]]
+func (c *HyperLogLogCommands) PFAdd(ctx *Context, args []string) payload.Reply {
+	changed, err := c.hllStore.Add(args[0], args[1:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(boolToInt(changed))
+}
+
+// PFCount runs PFCOUNT key [key ...]
+func (c *HyperLogLogCommands) PFCount(ctx *Context, args []string) payload.Reply {
+	count, err := c.hllStore.Count(args)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(int64(count))
+}
+
+// PFMerge runs PFMERGE destkey [sourcekey [sourcekey ...]]
+func (c *HyperLogLogCommands) PFMerge(ctx *Context, args []string) payload.Reply {
+	if err := c.hllStore.Merge(args[0], args[1:]); err != nil {
+		return errorReply(err)
+	}
+
+	return payload.SimpleString("OK")
+}
+
+// PFDebug runs PFDEBUG GETREG|DECODE|ENCODING|TODENSE key
+func (c *HyperLogLogCommands) PFDebug(ctx *Context, args []string) payload.Reply {
+	key := args[1]
+
+	switch strings.ToUpper(args[0]) {
+	case "GETREG":
+		registers, err := c.hllStore.Registers(key)
+		if err != nil {
+			return errorReply(err)
+		}
+
+		values := make([]int64, len(registers))
+		for i, value := range registers {
+			values[i] = int64(value)
+		}
+
+		return integerArray(values)
+	case "DECODE":
+		decoded, err := c.hllStore.Decode(key)
+		if err != nil {
+			return errorReply(err)
+		}
+
+		return payload.BulkString(decoded)
+	case "ENCODING":
+		encoding, err := c.hllStore.Encoding(key)
+		if err != nil {
+			return errorReply(err)
+		}
+
+		return payload.SimpleString(encoding)
+	case "TODENSE":
+		converted, err := c.hllStore.ToDense(key)
+		if err != nil {
+			return errorReply(err)
+		}
+
+		return payload.Integer(boolToInt(converted))
+	}
+
+	return errorReply(fmt.Errorf("ERR Unknown PFDEBUG subcommand '%s'", args[0]))
+}
+
+// PFSelfTest runs PFSELFTEST
+func (c *HyperLogLogCommands) PFSelfTest(ctx *Context, args []string) payload.Reply {
+	if err := hll.SelfTest(); err != nil {
+		return errorReply(err)
+	}
+
+	return payload.SimpleString("OK")
+}
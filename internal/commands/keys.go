@@ -0,0 +1,164 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/blocking"
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/keyparser"
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/scanparser"
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+	"github.com/codecrafters-io/redis-starter-go/internal/store"
+)
+
+// typeNames are the types SCAN can filter the keys by.
+var typeNames = map[string]bool{
+	"string": true,
+	"list":   true,
+	"set":    true,
+	"zset":   true,
+	"hash":   true,
+	"stream": true,
+}
+
+// KeyCommands are the commands working on keys of any type, and on the
+// databases themselves. They run against the database of index db.
+type KeyCommands struct {
+	kvStore  *store.KVStore
+	blocking *blocking.Manager
+
+	// dbs and blockingManagers are those of every database, for the commands
+	// working across databases.
+	dbs              []*store.KVStore
+	blockingManagers []*blocking.Manager
+}
+
+func NewKeyCommands(dbs []*store.KVStore, blockingManagers []*blocking.Manager, db int) *KeyCommands {
+	return &KeyCommands{
+		kvStore:          dbs[db],
+		blocking:         blockingManagers[db],
+		dbs:              dbs,
+		blockingManagers: blockingManagers,
+	}
+}
+
+// Del runs DEL key [key ...]
+func (c *KeyCommands) Del(ctx *Context, args []string) payload.Reply {
+	return payload.Integer(int64(c.kvStore.Del(args)))
+}
+
+// Unlink runs UNLINK key [key ...], which is the same as DEL since the
+// values are freed by the garbage collector anyway.
+func (c *KeyCommands) Unlink(ctx *Context, args []string) payload.Reply {
+	return payload.Integer(int64(c.kvStore.Del(args)))
+}
+
+// Exists runs EXISTS key [key ...]
+func (c *KeyCommands) Exists(ctx *Context, args []string) payload.Reply {
+	return payload.Integer(int64(c.kvStore.Exists(args)))
+}
+
+// Keys runs KEYS pattern
+func (c *KeyCommands) Keys(ctx *Context, args []string) payload.Reply {
+	return payload.BulkStrings(c.kvStore.Keys(args[0]))
+}
+
+// Rename runs RENAME key newkey
+func (c *KeyCommands) Rename(ctx *Context, args []string) payload.Reply {
+	if err := c.kvStore.Rename(args[0], args[1]); err != nil {
+		return errorReply(err)
+	}
+
+	c.blocking.SignalKeyAsReady(args[1])
+
+	return payload.SimpleString("OK")
+}
+
+// RenameNX runs RENAMENX key newkey
+func (c *KeyCommands) RenameNX(ctx *Context, args []string) payload.Reply {
+	renamed, err := c.kvStore.RenameNX(args[0], args[1])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	if renamed {
+		c.blocking.SignalKeyAsReady(args[1])
+	}
+
+	return payload.Integer(boolToInt(renamed))
+}
+
+// Copy runs COPY source destination [DB destination-db] [REPLACE]
+func (c *KeyCommands) Copy(ctx *Context, args []string) payload.Reply {
+	copyArgs, err := keyparser.ParseCopyArgs(args[2:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	db := ctx.DB
+	if copyArgs.HasDB {
+		if copyArgs.DB < 0 || copyArgs.DB >= len(c.dbs) {
+			return errorReply(errDBIndexOutOfRange)
+		}
+
+		db = copyArgs.DB
+	}
+
+	copied, err := c.kvStore.Copy(args[0], c.dbs[db], args[1], copyArgs.Replace)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	if copied {
+		c.blockingManagers[db].SignalKeyAsReady(args[1])
+	}
+
+	return payload.Integer(boolToInt(copied))
+}
+
+// Touch runs TOUCH key [key ...]
+func (c *KeyCommands) Touch(ctx *Context, args []string) payload.Reply {
+	return payload.Integer(int64(c.kvStore.Touch(args)))
+}
+
+// RandomKey runs RANDOMKEY
+func (c *KeyCommands) RandomKey(ctx *Context, args []string) payload.Reply {
+	key, found := c.kvStore.RandomKey()
+	if !found {
+		return payload.Null{}
+	}
+
+	return payload.BulkString(key)
+}
+
+// DBSize runs DBSIZE
+func (c *KeyCommands) DBSize(ctx *Context, args []string) payload.Reply {
+	return payload.Integer(int64(c.kvStore.DBSize()))
+}
+
+// Scan runs SCAN cursor [MATCH pattern] [COUNT count] [TYPE type]
+func (c *KeyCommands) Scan(ctx *Context, args []string) payload.Reply {
+	options, err := scanparser.ParseScanOptions(args, "TYPE")
+	if err != nil {
+		return errorReply(err)
+	}
+
+	typ := strings.ToLower(options.Type)
+	if typ != "" && !typeNames[typ] {
+		return errorReply(fmt.Errorf("ERR unknown type name '%s'", options.Type))
+	}
+
+	cursor, keys := c.kvStore.Scan(options.Cursor, options.Match, options.Count, typ)
+
+	return scanReply(cursor, payload.BulkStrings(keys))
+}
+
+// scanReply replies with the cursor to continue from and the elements found,
+// as every SCAN command does.
+func scanReply(cursor uint64, elements payload.Reply) payload.Reply {
+	return payload.Array{
+		payload.BulkString(strconv.FormatUint(cursor, 10)),
+		elements,
+	}
+}
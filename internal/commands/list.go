@@ -0,0 +1,478 @@
+package commands
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/blocking"
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/listparser"
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+	"github.com/codecrafters-io/redis-starter-go/internal/store"
+)
+
+var errNotPositive = errors.New("ERR value is out of range, must be positive")
+
+type ListCommands struct {
+	listStore *store.List
+	blocking  *blocking.Manager
+}
+
+func NewListCommands(listStore *store.List, blockingManager *blocking.Manager) *ListCommands {
+	return &ListCommands{
+		listStore: listStore,
+		blocking:  blockingManager,
+	}
+}
+
+// LPush runs LPUSH key element [element ...]
+func (c *ListCommands) LPush(ctx *Context, args []string) payload.Reply {
+	return c.push(args, store.Left, false)
+}
+
+// RPush runs RPUSH key element [element ...]
+func (c *ListCommands) RPush(ctx *Context, args []string) payload.Reply {
+	return c.push(args, store.Right, false)
+}
+
+// LPushX runs LPUSHX key element [element ...]
+func (c *ListCommands) LPushX(ctx *Context, args []string) payload.Reply {
+	return c.push(args, store.Left, true)
+}
+
+// RPushX runs RPUSHX key element [element ...]
+func (c *ListCommands) RPushX(ctx *Context, args []string) payload.Reply {
+	return c.push(args, store.Right, true)
+}
+
+// LPop runs LPOP key [count]
+func (c *ListCommands) LPop(ctx *Context, args []string) payload.Reply {
+	return c.pop(args, store.Left)
+}
+
+// RPop runs RPOP key [count]
+func (c *ListCommands) RPop(ctx *Context, args []string) payload.Reply {
+	return c.pop(args, store.Right)
+}
+
+// LLen runs LLEN key
+func (c *ListCommands) LLen(ctx *Context, args []string) payload.Reply {
+	length, err := c.listStore.Len(args[0])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(int64(length))
+}
+
+// LRange runs LRANGE key start stop
+func (c *ListCommands) LRange(ctx *Context, args []string) payload.Reply {
+	start, err := argparser.ParseInt(args[1])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	stop, err := argparser.ParseInt(args[2])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	values, err := c.listStore.Range(args[0], start, stop)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.BulkStrings(values)
+}
+
+// LIndex runs LINDEX key index
+func (c *ListCommands) LIndex(ctx *Context, args []string) payload.Reply {
+	index, err := argparser.ParseInt(args[1])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	value, found, err := c.listStore.Index(args[0], index)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	if !found {
+		return payload.Null{}
+	}
+
+	return payload.BulkString(value)
+}
+
+// LSet runs LSET key index element
+func (c *ListCommands) LSet(ctx *Context, args []string) payload.Reply {
+	index, err := argparser.ParseInt(args[1])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	if err := c.listStore.Set(args[0], index, args[2]); err != nil {
+		return errorReply(err)
+	}
+
+	return payload.SimpleString("OK")
+}
+
+// LRem runs LREM key count element
+func (c *ListCommands) LRem(ctx *Context, args []string) payload.Reply {
+	count, err := argparser.ParseInt(args[1])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	removed, err := c.listStore.Rem(args[0], count, args[2])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(int64(removed))
+}
+
+// LTrim runs LTRIM key start stop
+func (c *ListCommands) LTrim(ctx *Context, args []string) payload.Reply {
+	start, err := argparser.ParseInt(args[1])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	stop, err := argparser.ParseInt(args[2])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	if err := c.listStore.Trim(args[0], start, stop); err != nil {
+		return errorReply(err)
+	}
+
+	return payload.SimpleString("OK")
+}
+
+// LInsert runs LINSERT key BEFORE|AFTER pivot element
+func (c *ListCommands) LInsert(ctx *Context, args []string) payload.Reply {
+	var side store.ListSide
+
+	switch strings.ToUpper(args[1]) {
+	case "BEFORE":
+		side = store.Left
+	case "AFTER":
+		side = store.Right
+	default:
+		return errorReply(argparser.ErrSyntax)
+	}
+
+	length, err := c.listStore.Insert(args[0], side, args[2], args[3])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(int64(length))
+}
+
+// LPos runs LPOS key element [RANK rank] [COUNT num-matches] [MAXLEN len]
+func (c *ListCommands) LPos(ctx *Context, args []string) payload.Reply {
+	options, err := listparser.ParseLPosOptions(args[2:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	count := options.Count
+	if !options.HasCount {
+		count = 1
+	}
+
+	indexes, err := c.listStore.Pos(args[0], args[1], options.Rank, count, options.MaxLen)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	if !options.HasCount {
+		if len(indexes) == 0 {
+			return payload.Null{}
+		}
+
+		return payload.Integer(int64(indexes[0]))
+	}
+
+	elements := make([]payload.Reply, 0, len(indexes))
+	for _, index := range indexes {
+		elements = append(elements, payload.Integer(int64(index)))
+	}
+
+	return payload.Array(elements)
+}
+
+// BLPop runs BLPOP key [key ...] timeout
+func (c *ListCommands) BLPop(ctx *Context, args []string) payload.Reply {
+	return c.blockingPop(ctx, args, store.Left)
+}
+
+// BRPop runs BRPOP key [key ...] timeout
+func (c *ListCommands) BRPop(ctx *Context, args []string) payload.Reply {
+	return c.blockingPop(ctx, args, store.Right)
+}
+
+// LMove runs LMOVE source destination LEFT|RIGHT LEFT|RIGHT
+func (c *ListCommands) LMove(ctx *Context, args []string) payload.Reply {
+	from, to, err := parseMoveSides(args[2], args[3])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	reply, _, err := c.move(args[0], args[1], from, to)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return reply
+}
+
+// BLMove runs BLMOVE source destination LEFT|RIGHT LEFT|RIGHT timeout
+func (c *ListCommands) BLMove(ctx *Context, args []string) payload.Reply {
+	source, destination := args[0], args[1]
+
+	from, to, err := parseMoveSides(args[2], args[3])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	timeout, err := argparser.ParseTimeout(args[4])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	reply, moved, err := c.move(source, destination, from, to)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	if moved || ctx.InMulti {
+		return reply
+	}
+
+	w := c.blocking.Block([]string{source}, func(string) (payload.Reply, bool) {
+		reply, moved, _ := c.move(source, destination, from, to)
+		return reply, moved
+	})
+	ctx.block(w, timeout, payload.Null{})
+
+	return nil
+}
+
+// LMPop runs LMPOP numkeys key [key ...] LEFT|RIGHT [COUNT count]
+func (c *ListCommands) LMPop(ctx *Context, args []string) payload.Reply {
+	mpopArgs, err := listparser.ParseLMPopArgs(args)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	for _, key := range mpopArgs.Keys {
+		reply, popped, err := c.mpop(key, mpopArgs)
+		if err != nil {
+			return errorReply(err)
+		}
+
+		if popped {
+			return reply
+		}
+	}
+
+	return payload.NullArray{}
+}
+
+// BLMPop runs BLMPOP timeout numkeys key [key ...] LEFT|RIGHT [COUNT count]
+func (c *ListCommands) BLMPop(ctx *Context, args []string) payload.Reply {
+	timeout, err := argparser.ParseTimeout(args[0])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	mpopArgs, err := listparser.ParseLMPopArgs(args[1:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	for _, key := range mpopArgs.Keys {
+		reply, popped, err := c.mpop(key, mpopArgs)
+		if err != nil {
+			return errorReply(err)
+		}
+
+		if popped {
+			return reply
+		}
+	}
+
+	if ctx.InMulti {
+		return payload.NullArray{}
+	}
+
+	w := c.blocking.Block(mpopArgs.Keys, func(key string) (payload.Reply, bool) {
+		reply, popped, _ := c.mpop(key, mpopArgs)
+		return reply, popped
+	})
+	ctx.block(w, timeout, payload.NullArray{})
+
+	return nil
+}
+
+// blockingPop pops an element from the first non empty list, blocking until
+// one of the lists gets an element if they're all empty.
+func (c *ListCommands) blockingPop(ctx *Context, args []string, side store.ListSide) payload.Reply {
+	keys := args[:len(args)-1]
+
+	timeout, err := argparser.ParseTimeout(args[len(args)-1])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	for _, key := range keys {
+		reply, popped, err := c.popWithKey(key, side)
+		if err != nil {
+			return errorReply(err)
+		}
+
+		if popped {
+			return reply
+		}
+	}
+
+	if ctx.InMulti {
+		return payload.NullArray{}
+	}
+
+	w := c.blocking.Block(keys, func(key string) (payload.Reply, bool) {
+		reply, popped, _ := c.popWithKey(key, side)
+		return reply, popped
+	})
+	ctx.block(w, timeout, payload.NullArray{})
+
+	return nil
+}
+
+// popWithKey pops a single element, replying with both the key and the
+// element as BLPOP does.
+func (c *ListCommands) popWithKey(key string, side store.ListSide) (payload.Reply, bool, error) {
+	values, err := c.listStore.Pop(key, 1, side)
+	if err != nil || len(values) == 0 {
+		return nil, false, err
+	}
+
+	return payload.BulkStrings([]string{key, values[0]}), true, nil
+}
+
+// mpop pops the elements of LMPOP and BLMPOP from the given key, replying
+// with the key and the array of popped elements.
+func (c *ListCommands) mpop(key string, args *listparser.LMPopArgs) (payload.Reply, bool, error) {
+	side := store.Right
+	if args.Left {
+		side = store.Left
+	}
+
+	values, err := c.listStore.Pop(key, args.Count, side)
+	if err != nil || len(values) == 0 {
+		return nil, false, err
+	}
+
+	return payload.Array{
+		payload.BulkString(key),
+		payload.BulkStrings(values),
+	}, true, nil
+}
+
+func (c *ListCommands) move(source, destination string, from, to store.ListSide) (payload.Reply, bool, error) {
+	value, moved, err := c.listStore.Move(source, destination, from, to)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !moved {
+		return payload.Null{}, false, nil
+	}
+
+	c.blocking.SignalKeyAsReady(destination)
+
+	return payload.BulkString(value), true, nil
+}
+
+func parseMoveSides(from, to string) (store.ListSide, store.ListSide, error) {
+	fromSide, err := parseListSide(from)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	toSide, err := parseListSide(to)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return fromSide, toSide, nil
+}
+
+func parseListSide(side string) (store.ListSide, error) {
+	switch strings.ToUpper(side) {
+	case "LEFT":
+		return store.Left, nil
+	case "RIGHT":
+		return store.Right, nil
+	}
+
+	return 0, argparser.ErrSyntax
+}
+
+func (c *ListCommands) push(args []string, side store.ListSide, onlyIfExists bool) payload.Reply {
+	length, err := c.listStore.Push(args[0], args[1:], side, onlyIfExists)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	if length > 0 {
+		c.blocking.SignalKeyAsReady(args[0])
+	}
+
+	return payload.Integer(int64(length))
+}
+
+func (c *ListCommands) pop(args []string, side store.ListSide) payload.Reply {
+	if len(args) > 2 {
+		return errorReply(argparser.ErrSyntax)
+	}
+
+	count := 1
+	if len(args) == 2 {
+		var err error
+
+		count, err = argparser.ParseInt(args[1])
+		if err != nil {
+			return errorReply(err)
+		}
+
+		if count < 0 {
+			return errorReply(errNotPositive)
+		}
+	}
+
+	values, err := c.listStore.Pop(args[0], count, side)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	// without count a single element is returned instead of an array
+	if len(args) == 1 {
+		if len(values) == 0 {
+			return payload.Null{}
+		}
+
+		return payload.BulkString(values[0])
+	}
+
+	if values == nil {
+		return payload.NullArray{}
+	}
+
+	return payload.BulkStrings(values)
+}
@@ -0,0 +1,174 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+	"github.com/codecrafters-io/redis-starter-go/internal/pubsub"
+)
+
+// PubSubCommands are the commands of the Pub/Sub messaging. Subscriptions are
+// those of ctx.Subscriber, and aren't tied to a database.
+type PubSubCommands struct {
+	hub *pubsub.Hub
+}
+
+func NewPubSubCommands(hub *pubsub.Hub) *PubSubCommands {
+	return &PubSubCommands{
+		hub: hub,
+	}
+}
+
+// Subscribe runs SUBSCRIBE channel [channel ...]
+func (c *PubSubCommands) Subscribe(ctx *Context, args []string) payload.Reply {
+	replies := payload.Sequence{}
+	for _, channel := range args {
+		c.hub.Subscribe(ctx.Subscriber, channel)
+		replies = append(replies, subscriptionReply("subscribe", []byte(channel), c.hub.Count(ctx.Subscriber)))
+	}
+
+	return replies
+}
+
+// PSubscribe runs PSUBSCRIBE pattern [pattern ...]
+func (c *PubSubCommands) PSubscribe(ctx *Context, args []string) payload.Reply {
+	replies := payload.Sequence{}
+	for _, pattern := range args {
+		c.hub.PSubscribe(ctx.Subscriber, pattern)
+		replies = append(replies, subscriptionReply("psubscribe", []byte(pattern), c.hub.Count(ctx.Subscriber)))
+	}
+
+	return replies
+}
+
+// SSubscribe runs SSUBSCRIBE shardchannel [shardchannel ...]
+func (c *PubSubCommands) SSubscribe(ctx *Context, args []string) payload.Reply {
+	replies := payload.Sequence{}
+	for _, channel := range args {
+		c.hub.SSubscribe(ctx.Subscriber, channel)
+		replies = append(replies, subscriptionReply("ssubscribe", []byte(channel), c.hub.ShardCount(ctx.Subscriber)))
+	}
+
+	return replies
+}
+
+// Unsubscribe runs UNSUBSCRIBE [channel [channel ...]]
+func (c *PubSubCommands) Unsubscribe(ctx *Context, args []string) payload.Reply {
+	if len(args) == 0 {
+		args = c.hub.Channels(ctx.Subscriber)
+		if len(args) == 0 {
+			return subscriptionReply("unsubscribe", nil, c.hub.Count(ctx.Subscriber))
+		}
+	}
+
+	replies := payload.Sequence{}
+	for _, channel := range args {
+		c.hub.Unsubscribe(ctx.Subscriber, channel)
+		replies = append(replies, subscriptionReply("unsubscribe", []byte(channel), c.hub.Count(ctx.Subscriber)))
+	}
+
+	return replies
+}
+
+// PUnsubscribe runs PUNSUBSCRIBE [pattern [pattern ...]]
+func (c *PubSubCommands) PUnsubscribe(ctx *Context, args []string) payload.Reply {
+	if len(args) == 0 {
+		args = c.hub.Patterns(ctx.Subscriber)
+		if len(args) == 0 {
+			return subscriptionReply("punsubscribe", nil, c.hub.Count(ctx.Subscriber))
+		}
+	}
+
+	replies := payload.Sequence{}
+	for _, pattern := range args {
+		c.hub.PUnsubscribe(ctx.Subscriber, pattern)
+		replies = append(replies, subscriptionReply("punsubscribe", []byte(pattern), c.hub.Count(ctx.Subscriber)))
+	}
+
+	return replies
+}
+
+// SUnsubscribe runs SUNSUBSCRIBE [shardchannel [shardchannel ...]]
+func (c *PubSubCommands) SUnsubscribe(ctx *Context, args []string) payload.Reply {
+	if len(args) == 0 {
+		args = c.hub.ShardChannels(ctx.Subscriber)
+		if len(args) == 0 {
+			return subscriptionReply("sunsubscribe", nil, c.hub.ShardCount(ctx.Subscriber))
+		}
+	}
+
+	replies := payload.Sequence{}
+	for _, channel := range args {
+		c.hub.SUnsubscribe(ctx.Subscriber, channel)
+		replies = append(replies, subscriptionReply("sunsubscribe", []byte(channel), c.hub.ShardCount(ctx.Subscriber)))
+	}
+
+	return replies
+}
+
+// Publish runs PUBLISH channel message
+func (c *PubSubCommands) Publish(ctx *Context, args []string) payload.Reply {
+	return payload.Integer(int64(c.hub.Publish(args[0], []byte(args[1]))))
+}
+
+// SPublish runs SPUBLISH shardchannel message
+func (c *PubSubCommands) SPublish(ctx *Context, args []string) payload.Reply {
+	return payload.Integer(int64(c.hub.SPublish(args[0], []byte(args[1]))))
+}
+
+// PubSub runs PUBSUB CHANNELS [pattern], PUBSUB NUMSUB [channel ...],
+// PUBSUB NUMPAT, PUBSUB SHARDCHANNELS [pattern] and
+// PUBSUB SHARDNUMSUB [shardchannel ...]
+func (c *PubSubCommands) PubSub(ctx *Context, args []string) payload.Reply {
+	subcommand := strings.ToUpper(args[0])
+
+	switch {
+	case subcommand == "CHANNELS" && len(args) <= 2:
+		return payload.BulkStrings(c.hub.ActiveChannels(optionalPattern(args)))
+	case subcommand == "SHARDCHANNELS" && len(args) <= 2:
+		return payload.BulkStrings(c.hub.ActiveShardChannels(optionalPattern(args)))
+	case subcommand == "NUMSUB":
+		return numSubReply(args[1:], c.hub.NumSub)
+	case subcommand == "SHARDNUMSUB":
+		return numSubReply(args[1:], c.hub.ShardNumSub)
+	case subcommand == "NUMPAT" && len(args) == 1:
+		return payload.Integer(int64(c.hub.NumPat()))
+	case subcommand == "CHANNELS" || subcommand == "SHARDCHANNELS" || subcommand == "NUMPAT":
+		return payload.Error(fmt.Sprintf("ERR wrong number of arguments for 'pubsub|%s' command", strings.ToLower(args[0])))
+	}
+
+	return payload.Error(fmt.Sprintf("ERR unknown subcommand '%s'. Try PUBSUB HELP.", args[0]))
+}
+
+func optionalPattern(args []string) string {
+	if len(args) == 2 {
+		return args[1]
+	}
+
+	return ""
+}
+
+// numSubReply replies with every channel followed by its number of
+// subscribers, as a map over RESP3.
+func numSubReply(channels []string, numSub func(channel string) int) payload.Reply {
+	res := make(payload.Map, len(channels))
+	for i, channel := range channels {
+		res[i] = payload.MapEntry{Key: payload.BulkString(channel), Value: payload.Integer(numSub(channel))}
+	}
+
+	return res
+}
+
+// subscriptionReply confirms a subscription change with the number of
+// subscriptions left. name is nil when there was nothing to unsubscribe
+// from. Over RESP3, the confirmation is a push message as the messages
+// themselves.
+func subscriptionReply(kind string, name []byte, count int) payload.Reply {
+	var nameReply payload.Reply = payload.Null{}
+	if name != nil {
+		nameReply = payload.BulkString(name)
+	}
+
+	return payload.Push{payload.BulkString(kind), nameReply, payload.Integer(count)}
+}
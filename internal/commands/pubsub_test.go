@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/cluster"
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+	"github.com/codecrafters-io/redis-starter-go/internal/pubsub"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// subscriber records the messages it's sent
+type subscriber struct {
+	received []payload.Push
+}
+
+func (s *subscriber) Send(msg payload.Push) {
+	s.received = append(s.received, msg)
+}
+
+func TestPubSubCommands_SSubscribeAcrossSlots(t *testing.T) {
+	c := NewPubSubCommands(pubsub.NewHub())
+	s := &subscriber{}
+	ctx := &Context{Subscriber: s}
+
+	require.NotEqual(t, cluster.KeySlot("orders"), cluster.KeySlot("users"))
+
+	assert.Equal(t, payload.Sequence{
+		payload.Push{payload.BulkString("ssubscribe"), payload.BulkString("orders"), payload.Integer(1)},
+		payload.Push{payload.BulkString("ssubscribe"), payload.BulkString("users"), payload.Integer(2)},
+	}, c.SSubscribe(ctx, []string{"orders", "users"}))
+
+	assert.Equal(t, payload.Integer(1), c.SPublish(ctx, []string{"orders", "new"}))
+	assert.Equal(t, payload.Integer(1), c.SPublish(ctx, []string{"users", "joined"}))
+	assert.Equal(t, []payload.Push{
+		{payload.BulkString("smessage"), payload.BulkString("orders"), payload.BulkString("new")},
+		{payload.BulkString("smessage"), payload.BulkString("users"), payload.BulkString("joined")},
+	}, s.received)
+
+	assert.Equal(t, payload.Sequence{
+		payload.Push{payload.BulkString("sunsubscribe"), payload.BulkString("orders"), payload.Integer(1)},
+		payload.Push{payload.BulkString("sunsubscribe"), payload.BulkString("users"), payload.Integer(0)},
+	}, c.SUnsubscribe(ctx, []string{"orders", "users"}))
+}
@@ -0,0 +1,247 @@
+package commands
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/scanparser"
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/setparser"
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+	"github.com/codecrafters-io/redis-starter-go/internal/store"
+)
+
+type SetCommands struct {
+	setStore *store.Set
+}
+
+func NewSetCommands(setStore *store.Set) *SetCommands {
+	return &SetCommands{
+		setStore: setStore,
+	}
+}
+
+// SAdd runs SADD key member [member ...]
+func (c *SetCommands) SAdd(ctx *Context, args []string) payload.Reply {
+	added, err := c.setStore.Add(args[0], args[1:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(int64(added))
+}
+
+// SRem runs SREM key member [member ...]
+func (c *SetCommands) SRem(ctx *Context, args []string) payload.Reply {
+	removed, err := c.setStore.Rem(args[0], args[1:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(int64(removed))
+}
+
+// SIsMember runs SISMEMBER key member
+func (c *SetCommands) SIsMember(ctx *Context, args []string) payload.Reply {
+	isMember, err := c.setStore.IsMember(args[0], args[1:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(boolToInt(isMember[0]))
+}
+
+// SMIsMember runs SMISMEMBER key member [member ...]
+func (c *SetCommands) SMIsMember(ctx *Context, args []string) payload.Reply {
+	isMember, err := c.setStore.IsMember(args[0], args[1:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	res := make([]int64, len(isMember))
+	for i, found := range isMember {
+		res[i] = boolToInt(found)
+	}
+
+	return integerArray(res)
+}
+
+// SMembers runs SMEMBERS key
+func (c *SetCommands) SMembers(ctx *Context, args []string) payload.Reply {
+	members, err := c.setStore.Members(args[0])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return membersReply(members)
+}
+
+// SScan runs SSCAN key cursor [MATCH pattern] [COUNT count]
+func (c *SetCommands) SScan(ctx *Context, args []string) payload.Reply {
+	options, err := scanparser.ParseScanOptions(args[1:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	cursor, members, err := c.setStore.Scan(args[0], options.Cursor, options.Match, options.Count)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return scanReply(cursor, payload.BulkStrings(members))
+}
+
+// SCard runs SCARD key
+func (c *SetCommands) SCard(ctx *Context, args []string) payload.Reply {
+	count, err := c.setStore.Card(args[0])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(int64(count))
+}
+
+// SPop runs SPOP key [count]
+func (c *SetCommands) SPop(ctx *Context, args []string) payload.Reply {
+	if len(args) > 2 {
+		return errorReply(argparser.ErrSyntax)
+	}
+
+	count := 1
+	if len(args) == 2 {
+		var err error
+
+		count, err = argparser.ParseInt(args[1])
+		if err != nil {
+			return errorReply(err)
+		}
+
+		if count < 0 {
+			return errorReply(errNotPositive)
+		}
+	}
+
+	members, err := c.setStore.Pop(args[0], count)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	// without count a single member is returned instead of an array
+	if len(args) == 1 {
+		if len(members) == 0 {
+			return payload.Null{}
+		}
+
+		return payload.BulkString(members[0])
+	}
+
+	if members == nil {
+		members = []string{}
+	}
+
+	return payload.BulkStrings(members)
+}
+
+// SRandMember runs SRANDMEMBER key [count]
+func (c *SetCommands) SRandMember(ctx *Context, args []string) payload.Reply {
+	if len(args) > 2 {
+		return errorReply(argparser.ErrSyntax)
+	}
+
+	if len(args) == 1 {
+		members, err := c.setStore.RandMembers(args[0], 1, false)
+		if err != nil {
+			return errorReply(err)
+		}
+
+		if len(members) == 0 {
+			return payload.Null{}
+		}
+
+		return payload.BulkString(members[0])
+	}
+
+	count, err := argparser.ParseInt(args[1])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	// a negative count allows returning the same member many times
+	allowRepeat := count < 0
+	if allowRepeat {
+		count = -count
+	}
+
+	members, err := c.setStore.RandMembers(args[0], count, allowRepeat)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.BulkStrings(members)
+}
+
+// SInter runs SINTER key [key ...]
+func (c *SetCommands) SInter(ctx *Context, args []string) payload.Reply {
+	return c.combine(store.SetInter, args)
+}
+
+// SUnion runs SUNION key [key ...]
+func (c *SetCommands) SUnion(ctx *Context, args []string) payload.Reply {
+	return c.combine(store.SetUnion, args)
+}
+
+// SDiff runs SDIFF key [key ...]
+func (c *SetCommands) SDiff(ctx *Context, args []string) payload.Reply {
+	return c.combine(store.SetDiff, args)
+}
+
+// SInterStore runs SINTERSTORE destination key [key ...]
+func (c *SetCommands) SInterStore(ctx *Context, args []string) payload.Reply {
+	return c.combineStore(store.SetInter, args)
+}
+
+// SUnionStore runs SUNIONSTORE destination key [key ...]
+func (c *SetCommands) SUnionStore(ctx *Context, args []string) payload.Reply {
+	return c.combineStore(store.SetUnion, args)
+}
+
+// SDiffStore runs SDIFFSTORE destination key [key ...]
+func (c *SetCommands) SDiffStore(ctx *Context, args []string) payload.Reply {
+	return c.combineStore(store.SetDiff, args)
+}
+
+// SInterCard runs SINTERCARD numkeys key [key ...] [LIMIT limit]
+func (c *SetCommands) SInterCard(ctx *Context, args []string) payload.Reply {
+	keys, limit, err := setparser.ParseSInterCardArgs(args)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	count, err := c.setStore.InterCard(keys, limit)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(int64(count))
+}
+
+func (c *SetCommands) combine(op store.SetOperation, keys []string) payload.Reply {
+	members, err := c.setStore.Combine(op, keys)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return membersReply(members)
+}
+
+func (c *SetCommands) combineStore(op store.SetOperation, args []string) payload.Reply {
+	size, err := c.setStore.CombineStore(op, args[0], args[1:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(int64(size))
+}
+
+// membersReply replies with the members of a set, as a RESP3 set when the
+// client speaks RESP3.
+func membersReply(members []string) payload.Reply {
+	return payload.Set(payload.BulkStrings(members))
+}
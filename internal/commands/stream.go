@@ -0,0 +1,182 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/blocking"
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/streamparser"
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+	"github.com/codecrafters-io/redis-starter-go/internal/store"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/stream"
+)
+
+type StreamCommands struct {
+	streamStore *store.Stream
+	blocking    *blocking.Manager
+}
+
+func NewStreamCommands(streamStore *store.Stream, blockingManager *blocking.Manager) *StreamCommands {
+	return &StreamCommands{
+		streamStore: streamStore,
+		blocking:    blockingManager,
+	}
+}
+
+// XAdd runs XADD key id field value [field value ...]
+func (c *StreamCommands) XAdd(ctx *Context, args []string) payload.Reply {
+	key := args[0]
+	id := args[1]
+
+	res, err := c.streamStore.XAdd(key, id, args[2:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	c.blocking.SignalKeyAsReady(key)
+
+	return payload.SimpleString(res)
+}
+
+// XRange runs XRANGE key start end
+func (c *StreamCommands) XRange(ctx *Context, args []string) payload.Reply {
+	entries, err := c.streamStore.XRange(args[0], args[1], args[2])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return streamEntriesReply(entries)
+}
+
+// XLen runs XLEN key
+func (c *StreamCommands) XLen(ctx *Context, args []string) payload.Reply {
+	length, err := c.streamStore.Len(args[0])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(length)
+}
+
+// XInfo runs XINFO STREAM key
+func (c *StreamCommands) XInfo(ctx *Context, args []string) payload.Reply {
+	if strings.ToUpper(args[0]) != "STREAM" {
+		return payload.Error(fmt.Sprintf("ERR unknown subcommand '%s'. Try XINFO HELP.", args[0]))
+	}
+
+	if len(args) != 2 {
+		return payload.Error("ERR wrong number of arguments for 'xinfo|stream' command")
+	}
+
+	info, err := c.streamStore.Info(args[1])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	firstID := stream.MinID.String()
+	if info.FirstEntry != nil {
+		firstID = info.FirstEntry.ID
+	}
+
+	// there are no consumer groups, nor deleted entries
+	return payload.Map{
+		{Key: payload.BulkString("length"), Value: payload.Integer(info.Length)},
+		{Key: payload.BulkString("radix-tree-keys"), Value: payload.Integer(info.Blocks)},
+		{Key: payload.BulkString("last-generated-id"), Value: payload.BulkString(info.LastID)},
+		{Key: payload.BulkString("max-deleted-entry-id"), Value: payload.BulkString(stream.MinID.String())},
+		{Key: payload.BulkString("entries-added"), Value: payload.Integer(info.Length)},
+		{Key: payload.BulkString("recorded-first-entry-id"), Value: payload.BulkString(firstID)},
+		{Key: payload.BulkString("groups"), Value: payload.Integer(0)},
+		{Key: payload.BulkString("first-entry"), Value: streamEntryReply(info.FirstEntry)},
+		{Key: payload.BulkString("last-entry"), Value: streamEntryReply(info.LastEntry)},
+	}
+}
+
+// XRead runs XREAD [COUNT count] [BLOCK milliseconds] STREAMS key [key ...] id [id ...]
+func (c *StreamCommands) XRead(ctx *Context, args []string) payload.Reply {
+	options, err := streamparser.ParseXReadOptions(args)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	// $ stands for the last ID of the stream at the time XREAD is called
+	ids := make([]string, len(options.IDs))
+	for i, id := range options.IDs {
+		ids[i] = id
+		if id != "$" {
+			continue
+		}
+
+		ids[i], err = c.streamStore.LastID(options.Keys[i])
+		if err != nil {
+			return errorReply(err)
+		}
+	}
+
+	reply, found, err := c.read(ctx, options.Keys, ids, options.Count)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	if found || !options.HasBlock || ctx.InMulti {
+		return reply
+	}
+
+	w := c.blocking.Block(options.Keys, func(string) (payload.Reply, bool) {
+		reply, found, err := c.read(ctx, options.Keys, ids, options.Count)
+		return reply, found && err == nil
+	})
+	ctx.block(w, options.Block, payload.NullArray{})
+
+	return nil
+}
+
+// read replies with the entries of every stream, mapped by the key of their
+// stream over RESP3.
+func (c *StreamCommands) read(ctx *Context, keys, ids []string, count int) (payload.Reply, bool, error) {
+	res, err := c.streamStore.XRead(keys, ids, count)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if len(res) == 0 {
+		return payload.NullArray{}, false, nil
+	}
+
+	if ctx.RESP3() {
+		streams := make(payload.Map, len(res))
+		for i, read := range res {
+			streams[i] = payload.MapEntry{Key: payload.BulkString(read.Key), Value: streamEntriesReply(read.Entries)}
+		}
+
+		return streams, true, nil
+	}
+
+	streams := make(payload.Array, len(res))
+	for i, read := range res {
+		streams[i] = payload.Array{payload.BulkString(read.Key), streamEntriesReply(read.Entries)}
+	}
+
+	return streams, true, nil
+}
+
+// streamEntriesReply replies with every entry as its ID followed by its
+// fields and values.
+func streamEntriesReply(entries []*stream.Data) payload.Array {
+	res := make(payload.Array, len(entries))
+	for i, entry := range entries {
+		res[i] = streamEntryReply(entry)
+	}
+
+	return res
+}
+
+// streamEntryReply replies with the ID of the entry followed by its fields
+// and values, or null if there is no entry.
+func streamEntryReply(entry *stream.Data) payload.Reply {
+	if entry == nil {
+		return payload.Null{}
+	}
+
+	return payload.Array{payload.BulkString(entry.ID), payload.BulkStrings(entry.Values)}
+}
@@ -0,0 +1,403 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/stringparser"
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+	"github.com/codecrafters-io/redis-starter-go/internal/store"
+	"github.com/codecrafters-io/redis-starter-go/internal/stringfn"
+)
+
+var (
+	errDecrOverflow     = errors.New("ERR decrement would overflow")
+	errOffsetOutOfRange = errors.New("ERR offset is out of range")
+	errLCSNotString     = errors.New("ERR The specified keys must contain string values")
+)
+
+type StringCommands struct {
+	kvStore *store.KVStore
+}
+
+func NewStringCommands(kvStore *store.KVStore) *StringCommands {
+	return &StringCommands{
+		kvStore: kvStore,
+	}
+}
+
+// Set runs SET key value [NX|XX] [GET] [EX seconds|PX milliseconds|EXAT
+// unix-time-seconds|PXAT unix-time-milliseconds|KEEPTTL]
+func (c *StringCommands) Set(ctx *Context, args []string) payload.Reply {
+	setArgs, err := stringparser.ParseSetArgs(args[2:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	opts := store.SetOptions{
+		NX:      setArgs.NX,
+		XX:      setArgs.XX,
+		Get:     setArgs.Get,
+		KeepTTL: setArgs.KeepTTL,
+	}
+
+	if setArgs.IsSet() {
+		at, ok := setArgs.At(time.Now().UnixMilli())
+		if !ok {
+			return errorReply(stringparser.InvalidExpireTime("set"))
+		}
+
+		opts.ExpireAt = at
+	}
+
+	previous, set, err := c.kvStore.SetWithOptions(args[0], []byte(args[1]), opts)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	switch {
+	case setArgs.Get && previous == nil:
+		return payload.Null{}
+	case setArgs.Get:
+		return payload.BulkString(previous)
+	case !set:
+		return payload.Null{}
+	}
+
+	return payload.SimpleString("OK")
+}
+
+// SetNX runs SETNX key value
+func (c *StringCommands) SetNX(ctx *Context, args []string) payload.Reply {
+	_, set, _ := c.kvStore.SetWithOptions(args[0], []byte(args[1]), store.SetOptions{NX: true})
+
+	return payload.Integer(boolToInt(set))
+}
+
+// SetEx runs SETEX key seconds value
+func (c *StringCommands) SetEx(ctx *Context, args []string) payload.Reply {
+	return c.setWithExpiration("setex", args, time.Second)
+}
+
+// PSetEx runs PSETEX key milliseconds value
+func (c *StringCommands) PSetEx(ctx *Context, args []string) payload.Reply {
+	return c.setWithExpiration("psetex", args, time.Millisecond)
+}
+
+// GetSet runs GETSET key value
+func (c *StringCommands) GetSet(ctx *Context, args []string) payload.Reply {
+	previous, _, err := c.kvStore.SetWithOptions(args[0], []byte(args[1]), store.SetOptions{Get: true})
+	if err != nil {
+		return errorReply(err)
+	}
+
+	if previous == nil {
+		return payload.Null{}
+	}
+
+	return payload.BulkString(previous)
+}
+
+// MSet runs MSET key value [key value ...]
+func (c *StringCommands) MSet(ctx *Context, args []string) payload.Reply {
+	keys, values, err := keyValuePairs("mset", args)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	c.kvStore.MSet(keys, values, false)
+
+	return payload.SimpleString("OK")
+}
+
+// MSetNX runs MSETNX key value [key value ...]
+func (c *StringCommands) MSetNX(ctx *Context, args []string) payload.Reply {
+	keys, values, err := keyValuePairs("msetnx", args)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(boolToInt(c.kvStore.MSet(keys, values, true)))
+}
+
+// Get runs GET key
+func (c *StringCommands) Get(ctx *Context, args []string) payload.Reply {
+	val, found, err := c.kvStore.GetString(args[0])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	if !found {
+		return payload.Null{}
+	}
+
+	return payload.BulkString(val)
+}
+
+// MGet runs MGET key [key ...]
+func (c *StringCommands) MGet(ctx *Context, args []string) payload.Reply {
+	values := c.kvStore.MGet(args)
+
+	elements := make([]payload.Reply, len(values))
+	for i, value := range values {
+		if value == nil {
+			elements[i] = payload.Null{}
+		} else {
+			elements[i] = payload.BulkString(value)
+		}
+	}
+
+	return payload.Array(elements)
+}
+
+// GetDel runs GETDEL key
+func (c *StringCommands) GetDel(ctx *Context, args []string) payload.Reply {
+	val, found, err := c.kvStore.GetDel(args[0])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	if !found {
+		return payload.Null{}
+	}
+
+	return payload.BulkString(val)
+}
+
+// GetEx runs GETEX key [EX seconds|PX milliseconds|EXAT
+// unix-time-seconds|PXAT unix-time-milliseconds|PERSIST]
+func (c *StringCommands) GetEx(ctx *Context, args []string) payload.Reply {
+	expiration, err := stringparser.ParseGetExArgs(args[1:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	var expireAt int64
+	if expiration.IsSet() {
+		var ok bool
+
+		expireAt, ok = expiration.At(time.Now().UnixMilli())
+		if !ok {
+			return errorReply(stringparser.InvalidExpireTime("getex"))
+		}
+	}
+
+	val, found, err := c.kvStore.GetEx(args[0], expireAt, expiration.Persist)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	if !found {
+		return payload.Null{}
+	}
+
+	return payload.BulkString(val)
+}
+
+// Append runs APPEND key value
+func (c *StringCommands) Append(ctx *Context, args []string) payload.Reply {
+	length, err := c.kvStore.Append(args[0], []byte(args[1]))
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(int64(length))
+}
+
+// StrLen runs STRLEN key
+func (c *StringCommands) StrLen(ctx *Context, args []string) payload.Reply {
+	length, err := c.kvStore.StrLen(args[0])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(int64(length))
+}
+
+// GetRange runs GETRANGE key start end
+func (c *StringCommands) GetRange(ctx *Context, args []string) payload.Reply {
+	start, err := argparser.ParseInt(args[1])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	end, err := argparser.ParseInt(args[2])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	val, err := c.kvStore.GetRange(args[0], start, end)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.BulkString(val)
+}
+
+// SetRange runs SETRANGE key offset value
+func (c *StringCommands) SetRange(ctx *Context, args []string) payload.Reply {
+	offset, err := argparser.ParseInt(args[1])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	if offset < 0 {
+		return errorReply(errOffsetOutOfRange)
+	}
+
+	length, err := c.kvStore.SetRange(args[0], offset, []byte(args[2]))
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(int64(length))
+}
+
+// LCS runs LCS key1 key2 [LEN] [IDX] [MINMATCHLEN len] [WITHMATCHLEN]
+func (c *StringCommands) LCS(ctx *Context, args []string) payload.Reply {
+	lcsArgs, err := stringparser.ParseLCSArgs(args[2:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	values := make([][]byte, 2)
+	for i, key := range args[:2] {
+		if values[i], _, err = c.kvStore.GetString(key); err != nil {
+			return errorReply(errLCSNotString)
+		}
+	}
+
+	lcs, matches := stringfn.LCS(values[0], values[1])
+
+	switch {
+	case lcsArgs.Len:
+		return payload.Integer(int64(len(lcs)))
+	case !lcsArgs.Idx:
+		return payload.BulkString(lcs)
+	}
+
+	elements := []payload.Reply{}
+	for _, match := range matches {
+		if match.Len() < lcsArgs.MinMatchLen {
+			continue
+		}
+
+		fields := []payload.Reply{
+			integerArray([]int64{int64(match.AStart), int64(match.AEnd)}),
+			integerArray([]int64{int64(match.BStart), int64(match.BEnd)}),
+		}
+
+		if lcsArgs.WithMatchLen {
+			fields = append(fields, payload.Integer(int64(match.Len())))
+		}
+
+		elements = append(elements, payload.Array(fields))
+	}
+
+	return payload.Array{
+		payload.BulkString("matches"),
+		payload.Array(elements),
+		payload.BulkString("len"),
+		payload.Integer(int64(len(lcs))),
+	}
+}
+
+// Incr runs INCR key
+func (c *StringCommands) Incr(ctx *Context, args []string) payload.Reply {
+	return c.incrBy(args[0], 1)
+}
+
+// Decr runs DECR key
+func (c *StringCommands) Decr(ctx *Context, args []string) payload.Reply {
+	return c.incrBy(args[0], -1)
+}
+
+// IncrBy runs INCRBY key increment
+func (c *StringCommands) IncrBy(ctx *Context, args []string) payload.Reply {
+	increment, err := argparser.ParseInt(args[1])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return c.incrBy(args[0], int64(increment))
+}
+
+// DecrBy runs DECRBY key decrement
+func (c *StringCommands) DecrBy(ctx *Context, args []string) payload.Reply {
+	decrement, err := argparser.ParseInt(args[1])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	// the decrement can't be negated
+	if decrement == math.MinInt64 {
+		return errorReply(errDecrOverflow)
+	}
+
+	return c.incrBy(args[0], -int64(decrement))
+}
+
+// IncrByFloat runs INCRBYFLOAT key increment
+func (c *StringCommands) IncrByFloat(ctx *Context, args []string) payload.Reply {
+	increment, err := argparser.ParseFloat(args[1])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	value, err := c.kvStore.IncrByFloat(args[0], increment)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.BulkString(value)
+}
+
+func (c *StringCommands) incrBy(key string, increment int64) payload.Reply {
+	value, err := c.kvStore.IncrBy(key, increment)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(value)
+}
+
+// setWithExpiration sets a value expiring after the given number of units,
+// as SETEX and PSETEX do.
+func (c *StringCommands) setWithExpiration(command string, args []string, unit time.Duration) payload.Reply {
+	value, err := argparser.ParseInt(args[1])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	perMs := int64(unit / time.Millisecond)
+	now := time.Now().UnixMilli()
+
+	if value <= 0 || int64(value) > (math.MaxInt64-now)/perMs {
+		return errorReply(stringparser.InvalidExpireTime(command))
+	}
+
+	opts := store.SetOptions{ExpireAt: now + int64(value)*perMs}
+	c.kvStore.SetWithOptions(args[0], []byte(args[2]), opts)
+
+	return payload.SimpleString("OK")
+}
+
+// keyValuePairs splits the key value pairs of MSET and MSETNX.
+func keyValuePairs(command string, args []string) ([]string, [][]byte, error) {
+	if len(args)%2 != 0 {
+		return nil, nil, fmt.Errorf("ERR wrong number of arguments for '%s' command", command)
+	}
+
+	keys := make([]string, 0, len(args)/2)
+	values := make([][]byte, 0, len(args)/2)
+
+	for i := 0; i < len(args); i += 2 {
+		keys = append(keys, args[i])
+		values = append(values, []byte(args[i+1]))
+	}
+
+	return keys, values, nil
+}
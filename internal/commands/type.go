@@ -6,25 +6,15 @@ import (
 )
 
 type TypeCommand struct {
-	kvStore     *store.KVStore
-	streamStore *store.Stream
+	kvStore *store.KVStore
 }
 
-func NewTypeCommand(kvStore *store.KVStore, streamStore *store.Stream) *TypeCommand {
+func NewTypeCommand(kvStore *store.KVStore) *TypeCommand {
 	return &TypeCommand{
-		kvStore:     kvStore,
-		streamStore: streamStore,
+		kvStore: kvStore,
 	}
 }
 
-func (c *TypeCommand) GetType(key string) []byte {
-	if _, exists := c.kvStore.Get(key); exists {
-		return payload.GenerateBasicString([]byte("string"))
-	}
-
-	if c.streamStore.Exists(key) {
-		return payload.GenerateBasicString([]byte("stream"))
-	}
-
-	return payload.GenerateBasicString([]byte("none"))
+func (c *TypeCommand) GetType(key string) payload.Reply {
+	return payload.SimpleString(c.kvStore.Type(key))
 }
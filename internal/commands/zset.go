@@ -0,0 +1,659 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/blocking"
+	"github.com/codecrafters-io/redis-starter-go/internal/floatfn"
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/scanparser"
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/zsetparser"
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+	"github.com/codecrafters-io/redis-starter-go/internal/store"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/zset"
+)
+
+type ZSetCommands struct {
+	zsetStore *store.ZSet
+	blocking  *blocking.Manager
+}
+
+func NewZSetCommands(zsetStore *store.ZSet, blockingManager *blocking.Manager) *ZSetCommands {
+	return &ZSetCommands{
+		zsetStore: zsetStore,
+		blocking:  blockingManager,
+	}
+}
+
+// ZAdd runs ZADD key [NX|XX] [GT|LT] [CH] [INCR] score member [score member
+// ...]
+func (c *ZSetCommands) ZAdd(ctx *Context, args []string) payload.Reply {
+	zaddArgs, err := zsetparser.ParseZAddArgs(args[1:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	if zaddArgs.Flags.Incr {
+		entry := zaddArgs.Entries[0]
+
+		score, ok, err := c.zsetStore.IncrBy(args[0], entry.Member, entry.Score, zaddArgs.Flags)
+		if err != nil {
+			return errorReply(err)
+		}
+
+		if !ok {
+			return payload.Null{}
+		}
+
+		c.blocking.SignalKeyAsReady(args[0])
+
+		return scoreReply(score)
+	}
+
+	added, updated, err := c.zsetStore.Add(args[0], zaddArgs.Entries, zaddArgs.Flags)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	if added > 0 {
+		c.blocking.SignalKeyAsReady(args[0])
+	}
+
+	if zaddArgs.CH {
+		added += updated
+	}
+
+	return payload.Integer(int64(added))
+}
+
+// ZIncrBy runs ZINCRBY key increment member
+func (c *ZSetCommands) ZIncrBy(ctx *Context, args []string) payload.Reply {
+	increment, err := zset.ParseScore(args[1])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	score, _, err := c.zsetStore.IncrBy(args[0], args[2], increment, zset.AddFlags{})
+	if err != nil {
+		return errorReply(err)
+	}
+
+	c.blocking.SignalKeyAsReady(args[0])
+
+	return scoreReply(score)
+}
+
+// ZScore runs ZSCORE key member
+func (c *ZSetCommands) ZScore(ctx *Context, args []string) payload.Reply {
+	score, found, err := c.zsetStore.Score(args[0], args[1])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	if !found {
+		return payload.Null{}
+	}
+
+	return scoreReply(score)
+}
+
+// ZMScore runs ZMSCORE key member [member ...]
+func (c *ZSetCommands) ZMScore(ctx *Context, args []string) payload.Reply {
+	scores, found, err := c.zsetStore.MScore(args[0], args[1:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	elements := make([]payload.Reply, len(scores))
+	for i, score := range scores {
+		if found[i] {
+			elements[i] = scoreReply(score)
+		} else {
+			elements[i] = payload.Null{}
+		}
+	}
+
+	return payload.Array(elements)
+}
+
+// ZCard runs ZCARD key
+func (c *ZSetCommands) ZCard(ctx *Context, args []string) payload.Reply {
+	count, err := c.zsetStore.Card(args[0])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(int64(count))
+}
+
+// ZScan runs ZSCAN key cursor [MATCH pattern] [COUNT count]
+func (c *ZSetCommands) ZScan(ctx *Context, args []string) payload.Reply {
+	options, err := scanparser.ParseScanOptions(args[1:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	cursor, entries, err := c.zsetStore.Scan(args[0], options.Cursor, options.Match, options.Count)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return scanReply(cursor, entriesReply(entries, true))
+}
+
+// ZRem runs ZREM key member [member ...]
+func (c *ZSetCommands) ZRem(ctx *Context, args []string) payload.Reply {
+	removed, err := c.zsetStore.Rem(args[0], args[1:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(int64(removed))
+}
+
+// ZRange runs ZRANGE key start stop [BYSCORE|BYLEX] [REV] [LIMIT offset
+// count] [WITHSCORES]
+func (c *ZSetCommands) ZRange(ctx *Context, args []string) payload.Reply {
+	rangeArgs, err := zsetparser.ParseZRangeArgs(args[1:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return c.zrange(ctx, args[0], rangeArgs)
+}
+
+// ZRevRange runs ZREVRANGE key start stop [WITHSCORES]
+func (c *ZSetCommands) ZRevRange(ctx *Context, args []string) payload.Reply {
+	return c.legacyRange(ctx, args, zsetparser.ByRank, true)
+}
+
+// ZRangeByScore runs ZRANGEBYSCORE key min max [WITHSCORES] [LIMIT offset
+// count]
+func (c *ZSetCommands) ZRangeByScore(ctx *Context, args []string) payload.Reply {
+	return c.legacyRange(ctx, args, zsetparser.ByScore, false)
+}
+
+// ZRevRangeByScore runs ZREVRANGEBYSCORE key max min [WITHSCORES] [LIMIT
+// offset count]
+func (c *ZSetCommands) ZRevRangeByScore(ctx *Context, args []string) payload.Reply {
+	return c.legacyRange(ctx, args, zsetparser.ByScore, true)
+}
+
+// ZRangeByLex runs ZRANGEBYLEX key min max [LIMIT offset count]
+func (c *ZSetCommands) ZRangeByLex(ctx *Context, args []string) payload.Reply {
+	return c.legacyRange(ctx, args, zsetparser.ByLex, false)
+}
+
+// ZRevRangeByLex runs ZREVRANGEBYLEX key max min [LIMIT offset count]
+func (c *ZSetCommands) ZRevRangeByLex(ctx *Context, args []string) payload.Reply {
+	return c.legacyRange(ctx, args, zsetparser.ByLex, true)
+}
+
+// ZRank runs ZRANK key member [WITHSCORE]
+func (c *ZSetCommands) ZRank(ctx *Context, args []string) payload.Reply {
+	return c.rank(args, false)
+}
+
+// ZRevRank runs ZREVRANK key member [WITHSCORE]
+func (c *ZSetCommands) ZRevRank(ctx *Context, args []string) payload.Reply {
+	return c.rank(args, true)
+}
+
+// ZCount runs ZCOUNT key min max
+func (c *ZSetCommands) ZCount(ctx *Context, args []string) payload.Reply {
+	r, err := zset.ParseScoreRange(args[1], args[2])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	count, err := c.zsetStore.Count(args[0], r)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(int64(count))
+}
+
+// ZLexCount runs ZLEXCOUNT key min max
+func (c *ZSetCommands) ZLexCount(ctx *Context, args []string) payload.Reply {
+	r, err := zset.ParseLexRange(args[1], args[2])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	count, err := c.zsetStore.LexCount(args[0], r)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(int64(count))
+}
+
+// ZRemRangeByRank runs ZREMRANGEBYRANK key start stop
+func (c *ZSetCommands) ZRemRangeByRank(ctx *Context, args []string) payload.Reply {
+	start, err := argparser.ParseInt(args[1])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	stop, err := argparser.ParseInt(args[2])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	removed, err := c.zsetStore.RemRangeByRank(args[0], start, stop)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(int64(removed))
+}
+
+// ZRemRangeByScore runs ZREMRANGEBYSCORE key min max
+func (c *ZSetCommands) ZRemRangeByScore(ctx *Context, args []string) payload.Reply {
+	r, err := zset.ParseScoreRange(args[1], args[2])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	removed, err := c.zsetStore.RemRangeByScore(args[0], r)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(int64(removed))
+}
+
+// ZRemRangeByLex runs ZREMRANGEBYLEX key min max
+func (c *ZSetCommands) ZRemRangeByLex(ctx *Context, args []string) payload.Reply {
+	r, err := zset.ParseLexRange(args[1], args[2])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	removed, err := c.zsetStore.RemRangeByLex(args[0], r)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return payload.Integer(int64(removed))
+}
+
+// ZRangeStore runs ZRANGESTORE dst src min max [BYSCORE|BYLEX] [REV] [LIMIT
+// offset count]
+func (c *ZSetCommands) ZRangeStore(ctx *Context, args []string) payload.Reply {
+	rangeArgs, err := zsetparser.ParseZRangeArgs(args[2:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	if rangeArgs.WithScores {
+		return errorReply(argparser.ErrSyntax)
+	}
+
+	entries, err := c.rangeEntries(args[1], rangeArgs)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return c.store(args[0], c.zsetStore.Store(args[0], entries, "zrangestore"))
+}
+
+// ZUnion runs ZUNION numkeys key [key ...] [WEIGHTS weight [weight ...]]
+// [AGGREGATE SUM|MIN|MAX] [WITHSCORES]
+func (c *ZSetCommands) ZUnion(ctx *Context, args []string) payload.Reply {
+	return c.combine(ctx, "zunion", store.SetUnion, args)
+}
+
+// ZInter runs ZINTER numkeys key [key ...] [WEIGHTS weight [weight ...]]
+// [AGGREGATE SUM|MIN|MAX] [WITHSCORES]
+func (c *ZSetCommands) ZInter(ctx *Context, args []string) payload.Reply {
+	return c.combine(ctx, "zinter", store.SetInter, args)
+}
+
+// ZDiff runs ZDIFF numkeys key [key ...] [WITHSCORES]
+func (c *ZSetCommands) ZDiff(ctx *Context, args []string) payload.Reply {
+	return c.combine(ctx, "zdiff", store.SetDiff, args)
+}
+
+// ZUnionStore runs ZUNIONSTORE destination numkeys key [key ...] [WEIGHTS
+// weight [weight ...]] [AGGREGATE SUM|MIN|MAX]
+func (c *ZSetCommands) ZUnionStore(ctx *Context, args []string) payload.Reply {
+	return c.combineStore("zunionstore", store.SetUnion, args)
+}
+
+// ZInterStore runs ZINTERSTORE destination numkeys key [key ...] [WEIGHTS
+// weight [weight ...]] [AGGREGATE SUM|MIN|MAX]
+func (c *ZSetCommands) ZInterStore(ctx *Context, args []string) payload.Reply {
+	return c.combineStore("zinterstore", store.SetInter, args)
+}
+
+// ZDiffStore runs ZDIFFSTORE destination numkeys key [key ...]
+func (c *ZSetCommands) ZDiffStore(ctx *Context, args []string) payload.Reply {
+	return c.combineStore("zdiffstore", store.SetDiff, args)
+}
+
+// ZPopMin runs ZPOPMIN key [count]
+func (c *ZSetCommands) ZPopMin(ctx *Context, args []string) payload.Reply {
+	return c.pop(ctx, args, false)
+}
+
+// ZPopMax runs ZPOPMAX key [count]
+func (c *ZSetCommands) ZPopMax(ctx *Context, args []string) payload.Reply {
+	return c.pop(ctx, args, true)
+}
+
+// BZPopMin runs BZPOPMIN key [key ...] timeout
+func (c *ZSetCommands) BZPopMin(ctx *Context, args []string) payload.Reply {
+	return c.blockingPop(ctx, args, false)
+}
+
+// BZPopMax runs BZPOPMAX key [key ...] timeout
+func (c *ZSetCommands) BZPopMax(ctx *Context, args []string) payload.Reply {
+	return c.blockingPop(ctx, args, true)
+}
+
+// ZMPop runs ZMPOP numkeys key [key ...] MIN|MAX [COUNT count]
+func (c *ZSetCommands) ZMPop(ctx *Context, args []string) payload.Reply {
+	mpopArgs, err := zsetparser.ParseZMPopArgs(args)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	for _, key := range mpopArgs.Keys {
+		reply, popped, err := c.mpop(key, mpopArgs)
+		if err != nil {
+			return errorReply(err)
+		}
+
+		if popped {
+			return reply
+		}
+	}
+
+	return payload.NullArray{}
+}
+
+// BZMPop runs BZMPOP timeout numkeys key [key ...] MIN|MAX [COUNT count]
+func (c *ZSetCommands) BZMPop(ctx *Context, args []string) payload.Reply {
+	timeout, err := argparser.ParseTimeout(args[0])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	mpopArgs, err := zsetparser.ParseZMPopArgs(args[1:])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	for _, key := range mpopArgs.Keys {
+		reply, popped, err := c.mpop(key, mpopArgs)
+		if err != nil {
+			return errorReply(err)
+		}
+
+		if popped {
+			return reply
+		}
+	}
+
+	if ctx.InMulti {
+		return payload.NullArray{}
+	}
+
+	w := c.blocking.Block(mpopArgs.Keys, func(key string) (payload.Reply, bool) {
+		reply, popped, _ := c.mpop(key, mpopArgs)
+		return reply, popped
+	})
+	ctx.block(w, timeout, payload.NullArray{})
+
+	return nil
+}
+
+func (c *ZSetCommands) combine(ctx *Context, name string, op store.SetOperation, args []string) payload.Reply {
+	combineArgs, err := zsetparser.ParseCombineArgs(name, args, op != store.SetDiff, true)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	entries, err := c.zsetStore.Combine(op, combineArgs.Keys, combineArgs.Weights, combineArgs.Aggregate)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return rangeReply(ctx, entries, combineArgs.WithScores)
+}
+
+func (c *ZSetCommands) combineStore(name string, op store.SetOperation, args []string) payload.Reply {
+	combineArgs, err := zsetparser.ParseCombineArgs(name, args[1:], op != store.SetDiff, false)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	count, err := c.zsetStore.CombineStore(op, args[0], combineArgs.Keys, combineArgs.Weights, combineArgs.Aggregate)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return c.store(args[0], count)
+}
+
+// store replies with the size of the sorted set stored at dst, waking up the
+// clients blocked on it.
+func (c *ZSetCommands) store(dst string, count int) payload.Reply {
+	if count > 0 {
+		c.blocking.SignalKeyAsReady(dst)
+	}
+
+	return payload.Integer(int64(count))
+}
+
+func (c *ZSetCommands) pop(ctx *Context, args []string, max bool) payload.Reply {
+	if len(args) > 2 {
+		return errorReply(argparser.ErrSyntax)
+	}
+
+	count := 1
+	if len(args) == 2 {
+		var err error
+
+		count, err = argparser.ParseInt(args[1])
+		if err != nil {
+			return errorReply(err)
+		}
+
+		if count < 0 {
+			return errorReply(errNotPositive)
+		}
+	}
+
+	entries, err := c.zsetStore.Pop(args[0], count, max)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	// over RESP3, a single member is popped as a flat member, score pair
+	if len(args) == 1 && ctx.RESP3() {
+		elements := []payload.Reply{}
+		for _, entry := range entries {
+			elements = append(elements, payload.BulkString(entry.Member), scoreReply(entry.Score))
+		}
+
+		return payload.Array(elements)
+	}
+
+	return rangeReply(ctx, entries, true)
+}
+
+// blockingPop pops a member from the first non empty sorted set, blocking
+// until one of them gets a member if they're all empty.
+func (c *ZSetCommands) blockingPop(ctx *Context, args []string, max bool) payload.Reply {
+	keys := args[:len(args)-1]
+
+	timeout, err := argparser.ParseTimeout(args[len(args)-1])
+	if err != nil {
+		return errorReply(err)
+	}
+
+	for _, key := range keys {
+		reply, popped, err := c.popWithKey(key, max)
+		if err != nil {
+			return errorReply(err)
+		}
+
+		if popped {
+			return reply
+		}
+	}
+
+	if ctx.InMulti {
+		return payload.NullArray{}
+	}
+
+	w := c.blocking.Block(keys, func(key string) (payload.Reply, bool) {
+		reply, popped, _ := c.popWithKey(key, max)
+		return reply, popped
+	})
+	ctx.block(w, timeout, payload.NullArray{})
+
+	return nil
+}
+
+// popWithKey pops a single member, replying with the key, the member and its
+// score as BZPOPMIN does.
+func (c *ZSetCommands) popWithKey(key string, max bool) (payload.Reply, bool, error) {
+	entries, err := c.zsetStore.Pop(key, 1, max)
+	if err != nil || len(entries) == 0 {
+		return nil, false, err
+	}
+
+	return payload.Array{
+		payload.BulkString(key),
+		payload.BulkString(entries[0].Member),
+		scoreReply(entries[0].Score),
+	}, true, nil
+}
+
+// mpop pops the members of ZMPOP and BZMPOP from the given key, replying
+// with the key and the array of popped member, score pairs.
+func (c *ZSetCommands) mpop(key string, args *zsetparser.ZMPopArgs) (payload.Reply, bool, error) {
+	entries, err := c.zsetStore.Pop(key, args.Count, args.Max)
+	if err != nil || len(entries) == 0 {
+		return nil, false, err
+	}
+
+	pairs := make([]payload.Reply, len(entries))
+	for i, entry := range entries {
+		pairs[i] = payload.Array{
+			payload.BulkString(entry.Member),
+			scoreReply(entry.Score),
+		}
+	}
+
+	return payload.Array{
+		payload.BulkString(key),
+		payload.Array(pairs),
+	}, true, nil
+}
+
+func (c *ZSetCommands) legacyRange(ctx *Context, args []string, by zsetparser.RangeBy, reverse bool) payload.Reply {
+	rangeArgs, err := zsetparser.ParseLegacyZRangeArgs(args[1:], by, reverse)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return c.zrange(ctx, args[0], rangeArgs)
+}
+
+func (c *ZSetCommands) zrange(ctx *Context, key string, args *zsetparser.ZRangeArgs) payload.Reply {
+	entries, err := c.rangeEntries(key, args)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	return rangeReply(ctx, entries, args.WithScores)
+}
+
+// rangeEntries returns the members in the range of ZRANGE and ZRANGESTORE.
+func (c *ZSetCommands) rangeEntries(key string, args *zsetparser.ZRangeArgs) ([]zset.Entry, error) {
+	switch args.By {
+	case zsetparser.ByScore:
+		return c.zsetStore.RangeByScore(key, args.Score, args.Reverse, args.Offset, args.Count)
+	case zsetparser.ByLex:
+		return c.zsetStore.RangeByLex(key, args.Lex, args.Reverse, args.Offset, args.Count)
+	}
+
+	return c.zsetStore.RangeByRank(key, args.Start, args.Stop, args.Reverse)
+}
+
+func (c *ZSetCommands) rank(args []string, reverse bool) payload.Reply {
+	withScore := false
+
+	switch {
+	case len(args) == 3 && strings.ToUpper(args[2]) == "WITHSCORE":
+		withScore = true
+	case len(args) > 2:
+		return errorReply(argparser.ErrSyntax)
+	}
+
+	rank, score, found, err := c.zsetStore.Rank(args[0], args[1], reverse)
+	if err != nil {
+		return errorReply(err)
+	}
+
+	if !withScore {
+		if !found {
+			return payload.Null{}
+		}
+
+		return payload.Integer(int64(rank))
+	}
+
+	if !found {
+		return payload.NullArray{}
+	}
+
+	return payload.Array{
+		payload.Integer(int64(rank)),
+		scoreReply(score),
+	}
+}
+
+// rangeReply replies with the members, with their scores when withScores is
+// set. Over RESP3, every member comes with its score in a pair of its own.
+func rangeReply(ctx *Context, entries []zset.Entry, withScores bool) payload.Reply {
+	if !withScores || !ctx.RESP3() {
+		return entriesReply(entries, withScores)
+	}
+
+	pairs := make([]payload.Reply, len(entries))
+	for i, entry := range entries {
+		pairs[i] = payload.Array{
+			payload.BulkString(entry.Member),
+			scoreReply(entry.Score),
+		}
+	}
+
+	return payload.Array(pairs)
+}
+
+// entriesReply replies with the members, each followed by its score when
+// withScores is set.
+func entriesReply(entries []zset.Entry, withScores bool) payload.Reply {
+	values := make([]string, 0, len(entries))
+
+	for _, entry := range entries {
+		values = append(values, entry.Member)
+
+		if withScores {
+			values = append(values, floatfn.Format(entry.Score))
+		}
+	}
+
+	return payload.BulkStrings(values)
+}
+
+// scoreReply replies with a score, as a double over RESP3.
+func scoreReply(score float64) payload.Reply {
+	return payload.Double(score)
+}
@@ -0,0 +1,394 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/notify"
+)
+
+const (
+	StreamNodeMaxBytes   = "stream-node-max-bytes"
+	StreamNodeMaxEntries = "stream-node-max-entries"
+	ListMaxListpackSize  = "list-max-listpack-size"
+
+	HashMaxListpackEntries = "hash-max-listpack-entries"
+	HashMaxListpackValue   = "hash-max-listpack-value"
+
+	SetMaxIntsetEntries = "set-max-intset-entries"
+
+	ZSetMaxListpackEntries = "zset-max-listpack-entries"
+	ZSetMaxListpackValue   = "zset-max-listpack-value"
+
+	HLLSparseMaxBytes = "hll-sparse-max-bytes"
+
+	Databases = "databases"
+
+	ClientOutputBufferLimit = "client-output-buffer-limit"
+
+	NotifyKeyspaceEvents = "notify-keyspace-events"
+
+	RequirePass = "requirepass"
+
+	ACLFile      = "aclfile"
+	ACLLogMaxLen = "acllog-max-len"
+)
+
+// outputBufferClasses are the client classes of client-output-buffer-limit,
+// in the order they're listed.
+var outputBufferClasses = []string{"normal", "slave", "pubsub"}
+
+type param struct {
+	value string
+	parse func(string) (string, error) // validates and normalizes the value
+	// immutable parameters can only be given on the command line
+	immutable bool
+	// merge combines the parsed value with the current one, for parameters
+	// which can be set in parts
+	merge func(current, parsed string) string
+}
+
+// Config holds the server parameters which can be given on the command line
+// (--name value) and changed at runtime with CONFIG SET.
+type Config struct {
+	params map[string]*param
+	mu     *sync.RWMutex
+	// listeners are called once a parameter is set, outside of the lock.
+	listeners map[string][]func(value string)
+}
+
+func New() *Config {
+	return &Config{
+		params: map[string]*param{
+			StreamNodeMaxBytes:   {value: "4096", parse: parseMemory},
+			StreamNodeMaxEntries: {value: "100", parse: parseNonNegativeInt},
+			ListMaxListpackSize:  {value: "-2", parse: parseListpackSize},
+
+			HashMaxListpackEntries: {value: "128", parse: parseNonNegativeInt},
+			HashMaxListpackValue:   {value: "64", parse: parseNonNegativeInt},
+
+			SetMaxIntsetEntries: {value: "512", parse: parseNonNegativeInt},
+
+			ZSetMaxListpackEntries: {value: "128", parse: parseNonNegativeInt},
+			ZSetMaxListpackValue:   {value: "64", parse: parseNonNegativeInt},
+
+			HLLSparseMaxBytes: {value: "3000", parse: parseMemory},
+
+			Databases: {value: "16", parse: parsePositiveInt, immutable: true},
+
+			ClientOutputBufferLimit: {
+				value: "normal 0 0 0 slave 268435456 67108864 60 pubsub 33554432 8388608 60",
+				parse: parseOutputBufferLimit,
+				merge: mergeOutputBufferLimit,
+			},
+
+			NotifyKeyspaceEvents: {value: "", parse: parseKeyspaceEvents},
+
+			RequirePass: {value: "", parse: parseString},
+
+			ACLFile:      {value: "", parse: parseString, immutable: true},
+			ACLLogMaxLen: {value: "128", parse: parseNonNegativeInt},
+		},
+		mu:        &sync.RWMutex{},
+		listeners: map[string][]func(value string){},
+	}
+}
+
+// OnChange registers a function called with the new value whenever the
+// parameter is set, on the command line or with CONFIG SET.
+func (c *Config) OnChange(name string, fn func(value string)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.listeners[name] = append(c.listeners[name], fn)
+}
+
+// ParseArgs reads parameters in the `--name value` format used by
+// redis-server.
+func (c *Config) ParseArgs(args []string) error {
+	for i := 0; i < len(args); i++ {
+		if !strings.HasPrefix(args[i], "--") {
+			return fmt.Errorf("Invalid argument: %s", args[i])
+		}
+
+		name := strings.TrimPrefix(args[i], "--")
+
+		if i+1 >= len(args) {
+			return fmt.Errorf("Missing value for argument: %s", args[i])
+		}
+
+		if err := c.set(name, args[i+1], true); err != nil {
+			return err
+		}
+
+		i++
+	}
+
+	return nil
+}
+
+func (c *Config) Get(name string) (string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	p, exists := c.params[strings.ToLower(name)]
+	if !exists {
+		return "", false
+	}
+
+	return p.value, true
+}
+
+// Names returns every known parameter name in sorted order.
+func (c *Config) Names() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.params))
+	for name := range c.params {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+func (c *Config) Set(name, value string) error {
+	return c.set(name, value, false)
+}
+
+func (c *Config) set(name, value string, startup bool) error {
+	parsed, err := c.setLocked(name, value, startup)
+	if err != nil {
+		return err
+	}
+
+	c.mu.RLock()
+	listeners := c.listeners[strings.ToLower(name)]
+	c.mu.RUnlock()
+
+	for _, fn := range listeners {
+		fn(parsed)
+	}
+
+	return nil
+}
+
+func (c *Config) setLocked(name, value string, startup bool) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	p, exists := c.params[strings.ToLower(name)]
+	if !exists {
+		return "", fmt.Errorf("Unknown option or number of arguments for CONFIG SET - '%s'", name)
+	}
+
+	if p.immutable && !startup {
+		return "", fmt.Errorf("CONFIG SET failed (possibly related to argument '%s') - can't set immutable config", name)
+	}
+
+	parsed, err := p.parse(value)
+	if err != nil {
+		return "", fmt.Errorf("Invalid argument '%s' for CONFIG SET '%s' - %w", value, name, err)
+	}
+
+	if p.merge != nil {
+		parsed = p.merge(p.value, parsed)
+	}
+
+	p.value = parsed
+
+	return parsed, nil
+}
+
+// Int returns the value of an integer parameter. It panics for unknown
+// parameters, since those can only come from a programming error.
+func (c *Config) Int(name string) int {
+	value, exists := c.Get(name)
+	if !exists {
+		panic(fmt.Sprintf("config: unknown parameter %q", name))
+	}
+
+	intValue, err := strconv.Atoi(value)
+	if err != nil {
+		panic(fmt.Sprintf("config: parameter %q is not an integer: %s", name, value))
+	}
+
+	return intValue
+}
+
+// OutputBufferLimit returns the hard and soft limits in bytes and the soft
+// limit duration in seconds of a client class of client-output-buffer-limit.
+func (c *Config) OutputBufferLimit(class string) (int64, int64, int) {
+	value, _ := c.Get(ClientOutputBufferLimit)
+	limits := splitOutputBufferLimit(value)[class]
+
+	hard, _ := strconv.ParseInt(limits[0], 10, 64)
+	soft, _ := strconv.ParseInt(limits[1], 10, 64)
+	seconds, _ := strconv.Atoi(limits[2])
+
+	return hard, soft, seconds
+}
+
+// parseString accepts any value.
+func parseString(value string) (string, error) {
+	return value, nil
+}
+
+func parseNonNegativeInt(value string) (string, error) {
+	intValue, err := strconv.Atoi(value)
+	if err != nil {
+		return "", fmt.Errorf("argument couldn't be parsed into an integer")
+	}
+
+	if intValue < 0 {
+		return "", fmt.Errorf("argument must be a non negative integer")
+	}
+
+	return strconv.Itoa(intValue), nil
+}
+
+func parsePositiveInt(value string) (string, error) {
+	intValue, err := strconv.Atoi(value)
+	if err != nil {
+		return "", fmt.Errorf("argument couldn't be parsed into an integer")
+	}
+
+	if intValue < 1 {
+		return "", fmt.Errorf("argument must be between 1 and 2147483647 inclusive")
+	}
+
+	return strconv.Itoa(intValue), nil
+}
+
+// parseListpackSize accepts a positive number of elements or -1 to -5 for
+// the 4kb to 64kb size limits.
+func parseListpackSize(value string) (string, error) {
+	intValue, err := strconv.Atoi(value)
+	if err != nil {
+		return "", fmt.Errorf("argument couldn't be parsed into an integer")
+	}
+
+	if intValue == 0 || intValue < -5 {
+		return "", fmt.Errorf("argument must be a positive integer or between -5 and -1")
+	}
+
+	return strconv.Itoa(intValue), nil
+}
+
+// parseMemory accepts plain byte counts as well as the k/kb/m/mb/g/gb units
+// redis.conf uses.
+func parseMemory(value string) (string, error) {
+	units := []struct {
+		suffix     string
+		multiplier int
+	}{
+		{"kb", 1024}, {"mb", 1024 * 1024}, {"gb", 1024 * 1024 * 1024},
+		{"k", 1000}, {"m", 1000 * 1000}, {"g", 1000 * 1000 * 1000},
+		{"b", 1},
+	}
+
+	lower := strings.ToLower(value)
+	multiplier := 1
+
+	for _, unit := range units {
+		if strings.HasSuffix(lower, unit.suffix) {
+			lower = strings.TrimSuffix(lower, unit.suffix)
+			multiplier = unit.multiplier
+			break
+		}
+	}
+
+	parsed, err := parseNonNegativeInt(lower)
+	if err != nil {
+		return "", err
+	}
+
+	intValue, _ := strconv.Atoi(parsed)
+
+	return strconv.Itoa(intValue * multiplier), nil
+}
+
+// parseKeyspaceEvents accepts the event classes of keyspace notifications,
+// see notify.ParseClasses.
+func parseKeyspaceEvents(value string) (string, error) {
+	classes, err := notify.ParseClasses(value)
+	if err != nil {
+		return "", err
+	}
+
+	return classes.String(), nil
+}
+
+// parseOutputBufferLimit accepts one or more `class hard soft seconds`
+// groups, the class being normal, replica (or slave) or pubsub.
+func parseOutputBufferLimit(value string) (string, error) {
+	fields := strings.Fields(value)
+	if len(fields) == 0 || len(fields)%4 != 0 {
+		return "", fmt.Errorf("wrong number of arguments")
+	}
+
+	parsed := make([]string, 0, len(fields))
+
+	for i := 0; i < len(fields); i += 4 {
+		class := strings.ToLower(fields[i])
+		if class == "replica" {
+			class = "slave"
+		}
+
+		if class != "normal" && class != "slave" && class != "pubsub" {
+			return "", fmt.Errorf("Invalid client class specified in buffer limit configuration.")
+		}
+
+		hard, err := parseMemory(fields[i+1])
+		if err != nil {
+			return "", fmt.Errorf("Error in hard, soft or soft_seconds setting in buffer limit configuration.")
+		}
+
+		soft, err := parseMemory(fields[i+2])
+		if err != nil {
+			return "", fmt.Errorf("Error in hard, soft or soft_seconds setting in buffer limit configuration.")
+		}
+
+		seconds, err := parseNonNegativeInt(fields[i+3])
+		if err != nil {
+			return "", fmt.Errorf("Error in hard, soft or soft_seconds setting in buffer limit configuration.")
+		}
+
+		parsed = append(parsed, class, hard, soft, seconds)
+	}
+
+	return strings.Join(parsed, " "), nil
+}
+
+// mergeOutputBufferLimit replaces the limits of the classes given, keeping
+// the current limits of the other ones.
+func mergeOutputBufferLimit(current, parsed string) string {
+	limits := splitOutputBufferLimit(current)
+	for class, classLimits := range splitOutputBufferLimit(parsed) {
+		limits[class] = classLimits
+	}
+
+	merged := make([]string, 0, 4*len(outputBufferClasses))
+	for _, class := range outputBufferClasses {
+		classLimits := limits[class]
+		merged = append(merged, class, classLimits[0], classLimits[1], classLimits[2])
+	}
+
+	return strings.Join(merged, " ")
+}
+
+func splitOutputBufferLimit(value string) map[string][3]string {
+	fields := strings.Fields(value)
+
+	limits := map[string][3]string{}
+	for i := 0; i+3 < len(fields); i += 4 {
+		limits[fields[i]] = [3]string{fields[i+1], fields[i+2], fields[i+3]}
+	}
+
+	return limits
+}
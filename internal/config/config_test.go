@@ -0,0 +1,125 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseArgs(t *testing.T) {
+	testCases := map[string]struct {
+		args          []string
+		expected      map[string]string
+		expectedError bool
+	}{
+		"when no arguments given": {
+			args: []string{},
+			expected: map[string]string{
+				config.StreamNodeMaxBytes:   "4096",
+				config.StreamNodeMaxEntries: "100",
+			},
+		},
+		"when values given": {
+			args: []string{"--stream-node-max-bytes", "8kb", "--stream-node-max-entries", "0"},
+			expected: map[string]string{
+				config.StreamNodeMaxBytes:   "8192",
+				config.StreamNodeMaxEntries: "0",
+			},
+		},
+		"when unknown parameter given": {
+			args:          []string{"--unknown", "1"},
+			expectedError: true,
+		},
+		"when value is missing": {
+			args:          []string{"--stream-node-max-bytes"},
+			expectedError: true,
+		},
+		"when value is invalid": {
+			args:          []string{"--stream-node-max-entries", "-1"},
+			expectedError: true,
+		},
+		"when immutable value given": {
+			args:     []string{"--databases", "4"},
+			expected: map[string]string{config.Databases: "4"},
+		},
+		"when no database given": {
+			args:          []string{"--databases", "0"},
+			expectedError: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cfg := config.New()
+
+			err := cfg.ParseArgs(tc.args)
+
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			for name, value := range tc.expected {
+				got, exists := cfg.Get(name)
+				assert.True(t, exists)
+				assert.Equal(t, value, got)
+			}
+		})
+	}
+}
+
+func TestSet(t *testing.T) {
+	cfg := config.New()
+
+	require.NoError(t, cfg.Set(config.HashMaxListpackEntries, "10"))
+	assert.Equal(t, 10, cfg.Int(config.HashMaxListpackEntries))
+
+	assert.Error(t, cfg.Set(config.Databases, "4"))
+	assert.Equal(t, 16, cfg.Int(config.Databases))
+
+	require.NoError(t, cfg.Set(config.RequirePass, "s3cret with spaces"))
+	password, _ := cfg.Get(config.RequirePass)
+	assert.Equal(t, "s3cret with spaces", password)
+}
+
+func TestOnChange(t *testing.T) {
+	cfg := config.New()
+
+	changes := []string{}
+	cfg.OnChange(config.ACLLogMaxLen, func(value string) { changes = append(changes, value) })
+
+	require.NoError(t, cfg.ParseArgs([]string{"--acllog-max-len", "10"}))
+	require.NoError(t, cfg.Set("ACLLOG-MAX-LEN", "20"))
+	assert.Error(t, cfg.Set(config.ACLLogMaxLen, "-1"))
+	require.NoError(t, cfg.Set(config.HashMaxListpackEntries, "10"))
+
+	assert.Equal(t, []string{"10", "20"}, changes)
+}
+
+func TestOutputBufferLimit(t *testing.T) {
+	cfg := config.New()
+
+	hard, soft, seconds := cfg.OutputBufferLimit("pubsub")
+	assert.Equal(t, int64(32*1024*1024), hard)
+	assert.Equal(t, int64(8*1024*1024), soft)
+	assert.Equal(t, 60, seconds)
+
+	// the classes not given keep their limits
+	require.NoError(t, cfg.Set(config.ClientOutputBufferLimit, "replica 1mb 0 0 pubsub 2kb 1kb 10"))
+
+	value, _ := cfg.Get(config.ClientOutputBufferLimit)
+	assert.Equal(t, "normal 0 0 0 slave 1048576 0 0 pubsub 2048 1024 10", value)
+
+	hard, soft, seconds = cfg.OutputBufferLimit("pubsub")
+	assert.Equal(t, int64(2048), hard)
+	assert.Equal(t, int64(1024), soft)
+	assert.Equal(t, 10, seconds)
+
+	assert.Error(t, cfg.Set(config.ClientOutputBufferLimit, "pubsub 1mb 0"))
+	assert.Error(t, cfg.Set(config.ClientOutputBufferLimit, "master 1mb 0 0"))
+	assert.Error(t, cfg.Set(config.ClientOutputBufferLimit, "pubsub 1mb -1 0"))
+}
@@ -0,0 +1,48 @@
+package floatfn
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// FormatHuman formats a float the way INCRBYFLOAT and HINCRBYFLOAT reply:
+// never with an exponent, and without trailing zeros.
+func FormatHuman(value float64) string {
+	if value == 0 {
+		// avoids replying with -0
+		return "0"
+	}
+
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// Format formats a float the way sorted set scores are replied: with the
+// fewest digits that read back as the same value, switching to an exponent
+// for very big or small values.
+func Format(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "inf"
+	case math.IsInf(value, -1):
+		return "-inf"
+	}
+
+	exponential := strconv.FormatFloat(value, 'e', -1, 64)
+
+	exp, _ := strconv.Atoi(exponential[strings.IndexByte(exponential, 'e')+1:])
+	if exp < -4 || exp >= 17 {
+		return exponential
+	}
+
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// FormatPrecise formats a float with 17 decimals, without trailing zeros,
+// the way GEOPOS replies with coordinates.
+func FormatPrecise(value float64) string {
+	res := strconv.FormatFloat(value, 'f', 17, 64)
+	res = strings.TrimRight(res, "0")
+
+	return strings.TrimSuffix(res, ".")
+}
@@ -0,0 +1,69 @@
+package floatfn_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/floatfn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatHuman(t *testing.T) {
+	testCases := map[string]struct {
+		value    float64
+		expected string
+	}{
+		"when value is an integer": {value: 3, expected: "3"},
+		"when value has decimals":  {value: 10.5 + 0.1, expected: "10.6"},
+		"when value is negative":   {value: -1.25, expected: "-1.25"},
+		"when value is big":        {value: 5e20, expected: "500000000000000000000"},
+		"when value is small":      {value: 1e-5, expected: "0.00001"},
+		"when value is minus zero": {value: math.Copysign(0, -1), expected: "0"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, floatfn.FormatHuman(tc.value))
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	testCases := map[string]struct {
+		value    float64
+		expected string
+	}{
+		"when value is an integer":   {value: 3, expected: "3"},
+		"when value has decimals":    {value: 1.5, expected: "1.5"},
+		"when value is negative":     {value: -0.25, expected: "-0.25"},
+		"when value is big":          {value: 1e20, expected: "1e+20"},
+		"when value is long":         {value: 123456789012345678, expected: "1.2345678901234568e+17"},
+		"when value is small":        {value: 0.0001, expected: "0.0001"},
+		"when value is tiny":         {value: 1e-5, expected: "1e-05"},
+		"when value is infinite":     {value: math.Inf(1), expected: "inf"},
+		"when value is neg infinite": {value: math.Inf(-1), expected: "-inf"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, floatfn.Format(tc.value))
+		})
+	}
+}
+
+func TestFormatPrecise(t *testing.T) {
+	testCases := map[string]struct {
+		value    float64
+		expected string
+	}{
+		"when value is an integer": {value: 3, expected: "3"},
+		"when value has decimals":  {value: 13.361389338970184, expected: "13.36138933897018433"},
+		"when value is negative":   {value: -0.5, expected: "-0.5"},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, floatfn.FormatPrecise(tc.value))
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package glob
+
+import "unicode"
+
+// Match reports whether str matches the glob-style pattern, with the same
+// rules as Redis:
+//
+//   - matches any sequence of characters, including none
+//     ?       matches a single character
+//     [abc]   matches one of the characters, [^abc] any but them
+//     [a-z]   matches a range of characters
+//     \x      matches x literally
+func Match(pattern, str string, nocase bool) bool {
+	return match([]byte(pattern), []byte(str), nocase)
+}
+
+func match(pattern, str []byte, nocase bool) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for len(pattern) > 1 && pattern[1] == '*' {
+				pattern = pattern[1:]
+			}
+
+			if len(pattern) == 1 {
+				return true
+			}
+
+			for i := 0; i <= len(str); i++ {
+				if match(pattern[1:], str[i:], nocase) {
+					return true
+				}
+			}
+
+			return false
+		case '?':
+			if len(str) == 0 {
+				return false
+			}
+
+			str = str[1:]
+		case '[':
+			if len(str) == 0 {
+				return false
+			}
+
+			var matched bool
+			pattern, matched = matchClass(pattern[1:], str[0], nocase)
+			if !matched {
+				return false
+			}
+
+			str = str[1:]
+
+			// matchClass leaves the pattern on the closing bracket
+			if len(pattern) == 0 {
+				return len(str) == 0
+			}
+		case '\\':
+			if len(pattern) >= 2 {
+				pattern = pattern[1:]
+			}
+
+			fallthrough
+		default:
+			if len(str) == 0 || !equal(pattern[0], str[0], nocase) {
+				return false
+			}
+
+			str = str[1:]
+		}
+
+		pattern = pattern[1:]
+	}
+
+	return len(str) == 0
+}
+
+// matchClass matches c against the character class at the start of pattern,
+// right after the opening bracket. It returns the pattern starting at the
+// closing bracket, or empty if the class isn't closed.
+func matchClass(pattern []byte, c byte, nocase bool) ([]byte, bool) {
+	not := len(pattern) > 0 && pattern[0] == '^'
+	if not {
+		pattern = pattern[1:]
+	}
+
+	matched := false
+
+	for len(pattern) > 0 && pattern[0] != ']' {
+		switch {
+		case pattern[0] == '\\' && len(pattern) >= 2:
+			pattern = pattern[1:]
+			if equal(pattern[0], c, nocase) {
+				matched = true
+			}
+		case len(pattern) >= 3 && pattern[1] == '-':
+			start, end := pattern[0], pattern[2]
+			if start > end {
+				start, end = end, start
+			}
+
+			if nocase {
+				start, end, c = lower(start), lower(end), lower(c)
+			}
+
+			if c >= start && c <= end {
+				matched = true
+			}
+
+			pattern = pattern[2:]
+		default:
+			if equal(pattern[0], c, nocase) {
+				matched = true
+			}
+		}
+
+		pattern = pattern[1:]
+	}
+
+	if not {
+		matched = !matched
+	}
+
+	return pattern, matched
+}
+
+func equal(a, b byte, nocase bool) bool {
+	if nocase {
+		return lower(a) == lower(b)
+	}
+
+	return a == b
+}
+
+func lower(c byte) byte {
+	return byte(unicode.ToLower(rune(c)))
+}
@@ -0,0 +1,46 @@
+package glob_test
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/glob"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		str      string
+		nocase   bool
+		expected bool
+	}{
+		{pattern: "*", str: "", expected: true},
+		{pattern: "*", str: "anything", expected: true},
+		{pattern: "h?llo", str: "hello", expected: true},
+		{pattern: "h?llo", str: "hllo", expected: false},
+		{pattern: "h*llo", str: "heeeello", expected: true},
+		{pattern: "h*llo", str: "hellox", expected: false},
+		{pattern: "h[ae]llo", str: "hallo", expected: true},
+		{pattern: "h[ae]llo", str: "hillo", expected: false},
+		{pattern: "h[^e]llo", str: "hallo", expected: true},
+		{pattern: "h[^e]llo", str: "hello", expected: false},
+		{pattern: "h[a-b]llo", str: "hbllo", expected: true},
+		{pattern: "h[b-a]llo", str: "hallo", expected: true},
+		{pattern: "h[a-b]llo", str: "hcllo", expected: false},
+		{pattern: `h\*llo`, str: "h*llo", expected: true},
+		{pattern: `h\*llo`, str: "hello", expected: false},
+		{pattern: `[\]]`, str: "]", expected: true},
+		{pattern: "user:*:name", str: "user:1000:name", expected: true},
+		{pattern: "**a", str: "bba", expected: true},
+		{pattern: "HELLO", str: "hello", nocase: true, expected: true},
+		{pattern: "[A-C]x", str: "bx", nocase: true, expected: true},
+		{pattern: "abc[", str: "abcd", expected: false},
+		{pattern: "a[bc", str: "ab", expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.pattern+" "+tc.str, func(t *testing.T) {
+			assert.Equal(t, tc.expected, glob.Match(tc.pattern, tc.str, tc.nocase))
+		})
+	}
+}
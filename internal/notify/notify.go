@@ -0,0 +1,124 @@
+package notify
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/pubsub"
+)
+
+// Class is a set of event classes, as given to notify-keyspace-events.
+type Class int
+
+const (
+	Keyspace Class = 1 << iota // K, __keyspace@<db>__:<key> messages
+	Keyevent                   // E, __keyevent@<db>__:<event> messages
+	Generic                    // g, DEL, RENAME, COPY, ...
+	String                     // $
+	List                       // l
+	Set                        // s
+	Hash                       // h
+	ZSet                       // z
+	Expired                    // x, keys deleted once expired
+	Evicted                    // e, keys evicted under maxmemory
+	Stream                     // t
+	KeyMiss                    // m, keys missing when read
+
+	// All is the A alias, every event class but key misses.
+	All = Generic | String | List | Set | Hash | ZSet | Expired | Evicted | Stream
+)
+
+// flags are the characters of the classes, in the order they're listed by
+// String.
+var flags = []struct {
+	char  byte
+	class Class
+}{
+	{'g', Generic}, {'$', String}, {'l', List}, {'s', Set}, {'h', Hash},
+	{'z', ZSet}, {'x', Expired}, {'e', Evicted}, {'t', Stream},
+	{'K', Keyspace}, {'E', Keyevent}, {'m', KeyMiss},
+}
+
+// ParseClasses parses the flags of notify-keyspace-events.
+func ParseClasses(value string) (Class, error) {
+	classes := Class(0)
+
+	for i := 0; i < len(value); i++ {
+		if value[i] == 'A' {
+			classes |= All
+			continue
+		}
+
+		found := false
+		for _, flag := range flags {
+			if flag.char == value[i] {
+				classes |= flag.class
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			return 0, fmt.Errorf("invalid event class character '%c'", value[i])
+		}
+	}
+
+	return classes, nil
+}
+
+// String returns the flags of the classes, using the A alias when possible.
+func (c Class) String() string {
+	var sb strings.Builder
+
+	if c&All == All {
+		sb.WriteByte('A')
+	}
+
+	for _, flag := range flags {
+		if c&flag.class == 0 || (c&All == All && All&flag.class != 0) {
+			continue
+		}
+
+		sb.WriteByte(flag.char)
+	}
+
+	return sb.String()
+}
+
+// Notifier publishes the keyspace notifications of the enabled classes.
+type Notifier struct {
+	hub     *pubsub.Hub
+	classes func() Class
+}
+
+// NewNotifier returns a notifier publishing to the hub. classes is called on
+// every event, so that the enabled classes can be changed at runtime.
+func NewNotifier(hub *pubsub.Hub, classes func() Class) *Notifier {
+	return &Notifier{
+		hub:     hub,
+		classes: classes,
+	}
+}
+
+// Notify publishes the event of the class which happened to the key of the
+// database db, if the class is enabled. Keyspace messages are published to
+// __keyspace@<db>__:<key> with the event as message, and keyevent messages
+// to __keyevent@<db>__:<event> with the key as message.
+func (n *Notifier) Notify(class Class, event, key string, db int) {
+	classes := n.classes()
+	if classes&class == 0 {
+		return
+	}
+
+	dbIndex := strconv.Itoa(db)
+
+	if classes&Keyspace != 0 {
+		n.hub.Publish("__keyspace@"+dbIndex+"__:"+key, []byte(event))
+	}
+
+	if classes&Keyevent != 0 {
+		n.hub.Publish("__keyevent@"+dbIndex+"__:"+event, []byte(key))
+	}
+}
@@ -0,0 +1,97 @@
+package notify_test
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/notify"
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+	"github.com/codecrafters-io/redis-starter-go/internal/pubsub"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type subscriber struct {
+	received []string
+}
+
+func (s *subscriber) Send(msg payload.Push) {
+	s.received = append(s.received, string(payload.Encode(msg, 2)))
+}
+
+func TestParseClasses(t *testing.T) {
+	testCases := map[string]struct {
+		value    string
+		expected notify.Class
+		str      string
+		fail     bool
+	}{
+		"when no class is enabled": {
+			value:    "",
+			expected: 0,
+			str:      "",
+		},
+		"when some classes are enabled": {
+			value:    "Elg",
+			expected: notify.Keyevent | notify.List | notify.Generic,
+			str:      "glE",
+		},
+		"when A is used": {
+			value:    "KEA",
+			expected: notify.Keyspace | notify.Keyevent | notify.All,
+			str:      "AKE",
+		},
+		"when A and key misses are enabled": {
+			value:    "mKgA$",
+			expected: notify.Keyspace | notify.All | notify.KeyMiss,
+			str:      "AKm",
+		},
+		"when a class is unknown": {
+			value: "Kq",
+			fail:  true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			classes, err := notify.ParseClasses(tc.value)
+			if tc.fail {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, classes)
+			assert.Equal(t, tc.str, classes.String())
+		})
+	}
+}
+
+func TestNotify(t *testing.T) {
+	hub := pubsub.NewHub()
+	s := &subscriber{}
+	hub.PSubscribe(s, "__key*__:*")
+
+	classes := notify.Class(0)
+	n := notify.NewNotifier(hub, func() notify.Class { return classes })
+
+	n.Notify(notify.List, "lpush", "mylist", 0)
+	assert.Empty(t, s.received)
+
+	classes = notify.Keyspace | notify.Keyevent | notify.List
+	n.Notify(notify.List, "lpush", "mylist", 3)
+	n.Notify(notify.Generic, "del", "mylist", 3)
+
+	assert.Equal(t, []string{
+		string(payload.Encode(payload.BulkStrings([]string{"pmessage", "__key*__:*", "__keyspace@3__:mylist", "lpush"}), 2)),
+		string(payload.Encode(payload.BulkStrings([]string{"pmessage", "__key*__:*", "__keyevent@3__:lpush", "mylist"}), 2)),
+	}, s.received)
+
+	s.received = nil
+	classes = notify.Keyevent | notify.All
+	n.Notify(notify.Expired, "expired", "session", 0)
+
+	assert.Equal(t, []string{
+		string(payload.Encode(payload.BulkStrings([]string{"pmessage", "__key*__:*", "__keyevent@0__:expired", "session"}), 2)),
+	}, s.received)
+}
@@ -0,0 +1,74 @@
+package argparser
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrSyntax     = errors.New("ERR syntax error")
+	ErrNotInteger = errors.New("ERR value is not an integer or out of range")
+	ErrNotFloat   = errors.New("ERR value is not a valid float")
+
+	ErrNumKeysNotPositive = errors.New("ERR numkeys should be greater than 0")
+	ErrTooManyKeys        = errors.New("ERR Number of keys can't be greater than number of args")
+
+	ErrTimeoutNotFloat = errors.New("ERR timeout is not a float or out of range")
+	ErrNegativeTimeout = errors.New("ERR timeout is negative")
+)
+
+// ParseInt parses a command argument as an integer.
+func ParseInt(arg string) (int, error) {
+	value, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return 0, ErrNotInteger
+	}
+
+	return int(value), nil
+}
+
+// ParseFloat parses a finite float, as accepted by INCRBYFLOAT and friends.
+func ParseFloat(arg string) (float64, error) {
+	value, err := strconv.ParseFloat(arg, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, ErrNotFloat
+	}
+
+	return value, nil
+}
+
+// ParseTimeout parses the timeout of blocking commands, given in seconds
+// with an optional fractional part.
+func ParseTimeout(arg string) (time.Duration, error) {
+	seconds, err := strconv.ParseFloat(arg, 64)
+	if err != nil || math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+		return 0, ErrTimeoutNotFloat
+	}
+
+	if seconds < 0 {
+		return 0, ErrNegativeTimeout
+	}
+
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// ParseNumKeys parses numkeys key [key ...] and returns the keys together
+// with the arguments following them.
+func ParseNumKeys(payloads []string) ([]string, []string, error) {
+	numKeys, err := ParseInt(payloads[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if numKeys <= 0 {
+		return nil, nil, ErrNumKeysNotPositive
+	}
+
+	if numKeys > len(payloads)-1 {
+		return nil, nil, ErrTooManyKeys
+	}
+
+	return payloads[1 : numKeys+1], payloads[numKeys+1:], nil
+}
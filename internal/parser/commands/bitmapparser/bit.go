@@ -0,0 +1,103 @@
+package bitmapparser
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+)
+
+// maxBitOffset is the number of bits of the longest string, 512MB.
+const maxBitOffset = 512 * 1024 * 1024 * 8
+
+var (
+	ErrBitOffset = errors.New("ERR bit offset is not an integer or out of range")
+	ErrBitValue  = errors.New("ERR bit is not an integer or out of range")
+	ErrBitPosBit = errors.New("ERR The bit argument must be 1 or 0.")
+)
+
+// ParseBitOffset parses the bit offset of SETBIT and GETBIT.
+func ParseBitOffset(arg string) (uint64, error) {
+	offset, err := strconv.ParseUint(arg, 10, 64)
+	if err != nil || offset >= maxBitOffset {
+		return 0, ErrBitOffset
+	}
+
+	return offset, nil
+}
+
+// ParseBit parses the bit value of SETBIT.
+func ParseBit(arg string) (int, error) {
+	if arg != "0" && arg != "1" {
+		return 0, ErrBitValue
+	}
+
+	return int(arg[0] - '0'), nil
+}
+
+// ParseBitPosBit parses the bit looked for by BITPOS.
+func ParseBitPosBit(arg string) (int, error) {
+	bit, err := argparser.ParseInt(arg)
+	if err != nil {
+		return 0, err
+	}
+
+	if bit != 0 && bit != 1 {
+		return 0, ErrBitPosBit
+	}
+
+	return bit, nil
+}
+
+// BitRange is the range of BITCOUNT and BITPOS, given in bytes unless
+// BitUnit is set.
+type BitRange struct {
+	Start, End       int
+	HasStart, HasEnd bool
+	BitUnit          bool
+}
+
+// ParseBitRange parses [start end [BYTE|BIT]], the end being optional when
+// endOptional is set as with BITPOS.
+func ParseBitRange(payloads []string, endOptional bool) (*BitRange, error) {
+	bitRange := &BitRange{}
+
+	if len(payloads) == 0 {
+		return bitRange, nil
+	}
+
+	if len(payloads) > 3 || (len(payloads) == 1 && !endOptional) {
+		return nil, argparser.ErrSyntax
+	}
+
+	var err error
+
+	bitRange.Start, err = argparser.ParseInt(payloads[0])
+	if err != nil {
+		return nil, err
+	}
+
+	bitRange.HasStart = true
+
+	if len(payloads) > 1 {
+		bitRange.End, err = argparser.ParseInt(payloads[1])
+		if err != nil {
+			return nil, err
+		}
+
+		bitRange.HasEnd = true
+	}
+
+	if len(payloads) == 3 {
+		switch strings.ToUpper(payloads[2]) {
+		case "BIT":
+			bitRange.BitUnit = true
+		case "BYTE":
+		default:
+			return nil, argparser.ErrSyntax
+		}
+	}
+
+	return bitRange, nil
+}
@@ -0,0 +1,69 @@
+package bitmapparser
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBitOffset(t *testing.T) {
+	offset, err := ParseBitOffset("4294967295")
+	require.NoError(t, err)
+	assert.Equal(t, uint64(4294967295), offset)
+
+	_, err = ParseBitOffset("4294967296")
+	assert.ErrorIs(t, err, ErrBitOffset)
+
+	_, err = ParseBitOffset("-1")
+	assert.ErrorIs(t, err, ErrBitOffset)
+}
+
+func TestParseBitRange(t *testing.T) {
+	testCases := map[string]struct {
+		payloads      []string
+		endOptional   bool
+		expected      *BitRange
+		expectedError error
+	}{
+		"when no range given": {
+			payloads: []string{},
+			expected: &BitRange{},
+		},
+		"when range given in bits": {
+			payloads: []string{"1", "-1", "bit"},
+			expected: &BitRange{Start: 1, End: -1, HasStart: true, HasEnd: true, BitUnit: true},
+		},
+		"when only start given to BITPOS": {
+			payloads:    []string{"2"},
+			endOptional: true,
+			expected:    &BitRange{Start: 2, HasStart: true},
+		},
+		"when only start given to BITCOUNT": {
+			payloads:      []string{"2"},
+			expectedError: argparser.ErrSyntax,
+		},
+		"when unit is unknown": {
+			payloads:      []string{"1", "2", "WORD"},
+			expectedError: argparser.ErrSyntax,
+		},
+		"when end is not an integer": {
+			payloads:      []string{"1", "x"},
+			expectedError: argparser.ErrNotInteger,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			bitRange, err := ParseBitRange(tc.payloads, tc.endOptional)
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, bitRange)
+		})
+	}
+}
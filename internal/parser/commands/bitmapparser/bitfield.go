@@ -0,0 +1,145 @@
+package bitmapparser
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/bitfn"
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+)
+
+var (
+	ErrBitFieldType     = errors.New("ERR Invalid bitfield type. Use something like i16 u8. Note that u64 is not supported but i64 is.")
+	ErrOverflowType     = errors.New("ERR Invalid OVERFLOW type specified")
+	ErrBitFieldReadOnly = errors.New("ERR BITFIELD_RO only supports the GET subcommand")
+)
+
+// ParseBitFieldArgs parses the subcommands of BITFIELD, [GET encoding
+// offset | [OVERFLOW WRAP|SAT|FAIL] SET encoding offset value | INCRBY
+// encoding offset increment ...], or of BITFIELD_RO when readOnly is set.
+func ParseBitFieldArgs(payloads []string, readOnly bool) ([]bitfn.FieldOp, error) {
+	ops := []bitfn.FieldOp{}
+	overflow := bitfn.OverflowWrap
+
+	for i := 0; i < len(payloads); {
+		subcommand := strings.ToUpper(payloads[i])
+
+		if subcommand == "OVERFLOW" {
+			if readOnly {
+				return nil, ErrBitFieldReadOnly
+			}
+
+			if i+1 >= len(payloads) {
+				return nil, argparser.ErrSyntax
+			}
+
+			switch strings.ToUpper(payloads[i+1]) {
+			case "WRAP":
+				overflow = bitfn.OverflowWrap
+			case "SAT":
+				overflow = bitfn.OverflowSat
+			case "FAIL":
+				overflow = bitfn.OverflowFail
+			default:
+				return nil, ErrOverflowType
+			}
+
+			i += 2
+
+			continue
+		}
+
+		op := bitfn.FieldOp{Overflow: overflow}
+		argc := 3
+
+		switch subcommand {
+		case "GET":
+			op.Kind = bitfn.FieldGet
+		case "SET":
+			op.Kind = bitfn.FieldSet
+			argc = 4
+		case "INCRBY":
+			op.Kind = bitfn.FieldIncrBy
+			argc = 4
+		default:
+			return nil, argparser.ErrSyntax
+		}
+
+		if i+argc > len(payloads) {
+			return nil, argparser.ErrSyntax
+		}
+
+		if readOnly && op.Kind != bitfn.FieldGet {
+			return nil, ErrBitFieldReadOnly
+		}
+
+		var err error
+
+		op.Encoding, err = parseEncoding(payloads[i+1])
+		if err != nil {
+			return nil, err
+		}
+
+		op.Offset, err = parseFieldOffset(payloads[i+2], op.Encoding)
+		if err != nil {
+			return nil, err
+		}
+
+		if argc == 4 {
+			op.Value, err = strconv.ParseInt(payloads[i+3], 10, 64)
+			if err != nil {
+				return nil, argparser.ErrNotInteger
+			}
+		}
+
+		ops = append(ops, op)
+		i += argc
+	}
+
+	return ops, nil
+}
+
+// parseEncoding parses a type from i1 to i64 or from u1 to u63.
+func parseEncoding(arg string) (bitfn.Encoding, error) {
+	if len(arg) < 2 || (arg[0] != 'i' && arg[0] != 'u' && arg[0] != 'I' && arg[0] != 'U') {
+		return bitfn.Encoding{}, ErrBitFieldType
+	}
+
+	signed := arg[0] == 'i' || arg[0] == 'I'
+
+	bits, err := strconv.ParseUint(arg[1:], 10, 8)
+	if err != nil || bits < 1 || (signed && bits > 64) || (!signed && bits > 63) {
+		return bitfn.Encoding{}, ErrBitFieldType
+	}
+
+	return bitfn.Encoding{Signed: signed, Bits: uint(bits)}, nil
+}
+
+// parseFieldOffset parses a bit offset, or a multiple of the field size when
+// prefixed with #.
+func parseFieldOffset(arg string, encoding bitfn.Encoding) (uint64, error) {
+	multiply := strings.HasPrefix(arg, "#")
+	if multiply {
+		arg = arg[1:]
+	}
+
+	offset, err := strconv.ParseUint(arg, 10, 64)
+	if err != nil {
+		return 0, ErrBitOffset
+	}
+
+	if multiply {
+		if offset > maxBitOffset/uint64(encoding.Bits) {
+			return 0, ErrBitOffset
+		}
+
+		offset *= uint64(encoding.Bits)
+	}
+
+	if offset >= maxBitOffset {
+		return 0, ErrBitOffset
+	}
+
+	return offset, nil
+}
@@ -0,0 +1,74 @@
+package bitmapparser
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/bitfn"
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBitFieldArgs(t *testing.T) {
+	i8 := bitfn.Encoding{Signed: true, Bits: 8}
+	u4 := bitfn.Encoding{Bits: 4}
+
+	testCases := map[string]struct {
+		payloads      []string
+		readOnly      bool
+		expected      []bitfn.FieldOp
+		expectedError error
+	}{
+		"when subcommands given": {
+			payloads: []string{"GET", "i8", "0", "OVERFLOW", "SAT", "incrby", "u4", "#2", "-3", "SET", "i8", "8", "100"},
+			expected: []bitfn.FieldOp{
+				{Kind: bitfn.FieldGet, Encoding: i8},
+				{Kind: bitfn.FieldIncrBy, Encoding: u4, Offset: 8, Value: -3, Overflow: bitfn.OverflowSat},
+				{Kind: bitfn.FieldSet, Encoding: i8, Offset: 8, Value: 100, Overflow: bitfn.OverflowSat},
+			},
+		},
+		"when read only with GET": {
+			payloads: []string{"GET", "u4", "#1"},
+			readOnly: true,
+			expected: []bitfn.FieldOp{{Kind: bitfn.FieldGet, Encoding: u4, Offset: 4}},
+		},
+		"when read only with SET": {
+			payloads:      []string{"SET", "u4", "0", "1"},
+			readOnly:      true,
+			expectedError: ErrBitFieldReadOnly,
+		},
+		"when u64 given": {
+			payloads:      []string{"GET", "u64", "0"},
+			expectedError: ErrBitFieldType,
+		},
+		"when offset is negative": {
+			payloads:      []string{"GET", "i64", "-1"},
+			expectedError: ErrBitOffset,
+		},
+		"when overflow is unknown": {
+			payloads:      []string{"OVERFLOW", "CLAMP"},
+			expectedError: ErrOverflowType,
+		},
+		"when value is missing": {
+			payloads:      []string{"SET", "i8", "0"},
+			expectedError: argparser.ErrSyntax,
+		},
+		"when value is not an integer": {
+			payloads:      []string{"INCRBY", "i8", "0", "x"},
+			expectedError: argparser.ErrNotInteger,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ops, err := ParseBitFieldArgs(tc.payloads, tc.readOnly)
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, ops)
+		})
+	}
+}
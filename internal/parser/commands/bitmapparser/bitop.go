@@ -0,0 +1,54 @@
+package bitmapparser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/bitfn"
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+)
+
+var operations = map[string]bitfn.Operation{
+	"AND":   bitfn.OpAnd,
+	"OR":    bitfn.OpOr,
+	"XOR":   bitfn.OpXor,
+	"NOT":   bitfn.OpNot,
+	"DIFF":  bitfn.OpDiff,
+	"ANDOR": bitfn.OpAndOr,
+	"ONE":   bitfn.OpOne,
+}
+
+type BitOpArgs struct {
+	Op   bitfn.Operation
+	Dest string
+	Keys []string
+}
+
+// ParseBitOpArgs parses operation destkey key [key ...]
+func ParseBitOpArgs(payloads []string) (*BitOpArgs, error) {
+	name := strings.ToUpper(payloads[0])
+
+	op, ok := operations[name]
+	if !ok {
+		return nil, argparser.ErrSyntax
+	}
+
+	args := &BitOpArgs{
+		Op:   op,
+		Dest: payloads[1],
+		Keys: payloads[2:],
+	}
+
+	switch op {
+	case bitfn.OpNot:
+		if len(args.Keys) != 1 {
+			return nil, fmt.Errorf("ERR BITOP NOT must be called with a single source key.")
+		}
+	case bitfn.OpDiff, bitfn.OpAndOr:
+		if len(args.Keys) < 2 {
+			return nil, fmt.Errorf("ERR BITOP %s must be called with at least two source keys.", name)
+		}
+	}
+
+	return args, nil
+}
@@ -0,0 +1,52 @@
+package bitmapparser
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/bitfn"
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBitOpArgs(t *testing.T) {
+	testCases := map[string]struct {
+		payloads      []string
+		expected      *BitOpArgs
+		expectedError string
+	}{
+		"when AND given": {
+			payloads: []string{"and", "dest", "a", "b"},
+			expected: &BitOpArgs{Op: bitfn.OpAnd, Dest: "dest", Keys: []string{"a", "b"}},
+		},
+		"when NOT given a single key": {
+			payloads: []string{"NOT", "dest", "a"},
+			expected: &BitOpArgs{Op: bitfn.OpNot, Dest: "dest", Keys: []string{"a"}},
+		},
+		"when NOT given several keys": {
+			payloads:      []string{"NOT", "dest", "a", "b"},
+			expectedError: "ERR BITOP NOT must be called with a single source key.",
+		},
+		"when DIFF given a single key": {
+			payloads:      []string{"diff", "dest", "a"},
+			expectedError: "ERR BITOP DIFF must be called with at least two source keys.",
+		},
+		"when operation is unknown": {
+			payloads:      []string{"NAND", "dest", "a"},
+			expectedError: argparser.ErrSyntax.Error(),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			args, err := ParseBitOpArgs(tc.payloads)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, args)
+		})
+	}
+}
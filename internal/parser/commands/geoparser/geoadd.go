@@ -0,0 +1,99 @@
+package geoparser
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/geo"
+)
+
+var ErrUnsupportedUnit = errors.New("ERR unsupported unit provided. please use M, KM, FT, MI")
+
+// units are the distance units, as their length in meters
+var units = map[string]float64{
+	"m":  1,
+	"km": 1000,
+	"ft": 0.3048,
+	"mi": 1609.34,
+}
+
+type Location struct {
+	Member   string
+	Lon, Lat float64
+}
+
+type GeoAddArgs struct {
+	NX, XX    bool
+	CH        bool
+	Locations []Location
+}
+
+// ParseGeoAddArgs parses [NX|XX] [CH] longitude latitude member [longitude
+// latitude member ...], the arguments of GEOADD after the key.
+func ParseGeoAddArgs(payloads []string) (*GeoAddArgs, error) {
+	args := &GeoAddArgs{}
+
+	i := 0
+options:
+	for ; i < len(payloads); i++ {
+		switch strings.ToUpper(payloads[i]) {
+		case "NX":
+			args.NX = true
+		case "XX":
+			args.XX = true
+		case "CH":
+			args.CH = true
+		default:
+			break options
+		}
+	}
+
+	triples := payloads[i:]
+	if len(triples) == 0 || len(triples)%3 != 0 || (args.NX && args.XX) {
+		return nil, argparser.ErrSyntax
+	}
+
+	for j := 0; j < len(triples); j += 3 {
+		lon, lat, err := ParseCoordinates(triples[j], triples[j+1])
+		if err != nil {
+			return nil, err
+		}
+
+		args.Locations = append(args.Locations, Location{Member: triples[j+2], Lon: lon, Lat: lat})
+	}
+
+	return args, nil
+}
+
+// ParseCoordinates parses a longitude and a latitude, which must be in the
+// range that can be encoded.
+func ParseCoordinates(lonArg, latArg string) (float64, float64, error) {
+	lon, err := strconv.ParseFloat(lonArg, 64)
+	if err != nil {
+		return 0, 0, argparser.ErrNotFloat
+	}
+
+	lat, err := strconv.ParseFloat(latArg, 64)
+	if err != nil {
+		return 0, 0, argparser.ErrNotFloat
+	}
+
+	if !geo.Valid(lon, lat) {
+		return 0, 0, fmt.Errorf("ERR invalid longitude,latitude pair %f,%f", lon, lat)
+	}
+
+	return lon, lat, nil
+}
+
+// ParseUnit returns the length in meters of the unit.
+func ParseUnit(arg string) (float64, error) {
+	conversion, exists := units[strings.ToLower(arg)]
+	if !exists {
+		return 0, ErrUnsupportedUnit
+	}
+
+	return conversion, nil
+}
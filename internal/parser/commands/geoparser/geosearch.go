@@ -0,0 +1,178 @@
+package geoparser
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+)
+
+var (
+	ErrNegativeRadius   = errors.New("ERR radius cannot be negative")
+	ErrNegativeBox      = errors.New("ERR height or width cannot be negative")
+	ErrCountNotPositive = errors.New("ERR COUNT must be > 0")
+	ErrAnyWithoutCount  = errors.New("ERR the ANY argument requires COUNT argument")
+)
+
+// Sort is the order of the results of GEOSEARCH
+type Sort int
+
+const (
+	SortNone Sort = iota
+	SortAsc
+	SortDesc
+)
+
+type GeoSearchArgs struct {
+	// the center, either a member or coordinates
+	FromMember    string
+	HasFromMember bool
+	Lon, Lat      float64
+
+	// the shape, in Unit
+	Box           bool
+	Radius        float64
+	Width, Height float64
+	Unit          float64 // length of the unit in meters
+
+	Sort  Sort
+	Count int // 0 means all
+	Any   bool
+
+	WithCoord bool
+	WithDist  bool
+	WithHash  bool
+	StoreDist bool
+}
+
+// ParseGeoSearchArgs parses the arguments of GEOSEARCH after the key, or the
+// ones of GEOSEARCHSTORE after the source when store is set:
+//
+//	FROMMEMBER member | FROMLONLAT longitude latitude
+//	BYRADIUS radius unit | BYBOX width height unit
+//	[ASC|DESC] [COUNT count [ANY]] [WITHCOORD] [WITHDIST] [WITHHASH] [STOREDIST]
+func ParseGeoSearchArgs(name string, payloads []string, store bool) (*GeoSearchArgs, error) {
+	args := &GeoSearchArgs{}
+	fromLonLat, byRadius := false, false
+
+	for i := 0; i < len(payloads); i++ {
+		remaining := len(payloads) - i - 1
+
+		switch option := strings.ToUpper(payloads[i]); {
+		case option == "FROMMEMBER" && remaining >= 1:
+			args.FromMember = payloads[i+1]
+			args.HasFromMember = true
+			i++
+		case option == "FROMLONLAT" && remaining >= 2:
+			lon, lat, err := ParseCoordinates(payloads[i+1], payloads[i+2])
+			if err != nil {
+				return nil, err
+			}
+
+			args.Lon, args.Lat = lon, lat
+			fromLonLat = true
+			i += 2
+		case option == "BYRADIUS" && remaining >= 2:
+			radius, err := parseNumber(payloads[i+1], "radius")
+			if err != nil {
+				return nil, err
+			}
+
+			if radius < 0 {
+				return nil, ErrNegativeRadius
+			}
+
+			if args.Unit, err = ParseUnit(payloads[i+2]); err != nil {
+				return nil, err
+			}
+
+			args.Radius = radius
+			byRadius = true
+			i += 2
+		case option == "BYBOX" && remaining >= 3:
+			width, err := parseNumber(payloads[i+1], "width")
+			if err != nil {
+				return nil, err
+			}
+
+			height, err := parseNumber(payloads[i+2], "height")
+			if err != nil {
+				return nil, err
+			}
+
+			if width < 0 || height < 0 {
+				return nil, ErrNegativeBox
+			}
+
+			if args.Unit, err = ParseUnit(payloads[i+3]); err != nil {
+				return nil, err
+			}
+
+			args.Width, args.Height = width, height
+			args.Box = true
+			i += 3
+		case option == "ASC":
+			args.Sort = SortAsc
+		case option == "DESC":
+			args.Sort = SortDesc
+		case option == "COUNT" && remaining >= 1:
+			count, err := argparser.ParseInt(payloads[i+1])
+			if err != nil {
+				return nil, err
+			}
+
+			if count <= 0 {
+				return nil, ErrCountNotPositive
+			}
+
+			args.Count = count
+			i++
+		case option == "ANY":
+			args.Any = true
+		case option == "WITHCOORD":
+			args.WithCoord = true
+		case option == "WITHDIST":
+			args.WithDist = true
+		case option == "WITHHASH":
+			args.WithHash = true
+		case option == "STOREDIST" && store:
+			args.StoreDist = true
+		default:
+			return nil, argparser.ErrSyntax
+		}
+	}
+
+	if args.HasFromMember == fromLonLat {
+		return nil, fmt.Errorf("ERR exactly one of FROMMEMBER or FROMLONLAT can be specified for %s", name)
+	}
+
+	if byRadius == args.Box {
+		return nil, fmt.Errorf("ERR exactly one of BYRADIUS and BYBOX can be specified for %s", name)
+	}
+
+	if args.Any && args.Count == 0 {
+		return nil, ErrAnyWithoutCount
+	}
+
+	if store && (args.WithCoord || args.WithDist || args.WithHash) {
+		return nil, fmt.Errorf("ERR %s is not compatible with WITHDIST, WITHHASH and WITHCOORD options", name)
+	}
+
+	// the closest members are expected with COUNT, unless any will do
+	if args.Count > 0 && !args.Any && args.Sort == SortNone {
+		args.Sort = SortAsc
+	}
+
+	return args, nil
+}
+
+func parseNumber(arg, what string) (float64, error) {
+	value, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		return 0, fmt.Errorf("ERR need numeric %s", what)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,86 @@
+package geoparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseGeoSearchArgs(t *testing.T) {
+	testCases := map[string]struct {
+		payloads      []string
+		store         bool
+		expectedArgs  *GeoSearchArgs
+		expectedError string
+	}{
+		"when searching by radius from a member": {
+			payloads:     []string{"FROMMEMBER", "a", "BYRADIUS", "10", "km", "WITHDIST"},
+			expectedArgs: &GeoSearchArgs{FromMember: "a", HasFromMember: true, Radius: 10, Unit: 1000, WithDist: true},
+		},
+		"when searching by box from coordinates": {
+			payloads:     []string{"fromlonlat", "15", "37", "bybox", "400", "200", "m", "desc"},
+			expectedArgs: &GeoSearchArgs{Lon: 15, Lat: 37, Box: true, Width: 400, Height: 200, Unit: 1, Sort: SortDesc},
+		},
+		"when count given": {
+			payloads:     []string{"FROMMEMBER", "a", "BYRADIUS", "10", "m", "COUNT", "3"},
+			expectedArgs: &GeoSearchArgs{FromMember: "a", HasFromMember: true, Radius: 10, Unit: 1, Count: 3, Sort: SortAsc},
+		},
+		"when count given with any": {
+			payloads:     []string{"FROMMEMBER", "a", "BYRADIUS", "10", "m", "COUNT", "3", "ANY"},
+			expectedArgs: &GeoSearchArgs{FromMember: "a", HasFromMember: true, Radius: 10, Unit: 1, Count: 3, Any: true},
+		},
+		"when storing distances": {
+			payloads:     []string{"FROMMEMBER", "a", "BYRADIUS", "10", "m", "STOREDIST"},
+			store:        true,
+			expectedArgs: &GeoSearchArgs{FromMember: "a", HasFromMember: true, Radius: 10, Unit: 1, StoreDist: true},
+		},
+		"when any given without count": {
+			payloads:      []string{"FROMMEMBER", "a", "BYRADIUS", "10", "m", "ANY"},
+			expectedError: ErrAnyWithoutCount.Error(),
+		},
+		"when both a member and coordinates given": {
+			payloads:      []string{"FROMMEMBER", "a", "FROMLONLAT", "15", "37", "BYRADIUS", "10", "m"},
+			expectedError: "ERR exactly one of FROMMEMBER or FROMLONLAT can be specified for GEOSEARCH",
+		},
+		"when no center given": {
+			payloads:      []string{"BYRADIUS", "10", "m"},
+			expectedError: "ERR exactly one of FROMMEMBER or FROMLONLAT can be specified for GEOSEARCH",
+		},
+		"when both a radius and a box given": {
+			payloads:      []string{"FROMMEMBER", "a", "BYRADIUS", "10", "m", "BYBOX", "1", "1", "m"},
+			expectedError: "ERR exactly one of BYRADIUS and BYBOX can be specified for GEOSEARCH",
+		},
+		"when storing with coordinates": {
+			payloads:      []string{"FROMMEMBER", "a", "BYRADIUS", "10", "m", "WITHCOORD"},
+			store:         true,
+			expectedError: "ERR GEOSEARCH is not compatible with WITHDIST, WITHHASH and WITHCOORD options",
+		},
+		"when storing distances without store": {
+			payloads:      []string{"FROMMEMBER", "a", "BYRADIUS", "10", "m", "STOREDIST"},
+			expectedError: "ERR syntax error",
+		},
+		"when count is zero": {
+			payloads:      []string{"FROMMEMBER", "a", "BYRADIUS", "10", "m", "COUNT", "0"},
+			expectedError: ErrCountNotPositive.Error(),
+		},
+		"when radius is negative": {
+			payloads:      []string{"FROMMEMBER", "a", "BYRADIUS", "-1", "m"},
+			expectedError: ErrNegativeRadius.Error(),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			args, err := ParseGeoSearchArgs("GEOSEARCH", tc.payloads, tc.store)
+
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedArgs, args)
+		})
+	}
+}
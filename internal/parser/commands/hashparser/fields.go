@@ -0,0 +1,59 @@
+package hashparser
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+)
+
+var (
+	ErrFieldsMissing        = errors.New("ERR Mandatory argument FIELDS is missing or not at the right position")
+	ErrNumFieldsNotPositive = errors.New("ERR Parameter `numFields` should be greater than 0")
+	ErrNumFieldsMismatch    = errors.New("ERR The `numfields` parameter must match the number of arguments")
+)
+
+// ParseFields parses FIELDS numfields field [field ...], which ends the
+// arguments of the field expiration commands.
+func ParseFields(payloads []string) ([]string, error) {
+	if len(payloads) < 2 || strings.ToUpper(payloads[0]) != "FIELDS" {
+		return nil, ErrFieldsMissing
+	}
+
+	numFields, err := argparser.ParseInt(payloads[1])
+	if err != nil {
+		return nil, err
+	}
+
+	if numFields <= 0 {
+		return nil, ErrNumFieldsNotPositive
+	}
+
+	if numFields != len(payloads)-2 {
+		return nil, ErrNumFieldsMismatch
+	}
+
+	return payloads[2:], nil
+}
+
+// ParseExpireFields parses [NX | XX | GT | LT] FIELDS numfields field
+// [field ...], the arguments following the time of HEXPIRE and friends. The
+// condition is returned uppercased, empty if none given.
+func ParseExpireFields(payloads []string) (string, []string, error) {
+	condition := ""
+
+	if len(payloads) > 0 {
+		switch option := strings.ToUpper(payloads[0]); option {
+		case "NX", "XX", "GT", "LT":
+			condition = option
+			payloads = payloads[1:]
+		}
+	}
+
+	fields, err := ParseFields(payloads)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return condition, fields, nil
+}
@@ -0,0 +1,53 @@
+package hashparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseExpireFields(t *testing.T) {
+	testCases := map[string]struct {
+		payloads          []string
+		expectedCondition string
+		expectedFields    []string
+		expectedError     error
+	}{
+		"when no condition given": {
+			payloads:       []string{"FIELDS", "2", "a", "b"},
+			expectedFields: []string{"a", "b"},
+		},
+		"when condition given": {
+			payloads:          []string{"gt", "fields", "1", "a"},
+			expectedCondition: "GT",
+			expectedFields:    []string{"a"},
+		},
+		"when FIELDS is missing": {
+			payloads:      []string{"NX", "1", "a"},
+			expectedError: ErrFieldsMissing,
+		},
+		"when numfields is zero": {
+			payloads:      []string{"FIELDS", "0"},
+			expectedError: ErrNumFieldsNotPositive,
+		},
+		"when numfields doesn't match": {
+			payloads:      []string{"FIELDS", "2", "a"},
+			expectedError: ErrNumFieldsMismatch,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			condition, fields, err := ParseExpireFields(tc.payloads)
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCondition, condition)
+			assert.Equal(t, tc.expectedFields, fields)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package keyparser
+
+import (
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+)
+
+type CopyArgs struct {
+	DB      int
+	HasDB   bool
+	Replace bool
+}
+
+// ParseCopyArgs parses the options of COPY: [DB destination-db] [REPLACE]
+func ParseCopyArgs(payloads []string) (*CopyArgs, error) {
+	args := &CopyArgs{}
+
+	for i := 0; i < len(payloads); i++ {
+		switch strings.ToUpper(payloads[i]) {
+		case "REPLACE":
+			args.Replace = true
+		case "DB":
+			if i+1 >= len(payloads) {
+				return nil, argparser.ErrSyntax
+			}
+
+			db, err := argparser.ParseInt(payloads[i+1])
+			if err != nil {
+				return nil, err
+			}
+
+			args.DB, args.HasDB = db, true
+			i++
+		default:
+			return nil, argparser.ErrSyntax
+		}
+	}
+
+	return args, nil
+}
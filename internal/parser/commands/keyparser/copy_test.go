@@ -0,0 +1,51 @@
+package keyparser
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCopyArgs(t *testing.T) {
+	testCases := map[string]struct {
+		payloads      []string
+		expected      *CopyArgs
+		expectedError error
+	}{
+		"when no option given": {
+			payloads: []string{},
+			expected: &CopyArgs{},
+		},
+		"when all options given": {
+			payloads: []string{"replace", "DB", "3"},
+			expected: &CopyArgs{DB: 3, HasDB: true, Replace: true},
+		},
+		"when DB is not an integer": {
+			payloads:      []string{"DB", "x"},
+			expectedError: argparser.ErrNotInteger,
+		},
+		"when DB has no value": {
+			payloads:      []string{"DB"},
+			expectedError: argparser.ErrSyntax,
+		},
+		"when option is unknown": {
+			payloads:      []string{"FOO"},
+			expectedError: argparser.ErrSyntax,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			args, err := ParseCopyArgs(tc.payloads)
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, args)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package listparser
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+)
+
+var (
+	ErrNumKeysNotPositive = argparser.ErrNumKeysNotPositive
+	ErrCountNotPositive   = errors.New("ERR count should be greater than 0")
+)
+
+type LMPopArgs struct {
+	Keys  []string
+	Left  bool
+	Count int
+}
+
+// ParseLMPopArgs parses numkeys key [key ...] LEFT|RIGHT [COUNT count], the
+// arguments shared by LMPOP and BLMPOP.
+func ParseLMPopArgs(payloads []string) (*LMPopArgs, error) {
+	numKeys, err := argparser.ParseInt(payloads[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if numKeys <= 0 {
+		return nil, ErrNumKeysNotPositive
+	}
+
+	if len(payloads) < numKeys+2 {
+		return nil, argparser.ErrSyntax
+	}
+
+	args := &LMPopArgs{
+		Keys:  payloads[1 : numKeys+1],
+		Count: 1,
+	}
+
+	switch strings.ToUpper(payloads[numKeys+1]) {
+	case "LEFT":
+		args.Left = true
+	case "RIGHT":
+	default:
+		return nil, argparser.ErrSyntax
+	}
+
+	options := payloads[numKeys+2:]
+
+	switch {
+	case len(options) == 0:
+	case len(options) == 2 && strings.ToUpper(options[0]) == "COUNT":
+		count, err := argparser.ParseInt(options[1])
+		if err != nil || count <= 0 {
+			return nil, ErrCountNotPositive
+		}
+
+		args.Count = count
+	default:
+		return nil, argparser.ErrSyntax
+	}
+
+	return args, nil
+}
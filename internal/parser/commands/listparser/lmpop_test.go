@@ -0,0 +1,55 @@
+package listparser
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLMPopArgs(t *testing.T) {
+	testCases := map[string]struct {
+		payloads      []string
+		expectedArgs  *LMPopArgs
+		expectedError error
+	}{
+		"when single key given": {
+			payloads:     []string{"1", "list", "LEFT"},
+			expectedArgs: &LMPopArgs{Keys: []string{"list"}, Left: true, Count: 1},
+		},
+		"when count given": {
+			payloads:     []string{"2", "list-1", "list-2", "right", "count", "3"},
+			expectedArgs: &LMPopArgs{Keys: []string{"list-1", "list-2"}, Count: 3},
+		},
+		"when numkeys is zero": {
+			payloads:      []string{"0", "list", "LEFT"},
+			expectedError: ErrNumKeysNotPositive,
+		},
+		"when keys are missing": {
+			payloads:      []string{"3", "list", "LEFT"},
+			expectedError: argparser.ErrSyntax,
+		},
+		"when side is invalid": {
+			payloads:      []string{"1", "list", "MIDDLE"},
+			expectedError: argparser.ErrSyntax,
+		},
+		"when count is zero": {
+			payloads:      []string{"1", "list", "LEFT", "COUNT", "0"},
+			expectedError: ErrCountNotPositive,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			args, err := ParseLMPopArgs(tc.payloads)
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedArgs, args)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package listparser
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+)
+
+var (
+	ErrZeroRank       = errors.New("ERR RANK can't be zero: use 1 to start from the first match, 2 from the second ... or use negative to start from the last match")
+	ErrNegativeCount  = errors.New("ERR COUNT can't be negative")
+	ErrNegativeMaxLen = errors.New("ERR MAXLEN can't be negative")
+)
+
+type LPosOptions struct {
+	Rank     int
+	Count    int
+	HasCount bool // the reply is an array only when COUNT is given
+	MaxLen   int
+}
+
+// ParseLPosOptions parses the [RANK rank] [COUNT num-matches] [MAXLEN len]
+// options of LPOS.
+func ParseLPosOptions(payloads []string) (*LPosOptions, error) {
+	options := &LPosOptions{Rank: 1}
+
+	if len(payloads)%2 != 0 {
+		return nil, argparser.ErrSyntax
+	}
+
+	for i := 0; i < len(payloads); i += 2 {
+		value, err := argparser.ParseInt(payloads[i+1])
+		if err != nil {
+			return nil, err
+		}
+
+		switch strings.ToUpper(payloads[i]) {
+		case "RANK":
+			if value == 0 {
+				return nil, ErrZeroRank
+			}
+
+			options.Rank = value
+		case "COUNT":
+			if value < 0 {
+				return nil, ErrNegativeCount
+			}
+
+			options.Count = value
+			options.HasCount = true
+		case "MAXLEN":
+			if value < 0 {
+				return nil, ErrNegativeMaxLen
+			}
+
+			options.MaxLen = value
+		default:
+			return nil, argparser.ErrSyntax
+		}
+	}
+
+	return options, nil
+}
@@ -0,0 +1,59 @@
+package listparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLPosOptions(t *testing.T) {
+	testCases := map[string]struct {
+		payloads        []string
+		expectedOptions *LPosOptions
+		expectedError   bool
+	}{
+		"when no options given": {
+			payloads:        []string{},
+			expectedOptions: &LPosOptions{Rank: 1},
+		},
+		"when every option given": {
+			payloads:        []string{"rank", "-2", "COUNT", "0", "MAXLEN", "10"},
+			expectedOptions: &LPosOptions{Rank: -2, Count: 0, HasCount: true, MaxLen: 10},
+		},
+		"when rank is zero": {
+			payloads:      []string{"RANK", "0"},
+			expectedError: true,
+		},
+		"when count is negative": {
+			payloads:      []string{"COUNT", "-1"},
+			expectedError: true,
+		},
+		"when value is missing": {
+			payloads:      []string{"MAXLEN"},
+			expectedError: true,
+		},
+		"when value is not an integer": {
+			payloads:      []string{"MAXLEN", "abc"},
+			expectedError: true,
+		},
+		"when unknown option given": {
+			payloads:      []string{"FIRST", "1"},
+			expectedError: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			options, err := ParseLPosOptions(tc.payloads)
+
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedOptions, options)
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package scanparser
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+)
+
+var ErrInvalidCursor = errors.New("ERR invalid cursor")
+
+type ScanOptions struct {
+	Cursor uint64
+	Match  string // empty when every element matches
+	Count  int
+	// Type filters the keys of SCAN by type.
+	Type string
+	// NoValues leaves the values out of the reply of HSCAN.
+	NoValues bool
+}
+
+// ParseScanOptions parses cursor [MATCH pattern] [COUNT count] followed by
+// the options specific to each SCAN command, given in extra (TYPE,
+// NOVALUES).
+func ParseScanOptions(payloads []string, extra ...string) (*ScanOptions, error) {
+	cursor, err := strconv.ParseUint(payloads[0], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	options := &ScanOptions{Cursor: cursor, Count: 10}
+
+	for i := 1; i < len(payloads); i++ {
+		option := strings.ToUpper(payloads[i])
+
+		if option == "NOVALUES" && allowed(extra, option) {
+			options.NoValues = true
+			continue
+		}
+
+		if i+1 == len(payloads) {
+			return nil, argparser.ErrSyntax
+		}
+
+		switch {
+		case option == "MATCH":
+			options.Match = payloads[i+1]
+			if options.Match == "*" {
+				options.Match = ""
+			}
+		case option == "COUNT":
+			count, err := argparser.ParseInt(payloads[i+1])
+			if err != nil {
+				return nil, err
+			}
+
+			if count < 1 {
+				return nil, argparser.ErrSyntax
+			}
+
+			options.Count = count
+		case option == "TYPE" && allowed(extra, option):
+			options.Type = payloads[i+1]
+		default:
+			return nil, argparser.ErrSyntax
+		}
+
+		i++
+	}
+
+	return options, nil
+}
+
+func allowed(extra []string, option string) bool {
+	for _, o := range extra {
+		if o == option {
+			return true
+		}
+	}
+
+	return false
+}
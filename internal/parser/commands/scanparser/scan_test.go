@@ -0,0 +1,60 @@
+package scanparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScanOptions(t *testing.T) {
+	testCases := map[string]struct {
+		payloads        []string
+		extra           []string
+		expectedOptions *ScanOptions
+		expectedError   bool
+	}{
+		"when only cursor given": {
+			payloads:        []string{"0"},
+			expectedOptions: &ScanOptions{Count: 10},
+		},
+		"when match and count given": {
+			payloads:        []string{"42", "match", "user:*", "COUNT", "100"},
+			expectedOptions: &ScanOptions{Cursor: 42, Match: "user:*", Count: 100},
+		},
+		"when extra options given": {
+			payloads:        []string{"0", "TYPE", "hash", "NOVALUES"},
+			extra:           []string{"TYPE", "NOVALUES"},
+			expectedOptions: &ScanOptions{Count: 10, Type: "hash", NoValues: true},
+		},
+		"when extra option isn't allowed": {
+			payloads:      []string{"0", "NOVALUES"},
+			expectedError: true,
+		},
+		"when cursor is invalid": {
+			payloads:      []string{"-1"},
+			expectedError: true,
+		},
+		"when count is zero": {
+			payloads:      []string{"0", "COUNT", "0"},
+			expectedError: true,
+		},
+		"when value is missing": {
+			payloads:      []string{"0", "MATCH"},
+			expectedError: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			options, err := ParseScanOptions(tc.payloads, tc.extra...)
+			if tc.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedOptions, options)
+		})
+	}
+}
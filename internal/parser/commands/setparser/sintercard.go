@@ -0,0 +1,37 @@
+package setparser
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+)
+
+var ErrNegativeLimit = errors.New("ERR LIMIT can't be negative")
+
+// ParseSInterCardArgs parses numkeys key [key ...] [LIMIT limit] and returns
+// the keys with the limit, 0 meaning no limit.
+func ParseSInterCardArgs(payloads []string) ([]string, int, error) {
+	keys, options, err := argparser.ParseNumKeys(payloads)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	switch {
+	case len(options) == 0:
+		return keys, 0, nil
+	case len(options) == 2 && strings.ToUpper(options[0]) == "LIMIT":
+		limit, err := argparser.ParseInt(options[1])
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if limit < 0 {
+			return nil, 0, ErrNegativeLimit
+		}
+
+		return keys, limit, nil
+	}
+
+	return nil, 0, argparser.ErrSyntax
+}
@@ -0,0 +1,58 @@
+package setparser
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSInterCardArgs(t *testing.T) {
+	testCases := map[string]struct {
+		payloads      []string
+		expectedKeys  []string
+		expectedLimit int
+		expectedError error
+	}{
+		"when no limit given": {
+			payloads:     []string{"2", "set-1", "set-2"},
+			expectedKeys: []string{"set-1", "set-2"},
+		},
+		"when limit given": {
+			payloads:      []string{"1", "set", "limit", "5"},
+			expectedKeys:  []string{"set"},
+			expectedLimit: 5,
+		},
+		"when numkeys is zero": {
+			payloads:      []string{"0", "set"},
+			expectedError: argparser.ErrNumKeysNotPositive,
+		},
+		"when numkeys is too big": {
+			payloads:      []string{"3", "set-1", "set-2"},
+			expectedError: argparser.ErrTooManyKeys,
+		},
+		"when limit is negative": {
+			payloads:      []string{"1", "set", "LIMIT", "-1"},
+			expectedError: ErrNegativeLimit,
+		},
+		"when unknown option given": {
+			payloads:      []string{"1", "set", "COUNT", "1"},
+			expectedError: argparser.ErrSyntax,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			keys, limit, err := ParseSInterCardArgs(tc.payloads)
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedKeys, keys)
+			assert.Equal(t, tc.expectedLimit, limit)
+		})
+	}
+}
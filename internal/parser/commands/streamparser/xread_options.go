@@ -0,0 +1,79 @@
+package streamparser
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+)
+
+var ErrUnbalancedStreams = errors.New("ERR Unbalanced 'xread' list of streams: for each stream key an ID or '$' must be specified.")
+
+type XReadOptions struct {
+	Count int
+	// Block is the timeout of BLOCK, zero meaning forever. It's only set when
+	// HasBlock is.
+	Block    time.Duration
+	HasBlock bool
+	Keys     []string
+	IDs      []string
+}
+
+// ParseXReadOptions parses the arguments of
+// XREAD [COUNT count] [BLOCK milliseconds] STREAMS key [key ...] id [id ...]
+func ParseXReadOptions(payloads []string) (*XReadOptions, error) {
+	options := &XReadOptions{}
+
+	for i := 0; i < len(payloads); i++ {
+		switch strings.ToUpper(payloads[i]) {
+		case "STREAMS":
+			if (len(payloads)-i)%2 == 0 {
+				return nil, ErrUnbalancedStreams
+			}
+
+			keys, ids, err := ParseXReadCommand(payloads[i:])
+			if err != nil {
+				return nil, argparser.ErrSyntax
+			}
+
+			options.Keys = keys
+			options.IDs = ids
+
+			return options, nil
+		case "COUNT":
+			if i+1 == len(payloads) {
+				return nil, argparser.ErrSyntax
+			}
+
+			count, err := argparser.ParseInt(payloads[i+1])
+			if err != nil {
+				return nil, err
+			}
+
+			options.Count = count
+			i++
+		case "BLOCK":
+			if i+1 == len(payloads) {
+				return nil, argparser.ErrSyntax
+			}
+
+			ms, err := argparser.ParseInt(payloads[i+1])
+			if err != nil {
+				return nil, errors.New("ERR timeout is not an integer or out of range")
+			}
+
+			if ms < 0 {
+				return nil, argparser.ErrNegativeTimeout
+			}
+
+			options.Block = time.Duration(ms) * time.Millisecond
+			options.HasBlock = true
+			i++
+		default:
+			return nil, argparser.ErrSyntax
+		}
+	}
+
+	return nil, argparser.ErrSyntax
+}
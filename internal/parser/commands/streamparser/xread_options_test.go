@@ -0,0 +1,57 @@
+package streamparser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseXReadOptions(t *testing.T) {
+	testCases := map[string]struct {
+		payloads        []string
+		expectedOptions *XReadOptions
+		expectedError   bool
+	}{
+		"when only streams given": {
+			payloads:        []string{"STREAMS", "key", "0-0"},
+			expectedOptions: &XReadOptions{Keys: []string{"key"}, IDs: []string{"0-0"}},
+		},
+		"when count and block given": {
+			payloads: []string{"count", "2", "block", "1500", "streams", "key-1", "key-2", "0-1", "$"},
+			expectedOptions: &XReadOptions{
+				Count:    2,
+				Block:    1500 * time.Millisecond,
+				HasBlock: true,
+				Keys:     []string{"key-1", "key-2"},
+				IDs:      []string{"0-1", "$"},
+			},
+		},
+		"when block is negative": {
+			payloads:      []string{"BLOCK", "-1", "STREAMS", "key", "0-0"},
+			expectedError: true,
+		},
+		"when streams are unbalanced": {
+			payloads:      []string{"STREAMS", "key-1", "key-2", "0-0"},
+			expectedError: true,
+		},
+		"when streams are missing": {
+			payloads:      []string{"COUNT", "1"},
+			expectedError: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			options, err := ParseXReadOptions(tc.payloads)
+			if tc.expectedError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedOptions, options)
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package stringparser
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+)
+
+// Expiration is the expiration option of SET and GETEX.
+type Expiration struct {
+	Ms       int64 // milliseconds, relative to now unless Absolute is set
+	Absolute bool
+	KeepTTL  bool
+	Persist  bool
+}
+
+// IsSet returns whether an expiration time was given.
+func (e Expiration) IsSet() bool {
+	return e.Ms != 0
+}
+
+// At returns the expiration time as unix milliseconds, and false if it
+// overflows.
+func (e Expiration) At(now int64) (int64, bool) {
+	if e.Absolute {
+		return e.Ms, true
+	}
+
+	if e.Ms > math.MaxInt64-now {
+		return 0, false
+	}
+
+	return e.Ms + now, true
+}
+
+// InvalidExpireTime is the error of an expiration time which is not positive
+// or overflows.
+func InvalidExpireTime(command string) error {
+	return fmt.Errorf("ERR invalid expire time in '%s' command", command)
+}
+
+// parseExpiration parses the value of EX, PX, EXAT or PXAT.
+func parseExpiration(command, option, arg string) (Expiration, error) {
+	value, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		return Expiration{}, argparser.ErrNotInteger
+	}
+
+	if value <= 0 {
+		return Expiration{}, InvalidExpireTime(command)
+	}
+
+	option = strings.ToUpper(option)
+	if option == "EX" || option == "EXAT" {
+		if value > math.MaxInt64/1000 {
+			return Expiration{}, InvalidExpireTime(command)
+		}
+
+		value *= 1000
+	}
+
+	return Expiration{Ms: value, Absolute: strings.HasSuffix(option, "AT")}, nil
+}
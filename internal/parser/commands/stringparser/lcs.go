@@ -0,0 +1,57 @@
+package stringparser
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+)
+
+var ErrLenAndIdx = errors.New("ERR If you want both the length and indexes, please just use IDX.")
+
+type LCSArgs struct {
+	Len          bool
+	Idx          bool
+	MinMatchLen  int
+	WithMatchLen bool
+}
+
+// ParseLCSArgs parses the options of LCS: [LEN] [IDX] [MINMATCHLEN len]
+// [WITHMATCHLEN]
+func ParseLCSArgs(payloads []string) (*LCSArgs, error) {
+	args := &LCSArgs{}
+
+	for i := 0; i < len(payloads); i++ {
+		switch strings.ToUpper(payloads[i]) {
+		case "LEN":
+			args.Len = true
+		case "IDX":
+			args.Idx = true
+		case "WITHMATCHLEN":
+			args.WithMatchLen = true
+		case "MINMATCHLEN":
+			if i+1 >= len(payloads) {
+				return nil, argparser.ErrSyntax
+			}
+
+			minMatchLen, err := argparser.ParseInt(payloads[i+1])
+			if err != nil {
+				return nil, err
+			}
+
+			if minMatchLen > 0 {
+				args.MinMatchLen = minMatchLen
+			}
+
+			i++
+		default:
+			return nil, argparser.ErrSyntax
+		}
+	}
+
+	if args.Len && args.Idx {
+		return nil, ErrLenAndIdx
+	}
+
+	return args, nil
+}
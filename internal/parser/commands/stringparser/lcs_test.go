@@ -0,0 +1,55 @@
+package stringparser
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLCSArgs(t *testing.T) {
+	testCases := map[string]struct {
+		payloads      []string
+		expected      *LCSArgs
+		expectedError error
+	}{
+		"when no option given": {
+			payloads: []string{},
+			expected: &LCSArgs{},
+		},
+		"when IDX options given": {
+			payloads: []string{"idx", "MINMATCHLEN", "4", "WITHMATCHLEN"},
+			expected: &LCSArgs{Idx: true, MinMatchLen: 4, WithMatchLen: true},
+		},
+		"when MINMATCHLEN is negative": {
+			payloads: []string{"IDX", "MINMATCHLEN", "-4"},
+			expected: &LCSArgs{Idx: true},
+		},
+		"when LEN and IDX given": {
+			payloads:      []string{"LEN", "IDX"},
+			expectedError: ErrLenAndIdx,
+		},
+		"when MINMATCHLEN is not an integer": {
+			payloads:      []string{"MINMATCHLEN", "x"},
+			expectedError: argparser.ErrNotInteger,
+		},
+		"when option is unknown": {
+			payloads:      []string{"FOO"},
+			expectedError: argparser.ErrSyntax,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			args, err := ParseLCSArgs(tc.payloads)
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, args)
+		})
+	}
+}
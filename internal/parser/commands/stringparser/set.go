@@ -0,0 +1,84 @@
+package stringparser
+
+import (
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+)
+
+type SetArgs struct {
+	NX, XX bool
+	Get    bool
+	Expiration
+}
+
+// ParseSetArgs parses the options of SET: [NX|XX] [GET] [EX seconds|PX
+// milliseconds|EXAT unix-time-seconds|PXAT unix-time-milliseconds|KEEPTTL]
+func ParseSetArgs(payloads []string) (*SetArgs, error) {
+	args := &SetArgs{}
+	hasExpiration := false
+
+	for i := 0; i < len(payloads); i++ {
+		option := strings.ToUpper(payloads[i])
+
+		switch option {
+		case "NX":
+			if args.XX {
+				return nil, argparser.ErrSyntax
+			}
+
+			args.NX = true
+		case "XX":
+			if args.NX {
+				return nil, argparser.ErrSyntax
+			}
+
+			args.XX = true
+		case "GET":
+			args.Get = true
+		case "KEEPTTL":
+			if hasExpiration {
+				return nil, argparser.ErrSyntax
+			}
+
+			hasExpiration = true
+			args.KeepTTL = true
+		case "EX", "PX", "EXAT", "PXAT":
+			if hasExpiration || i+1 >= len(payloads) {
+				return nil, argparser.ErrSyntax
+			}
+
+			expiration, err := parseExpiration("set", option, payloads[i+1])
+			if err != nil {
+				return nil, err
+			}
+
+			hasExpiration = true
+			args.Expiration = expiration
+			i++
+		default:
+			return nil, argparser.ErrSyntax
+		}
+	}
+
+	return args, nil
+}
+
+// ParseGetExArgs parses the options of GETEX: [EX seconds|PX
+// milliseconds|EXAT unix-time-seconds|PXAT unix-time-milliseconds|PERSIST]
+func ParseGetExArgs(payloads []string) (Expiration, error) {
+	if len(payloads) == 0 {
+		return Expiration{}, nil
+	}
+
+	option := strings.ToUpper(payloads[0])
+
+	switch {
+	case option == "PERSIST" && len(payloads) == 1:
+		return Expiration{Persist: true}, nil
+	case (option == "EX" || option == "PX" || option == "EXAT" || option == "PXAT") && len(payloads) == 2:
+		return parseExpiration("getex", option, payloads[1])
+	default:
+		return Expiration{}, argparser.ErrSyntax
+	}
+}
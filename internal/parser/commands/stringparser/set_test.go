@@ -0,0 +1,113 @@
+package stringparser
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSetArgs(t *testing.T) {
+	testCases := map[string]struct {
+		payloads      []string
+		expected      *SetArgs
+		expectedError string
+	}{
+		"when no option given": {
+			payloads: []string{},
+			expected: &SetArgs{},
+		},
+		"when NX, GET and EX given": {
+			payloads: []string{"nx", "GET", "ex", "10"},
+			expected: &SetArgs{NX: true, Get: true, Expiration: Expiration{Ms: 10000}},
+		},
+		"when PXAT given": {
+			payloads: []string{"PXAT", "1700000000000"},
+			expected: &SetArgs{Expiration: Expiration{Ms: 1700000000000, Absolute: true}},
+		},
+		"when KEEPTTL and XX given": {
+			payloads: []string{"KEEPTTL", "XX"},
+			expected: &SetArgs{XX: true, Expiration: Expiration{KeepTTL: true}},
+		},
+		"when NX and XX given": {
+			payloads:      []string{"NX", "XX"},
+			expectedError: argparser.ErrSyntax.Error(),
+		},
+		"when two expirations given": {
+			payloads:      []string{"EX", "10", "KEEPTTL"},
+			expectedError: argparser.ErrSyntax.Error(),
+		},
+		"when expiration value is missing": {
+			payloads:      []string{"PX"},
+			expectedError: argparser.ErrSyntax.Error(),
+		},
+		"when expiration is not an integer": {
+			payloads:      []string{"EX", "ten"},
+			expectedError: argparser.ErrNotInteger.Error(),
+		},
+		"when expiration is zero": {
+			payloads:      []string{"EX", "0"},
+			expectedError: "ERR invalid expire time in 'set' command",
+		},
+		"when expiration overflows": {
+			payloads:      []string{"EX", "9223372036854775807"},
+			expectedError: "ERR invalid expire time in 'set' command",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			args, err := ParseSetArgs(tc.payloads)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, args)
+		})
+	}
+}
+
+func TestParseGetExArgs(t *testing.T) {
+	testCases := map[string]struct {
+		payloads      []string
+		expected      Expiration
+		expectedError string
+	}{
+		"when no option given": {
+			payloads: []string{},
+			expected: Expiration{},
+		},
+		"when PERSIST given": {
+			payloads: []string{"persist"},
+			expected: Expiration{Persist: true},
+		},
+		"when EXAT given": {
+			payloads: []string{"EXAT", "1700000000"},
+			expected: Expiration{Ms: 1700000000000, Absolute: true},
+		},
+		"when expiration is negative": {
+			payloads:      []string{"PX", "-1"},
+			expectedError: "ERR invalid expire time in 'getex' command",
+		},
+		"when two options given": {
+			payloads:      []string{"PX", "10", "PERSIST"},
+			expectedError: argparser.ErrSyntax.Error(),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			expiration, err := ParseGetExArgs(tc.payloads)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, expiration)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package zsetparser
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/zset"
+)
+
+var ErrWeightNotFloat = errors.New("ERR weight value is not a float")
+
+type CombineArgs struct {
+	Keys       []string
+	Weights    []float64
+	Aggregate  zset.Aggregate
+	WithScores bool
+}
+
+// ParseCombineArgs parses numkeys key [key ...] [WEIGHTS weight [weight
+// ...]] [AGGREGATE SUM|MIN|MAX] [WITHSCORES], the arguments of ZUNION and the
+// like. ZDIFF doesn't take weights nor an aggregate, and the commands
+// storing their result don't take WITHSCORES.
+func ParseCombineArgs(name string, payloads []string, allowWeights, allowWithScores bool) (*CombineArgs, error) {
+	numKeys, err := argparser.ParseInt(payloads[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if numKeys < 1 {
+		return nil, fmt.Errorf("ERR at least 1 input key is needed for '%s' command", name)
+	}
+
+	if numKeys > len(payloads)-1 {
+		return nil, argparser.ErrSyntax
+	}
+
+	args := &CombineArgs{Keys: payloads[1 : numKeys+1]}
+
+	weights := make([]float64, numKeys)
+	for i := range weights {
+		weights[i] = 1
+	}
+
+	options := payloads[numKeys+1:]
+	for i := 0; i < len(options); i++ {
+		remaining := len(options) - i - 1
+
+		switch option := strings.ToUpper(options[i]); {
+		case option == "WEIGHTS" && allowWeights && remaining >= numKeys:
+			for j := range weights {
+				weight, err := strconv.ParseFloat(options[i+1+j], 64)
+				if err != nil {
+					return nil, ErrWeightNotFloat
+				}
+
+				weights[j] = weight
+			}
+
+			i += numKeys
+		case option == "AGGREGATE" && allowWeights && remaining >= 1:
+			switch strings.ToUpper(options[i+1]) {
+			case "SUM":
+				args.Aggregate = zset.AggregateSum
+			case "MIN":
+				args.Aggregate = zset.AggregateMin
+			case "MAX":
+				args.Aggregate = zset.AggregateMax
+			default:
+				return nil, argparser.ErrSyntax
+			}
+
+			i++
+		case option == "WITHSCORES" && allowWithScores:
+			args.WithScores = true
+		default:
+			return nil, argparser.ErrSyntax
+		}
+	}
+
+	args.Weights = weights
+
+	return args, nil
+}
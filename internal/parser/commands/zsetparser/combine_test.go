@@ -0,0 +1,77 @@
+package zsetparser
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/zset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCombineArgs(t *testing.T) {
+	testCases := map[string]struct {
+		payloads        []string
+		allowWeights    bool
+		allowWithScores bool
+		expected        *CombineArgs
+		expectedError   string
+	}{
+		"when only keys given": {
+			payloads:     []string{"2", "a", "b"},
+			allowWeights: true,
+			expected:     &CombineArgs{Keys: []string{"a", "b"}, Weights: []float64{1, 1}},
+		},
+		"when every option given": {
+			payloads:        []string{"2", "a", "b", "WEIGHTS", "2", "0.5", "aggregate", "max", "WITHSCORES"},
+			allowWeights:    true,
+			allowWithScores: true,
+			expected: &CombineArgs{
+				Keys:       []string{"a", "b"},
+				Weights:    []float64{2, 0.5},
+				Aggregate:  zset.AggregateMax,
+				WithScores: true,
+			},
+		},
+		"when numkeys is zero": {
+			payloads:      []string{"0", "a"},
+			expectedError: "ERR at least 1 input key is needed for 'zunion' command",
+		},
+		"when numkeys is too big": {
+			payloads:      []string{"3", "a", "b"},
+			expectedError: argparser.ErrSyntax.Error(),
+		},
+		"when too few weights given": {
+			payloads:      []string{"2", "a", "b", "WEIGHTS", "1"},
+			allowWeights:  true,
+			expectedError: argparser.ErrSyntax.Error(),
+		},
+		"when weight isn't a float": {
+			payloads:      []string{"1", "a", "WEIGHTS", "x"},
+			allowWeights:  true,
+			expectedError: ErrWeightNotFloat.Error(),
+		},
+		"when weights aren't allowed": {
+			payloads:      []string{"1", "a", "WEIGHTS", "1"},
+			expectedError: argparser.ErrSyntax.Error(),
+		},
+		"when WITHSCORES isn't allowed": {
+			payloads:      []string{"1", "a", "WITHSCORES"},
+			allowWeights:  true,
+			expectedError: argparser.ErrSyntax.Error(),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			args, err := ParseCombineArgs("zunion", tc.payloads, tc.allowWeights, tc.allowWithScores)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, args)
+		})
+	}
+}
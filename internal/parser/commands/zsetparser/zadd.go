@@ -0,0 +1,74 @@
+package zsetparser
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/zset"
+)
+
+var (
+	ErrXXAndNX        = errors.New("ERR XX and NX options at the same time are not compatible")
+	ErrGTLTAndNX      = errors.New("ERR GT, LT, and/or NX options at the same time are not compatible")
+	ErrIncrSinglePair = errors.New("ERR INCR option supports a single increment-element pair")
+)
+
+type ZAddArgs struct {
+	Flags   zset.AddFlags
+	CH      bool // reply with the number of changed members, not only added
+	Entries []zset.Entry
+}
+
+// ParseZAddArgs parses [NX|XX] [GT|LT] [CH] [INCR] score member
+// [score member ...], the arguments of ZADD after the key.
+func ParseZAddArgs(payloads []string) (*ZAddArgs, error) {
+	args := &ZAddArgs{}
+
+	i := 0
+options:
+	for ; i < len(payloads); i++ {
+		switch strings.ToUpper(payloads[i]) {
+		case "NX":
+			args.Flags.NX = true
+		case "XX":
+			args.Flags.XX = true
+		case "GT":
+			args.Flags.GT = true
+		case "LT":
+			args.Flags.LT = true
+		case "CH":
+			args.CH = true
+		case "INCR":
+			args.Flags.Incr = true
+		default:
+			break options
+		}
+	}
+
+	pairs := payloads[i:]
+	if len(pairs) == 0 || len(pairs)%2 != 0 {
+		return nil, argparser.ErrSyntax
+	}
+
+	flags := args.Flags
+	switch {
+	case flags.NX && flags.XX:
+		return nil, ErrXXAndNX
+	case (flags.GT && flags.LT) || (flags.NX && (flags.GT || flags.LT)):
+		return nil, ErrGTLTAndNX
+	case flags.Incr && len(pairs) > 2:
+		return nil, ErrIncrSinglePair
+	}
+
+	for j := 0; j < len(pairs); j += 2 {
+		score, err := zset.ParseScore(pairs[j])
+		if err != nil {
+			return nil, err
+		}
+
+		args.Entries = append(args.Entries, zset.Entry{Member: pairs[j+1], Score: score})
+	}
+
+	return args, nil
+}
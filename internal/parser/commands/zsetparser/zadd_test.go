@@ -0,0 +1,64 @@
+package zsetparser
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/zset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseZAddArgs(t *testing.T) {
+	testCases := map[string]struct {
+		payloads      []string
+		expected      *ZAddArgs
+		expectedError error
+	}{
+		"when only pairs given": {
+			payloads: []string{"1", "a", "2.5", "b"},
+			expected: &ZAddArgs{Entries: []zset.Entry{{Member: "a", Score: 1}, {Member: "b", Score: 2.5}}},
+		},
+		"when options given": {
+			payloads: []string{"xx", "GT", "ch", "-inf", "a"},
+			expected: &ZAddArgs{
+				Flags:   zset.AddFlags{XX: true, GT: true},
+				CH:      true,
+				Entries: []zset.Entry{{Member: "a", Score: negInf}},
+			},
+		},
+		"when NX and XX given": {
+			payloads:      []string{"NX", "XX", "1", "a"},
+			expectedError: ErrXXAndNX,
+		},
+		"when GT and LT given": {
+			payloads:      []string{"GT", "LT", "1", "a"},
+			expectedError: ErrGTLTAndNX,
+		},
+		"when INCR given with many pairs": {
+			payloads:      []string{"INCR", "1", "a", "2", "b"},
+			expectedError: ErrIncrSinglePair,
+		},
+		"when a member is missing": {
+			payloads:      []string{"1", "a", "2"},
+			expectedError: argparser.ErrSyntax,
+		},
+		"when score isn't a float": {
+			payloads:      []string{"one", "a"},
+			expectedError: zset.ErrInvalidScore,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			args, err := ParseZAddArgs(tc.payloads)
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, args)
+		})
+	}
+}
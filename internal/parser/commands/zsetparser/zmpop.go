@@ -0,0 +1,59 @@
+package zsetparser
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+)
+
+var ErrCountNotPositive = errors.New("ERR count should be greater than 0")
+
+type ZMPopArgs struct {
+	Keys  []string
+	Max   bool
+	Count int
+}
+
+// ParseZMPopArgs parses numkeys key [key ...] MIN|MAX [COUNT count], the
+// arguments shared by ZMPOP and BZMPOP.
+func ParseZMPopArgs(payloads []string) (*ZMPopArgs, error) {
+	keys, rest, err := argparser.ParseNumKeys(payloads)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rest) == 0 {
+		return nil, argparser.ErrSyntax
+	}
+
+	args := &ZMPopArgs{
+		Keys:  keys,
+		Count: 1,
+	}
+
+	switch strings.ToUpper(rest[0]) {
+	case "MAX":
+		args.Max = true
+	case "MIN":
+	default:
+		return nil, argparser.ErrSyntax
+	}
+
+	options := rest[1:]
+
+	switch {
+	case len(options) == 0:
+	case len(options) == 2 && strings.ToUpper(options[0]) == "COUNT":
+		count, err := argparser.ParseInt(options[1])
+		if err != nil || count <= 0 {
+			return nil, ErrCountNotPositive
+		}
+
+		args.Count = count
+	default:
+		return nil, argparser.ErrSyntax
+	}
+
+	return args, nil
+}
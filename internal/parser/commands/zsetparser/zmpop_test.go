@@ -0,0 +1,51 @@
+package zsetparser
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseZMPopArgs(t *testing.T) {
+	testCases := map[string]struct {
+		payloads      []string
+		expected      *ZMPopArgs
+		expectedError error
+	}{
+		"when MIN given": {
+			payloads: []string{"2", "a", "b", "min"},
+			expected: &ZMPopArgs{Keys: []string{"a", "b"}, Count: 1},
+		},
+		"when MAX and COUNT given": {
+			payloads: []string{"1", "a", "MAX", "COUNT", "3"},
+			expected: &ZMPopArgs{Keys: []string{"a"}, Max: true, Count: 3},
+		},
+		"when count is zero": {
+			payloads:      []string{"1", "a", "MIN", "COUNT", "0"},
+			expectedError: ErrCountNotPositive,
+		},
+		"when MIN or MAX is missing": {
+			payloads:      []string{"1", "a"},
+			expectedError: argparser.ErrSyntax,
+		},
+		"when numkeys is zero": {
+			payloads:      []string{"0", "a", "MIN"},
+			expectedError: argparser.ErrNumKeysNotPositive,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			args, err := ParseZMPopArgs(tc.payloads)
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, args)
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package zsetparser
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/zset"
+)
+
+var (
+	ErrLimitWithoutBy     = errors.New("ERR syntax error, LIMIT is only supported in combination with either BYSCORE or BYLEX")
+	ErrWithScoresAndByLex = errors.New("ERR syntax error, WITHSCORES not supported in combination with BYLEX")
+)
+
+// RangeBy is the kind of range of a ZRANGE
+type RangeBy int
+
+const (
+	ByRank RangeBy = iota
+	ByScore
+	ByLex
+)
+
+type ZRangeArgs struct {
+	By      RangeBy
+	Reverse bool
+
+	Start, Stop int // when by rank
+	Score       zset.ScoreRange
+	Lex         zset.LexRange
+
+	Offset int
+	Count  int // negative means all
+
+	WithScores bool
+}
+
+// ParseZRangeArgs parses start stop [BYSCORE|BYLEX] [REV] [LIMIT offset
+// count] [WITHSCORES], the arguments of ZRANGE after the key. With REV and
+// BYSCORE or BYLEX, start is the maximum and stop the minimum.
+func ParseZRangeArgs(payloads []string) (*ZRangeArgs, error) {
+	return parseZRangeArgs(payloads, ByRank, false, true)
+}
+
+// ParseLegacyZRangeArgs parses the arguments of ZREVRANGE, ZRANGEBYSCORE and
+// the like, for which the kind of range and its direction are implied by the
+// command.
+func ParseLegacyZRangeArgs(payloads []string, by RangeBy, reverse bool) (*ZRangeArgs, error) {
+	return parseZRangeArgs(payloads, by, reverse, false)
+}
+
+func parseZRangeArgs(payloads []string, by RangeBy, reverse bool, allowBy bool) (*ZRangeArgs, error) {
+	if len(payloads) < 2 {
+		return nil, argparser.ErrSyntax
+	}
+
+	args := &ZRangeArgs{By: by, Reverse: reverse, Count: -1}
+	hasLimit := false
+
+	for i := 2; i < len(payloads); i++ {
+		switch option := strings.ToUpper(payloads[i]); {
+		case option == "WITHSCORES":
+			args.WithScores = true
+		case option == "LIMIT" && i+2 < len(payloads):
+			offset, err := argparser.ParseInt(payloads[i+1])
+			if err != nil {
+				return nil, err
+			}
+
+			count, err := argparser.ParseInt(payloads[i+2])
+			if err != nil {
+				return nil, err
+			}
+
+			args.Offset, args.Count = offset, count
+			hasLimit = true
+			i += 2
+		case option == "BYSCORE" && allowBy:
+			args.By = ByScore
+		case option == "BYLEX" && allowBy:
+			args.By = ByLex
+		case option == "REV" && allowBy:
+			args.Reverse = true
+		default:
+			return nil, argparser.ErrSyntax
+		}
+	}
+
+	if hasLimit && args.By == ByRank {
+		return nil, ErrLimitWithoutBy
+	}
+
+	if args.WithScores && args.By == ByLex {
+		return nil, ErrWithScoresAndByLex
+	}
+
+	min, max := payloads[0], payloads[1]
+	if args.Reverse {
+		min, max = max, min
+	}
+
+	var err error
+
+	switch args.By {
+	case ByRank:
+		if args.Start, err = argparser.ParseInt(payloads[0]); err != nil {
+			return nil, err
+		}
+
+		if args.Stop, err = argparser.ParseInt(payloads[1]); err != nil {
+			return nil, err
+		}
+	case ByScore:
+		args.Score, err = zset.ParseScoreRange(min, max)
+	case ByLex:
+		args.Lex, err = zset.ParseLexRange(min, max)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return args, nil
+}
@@ -0,0 +1,79 @@
+package zsetparser
+
+import (
+	"math"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/parser/commands/argparser"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/zset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var negInf = math.Inf(-1)
+
+func TestParseZRangeArgs(t *testing.T) {
+	testCases := map[string]struct {
+		payloads      []string
+		expected      *ZRangeArgs
+		expectedError error
+	}{
+		"when ranks given": {
+			payloads: []string{"0", "-1", "WITHSCORES"},
+			expected: &ZRangeArgs{Start: 0, Stop: -1, Count: -1, WithScores: true},
+		},
+		"when scores given in reverse": {
+			payloads: []string{"(5", "-inf", "byscore", "rev", "LIMIT", "1", "2"},
+			expected: &ZRangeArgs{
+				By:      ByScore,
+				Reverse: true,
+				Score:   zset.ScoreRange{Min: negInf, Max: 5, MaxEx: true},
+				Offset:  1,
+				Count:   2,
+			},
+		},
+		"when LIMIT given without BYSCORE": {
+			payloads:      []string{"0", "1", "LIMIT", "0", "1"},
+			expectedError: ErrLimitWithoutBy,
+		},
+		"when WITHSCORES given with BYLEX": {
+			payloads:      []string{"-", "+", "BYLEX", "WITHSCORES"},
+			expectedError: ErrWithScoresAndByLex,
+		},
+		"when rank isn't an integer": {
+			payloads:      []string{"a", "1"},
+			expectedError: argparser.ErrNotInteger,
+		},
+		"when lex range is invalid": {
+			payloads:      []string{"a", "+", "BYLEX"},
+			expectedError: zset.ErrInvalidLexRange,
+		},
+		"when LIMIT is incomplete": {
+			payloads:      []string{"0", "1", "BYSCORE", "LIMIT", "0"},
+			expectedError: argparser.ErrSyntax,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			args, err := ParseZRangeArgs(tc.payloads)
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, args)
+		})
+	}
+}
+
+func TestParseLegacyZRangeArgs(t *testing.T) {
+	args, err := ParseLegacyZRangeArgs([]string{"1", "2", "WITHSCORES"}, ByScore, false)
+	require.NoError(t, err)
+	assert.Equal(t, zset.ScoreRange{Min: 1, Max: 2}, args.Score)
+	assert.True(t, args.WithScores)
+
+	_, err = ParseLegacyZRangeArgs([]string{"1", "2", "BYSCORE"}, ByScore, false)
+	assert.ErrorIs(t, err, argparser.ErrSyntax)
+}
@@ -0,0 +1,168 @@
+package parser
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+)
+
+// maxInlineLen is the maximum length of an inline request, 64KB as in
+// Redis, so that a client can't make the server buffer an endless line.
+const maxInlineLen = 64 * 1024
+
+// readInline reads a request sent as a line of space separated arguments,
+// the way telnet or a health check would. It returns a nil request for a
+// blank line.
+func (r *Reader) readInline() (*RedisRequest, error) {
+	line := []byte{}
+
+	for {
+		fragment, err := r.rd.ReadSlice('\n')
+		if len(line)+len(fragment) > maxInlineLen {
+			return nil, &ProtocolError{msg: "too big inline request"}
+		}
+
+		line = append(line, fragment...)
+
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read line: %w", err)
+		}
+
+		break
+	}
+
+	// a bare LF ends the line as well, as sent by netcat
+	line = line[:len(line)-1]
+	if len(line) > 0 && line[len(line)-1] == '\r' {
+		line = line[:len(line)-1]
+	}
+
+	args, err := splitArgs(line)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(args) == 0 {
+		return nil, nil
+	}
+
+	return &RedisRequest{Command: strings.ToUpper(string(args[0])), Payload: args[1:]}, nil
+}
+
+// splitArgs splits the line on spaces as redis-cli does. Arguments may be
+// double quoted, with the escape sequences \n, \r, \t, \b, \a, \\, \" and
+// \xHH, or single quoted where only \' is escaped. A closing quote must end
+// the argument.
+func splitArgs(line []byte) ([][]byte, error) {
+	args := [][]byte{}
+
+	for i := 0; ; {
+		for i < len(line) && isSpace(line[i]) {
+			i++
+		}
+
+		if i == len(line) {
+			return args, nil
+		}
+
+		arg := []byte{}
+		inDouble, inSingle := false, false
+
+		for done := false; !done; i++ {
+			if i == len(line) {
+				if inDouble || inSingle {
+					return nil, &ProtocolError{msg: "unbalanced quotes in request"}
+				}
+
+				break
+			}
+
+			c := line[i]
+
+			switch {
+			case inDouble:
+				switch {
+				case c == '\\' && i+3 < len(line) && line[i+1] == 'x' && isHexDigit(line[i+2]) && isHexDigit(line[i+3]):
+					arg = append(arg, hexValue(line[i+2])<<4|hexValue(line[i+3]))
+					i += 3
+				case c == '\\' && i+1 < len(line):
+					i++
+					arg = append(arg, unescape(line[i]))
+				case c == '"':
+					// the closing quote must be followed by a space or nothing
+					if i+1 < len(line) && !isSpace(line[i+1]) {
+						return nil, &ProtocolError{msg: "unbalanced quotes in request"}
+					}
+
+					done = true
+				default:
+					arg = append(arg, c)
+				}
+			case inSingle:
+				switch {
+				case c == '\\' && i+1 < len(line) && line[i+1] == '\'':
+					i++
+					arg = append(arg, '\'')
+				case c == '\'':
+					if i+1 < len(line) && !isSpace(line[i+1]) {
+						return nil, &ProtocolError{msg: "unbalanced quotes in request"}
+					}
+
+					done = true
+				default:
+					arg = append(arg, c)
+				}
+			case isSpace(c):
+				done = true
+			case c == '"':
+				inDouble = true
+			case c == '\'':
+				inSingle = true
+			default:
+				arg = append(arg, c)
+			}
+		}
+
+		args = append(args, arg)
+	}
+}
+
+func unescape(c byte) byte {
+	switch c {
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	case 'b':
+		return '\b'
+	case 'a':
+		return '\a'
+	}
+
+	return c
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f'
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func hexValue(c byte) byte {
+	switch {
+	case c >= 'a':
+		return c - 'a' + 10
+	case c >= 'A':
+		return c - 'A' + 10
+	}
+
+	return c - '0'
+}
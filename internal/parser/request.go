@@ -1,129 +1,221 @@
 package parser
 
 import (
+	"bufio"
+	"bytes"
 	"fmt"
+	"io"
 	"strconv"
 	"strings"
 )
 
+const (
+	// maxBulkLen is the maximum length of an argument, 512MB as the default
+	// proto-max-bulk-len of Redis.
+	maxBulkLen = 512 * 1024 * 1024
+	// maxMultiBulkLen is the maximum number of arguments of a request.
+	maxMultiBulkLen = 1024 * 1024
+	// maxUnauthenticatedBulkLen and maxUnauthenticatedMultiBulkLen are the
+	// limits for the clients which haven't authenticated yet, as in Redis.
+	maxUnauthenticatedBulkLen      = 16 * 1024
+	maxUnauthenticatedMultiBulkLen = 10
+	// bulkChunkLen is the size of the chunks a bulk string is read in.
+	bulkChunkLen = 16 * 1024
+)
+
 type RedisRequest struct {
 	Command string
-	Payload []string
+	Payload [][]byte
+}
+
+// Args returns the arguments for the command handlers. Go strings hold
+// arbitrary bytes, so the arguments stay binary-safe. Every argument is
+// copied, so it should only be called once per request.
+func (r *RedisRequest) Args() []string {
+	args := make([]string, len(r.Payload))
+	for i, arg := range r.Payload {
+		args[i] = string(arg)
+	}
+
+	return args
+}
+
+// ProtocolError is returned for requests which don't follow RESP. The
+// connection can't be read any further after it.
+type ProtocolError struct {
+	msg string
+}
+
+func (e *ProtocolError) Error() string {
+	return "Protocol error: " + e.msg
+}
+
+// Reader reads the requests sent on a connection, one after the other.
+type Reader struct {
+	rd *bufio.Reader
+	// unauthenticated is set while the client hasn't authenticated, its
+	// requests being kept small then.
+	unauthenticated bool
+}
+
+func NewReader(r io.Reader) *Reader {
+	return &Reader{rd: bufio.NewReader(r)}
 }
 
+// SetAuthenticated lifts the limits on the length of the requests of a
+// client which hasn't authenticated, or sets them back.
+func (r *Reader) SetAuthenticated(authenticated bool) {
+	r.unauthenticated = !authenticated
+}
+
+// ParseRequest parses a single request.
 func ParseRequest(content []byte) (*RedisRequest, error) {
-	redisRequest := &RedisRequest{}
+	return NewReader(bytes.NewReader(content)).ReadRequest()
+}
+
+// ReadRequest reads the next request, which may be split across several
+// reads of the connection. Requests not starting with '*' are inline
+// requests. Empty requests are skipped.
+func (r *Reader) ReadRequest() (*RedisRequest, error) {
+	for {
+		firstByte, err := r.rd.ReadByte()
+		if err != nil {
+			return nil, fmt.Errorf("Invalid request: %w", err)
+		}
 
-	reader := strings.NewReader(string(content))
+		if firstByte != '*' {
+			r.rd.UnreadByte()
 
-	firstByte, err := reader.ReadByte()
-	if err != nil {
-		return nil, fmt.Errorf("Invalid request: %w", err)
-	}
+			req, err := r.readInline()
+			if err != nil || req != nil {
+				return req, err
+			}
 
-	if firstByte != '*' {
-		return nil, fmt.Errorf("Request needs to be start with *, but given %q", rune(firstByte))
-	}
+			continue
+		}
 
-	numberOfParams, err := readUntilCRLFAsInt(reader)
-	if err != nil {
-		return nil, fmt.Errorf("readUntilCRLFAsInt: %w", err)
+		numberOfParams, err := r.readLength(maxMultiBulkLen, "invalid multibulk length")
+		if err != nil {
+			return nil, err
+		}
+
+		if r.unauthenticated && numberOfParams > maxUnauthenticatedMultiBulkLen {
+			return nil, &ProtocolError{msg: "unauthenticated multibulk length"}
+		}
+
+		if numberOfParams <= 0 {
+			continue
+		}
+
+		return r.readParams(numberOfParams)
 	}
+}
+
+func (r *Reader) readParams(numberOfParams int) (*RedisRequest, error) {
+	redisRequest := &RedisRequest{Payload: make([][]byte, 0, numberOfParams-1)}
 
 	for i := 0; i < numberOfParams; i++ {
-		char, err := reader.ReadByte()
+		char, err := r.rd.ReadByte()
 		if err != nil {
 			return nil, fmt.Errorf("Failed to read byte: %w", err)
 		}
 
 		if char != '$' {
-			return nil, fmt.Errorf("Invalid redis request, expected $, received: %q", char)
+			return nil, &ProtocolError{msg: fmt.Sprintf("expected '$', got '%c'", char)}
 		}
 
-		contentLen, err := readUntilCRLFAsInt(reader)
+		contentLen, err := r.readLength(maxBulkLen, "invalid bulk length")
 		if err != nil {
-			return nil, fmt.Errorf("readUntilCRLFAsInt: %w", err)
+			return nil, err
 		}
 
-		buf := make([]byte, contentLen)
-		n, err := reader.Read(buf)
-		if err != nil {
-			return nil, fmt.Errorf("Failed during read: %w", err)
+		if contentLen < 0 {
+			return nil, &ProtocolError{msg: "invalid bulk length"}
 		}
 
-		content := buf[:n]
-
-		if redisRequest.Command == "" {
-			redisRequest.Command = strings.ToUpper(string(content))
+		if r.unauthenticated && contentLen > maxUnauthenticatedBulkLen {
+			return nil, &ProtocolError{msg: "unauthenticated bulk length"}
+		}
 
-			err := discardCRLF(reader)
-			if err != nil {
-				return nil, fmt.Errorf("Failed to discardCRLF: %w", err)
-			}
+		content, err := r.readBulk(contentLen)
+		if err != nil {
+			return nil, err
+		}
 
+		if i == 0 {
+			redisRequest.Command = strings.ToUpper(string(content))
 			continue
 		}
 
-		redisRequest.Payload = append(redisRequest.Payload, string(content))
-		err = discardCRLF(reader)
-		if err != nil {
-			return nil, fmt.Errorf("Failed to discardCRLF: %w", err)
-		}
+		redisRequest.Payload = append(redisRequest.Payload, content)
 	}
 
 	return redisRequest, nil
 }
 
-func readUntilCRLFAsInt(reader *strings.Reader) (int, error) {
-	content, err := readUntilCRLF(reader)
-	if err != nil {
-		return 0, fmt.Errorf("readUntilCRLF failed: %w", err)
-	}
-
-	contentAsInt, err := strconv.Atoi(string(content))
-	if err != nil {
-		return 0, fmt.Errorf("Failed to convert the content to Int: %w", err)
-	}
-
-	return contentAsInt, nil
-}
+// readBulk reads the content of a bulk string and its CRLF, however many
+// reads of the connection it takes. The content is read in chunks, the
+// buffer growing as data arrives, so that the length announced by the
+// client isn't allocated before it sends the data.
+func (r *Reader) readBulk(length int) ([]byte, error) {
+	content := []byte{}
+
+	for len(content) < length {
+		chunk := length - len(content)
+		if chunk > bulkChunkLen {
+			chunk = bulkChunkLen
+		}
 
-func readUntilCRLF(reader *strings.Reader) ([]byte, error) {
-	content := make([]byte, 0, 256)
+		start := len(content)
+		content = append(content, make([]byte, chunk)...)
 
-	for {
-		char, err := reader.ReadByte()
-		if err != nil {
-			return nil, fmt.Errorf("Failed to read byte: %w", err)
+		if _, err := io.ReadFull(r.rd, content[start:]); err != nil {
+			return nil, fmt.Errorf("Failed during read: %w", err)
 		}
+	}
 
-		if char == '\r' {
-			break
-		}
+	var crlf [2]byte
+	if _, err := io.ReadFull(r.rd, crlf[:]); err != nil {
+		return nil, fmt.Errorf("Failed during read: %w", err)
+	}
 
-		content = append(content, char)
+	if crlf[0] != '\r' || crlf[1] != '\n' {
+		return nil, &ProtocolError{msg: "expected CRLF after bulk string"}
 	}
 
-	// read the '\n' character
-	_, err := reader.ReadByte()
+	return content, nil
+}
+
+// readLength reads the length ending the header line of an array or a bulk
+// string, which must not be greater than limit.
+func (r *Reader) readLength(limit int, errMsg string) (int, error) {
+	line, err := r.readLine()
 	if err != nil {
-		return nil, fmt.Errorf("Failed to read byte: %w", err)
+		return 0, err
 	}
 
-	return content, nil
+	length, err := strconv.ParseInt(string(line), 10, 64)
+	if err != nil || length > int64(limit) {
+		return 0, &ProtocolError{msg: errMsg}
+	}
+
+	return int(length), nil
 }
 
-func discardCRLF(reader *strings.Reader) error {
-	discarded := make([]byte, 2)
+// readLine reads a line ending with CRLF and returns it without the CRLF.
+func (r *Reader) readLine() ([]byte, error) {
+	line, err := r.rd.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		return nil, &ProtocolError{msg: "too big header line"}
+	}
 
-	n, err := reader.Read(discarded)
 	if err != nil {
-		return fmt.Errorf("Failed to read to discarded data: %w", err)
+		return nil, fmt.Errorf("Failed to read line: %w", err)
 	}
 
-	if string(discarded[:n]) != "\r\n" {
-		return fmt.Errorf("Expected a CRLF return, but received: %s", string(discarded[:n]))
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return nil, &ProtocolError{msg: "expected CRLF"}
 	}
 
-	return nil
+	return line[:len(line)-2], nil
 }
@@ -1,10 +1,17 @@
 package parser_test
 
 import (
+	"bytes"
+	"io"
 	"reflect"
+	"runtime"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/codecrafters-io/redis-starter-go/internal/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestParseRequest(t *testing.T) {
@@ -24,7 +31,7 @@ func TestParseRequest(t *testing.T) {
 			},
 			want: &parser.RedisRequest{
 				Command: "LLEN",
-				Payload: []string{"mylist"},
+				Payload: [][]byte{[]byte("mylist")},
 			},
 			wantErr: false,
 		},
@@ -35,10 +42,53 @@ func TestParseRequest(t *testing.T) {
 			},
 			want: &parser.RedisRequest{
 				Command: "LLEN",
-				Payload: []string{"mylist"},
+				Payload: [][]byte{[]byte("mylist")},
 			},
 			wantErr: false,
 		},
+		{
+			name: "argument containing CRLF and NUL bytes",
+			args: args{
+				content: []byte("*3\r\n$3\r\nSET\r\n$1\r\nk\r\n$6\r\na\r\n\x00b\n\r\n"),
+			},
+			want: &parser.RedisRequest{
+				Command: "SET",
+				Payload: [][]byte{[]byte("k"), []byte("a\r\n\x00b\n")},
+			},
+			wantErr: false,
+		},
+		{
+			name: "empty argument",
+			args: args{
+				content: []byte("*2\r\n$4\r\nECHO\r\n$0\r\n\r\n"),
+			},
+			want: &parser.RedisRequest{
+				Command: "ECHO",
+				Payload: [][]byte{{}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "bulk string shorter than its length",
+			args: args{
+				content: []byte("*2\r\n$4\r\nECHO\r\n$10\r\nabc\r\n"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "bulk string longer than its length",
+			args: args{
+				content: []byte("*2\r\n$4\r\nECHO\r\n$2\r\nabc\r\n"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative bulk length",
+			args: args{
+				content: []byte("*2\r\n$4\r\nECHO\r\n$-1\r\n"),
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -53,3 +103,173 @@ func TestParseRequest(t *testing.T) {
 		})
 	}
 }
+
+func TestReader_ReadRequest(t *testing.T) {
+	t.Run("when requests are pipelined and read one byte at a time", func(t *testing.T) {
+		value := "\x00\r\n" + strings.Repeat("x", 10000) + "\r\n\x00"
+		content := "*0\r\n" +
+			"*3\r\n$3\r\nSET\r\n$1\r\nk\r\n$10006\r\n" + value + "\r\n" +
+			"*2\r\n$3\r\nGET\r\n$1\r\nk\r\n"
+
+		reader := parser.NewReader(iotest.OneByteReader(strings.NewReader(content)))
+
+		req, err := reader.ReadRequest()
+		require.NoError(t, err)
+		assert.Equal(t, "SET", req.Command)
+		assert.Equal(t, []string{"k", value}, req.Args())
+
+		req, err = reader.ReadRequest()
+		require.NoError(t, err)
+		assert.Equal(t, "GET", req.Command)
+		assert.Equal(t, []string{"k"}, req.Args())
+
+		_, err = reader.ReadRequest()
+		assert.ErrorIs(t, err, io.EOF)
+	})
+
+	t.Run("when the connection closes in the middle of a request", func(t *testing.T) {
+		reader := parser.NewReader(bytes.NewReader([]byte("*2\r\n$4\r\nECHO\r\n$5\r\nab")))
+
+		_, err := reader.ReadRequest()
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+
+	t.Run("when the request isn't an array, it's read inline", func(t *testing.T) {
+		reader := parser.NewReader(bytes.NewReader([]byte("$4\r\nECHO\r\n")))
+
+		req, err := reader.ReadRequest()
+		require.NoError(t, err)
+		assert.Equal(t, "$4", req.Command)
+
+		req, err = reader.ReadRequest()
+		require.NoError(t, err)
+		assert.Equal(t, "ECHO", req.Command)
+	})
+
+	t.Run("when inline and multibulk requests are pipelined", func(t *testing.T) {
+		reader := parser.NewReader(iotest.OneByteReader(strings.NewReader("\r\n  \nping\n*1\r\n$4\r\nPING\r\nECHO hi\r\n")))
+
+		for _, expected := range [][]string{{"PING"}, {"PING"}, {"ECHO", "hi"}} {
+			req, err := reader.ReadRequest()
+			require.NoError(t, err)
+			assert.Equal(t, expected, append([]string{req.Command}, req.Args()...))
+		}
+	})
+
+	t.Run("when the inline request is too big", func(t *testing.T) {
+		reader := parser.NewReader(strings.NewReader("SET k " + strings.Repeat("x", 64*1024) + "\r\n"))
+
+		_, err := reader.ReadRequest()
+		assert.EqualError(t, err, "Protocol error: too big inline request")
+	})
+
+	t.Run("when the bulk length is too big", func(t *testing.T) {
+		reader := parser.NewReader(bytes.NewReader([]byte("*1\r\n$536870913\r\n")))
+
+		_, err := reader.ReadRequest()
+		assert.EqualError(t, err, "Protocol error: invalid bulk length")
+	})
+
+	t.Run("when the bulk length is announced without the data", func(t *testing.T) {
+		reader := parser.NewReader(bytes.NewReader([]byte("*1\r\n$536870911\r\nabc")))
+
+		var before, after runtime.MemStats
+		runtime.ReadMemStats(&before)
+
+		_, err := reader.ReadRequest()
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+		runtime.ReadMemStats(&after)
+		assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(1024*1024))
+	})
+
+	t.Run("when the client hasn't authenticated", func(t *testing.T) {
+		testCases := map[string]struct {
+			content       string
+			expectedError string
+		}{
+			"when too many arguments given": {
+				content:       "*11\r\n",
+				expectedError: "Protocol error: unauthenticated multibulk length",
+			},
+			"when the argument is too long": {
+				content:       "*2\r\n$4\r\nAUTH\r\n$16385\r\n",
+				expectedError: "Protocol error: unauthenticated bulk length",
+			},
+		}
+
+		for name, tc := range testCases {
+			t.Run(name, func(t *testing.T) {
+				reader := parser.NewReader(strings.NewReader(tc.content))
+				reader.SetAuthenticated(false)
+
+				_, err := reader.ReadRequest()
+				assert.EqualError(t, err, tc.expectedError)
+			})
+		}
+
+		reader := parser.NewReader(strings.NewReader("*2\r\n$4\r\nAUTH\r\n$3\r\npwd\r\n*2\r\n$4\r\nECHO\r\n$16385\r\n" + strings.Repeat("x", 16385) + "\r\n"))
+		reader.SetAuthenticated(false)
+
+		req, err := reader.ReadRequest()
+		require.NoError(t, err)
+		assert.Equal(t, "AUTH", req.Command)
+
+		reader.SetAuthenticated(true)
+
+		req, err = reader.ReadRequest()
+		require.NoError(t, err)
+		assert.Equal(t, "ECHO", req.Command)
+	})
+}
+
+func TestReader_ReadInlineRequest(t *testing.T) {
+	testCases := map[string]struct {
+		line          string
+		expectedArgs  []string
+		expectedError string
+	}{
+		"when arguments are separated by several spaces": {
+			line:         "set  key\tvalue \r\n",
+			expectedArgs: []string{"SET", "key", "value"},
+		},
+		"when the line ends with a bare LF": {
+			line:         "GET key\n",
+			expectedArgs: []string{"GET", "key"},
+		},
+		"when arguments are double quoted": {
+			line:         `SET "my key" "a\"b\\c\n\x41\x4g" ""` + "\r\n",
+			expectedArgs: []string{"SET", "my key", "a\"b\\c\nAx4g", ""},
+		},
+		"when arguments are single quoted": {
+			line:         `SET 'it\'s' 'a\nb "c"'` + "\r\n",
+			expectedArgs: []string{"SET", "it's", `a\nb "c"`},
+		},
+		"when quotes start in the middle of an argument": {
+			line:         `SET k a"b c"` + "\r\n",
+			expectedArgs: []string{"SET", "k", "ab c"},
+		},
+		"when a quote is not closed": {
+			line:          `SET k "value` + "\r\n",
+			expectedError: "Protocol error: unbalanced quotes in request",
+		},
+		"when a closing quote is followed by a character": {
+			line:          `SET k 'a'b` + "\r\n",
+			expectedError: "Protocol error: unbalanced quotes in request",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req, err := parser.NewReader(strings.NewReader(tc.line)).ReadRequest()
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedArgs, append([]string{req.Command}, req.Args()...))
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package payload
+
+import (
+	"bufio"
+	"bytes"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/floatfn"
+)
+
+// Reply is a node of a reply tree. The whole tree is encoded in a single pass
+// into a writer, rather than by concatenating the encoding of every node.
+//
+// Replies are encoded for the RESP version of the client: the RESP3 types
+// fall back to their closest RESP2 type, e.g. a Map becomes a flat array of
+// keys and values, and a Double a bulk string.
+type Reply interface {
+	// WriteRESP encodes the reply. Write errors are kept by w, and returned
+	// by its Flush.
+	WriteRESP(w *bufio.Writer, protocol int)
+}
+
+// Encode returns the encoding of the reply, for the replies which are kept
+// around before being written, e.g. the Pub/Sub messages pending for a
+// subscriber.
+func Encode(r Reply, protocol int) []byte {
+	var buf bytes.Buffer
+
+	w := bufio.NewWriter(&buf)
+	r.WriteRESP(w, protocol)
+	w.Flush()
+
+	return buf.Bytes()
+}
+
+type SimpleString string
+
+func (r SimpleString) WriteRESP(w *bufio.Writer, protocol int) {
+	w.WriteByte('+')
+	w.WriteString(string(r))
+	writeCRLF(w)
+}
+
+// Error is an error reply. The error can't span several lines, so newlines
+// are replaced with spaces.
+type Error string
+
+func (r Error) WriteRESP(w *bufio.Writer, protocol int) {
+	w.WriteByte('-')
+	w.WriteString(strings.NewReplacer("\r", " ", "\n", " ").Replace(string(r)))
+	writeCRLF(w)
+}
+
+type Integer int64
+
+func (r Integer) WriteRESP(w *bufio.Writer, protocol int) {
+	writeHeader(w, ':', int64(r))
+}
+
+type BulkString string
+
+func (r BulkString) WriteRESP(w *bufio.Writer, protocol int) {
+	writeHeader(w, '$', int64(len(r)))
+	w.WriteString(string(r))
+	writeCRLF(w)
+}
+
+// Null is a missing value, a null bulk string over RESP2.
+type Null struct{}
+
+func (Null) WriteRESP(w *bufio.Writer, protocol int) {
+	if protocol == 3 {
+		w.WriteString("_\r\n")
+		return
+	}
+
+	w.WriteString("$-1\r\n")
+}
+
+// NullArray is a missing aggregate, e.g. when a blocking command times out.
+// It's the same as Null over RESP3.
+type NullArray struct{}
+
+func (NullArray) WriteRESP(w *bufio.Writer, protocol int) {
+	if protocol == 3 {
+		w.WriteString("_\r\n")
+		return
+	}
+
+	w.WriteString("*-1\r\n")
+}
+
+type Array []Reply
+
+func (r Array) WriteRESP(w *bufio.Writer, protocol int) {
+	writeAggregate(w, '*', r, protocol)
+}
+
+// BulkStrings returns the array of the values.
+func BulkStrings(values []string) Array {
+	res := make(Array, len(values))
+	for i, val := range values {
+		res[i] = BulkString(val)
+	}
+
+	return res
+}
+
+type MapEntry struct {
+	Key   Reply
+	Value Reply
+}
+
+// Map is an ordered list of key value pairs, a flat array of keys and values
+// over RESP2.
+type Map []MapEntry
+
+func (r Map) WriteRESP(w *bufio.Writer, protocol int) {
+	if protocol == 3 {
+		writeHeader(w, '%', int64(len(r)))
+	} else {
+		writeHeader(w, '*', int64(2*len(r)))
+	}
+
+	for _, entry := range r {
+		entry.Key.WriteRESP(w, protocol)
+		entry.Value.WriteRESP(w, protocol)
+	}
+}
+
+// Set is an unordered collection of distinct elements, an array over RESP2.
+type Set []Reply
+
+func (r Set) WriteRESP(w *bufio.Writer, protocol int) {
+	kind := byte('*')
+	if protocol == 3 {
+		kind = '~'
+	}
+
+	writeAggregate(w, kind, r, protocol)
+}
+
+// Push is an out of band message, such as a Pub/Sub message, which isn't the
+// reply to a command. It's an array over RESP2.
+type Push []Reply
+
+func (r Push) WriteRESP(w *bufio.Writer, protocol int) {
+	kind := byte('*')
+	if protocol == 3 {
+		kind = '>'
+	}
+
+	writeAggregate(w, kind, r, protocol)
+}
+
+// Double is a floating point number, formatted as sorted set scores are in a
+// bulk string over RESP2.
+type Double float64
+
+func (r Double) WriteRESP(w *bufio.Writer, protocol int) {
+	value := float64(r)
+
+	str := floatfn.Format(value)
+	if math.IsNaN(value) {
+		str = "nan"
+	}
+
+	if protocol != 3 {
+		BulkString(str).WriteRESP(w, protocol)
+		return
+	}
+
+	w.WriteByte(',')
+	w.WriteString(str)
+	writeCRLF(w)
+}
+
+// Boolean is the integer 1 or 0 over RESP2.
+type Boolean bool
+
+func (r Boolean) WriteRESP(w *bufio.Writer, protocol int) {
+	switch {
+	case protocol != 3 && bool(r):
+		Integer(1).WriteRESP(w, protocol)
+	case protocol != 3:
+		Integer(0).WriteRESP(w, protocol)
+	case bool(r):
+		w.WriteString("#t\r\n")
+	default:
+		w.WriteString("#f\r\n")
+	}
+}
+
+// BigNumber is an integer out of the 64 bits range, a bulk string over
+// RESP2.
+type BigNumber struct {
+	Value *big.Int
+}
+
+func (r BigNumber) WriteRESP(w *bufio.Writer, protocol int) {
+	if protocol != 3 {
+		BulkString(r.Value.String()).WriteRESP(w, protocol)
+		return
+	}
+
+	w.WriteByte('(')
+	w.WriteString(r.Value.String())
+	writeCRLF(w)
+}
+
+// Verbatim is a text meant to be displayed as is, Format being its three
+// letters type, e.g. "txt" or "mkd". It's a bulk string over RESP2.
+type Verbatim struct {
+	Format string
+	Text   string
+}
+
+func (r Verbatim) WriteRESP(w *bufio.Writer, protocol int) {
+	if protocol != 3 {
+		BulkString(r.Text).WriteRESP(w, protocol)
+		return
+	}
+
+	writeHeader(w, '=', int64(len(r.Format)+1+len(r.Text)))
+	w.WriteString(r.Format)
+	w.WriteByte(':')
+	w.WriteString(r.Text)
+	writeCRLF(w)
+}
+
+// Attribute is auxiliary data about the reply it precedes. Over RESP2, only
+// the reply itself is sent.
+type Attribute struct {
+	Attributes Map
+	Reply      Reply
+}
+
+func (r Attribute) WriteRESP(w *bufio.Writer, protocol int) {
+	if protocol == 3 {
+		writeHeader(w, '|', int64(len(r.Attributes)))
+
+		for _, entry := range r.Attributes {
+			entry.Key.WriteRESP(w, protocol)
+			entry.Value.WriteRESP(w, protocol)
+		}
+	}
+
+	r.Reply.WriteRESP(w, protocol)
+}
+
+// Sequence is several replies sent one after the other, as SUBSCRIBE
+// confirms every channel with a reply of its own.
+type Sequence []Reply
+
+func (r Sequence) WriteRESP(w *bufio.Writer, protocol int) {
+	for _, reply := range r {
+		reply.WriteRESP(w, protocol)
+	}
+}
+
+func writeAggregate(w *bufio.Writer, kind byte, elements []Reply, protocol int) {
+	writeHeader(w, kind, int64(len(elements)))
+
+	for _, elem := range elements {
+		elem.WriteRESP(w, protocol)
+	}
+}
+
+func writeHeader(w *bufio.Writer, kind byte, n int64) {
+	var digits [20]byte
+
+	w.WriteByte(kind)
+	w.Write(strconv.AppendInt(digits[:0], n, 10))
+	writeCRLF(w)
+}
+
+func writeCRLF(w *bufio.Writer) {
+	w.WriteString("\r\n")
+}
@@ -0,0 +1,144 @@
+package payload
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode(t *testing.T) {
+	bigNumber, _ := new(big.Int).SetString("-3492890328409238509324850943850943825024385", 10)
+
+	testCases := map[string]struct {
+		reply Reply
+		resp2 string
+		resp3 string
+	}{
+		"when simple string given": {
+			reply: SimpleString("OK"),
+			resp2: "+OK\r\n",
+			resp3: "+OK\r\n",
+		},
+		"when error spans several lines": {
+			reply: Error("ERR unknown command 'a\r\nb'"),
+			resp2: "-ERR unknown command 'a  b'\r\n",
+			resp3: "-ERR unknown command 'a  b'\r\n",
+		},
+		"when integer given": {
+			reply: Integer(-12),
+			resp2: ":-12\r\n",
+			resp3: ":-12\r\n",
+		},
+		"when bulk string is binary": {
+			reply: BulkString("a\r\n\x00b"),
+			resp2: "$5\r\na\r\n\x00b\r\n",
+			resp3: "$5\r\na\r\n\x00b\r\n",
+		},
+		"when null given": {
+			reply: Null{},
+			resp2: "$-1\r\n",
+			resp3: "_\r\n",
+		},
+		"when null array given": {
+			reply: NullArray{},
+			resp2: "*-1\r\n",
+			resp3: "_\r\n",
+		},
+		"when array is nested": {
+			reply: Array{Integer(1), Array{BulkString("a"), Null{}}, Array{}},
+			resp2: "*3\r\n:1\r\n*2\r\n$1\r\na\r\n$-1\r\n*0\r\n",
+			resp3: "*3\r\n:1\r\n*2\r\n$1\r\na\r\n_\r\n*0\r\n",
+		},
+		"when map given": {
+			reply: Map{{Key: BulkString("first"), Value: Integer(1)}, {Key: BulkString("second"), Value: Double(2.5)}},
+			resp2: "*4\r\n$5\r\nfirst\r\n:1\r\n$6\r\nsecond\r\n$3\r\n2.5\r\n",
+			resp3: "%2\r\n$5\r\nfirst\r\n:1\r\n$6\r\nsecond\r\n,2.5\r\n",
+		},
+		"when set given": {
+			reply: Set(BulkStrings([]string{"a", "b"})),
+			resp2: "*2\r\n$1\r\na\r\n$1\r\nb\r\n",
+			resp3: "~2\r\n$1\r\na\r\n$1\r\nb\r\n",
+		},
+		"when push given": {
+			reply: Push{BulkString("message"), BulkString("ch")},
+			resp2: "*2\r\n$7\r\nmessage\r\n$2\r\nch\r\n",
+			resp3: ">2\r\n$7\r\nmessage\r\n$2\r\nch\r\n",
+		},
+		"when double is very big": {
+			reply: Double(1e300),
+			resp2: "$6\r\n1e+300\r\n",
+			resp3: ",1e+300\r\n",
+		},
+		"when double is infinite": {
+			reply: Double(math.Inf(-1)),
+			resp2: "$4\r\n-inf\r\n",
+			resp3: ",-inf\r\n",
+		},
+		"when double is not a number": {
+			reply: Double(math.NaN()),
+			resp2: "$3\r\nnan\r\n",
+			resp3: ",nan\r\n",
+		},
+		"when booleans given": {
+			reply: Array{Boolean(true), Boolean(false)},
+			resp2: "*2\r\n:1\r\n:0\r\n",
+			resp3: "*2\r\n#t\r\n#f\r\n",
+		},
+		"when big number given": {
+			reply: BigNumber{Value: bigNumber},
+			resp2: "$44\r\n-3492890328409238509324850943850943825024385\r\n",
+			resp3: "(-3492890328409238509324850943850943825024385\r\n",
+		},
+		"when verbatim string given": {
+			reply: Verbatim{Format: "txt", Text: "Some string"},
+			resp2: "$11\r\nSome string\r\n",
+			resp3: "=15\r\ntxt:Some string\r\n",
+		},
+		"when attribute given": {
+			reply: Attribute{Attributes: Map{{Key: SimpleString("ttl"), Value: Integer(3600)}}, Reply: Integer(2039123)},
+			resp2: ":2039123\r\n",
+			resp3: "|1\r\n+ttl\r\n:3600\r\n:2039123\r\n",
+		},
+		"when sequence given": {
+			reply: Sequence{Push{BulkString("subscribe"), BulkString("a"), Integer(1)}, Push{BulkString("subscribe"), BulkString("b"), Integer(2)}},
+			resp2: "*3\r\n$9\r\nsubscribe\r\n$1\r\na\r\n:1\r\n*3\r\n$9\r\nsubscribe\r\n$1\r\nb\r\n:2\r\n",
+			resp3: ">3\r\n$9\r\nsubscribe\r\n$1\r\na\r\n:1\r\n>3\r\n$9\r\nsubscribe\r\n$1\r\nb\r\n:2\r\n",
+		},
+		"when XRANGE result given": {
+			reply: Array{
+				Array{BulkString("1526985054069-0"), BulkStrings([]string{"temperature", "36", "humidity", "95"})},
+				Array{BulkString("1526985054079-0"), BulkStrings([]string{"temperature", "37", "humidity", "94"})},
+			},
+			resp2: "*2\r\n*2\r\n$15\r\n1526985054069-0\r\n*4\r\n$11\r\ntemperature\r\n$2\r\n36\r\n$8\r\nhumidity\r\n$2\r\n95\r\n*2\r\n$15\r\n1526985054079-0\r\n*4\r\n$11\r\ntemperature\r\n$2\r\n37\r\n$8\r\nhumidity\r\n$2\r\n94\r\n",
+			resp3: "*2\r\n*2\r\n$15\r\n1526985054069-0\r\n*4\r\n$11\r\ntemperature\r\n$2\r\n36\r\n$8\r\nhumidity\r\n$2\r\n95\r\n*2\r\n$15\r\n1526985054079-0\r\n*4\r\n$11\r\ntemperature\r\n$2\r\n37\r\n$8\r\nhumidity\r\n$2\r\n94\r\n",
+		},
+		"when XREAD result given": {
+			reply: Array{
+				Array{BulkString("stream_key"), Array{Array{BulkString("0-1"), BulkStrings([]string{"temperature", "95"})}}},
+				Array{BulkString("other_stream_key"), Array{Array{BulkString("0-2"), BulkStrings([]string{"humidity", "97"})}}},
+			},
+			resp2: "*2\r\n*2\r\n$10\r\nstream_key\r\n*1\r\n*2\r\n$3\r\n0-1\r\n*2\r\n$11\r\ntemperature\r\n$2\r\n95\r\n*2\r\n$16\r\nother_stream_key\r\n*1\r\n*2\r\n$3\r\n0-2\r\n*2\r\n$8\r\nhumidity\r\n$2\r\n97\r\n",
+			resp3: "*2\r\n*2\r\n$10\r\nstream_key\r\n*1\r\n*2\r\n$3\r\n0-1\r\n*2\r\n$11\r\ntemperature\r\n$2\r\n95\r\n*2\r\n$16\r\nother_stream_key\r\n*1\r\n*2\r\n$3\r\n0-2\r\n*2\r\n$8\r\nhumidity\r\n$2\r\n97\r\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.resp2, string(Encode(tc.reply, 2)))
+			assert.Equal(t, tc.resp3, string(Encode(tc.reply, 3)))
+		})
+	}
+}
+
+func TestEncode_LargeArray(t *testing.T) {
+	values := make([]string, 100000)
+	for i := range values {
+		values[i] = "value"
+	}
+
+	encoded := Encode(BulkStrings(values), 2)
+
+	assert.Len(t, encoded, len("*100000\r\n")+100000*len("$5\r\nvalue\r\n"))
+}
@@ -0,0 +1,358 @@
+package pubsub
+
+import (
+	"sort"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/cluster"
+	"github.com/codecrafters-io/redis-starter-go/internal/glob"
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+)
+
+// Subscriber receives the messages published to its channels and patterns,
+// as push messages which are arrays over RESP2.
+type Subscriber interface {
+	Send(msg payload.Push)
+}
+
+// subscriptions are the channels, patterns and shard channels of a
+// subscriber.
+type subscriptions struct {
+	channels      map[string]bool
+	patterns      map[string]bool
+	shardChannels map[string]bool
+}
+
+// Hub keeps track of the subscriptions and delivers the published messages.
+//
+// Hub isn't safe for concurrent use: as blocking.Manager, it's only accessed
+// while holding the lock which serializes command execution.
+type Hub struct {
+	channels map[string]map[Subscriber]bool
+	patterns map[string]map[Subscriber]bool
+	// shards holds the shard channels by hash slot, as a cluster node only
+	// serves the shard channels of its slots.
+	shards map[int]map[string]map[Subscriber]bool
+
+	subscribers map[Subscriber]*subscriptions
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		channels:    map[string]map[Subscriber]bool{},
+		patterns:    map[string]map[Subscriber]bool{},
+		shards:      map[int]map[string]map[Subscriber]bool{},
+		subscribers: map[Subscriber]*subscriptions{},
+	}
+}
+
+// Count returns the number of channels and patterns the subscriber is
+// subscribed to.
+func (h *Hub) Count(s Subscriber) int {
+	subs, exists := h.subscribers[s]
+	if !exists {
+		return 0
+	}
+
+	return len(subs.channels) + len(subs.patterns)
+}
+
+// ShardCount returns the number of shard channels the subscriber is
+// subscribed to.
+func (h *Hub) ShardCount(s Subscriber) int {
+	subs, exists := h.subscribers[s]
+	if !exists {
+		return 0
+	}
+
+	return len(subs.shardChannels)
+}
+
+// Subscribed returns true if the subscriber has any subscription.
+func (h *Hub) Subscribed(s Subscriber) bool {
+	_, exists := h.subscribers[s]
+	return exists
+}
+
+// Subscribe subscribes to the channel and returns false if the subscriber
+// was already subscribed to it.
+func (h *Hub) Subscribe(s Subscriber, channel string) bool {
+	return subscribe(h.channels, h.get(s).channels, s, channel)
+}
+
+// Unsubscribe unsubscribes from the channel and returns false if the
+// subscriber wasn't subscribed to it.
+func (h *Hub) Unsubscribe(s Subscriber, channel string) bool {
+	subs, exists := h.subscribers[s]
+	if !exists {
+		return false
+	}
+
+	defer h.forgetIfIdle(s)
+
+	return unsubscribe(h.channels, subs.channels, s, channel)
+}
+
+// PSubscribe subscribes to the channels matching the glob-style pattern and
+// returns false if the subscriber was already subscribed to it.
+func (h *Hub) PSubscribe(s Subscriber, pattern string) bool {
+	return subscribe(h.patterns, h.get(s).patterns, s, pattern)
+}
+
+// PUnsubscribe unsubscribes from the pattern and returns false if the
+// subscriber wasn't subscribed to it.
+func (h *Hub) PUnsubscribe(s Subscriber, pattern string) bool {
+	subs, exists := h.subscribers[s]
+	if !exists {
+		return false
+	}
+
+	defer h.forgetIfIdle(s)
+
+	return unsubscribe(h.patterns, subs.patterns, s, pattern)
+}
+
+// SSubscribe subscribes to the shard channel and returns false if the
+// subscriber was already subscribed to it.
+func (h *Hub) SSubscribe(s Subscriber, channel string) bool {
+	slot := cluster.KeySlot(channel)
+	if h.shards[slot] == nil {
+		h.shards[slot] = map[string]map[Subscriber]bool{}
+	}
+
+	return subscribe(h.shards[slot], h.get(s).shardChannels, s, channel)
+}
+
+// SUnsubscribe unsubscribes from the shard channel and returns false if the
+// subscriber wasn't subscribed to it.
+func (h *Hub) SUnsubscribe(s Subscriber, channel string) bool {
+	subs, exists := h.subscribers[s]
+	if !exists {
+		return false
+	}
+
+	defer h.forgetIfIdle(s)
+
+	slot := cluster.KeySlot(channel)
+	defer func() {
+		if len(h.shards[slot]) == 0 {
+			delete(h.shards, slot)
+		}
+	}()
+
+	return unsubscribe(h.shards[slot], subs.shardChannels, s, channel)
+}
+
+// Channels returns the channels the subscriber is subscribed to.
+func (h *Hub) Channels(s Subscriber) []string {
+	if subs, exists := h.subscribers[s]; exists {
+		return sortedKeys(subs.channels)
+	}
+
+	return []string{}
+}
+
+// Patterns returns the patterns the subscriber is subscribed to.
+func (h *Hub) Patterns(s Subscriber) []string {
+	if subs, exists := h.subscribers[s]; exists {
+		return sortedKeys(subs.patterns)
+	}
+
+	return []string{}
+}
+
+// ShardChannels returns the shard channels the subscriber is subscribed to.
+func (h *Hub) ShardChannels(s Subscriber) []string {
+	if subs, exists := h.subscribers[s]; exists {
+		return sortedKeys(subs.shardChannels)
+	}
+
+	return []string{}
+}
+
+// Remove drops every subscription of the subscriber, e.g. once it
+// disconnected.
+func (h *Hub) Remove(s Subscriber) {
+	for _, channel := range h.Channels(s) {
+		h.Unsubscribe(s, channel)
+	}
+
+	for _, pattern := range h.Patterns(s) {
+		h.PUnsubscribe(s, pattern)
+	}
+
+	for _, channel := range h.ShardChannels(s) {
+		h.SUnsubscribe(s, channel)
+	}
+}
+
+// Publish sends the message to the subscribers of the channel and of the
+// patterns matching it, and returns the number of messages sent. A
+// subscriber gets the message once per matching subscription.
+func (h *Hub) Publish(channel string, message []byte) int {
+	receivers := 0
+
+	if subscribers := h.channels[channel]; len(subscribers) > 0 {
+		msg := payload.Push{
+			payload.BulkString("message"),
+			payload.BulkString(channel),
+			payload.BulkString(message),
+		}
+
+		for s := range subscribers {
+			s.Send(msg)
+			receivers++
+		}
+	}
+
+	for pattern, subscribers := range h.patterns {
+		if !glob.Match(pattern, channel, false) {
+			continue
+		}
+
+		msg := payload.Push{
+			payload.BulkString("pmessage"),
+			payload.BulkString(pattern),
+			payload.BulkString(channel),
+			payload.BulkString(message),
+		}
+
+		for s := range subscribers {
+			s.Send(msg)
+			receivers++
+		}
+	}
+
+	return receivers
+}
+
+// SPublish sends the message to the subscribers of the shard channel, and
+// returns the number of messages sent. Patterns don't apply to shard
+// channels.
+func (h *Hub) SPublish(channel string, message []byte) int {
+	subscribers := h.shards[cluster.KeySlot(channel)][channel]
+	if len(subscribers) == 0 {
+		return 0
+	}
+
+	msg := payload.Push{
+		payload.BulkString("smessage"),
+		payload.BulkString(channel),
+		payload.BulkString(message),
+	}
+
+	for s := range subscribers {
+		s.Send(msg)
+	}
+
+	return len(subscribers)
+}
+
+// ActiveChannels returns the channels having at least one subscriber,
+// matching the pattern if it's not empty. Pattern subscriptions aren't
+// counted.
+func (h *Hub) ActiveChannels(pattern string) []string {
+	res := []string{}
+	for channel := range h.channels {
+		if pattern == "" || glob.Match(pattern, channel, false) {
+			res = append(res, channel)
+		}
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+// NumSub returns the number of subscribers of the channel.
+func (h *Hub) NumSub(channel string) int {
+	return len(h.channels[channel])
+}
+
+// ActiveShardChannels returns the shard channels having at least one
+// subscriber, matching the pattern if it's not empty.
+func (h *Hub) ActiveShardChannels(pattern string) []string {
+	res := []string{}
+	for _, channels := range h.shards {
+		for channel := range channels {
+			if pattern == "" || glob.Match(pattern, channel, false) {
+				res = append(res, channel)
+			}
+		}
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+// ShardNumSub returns the number of subscribers of the shard channel.
+func (h *Hub) ShardNumSub(channel string) int {
+	return len(h.shards[cluster.KeySlot(channel)][channel])
+}
+
+// NumPat returns the number of patterns subscribed to, by any subscriber.
+func (h *Hub) NumPat() int {
+	return len(h.patterns)
+}
+
+func (h *Hub) get(s Subscriber) *subscriptions {
+	subs, exists := h.subscribers[s]
+	if !exists {
+		subs = &subscriptions{
+			channels:      map[string]bool{},
+			patterns:      map[string]bool{},
+			shardChannels: map[string]bool{},
+		}
+		h.subscribers[s] = subs
+	}
+
+	return subs
+}
+
+func (h *Hub) forgetIfIdle(s Subscriber) {
+	if h.Count(s) == 0 && h.ShardCount(s) == 0 {
+		delete(h.subscribers, s)
+	}
+}
+
+func subscribe(index map[string]map[Subscriber]bool, own map[string]bool, s Subscriber, name string) bool {
+	if own[name] {
+		return false
+	}
+
+	own[name] = true
+
+	if index[name] == nil {
+		index[name] = map[Subscriber]bool{}
+	}
+
+	index[name][s] = true
+
+	return true
+}
+
+func unsubscribe(index map[string]map[Subscriber]bool, own map[string]bool, s Subscriber, name string) bool {
+	if !own[name] {
+		return false
+	}
+
+	delete(own, name)
+	delete(index[name], s)
+
+	if len(index[name]) == 0 {
+		delete(index, name)
+	}
+
+	return true
+}
+
+func sortedKeys(m map[string]bool) []string {
+	res := make([]string, 0, len(m))
+	for key := range m {
+		res = append(res, key)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
@@ -0,0 +1,122 @@
+package pubsub_test
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+	"github.com/codecrafters-io/redis-starter-go/internal/pubsub"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// subscriber records the messages it's sent
+type subscriber struct {
+	received []string
+}
+
+func (s *subscriber) Send(msg payload.Push) {
+	s.received = append(s.received, string(payload.Encode(msg, 2)))
+}
+
+func message(parts ...string) string {
+	return string(payload.Encode(payload.BulkStrings(parts), 2))
+}
+
+func TestPublish(t *testing.T) {
+	h := pubsub.NewHub()
+	first, second := &subscriber{}, &subscriber{}
+
+	assert.True(t, h.Subscribe(first, "news"))
+	assert.False(t, h.Subscribe(first, "news"))
+	assert.True(t, h.PSubscribe(first, "n*"))
+	assert.True(t, h.Subscribe(second, "news"))
+	assert.True(t, h.PSubscribe(second, "sport.*"))
+
+	// first gets the message once per matching subscription
+	assert.Equal(t, 3, h.Publish("news", []byte("hello")))
+	assert.Equal(t, []string{message("message", "news", "hello"), message("pmessage", "n*", "news", "hello")}, first.received)
+	assert.Equal(t, []string{message("message", "news", "hello")}, second.received)
+
+	assert.Equal(t, 1, h.Publish("sport.tennis", []byte("ace")))
+	assert.Equal(t, message("pmessage", "sport.*", "sport.tennis", "ace"), second.received[1])
+
+	assert.Equal(t, 0, h.Publish("weather", []byte("rain")))
+}
+
+func TestUnsubscribe(t *testing.T) {
+	h := pubsub.NewHub()
+	s := &subscriber{}
+
+	assert.False(t, h.Unsubscribe(s, "news"))
+
+	h.Subscribe(s, "news")
+	h.Subscribe(s, "sport")
+	h.PSubscribe(s, "n*")
+	assert.Equal(t, 3, h.Count(s))
+	assert.Equal(t, []string{"news", "sport"}, h.Channels(s))
+	assert.Equal(t, []string{"n*"}, h.Patterns(s))
+
+	assert.True(t, h.Unsubscribe(s, "news"))
+	assert.False(t, h.PUnsubscribe(s, "s*"))
+	assert.True(t, h.PUnsubscribe(s, "n*"))
+	assert.Equal(t, 1, h.Count(s))
+	assert.Equal(t, 0, h.Publish("news", []byte("hello")))
+
+	h.Remove(s)
+	assert.Equal(t, 0, h.Count(s))
+	assert.Empty(t, h.ActiveChannels(""))
+}
+
+func TestIntrospection(t *testing.T) {
+	h := pubsub.NewHub()
+	first, second := &subscriber{}, &subscriber{}
+
+	h.Subscribe(first, "news.tech")
+	h.Subscribe(first, "sport")
+	h.Subscribe(second, "news.tech")
+	h.PSubscribe(first, "news.*")
+	h.PSubscribe(second, "news.*")
+	h.PSubscribe(second, "*")
+
+	assert.Equal(t, []string{"news.tech", "sport"}, h.ActiveChannels(""))
+	assert.Equal(t, []string{"news.tech"}, h.ActiveChannels("news.*"))
+	assert.Equal(t, 2, h.NumSub("news.tech"))
+	assert.Equal(t, 0, h.NumSub("news.*"))
+	assert.Equal(t, 2, h.NumPat())
+}
+
+func TestShardChannels(t *testing.T) {
+	h := pubsub.NewHub()
+	first, second := &subscriber{}, &subscriber{}
+
+	assert.True(t, h.SSubscribe(first, "{user}.orders"))
+	assert.False(t, h.SSubscribe(first, "{user}.orders"))
+	assert.True(t, h.SSubscribe(first, "{user}.carts"))
+	assert.True(t, h.SSubscribe(second, "{user}.orders"))
+	h.PSubscribe(second, "*")
+
+	assert.Equal(t, 0, h.Count(first))
+	assert.Equal(t, 2, h.ShardCount(first))
+	assert.True(t, h.Subscribed(first))
+
+	// shard channels are apart from the channels and patterns
+	assert.Equal(t, 1, h.Publish("{user}.orders", []byte("new")))
+	assert.Empty(t, first.received)
+
+	assert.Equal(t, 2, h.SPublish("{user}.orders", []byte("new")))
+	assert.Equal(t, []string{message("smessage", "{user}.orders", "new")}, first.received)
+	assert.Len(t, second.received, 2)
+
+	assert.Equal(t, []string{"{user}.carts", "{user}.orders"}, h.ActiveShardChannels(""))
+	assert.Equal(t, []string{"{user}.carts"}, h.ActiveShardChannels("*carts"))
+	assert.Empty(t, h.ActiveChannels(""))
+	assert.Equal(t, 2, h.ShardNumSub("{user}.orders"))
+
+	assert.True(t, h.SUnsubscribe(first, "{user}.orders"))
+	assert.False(t, h.SUnsubscribe(first, "{user}.orders"))
+	assert.Equal(t, 1, h.ShardNumSub("{user}.orders"))
+
+	h.Remove(first)
+	assert.False(t, h.Subscribed(first))
+	assert.Equal(t, []string{"{user}.orders"}, h.ActiveShardChannels(""))
+}
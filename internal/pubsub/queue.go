@@ -0,0 +1,128 @@
+package pubsub
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// Limits are the output buffer limits of a subscriber, as the pubsub class
+// of client-output-buffer-limit: the subscriber is disconnected once its
+// pending messages take more than Hard bytes, or more than Soft bytes for
+// longer than SoftDuration. A zero limit is no limit.
+type Limits struct {
+	Hard         int64
+	Soft         int64
+	SoftDuration time.Duration
+}
+
+// Queue holds the messages sent to a subscriber until they're written to its
+// connection. Messages are sent while the subscriber is busy, or slow to
+// read, so the queue is bounded by the output buffer limits.
+type Queue struct {
+	mu      sync.Mutex
+	pending [][]byte
+	// size is the number of bytes of the pending messages and of the ones
+	// being written.
+	size int64
+	// softSince is when size went over the soft limit, zero while it's under.
+	softSince time.Time
+	overflow  bool
+
+	limits func() Limits
+	ready  chan struct{}
+	nowFn  func() time.Time
+}
+
+// NewQueue returns an empty queue. limits is called on every message, so
+// that the limits can be changed at runtime.
+func NewQueue(limits func() Limits) *Queue {
+	return &Queue{
+		limits: limits,
+		ready:  make(chan struct{}, 1),
+		nowFn:  time.Now,
+	}
+}
+
+// Push adds a message and returns false if it made the queue go over its
+// limits, in which case the subscriber should be disconnected. The queue
+// silently drops every message from then on.
+func (q *Queue) Push(msg []byte) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.overflow {
+		return true
+	}
+
+	q.pending = append(q.pending, msg)
+	q.size += int64(len(msg))
+
+	if q.overLimits() {
+		q.overflow = true
+		q.pending = nil
+
+		return false
+	}
+
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+
+	return true
+}
+
+// Ready receives a value when messages have been pushed since the last
+// Flush.
+func (q *Queue) Ready() <-chan struct{} {
+	return q.ready
+}
+
+// Flush writes the pending messages. They keep counting against the limits
+// until they're written.
+func (q *Queue) Flush(w io.Writer) error {
+	q.mu.Lock()
+	pending := q.pending
+	q.pending = nil
+	q.mu.Unlock()
+
+	written := int64(0)
+	defer func() {
+		q.mu.Lock()
+		q.size -= written
+		q.mu.Unlock()
+	}()
+
+	for _, msg := range pending {
+		if _, err := w.Write(msg); err != nil {
+			return err
+		}
+
+		written += int64(len(msg))
+	}
+
+	return nil
+}
+
+// overLimits checks the limits once a message has been added. The caller
+// should hold the lock.
+func (q *Queue) overLimits() bool {
+	limits := q.limits()
+
+	if limits.Hard > 0 && q.size > limits.Hard {
+		return true
+	}
+
+	if limits.Soft == 0 || q.size <= limits.Soft {
+		q.softSince = time.Time{}
+		return false
+	}
+
+	now := q.nowFn()
+	if q.softSince.IsZero() {
+		q.softSince = now
+	}
+
+	return now.Sub(q.softSince) > limits.SoftDuration
+}
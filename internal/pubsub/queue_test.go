@@ -0,0 +1,86 @@
+package pubsub
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestQueue(t *testing.T) {
+	t.Run("when messages are flushed in order", func(t *testing.T) {
+		q := NewQueue(func() Limits { return Limits{} })
+
+		require.True(t, q.Push([]byte("a")))
+		require.True(t, q.Push([]byte("b")))
+
+		select {
+		case <-q.Ready():
+		default:
+			t.Fatal("queue is not ready")
+		}
+
+		var buf bytes.Buffer
+		require.NoError(t, q.Flush(&buf))
+		assert.Equal(t, "ab", buf.String())
+		assert.Equal(t, int64(0), q.size)
+	})
+
+	t.Run("when the hard limit is exceeded", func(t *testing.T) {
+		q := NewQueue(func() Limits { return Limits{Hard: 4} })
+
+		require.True(t, q.Push([]byte("abcd")))
+		assert.False(t, q.Push([]byte("e")))
+
+		// the messages are dropped once the queue overflowed
+		assert.True(t, q.Push([]byte("f")))
+
+		var buf bytes.Buffer
+		require.NoError(t, q.Flush(&buf))
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("when the soft limit is exceeded for too long", func(t *testing.T) {
+		now := time.Unix(0, 0)
+		q := NewQueue(func() Limits { return Limits{Soft: 2, SoftDuration: time.Minute} })
+		q.nowFn = func() time.Time { return now }
+
+		require.True(t, q.Push([]byte("abc")))
+
+		now = now.Add(time.Minute)
+		require.True(t, q.Push([]byte("d")))
+
+		now = now.Add(time.Second)
+		assert.False(t, q.Push([]byte("e")))
+	})
+
+	t.Run("when the size goes back under the soft limit", func(t *testing.T) {
+		now := time.Unix(0, 0)
+		q := NewQueue(func() Limits { return Limits{Soft: 2, SoftDuration: time.Minute} })
+		q.nowFn = func() time.Time { return now }
+
+		require.True(t, q.Push([]byte("abc")))
+		require.NoError(t, q.Flush(&bytes.Buffer{}))
+
+		require.True(t, q.Push([]byte("a")))
+
+		now = now.Add(2 * time.Minute)
+		assert.True(t, q.Push([]byte("bcd")))
+	})
+
+	t.Run("when the write fails", func(t *testing.T) {
+		q := NewQueue(func() Limits { return Limits{} })
+
+		require.True(t, q.Push([]byte("a")))
+		assert.Error(t, q.Flush(failingWriter{}))
+	})
+}
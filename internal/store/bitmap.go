@@ -0,0 +1,129 @@
+package store
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/internal/bitfn"
+	"github.com/codecrafters-io/redis-starter-go/internal/notify"
+)
+
+// SetBit sets the bit at offset of the string stored at key, growing it with
+// zero bytes as needed, and returns the previous bit.
+func (s *KVStore) SetBit(key string, offset uint64, bit int) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, err := s.getString(key)
+	if err != nil {
+		return 0, err
+	}
+
+	var str []byte
+	if val != nil {
+		str = val.str
+	}
+
+	str = growString(str, offset>>3+1)
+	previous := bitfn.SetBit(str, offset, bit)
+	s.setString(key, val, str)
+	s.notify(notify.String, "setbit", key)
+
+	return previous, nil
+}
+
+// BitOp stores the result of the operation between the strings stored at
+// the keys into dest, deleting it if the result is empty, and returns its
+// length. Missing keys are empty strings.
+func (s *KVStore) BitOp(op bitfn.Operation, dest string, keys []string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sources := make([][]byte, len(keys))
+	for i, key := range keys {
+		val, err := s.getString(key)
+		if err != nil {
+			return 0, err
+		}
+
+		if val != nil {
+			sources[i] = val.str
+		}
+	}
+
+	res := bitfn.Op(op, sources)
+	if len(res) == 0 {
+		if s.store.Delete(dest) {
+			s.notify(notify.Generic, "del", dest)
+		}
+
+		return 0, nil
+	}
+
+	s.store.Set(dest, &Value{str: res, perm: true})
+	s.notify(notify.String, "set", dest)
+
+	return len(res), nil
+}
+
+// BitField runs the BITFIELD subcommands on the string stored at key, in
+// order, and returns their results. A result is missing when the operation
+// failed because of an overflow. The string is created or grown as needed
+// when there are writes, even if they all fail.
+func (s *KVStore) BitField(key string, ops []bitfn.FieldOp) ([]int64, []bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, err := s.getString(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var length uint64
+	for _, op := range ops {
+		if end := (op.Offset+uint64(op.Encoding.Bits)-1)>>3 + 1; op.Kind != bitfn.FieldGet && end > length {
+			length = end
+		}
+	}
+
+	var str []byte
+	if val != nil {
+		str = val.str
+	}
+
+	if length > 0 {
+		str = growString(str, length)
+		s.setString(key, val, str)
+		s.notify(notify.String, "setbit", key)
+	}
+
+	results := make([]int64, len(ops))
+	ok := make([]bool, len(ops))
+
+	for i, op := range ops {
+		current := op.Encoding.Get(str, op.Offset)
+
+		switch op.Kind {
+		case bitfn.FieldGet:
+			results[i], ok[i] = current, true
+		case bitfn.FieldSet:
+			var value int64
+			if value, ok[i] = op.Encoding.Add(op.Value, 0, op.Overflow); ok[i] {
+				op.Encoding.Set(str, op.Offset, value)
+				results[i] = current
+			}
+		case bitfn.FieldIncrBy:
+			if results[i], ok[i] = op.Encoding.Add(current, op.Value, op.Overflow); ok[i] {
+				op.Encoding.Set(str, op.Offset, results[i])
+			}
+		}
+	}
+
+	return results, ok, nil
+}
+
+// growString pads str with zero bytes to be at least length bytes long.
+func growString(str []byte, length uint64) []byte {
+	if uint64(len(str)) < length {
+		str = append(str, make([]byte, length-uint64(len(str)))...)
+	}
+
+	return str
+}
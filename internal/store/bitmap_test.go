@@ -0,0 +1,71 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/bitfn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKVStore_SetBit(t *testing.T) {
+	s := NewKVStore()
+
+	previous, err := s.SetBit("key", 17, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 0, previous)
+
+	stored, _ := s.Get("key")
+	assert.Equal(t, []byte{0x00, 0x00, 0x40}, stored)
+
+	previous, err = s.SetBit("key", 17, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, previous)
+
+	s.setObject("list", "not a string")
+	_, err = s.SetBit("list", 0, 1)
+	assert.ErrorIs(t, err, ErrWrongType)
+}
+
+func TestKVStore_BitOp(t *testing.T) {
+	s := NewKVStore()
+	s.Set("a", []byte{0xff, 0x0f}, 0)
+	s.Set("b", []byte{0x0f}, 0)
+
+	length, err := s.BitOp(bitfn.OpAnd, "dest", []string{"a", "b", "missing"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, length)
+
+	stored, _ := s.Get("dest")
+	assert.Equal(t, []byte{0x00, 0x00}, stored)
+
+	// an empty result deletes the destination
+	length, err = s.BitOp(bitfn.OpOr, "dest", []string{"missing"})
+	require.NoError(t, err)
+	assert.Equal(t, 0, length)
+	assert.Equal(t, "none", s.Type("dest"))
+}
+
+func TestKVStore_BitField(t *testing.T) {
+	s := NewKVStore()
+	u8 := bitfn.Encoding{Bits: 8}
+
+	// reads don't create the key
+	results, ok, err := s.BitField("key", []bitfn.FieldOp{{Kind: bitfn.FieldGet, Encoding: u8}})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{0}, results)
+	assert.Equal(t, []bool{true}, ok)
+	assert.Equal(t, "none", s.Type("key"))
+
+	results, ok, err = s.BitField("key", []bitfn.FieldOp{
+		{Kind: bitfn.FieldSet, Encoding: u8, Offset: 8, Value: 200},
+		{Kind: bitfn.FieldIncrBy, Encoding: u8, Offset: 8, Value: 100, Overflow: bitfn.OverflowFail},
+		{Kind: bitfn.FieldIncrBy, Encoding: u8, Offset: 8, Value: 100},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{0, 0, 44}, results)
+	assert.Equal(t, []bool{true, false, true}, ok)
+
+	stored, _ := s.Get("key")
+	assert.Equal(t, []byte{0, 44}, stored)
+}
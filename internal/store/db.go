@@ -0,0 +1,72 @@
+package store
+
+import (
+	"sync"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/notify"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/dict"
+)
+
+// NewDatabases returns n empty logical databases. They share their lock, so
+// that the commands working across databases (MOVE, SWAPDB, COPY ... DB) are
+// atomic. The changes to their keys are told to the notifier, if not nil.
+func NewDatabases(n int, notifier *notify.Notifier) []*KVStore {
+	mu := &sync.Mutex{}
+
+	dbs := make([]*KVStore, n)
+	for i := range dbs {
+		dbs[i] = &KVStore{store: dict.New[*Value](), mu: mu, notifier: notifier, db: i}
+	}
+
+	return dbs
+}
+
+// Move moves the key, with its expiration time, to the database db, which
+// must share its lock with s. It returns false if the key doesn't exist or
+// already exists in db.
+func (s *KVStore) Move(key string, db *KVStore) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s == db {
+		return false, ErrSameSourceAndDst
+	}
+
+	val, exists := s.lookup(key)
+	if !exists {
+		return false, nil
+	}
+
+	if _, exists := db.lookup(key); exists {
+		return false, nil
+	}
+
+	s.store.Delete(key)
+	db.store.Set(key, val)
+
+	s.notify(notify.Generic, "move_from", key)
+	db.notify(notify.Generic, "move_to", key)
+
+	return true, nil
+}
+
+// Swap exchanges the keys of the two databases, which must share their
+// lock. The stores built on either database see the keys of the other one
+// afterwards.
+func (s *KVStore) Swap(db *KVStore) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.store, db.store = db.store, s.store
+	s.expireCursor, db.expireCursor = db.expireCursor, s.expireCursor
+}
+
+// Flush deletes every key. The values are left to the garbage collector, so
+// flushing doesn't depend on the size of the database.
+func (s *KVStore) Flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.store = dict.New[*Value]()
+	s.expireCursor = 0
+}
@@ -0,0 +1,78 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKVStore_Move(t *testing.T) {
+	dbs := NewDatabases(2, nil)
+	dbs[0].Set("a", []byte("1"), 10000)
+	dbs[0].Set("b", []byte("1"), 0)
+	dbs[1].Set("b", []byte("2"), 0)
+
+	moved, err := dbs[0].Move("a", dbs[1])
+	require.NoError(t, err)
+	assert.True(t, moved)
+	assert.Equal(t, 0, dbs[0].Exists([]string{"a"}))
+	assert.False(t, contents(dbs[1])["a"].IsPermanent())
+
+	moved, err = dbs[0].Move("b", dbs[1])
+	require.NoError(t, err)
+	assert.False(t, moved)
+
+	moved, err = dbs[0].Move("missing", dbs[1])
+	require.NoError(t, err)
+	assert.False(t, moved)
+
+	_, err = dbs[0].Move("b", dbs[0])
+	assert.ErrorIs(t, err, ErrSameSourceAndDst)
+}
+
+func TestKVStore_Swap(t *testing.T) {
+	dbs := NewDatabases(2, nil)
+	dbs[0].Set("a", []byte("0"), 0)
+	sets := NewSet(dbs[1], config.New())
+
+	_, err := sets.Add("s", []string{"x"})
+	require.NoError(t, err)
+
+	dbs[0].Swap(dbs[1])
+
+	// the stores built on a database follow the swap
+	count, err := sets.Card("s")
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	value, found := dbs[1].Get("a")
+	assert.True(t, found)
+	assert.Equal(t, "0", string(value))
+	assert.Equal(t, "set", dbs[0].Type("s"))
+}
+
+func TestKVStore_Flush(t *testing.T) {
+	dbs := NewDatabases(2, nil)
+	dbs[0].Set("a", []byte("1"), 0)
+	dbs[1].Set("a", []byte("1"), 0)
+
+	dbs[0].Flush()
+
+	assert.Equal(t, 0, dbs[0].DBSize())
+	assert.Equal(t, 1, dbs[1].DBSize())
+}
+
+func TestKVStore_CopyToDatabase(t *testing.T) {
+	dbs := NewDatabases(2, nil)
+	dbs[0].Set("a", []byte("1"), 0)
+
+	copied, err := dbs[0].Copy("a", dbs[1], "a", false)
+	require.NoError(t, err)
+	assert.True(t, copied)
+
+	value, _ := dbs[1].Get("a")
+	assert.Equal(t, "1", string(value))
+}
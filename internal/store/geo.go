@@ -0,0 +1,50 @@
+package store
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/geo"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/zset"
+)
+
+// GeoMatch is a member found by GeoSearch, its score being its geohash.
+type GeoMatch struct {
+	zset.Entry
+
+	Lon, Lat float64
+	Distance float64 // to the center of the shape, in meters
+}
+
+// GeoSearch returns the members of the sorted set located in the shape.
+// When limit is positive the search stops as soon as that many members are
+// found.
+func (z *ZSet) GeoSearch(key string, shape *geo.Shape, limit int) ([]GeoMatch, error) {
+	z.kv.mu.Lock()
+	defer z.kv.mu.Unlock()
+
+	res := []GeoMatch{}
+
+	zs, err := readObject[*zset.ZSet](z.kv, key)
+	if err != nil || zs == nil {
+		return res, err
+	}
+
+	for _, r := range shape.Ranges() {
+		scores := zset.ScoreRange{Min: float64(r.Min), Max: float64(r.Max), MaxEx: true}
+
+		for _, entry := range zs.RangeByScore(scores, false, 0, -1) {
+			lon, lat := geo.Decode(uint64(entry.Score))
+
+			distance, ok := shape.Contains(lon, lat)
+			if !ok {
+				continue
+			}
+
+			res = append(res, GeoMatch{Entry: entry, Lon: lon, Lat: lat, Distance: distance})
+
+			if limit > 0 && len(res) == limit {
+				return res, nil
+			}
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,477 @@
+package store
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/config"
+	"github.com/codecrafters-io/redis-starter-go/internal/floatfn"
+	"github.com/codecrafters-io/redis-starter-go/internal/glob"
+	"github.com/codecrafters-io/redis-starter-go/internal/notify"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/hash"
+)
+
+var (
+	ErrHashValueNotInteger = errors.New("ERR hash value is not an integer")
+	ErrHashValueNotFloat   = errors.New("ERR hash value is not a float")
+	ErrIncrOverflow        = errors.New("ERR increment or decrement would overflow")
+	ErrIncrNaNOrInfinity   = errors.New("ERR increment would produce NaN or Infinity")
+)
+
+// ExpireCondition restricts when the expiration time of a hash field is set
+type ExpireCondition string
+
+const (
+	ExpireAlways ExpireCondition = ""
+	ExpireNX     ExpireCondition = "NX" // only if the field has no expiration time
+	ExpireXX     ExpireCondition = "XX" // only if the field has an expiration time
+	ExpireGT     ExpireCondition = "GT" // only if later than the current one
+	ExpireLT     ExpireCondition = "LT" // only if earlier than the current one
+)
+
+// The results of the field expiration commands, replied for every field
+const (
+	FieldNotFound        = -2
+	FieldNoExpire        = -1
+	FieldConditionNotMet = 0
+	FieldExpireSet       = 1
+	FieldPersisted       = 1
+	FieldDeleted         = 2
+)
+
+// FieldValue is a field of a hash together with its value.
+type FieldValue struct {
+	Field string
+	Value string
+}
+
+type Hash struct {
+	kv  *KVStore
+	cfg *config.Config
+}
+
+func NewHash(kv *KVStore, cfg *config.Config) *Hash {
+	return &Hash{
+		kv:  kv,
+		cfg: cfg,
+	}
+}
+
+// Set sets the given field, value pairs and returns the number of fields
+// which were added.
+func (h *Hash) Set(key string, pairs []FieldValue) (int, error) {
+	h.kv.mu.Lock()
+	defer h.kv.mu.Unlock()
+
+	fields, err := h.getOrCreate(key)
+	if err != nil {
+		return 0, err
+	}
+
+	added := 0
+	for _, pair := range pairs {
+		if fields.Set([]byte(pair.Field), []byte(pair.Value), h.limits()) {
+			added++
+		}
+	}
+
+	h.kv.notify(notify.Hash, "hset", key)
+
+	return added, nil
+}
+
+// SetNX sets the field only if it doesn't exist yet, and returns true if it
+// was set.
+func (h *Hash) SetNX(key, field, value string) (bool, error) {
+	h.kv.mu.Lock()
+	defer h.kv.mu.Unlock()
+
+	fields, err := h.getOrCreate(key)
+	if err != nil {
+		return false, err
+	}
+
+	if _, exists := fields.Get([]byte(field)); exists {
+		return false, nil
+	}
+
+	fields.Set([]byte(field), []byte(value), h.limits())
+	h.kv.notify(notify.Hash, "hset", key)
+
+	return true, nil
+}
+
+func (h *Hash) Get(key, field string) (string, bool, error) {
+	values, err := h.MGet(key, []string{field})
+	if err != nil || values[0] == nil {
+		return "", false, err
+	}
+
+	return string(values[0]), true, nil
+}
+
+// MGet returns the values of the given fields, nil for the missing ones.
+func (h *Hash) MGet(key string, fields []string) ([][]byte, error) {
+	h.kv.mu.Lock()
+	defer h.kv.mu.Unlock()
+
+	res := make([][]byte, len(fields))
+
+	entries, err := readObject[*hash.Hash](h.kv, key)
+	if err != nil || entries == nil {
+		return res, err
+	}
+
+	for i, field := range fields {
+		if value, exists := entries.Get([]byte(field)); exists {
+			res[i] = value
+		}
+	}
+
+	return res, nil
+}
+
+// Del removes the given fields and returns how many of them existed.
+func (h *Hash) Del(key string, fields []string) (int, error) {
+	h.kv.mu.Lock()
+	defer h.kv.mu.Unlock()
+
+	entries, err := h.get(key)
+	if err != nil || entries == nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, field := range fields {
+		if entries.Delete([]byte(field)) {
+			removed++
+		}
+	}
+
+	if removed > 0 {
+		h.kv.notify(notify.Hash, "hdel", key)
+	}
+
+	h.deleteIfEmpty(key, entries)
+
+	return removed, nil
+}
+
+func (h *Hash) Len(key string) (int, error) {
+	h.kv.mu.Lock()
+	defer h.kv.mu.Unlock()
+
+	entries, err := readObject[*hash.Hash](h.kv, key)
+	if err != nil || entries == nil {
+		return 0, err
+	}
+
+	return entries.Len(), nil
+}
+
+// GetAll returns every field of the hash with its value.
+func (h *Hash) GetAll(key string) ([]FieldValue, error) {
+	h.kv.mu.Lock()
+	defer h.kv.mu.Unlock()
+
+	entries, err := readObject[*hash.Hash](h.kv, key)
+	if err != nil || entries == nil {
+		return []FieldValue{}, err
+	}
+
+	return allFields(entries), nil
+}
+
+// IncrBy increments the integer value of the field, which is set to 0 before
+// the operation if it doesn't exist.
+func (h *Hash) IncrBy(key, field string, increment int64) (int64, error) {
+	h.kv.mu.Lock()
+	defer h.kv.mu.Unlock()
+
+	entries, err := h.getOrCreate(key)
+	if err != nil {
+		return 0, err
+	}
+
+	var current int64
+	if value, exists := entries.Get([]byte(field)); exists {
+		current, err = strconv.ParseInt(string(value), 10, 64)
+		if err != nil {
+			return 0, ErrHashValueNotInteger
+		}
+	}
+
+	if (increment > 0 && current > math.MaxInt64-increment) || (increment < 0 && current < math.MinInt64-increment) {
+		return 0, ErrIncrOverflow
+	}
+
+	current += increment
+	entries.Set([]byte(field), strconv.AppendInt(nil, current, 10), h.limits())
+	h.kv.notify(notify.Hash, "hincrby", key)
+
+	return current, nil
+}
+
+// IncrByFloat increments the float value of the field, which is set to 0
+// before the operation if it doesn't exist, and returns the new value as
+// stored.
+func (h *Hash) IncrByFloat(key, field string, increment float64) (string, error) {
+	h.kv.mu.Lock()
+	defer h.kv.mu.Unlock()
+
+	entries, err := h.getOrCreate(key)
+	if err != nil {
+		return "", err
+	}
+
+	var current float64
+	if value, exists := entries.Get([]byte(field)); exists {
+		current, err = strconv.ParseFloat(string(value), 64)
+		if err != nil || math.IsNaN(current) || math.IsInf(current, 0) {
+			return "", ErrHashValueNotFloat
+		}
+	}
+
+	current += increment
+	if math.IsNaN(current) || math.IsInf(current, 0) {
+		return "", ErrIncrNaNOrInfinity
+	}
+
+	formatted := floatfn.FormatHuman(current)
+	entries.Set([]byte(field), []byte(formatted), h.limits())
+	h.kv.notify(notify.Hash, "hincrbyfloat", key)
+
+	return formatted, nil
+}
+
+// RandFields returns up to count distinct random fields. When allowRepeat is
+// set exactly count fields are returned, possibly the same ones many times.
+func (h *Hash) RandFields(key string, count int, allowRepeat bool) ([]FieldValue, error) {
+	h.kv.mu.Lock()
+	defer h.kv.mu.Unlock()
+
+	entries, err := readObject[*hash.Hash](h.kv, key)
+	if err != nil || entries == nil {
+		return []FieldValue{}, err
+	}
+
+	all := allFields(entries)
+
+	if allowRepeat {
+		res := make([]FieldValue, count)
+		for i := range res {
+			res[i] = all[rand.Intn(len(all))]
+		}
+
+		return res, nil
+	}
+
+	rand.Shuffle(len(all), func(i, j int) {
+		all[i], all[j] = all[j], all[i]
+	})
+
+	if count < len(all) {
+		all = all[:count]
+	}
+
+	return all, nil
+}
+
+// Scan returns some of the fields from the cursor, visiting about count of
+// them, and the cursor to continue from, 0 once every field has been
+// visited. Only the fields matching the pattern, if any, are returned.
+// Compact hashes are returned in a single call.
+func (h *Hash) Scan(key string, cursor uint64, match string, count int) (uint64, []FieldValue, error) {
+	h.kv.mu.Lock()
+	defer h.kv.mu.Unlock()
+
+	entries, err := readObject[*hash.Hash](h.kv, key)
+	if err != nil || entries == nil {
+		return 0, []FieldValue{}, err
+	}
+
+	res := []FieldValue{}
+	cursor = scanUntil(cursor, count, &res, func(cursor uint64) uint64 {
+		return entries.Scan(cursor, func(field, value []byte) {
+			if match == "" || glob.Match(match, string(field), false) {
+				res = append(res, FieldValue{Field: string(field), Value: string(value)})
+			}
+		})
+	})
+
+	return cursor, res, nil
+}
+
+// Expire sets the expiration time of the fields, in unix milliseconds, and
+// returns the result for every field. Fields are deleted right away if the
+// time is in the past.
+func (h *Hash) Expire(key string, fields []string, at int64, condition ExpireCondition) ([]int64, error) {
+	h.kv.mu.Lock()
+	defer h.kv.mu.Unlock()
+
+	res := make([]int64, len(fields))
+
+	entries, err := h.get(key)
+	if err != nil || entries == nil {
+		for i := range res {
+			res[i] = FieldNotFound
+		}
+
+		return res, err
+	}
+
+	now := time.Now().UnixMilli()
+	set, deleted := false, false
+
+	for i, field := range fields {
+		if _, exists := entries.Get([]byte(field)); !exists {
+			res[i] = FieldNotFound
+			continue
+		}
+
+		current, hasExpire := entries.Expire([]byte(field))
+
+		// a field without expiration time is treated as never expiring
+		met := true
+		switch condition {
+		case ExpireNX:
+			met = !hasExpire
+		case ExpireXX:
+			met = hasExpire
+		case ExpireGT:
+			met = hasExpire && at > current
+		case ExpireLT:
+			met = !hasExpire || at < current
+		}
+
+		switch {
+		case !met:
+			res[i] = FieldConditionNotMet
+		case at <= now:
+			entries.Delete([]byte(field))
+			res[i] = FieldDeleted
+			deleted = true
+		default:
+			entries.SetExpire([]byte(field), at)
+			res[i] = FieldExpireSet
+			set = true
+		}
+	}
+
+	if set {
+		h.kv.notify(notify.Hash, "hexpire", key)
+	}
+
+	if deleted {
+		h.kv.notify(notify.Hash, "hexpired", key)
+	}
+
+	h.deleteIfEmpty(key, entries)
+
+	return res, nil
+}
+
+// ExpireTimes returns the expiration time of every field in unix
+// milliseconds, or FieldNotFound and FieldNoExpire.
+func (h *Hash) ExpireTimes(key string, fields []string) ([]int64, error) {
+	h.kv.mu.Lock()
+	defer h.kv.mu.Unlock()
+
+	entries, err := readObject[*hash.Hash](h.kv, key)
+
+	res := make([]int64, len(fields))
+	for i, field := range fields {
+		if entries == nil {
+			res[i] = FieldNotFound
+		} else if _, exists := entries.Get([]byte(field)); !exists {
+			res[i] = FieldNotFound
+		} else if at, hasExpire := entries.Expire([]byte(field)); hasExpire {
+			res[i] = at
+		} else {
+			res[i] = FieldNoExpire
+		}
+	}
+
+	return res, err
+}
+
+// Persist removes the expiration time of the fields and returns the result
+// for every field.
+func (h *Hash) Persist(key string, fields []string) ([]int64, error) {
+	h.kv.mu.Lock()
+	defer h.kv.mu.Unlock()
+
+	entries, err := h.get(key)
+
+	persisted := false
+
+	res := make([]int64, len(fields))
+	for i, field := range fields {
+		if entries == nil {
+			res[i] = FieldNotFound
+		} else if _, exists := entries.Get([]byte(field)); !exists {
+			res[i] = FieldNotFound
+		} else if entries.Persist([]byte(field)) {
+			res[i] = FieldPersisted
+			persisted = true
+		} else {
+			res[i] = FieldNoExpire
+		}
+	}
+
+	if persisted {
+		h.kv.notify(notify.Hash, "hpersist", key)
+	}
+
+	return res, err
+}
+
+func allFields(entries *hash.Hash) []FieldValue {
+	res := make([]FieldValue, 0, entries.Len())
+
+	entries.ForEach(func(field, value []byte) bool {
+		res = append(res, FieldValue{Field: string(field), Value: string(value)})
+		return true
+	})
+
+	return res
+}
+
+func (h *Hash) limits() hash.Limits {
+	return hash.Limits{
+		MaxEntries: h.cfg.Int(config.HashMaxListpackEntries),
+		MaxValue:   h.cfg.Int(config.HashMaxListpackValue),
+	}
+}
+
+// get returns the hash stored at key, or nil if the key doesn't exist. The
+// caller should hold the lock.
+func (h *Hash) get(key string) (*hash.Hash, error) {
+	return getObject[*hash.Hash](h.kv, key)
+}
+
+// getOrCreate is like get, but creates the hash if the key doesn't exist.
+// Callers should delete the hash again if nothing ends up in it.
+func (h *Hash) getOrCreate(key string) (*hash.Hash, error) {
+	entries, err := h.get(key)
+	if err != nil || entries != nil {
+		return entries, err
+	}
+
+	entries = hash.New()
+	h.kv.setObject(key, entries)
+
+	return entries, nil
+}
+
+// deleteIfEmpty removes the key once its hash has no fields left. The caller
+// should hold the lock.
+func (h *Hash) deleteIfEmpty(key string, entries *hash.Hash) {
+	if entries.Len() == 0 {
+		h.kv.store.Delete(key)
+		h.kv.notify(notify.Generic, "del", key)
+	}
+}
@@ -0,0 +1,230 @@
+package store
+
+import (
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHash(t *testing.T, pairs ...FieldValue) *Hash {
+	cfg := config.New()
+	require.NoError(t, cfg.Set(config.HashMaxListpackEntries, "4"))
+
+	h := NewHash(NewKVStore(), cfg)
+
+	if len(pairs) > 0 {
+		_, err := h.Set("hash", pairs)
+		require.NoError(t, err)
+	}
+
+	return h
+}
+
+func TestHash_Set(t *testing.T) {
+	h := newTestHash(t, FieldValue{"a", "1"})
+
+	added, err := h.Set("hash", []FieldValue{{"a", "2"}, {"b", "3"}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, added)
+
+	values, err := h.MGet("hash", []string{"a", "missing", "b"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("2"), nil, []byte("3")}, values)
+
+	set, err := h.SetNX("hash", "a", "4")
+	require.NoError(t, err)
+	assert.False(t, set)
+
+	set, err = h.SetNX("hash", "c", "4")
+	require.NoError(t, err)
+	assert.True(t, set)
+
+	h.kv.Set("string", []byte("value"), 0)
+	_, err = h.Set("string", []FieldValue{{"a", "1"}})
+	assert.ErrorIs(t, err, ErrWrongType)
+}
+
+func TestHash_Del(t *testing.T) {
+	h := newTestHash(t, FieldValue{"a", "1"}, FieldValue{"b", "2"})
+
+	removed, err := h.Del("hash", []string{"a", "missing"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	removed, err = h.Del("hash", []string{"b"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	assert.Equal(t, "none", h.kv.Type("hash"))
+}
+
+func TestHash_IncrBy(t *testing.T) {
+	h := newTestHash(t, FieldValue{"text", "abc"}, FieldValue{"max", strconv.FormatInt(math.MaxInt64, 10)})
+
+	value, err := h.IncrBy("hash", "counter", 5)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), value)
+
+	value, err = h.IncrBy("hash", "counter", -7)
+	require.NoError(t, err)
+	assert.Equal(t, int64(-2), value)
+
+	_, err = h.IncrBy("hash", "text", 1)
+	assert.ErrorIs(t, err, ErrHashValueNotInteger)
+
+	_, err = h.IncrBy("hash", "max", 1)
+	assert.ErrorIs(t, err, ErrIncrOverflow)
+}
+
+func TestHash_IncrByFloat(t *testing.T) {
+	h := newTestHash(t, FieldValue{"value", "10.50"}, FieldValue{"text", "abc"})
+
+	value, err := h.IncrByFloat("hash", "value", 0.1)
+	require.NoError(t, err)
+	assert.Equal(t, "10.6", value)
+
+	value, err = h.IncrByFloat("hash", "new", 3e2)
+	require.NoError(t, err)
+	assert.Equal(t, "300", value)
+
+	_, err = h.IncrByFloat("hash", "text", 1)
+	assert.ErrorIs(t, err, ErrHashValueNotFloat)
+
+	_, err = h.IncrByFloat("hash", "value", math.MaxFloat64)
+	require.NoError(t, err)
+	_, err = h.IncrByFloat("hash", "value", math.MaxFloat64)
+	assert.ErrorIs(t, err, ErrIncrNaNOrInfinity)
+}
+
+func TestHash_RandFields(t *testing.T) {
+	h := newTestHash(t, FieldValue{"a", "1"}, FieldValue{"b", "2"}, FieldValue{"c", "3"})
+
+	fields, err := h.RandFields("hash", 2, false)
+	require.NoError(t, err)
+	assert.Len(t, fields, 2)
+	assert.NotEqual(t, fields[0], fields[1])
+
+	fields, err = h.RandFields("hash", 10, false)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []FieldValue{{"a", "1"}, {"b", "2"}, {"c", "3"}}, fields)
+
+	fields, err = h.RandFields("hash", 10, true)
+	require.NoError(t, err)
+	assert.Len(t, fields, 10)
+
+	fields, err = h.RandFields("missing", 10, true)
+	require.NoError(t, err)
+	assert.Empty(t, fields)
+}
+
+func TestHash_Scan(t *testing.T) {
+	t.Run("when hash is compact", func(t *testing.T) {
+		h := newTestHash(t, FieldValue{"a", "1"}, FieldValue{"b", "2"})
+
+		cursor, fields, err := h.Scan("hash", 0, "", 1)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0), cursor)
+		assert.Equal(t, []FieldValue{{"a", "1"}, {"b", "2"}}, fields)
+	})
+
+	t.Run("when hash is a table", func(t *testing.T) {
+		pairs := []FieldValue{}
+		for i := 0; i < 10; i++ {
+			pairs = append(pairs, FieldValue{"field:" + strconv.Itoa(i), strconv.Itoa(i)})
+		}
+		h := newTestHash(t, pairs...)
+
+		visited := []FieldValue{}
+		cursor := uint64(0)
+
+		for {
+			var fields []FieldValue
+			var err error
+
+			cursor, fields, err = h.Scan("hash", cursor, "field:[0-4]", 3)
+			require.NoError(t, err)
+
+			visited = append(visited, fields...)
+			if cursor == 0 {
+				break
+			}
+		}
+
+		assert.ElementsMatch(t, pairs[:5], visited)
+	})
+}
+
+func TestHash_Expire(t *testing.T) {
+	h := newTestHash(t, FieldValue{"a", "1"}, FieldValue{"b", "2"}, FieldValue{"c", "3"})
+	later := time.Now().Add(time.Hour).UnixMilli()
+
+	res, err := h.Expire("hash", []string{"a", "missing"}, later, ExpireAlways)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{FieldExpireSet, FieldNotFound}, res)
+
+	testCases := map[string]struct {
+		at        int64
+		condition ExpireCondition
+		expected  []int64
+	}{
+		"when only without expiration": {at: later, condition: ExpireNX, expected: []int64{FieldConditionNotMet, FieldExpireSet}},
+		"when only with expiration":    {at: later, condition: ExpireXX, expected: []int64{FieldExpireSet, FieldConditionNotMet}},
+		"when only if later":           {at: later + 1, condition: ExpireGT, expected: []int64{FieldExpireSet, FieldConditionNotMet}},
+		"when only if earlier":         {at: later - 1, condition: ExpireLT, expected: []int64{FieldExpireSet, FieldExpireSet}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			h := newTestHash(t, FieldValue{"a", "1"}, FieldValue{"b", "2"})
+			_, err := h.Expire("hash", []string{"a"}, later, ExpireAlways)
+			require.NoError(t, err)
+
+			res, err := h.Expire("hash", []string{"a", "b"}, tc.at, tc.condition)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, res)
+		})
+	}
+
+	times, err := h.ExpireTimes("hash", []string{"a", "b", "missing"})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{later, FieldNoExpire, FieldNotFound}, times)
+
+	res, err = h.Persist("hash", []string{"a", "b", "missing"})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{FieldPersisted, FieldNoExpire, FieldNotFound}, res)
+
+	res, err = h.Expire("hash", []string{"a", "b", "c"}, 1, ExpireAlways)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{FieldDeleted, FieldDeleted, FieldDeleted}, res)
+	assert.Equal(t, "none", h.kv.Type("hash"))
+}
+
+func TestHash_ExpiredFields(t *testing.T) {
+	h := newTestHash(t, FieldValue{"a", "1"}, FieldValue{"b", "2"})
+
+	_, err := h.Expire("hash", []string{"a"}, time.Now().Add(10*time.Millisecond).UnixMilli(), ExpireAlways)
+	require.NoError(t, err)
+
+	time.Sleep(20 * time.Millisecond)
+
+	t.Run("when accessed lazily", func(t *testing.T) {
+		length, err := h.Len("hash")
+		require.NoError(t, err)
+		assert.Equal(t, 1, length)
+	})
+
+	t.Run("when removed by the active expire cycle", func(t *testing.T) {
+		_, err := h.Expire("hash", []string{"b"}, time.Now().Add(10*time.Millisecond).UnixMilli(), ExpireAlways)
+		require.NoError(t, err)
+
+		time.Sleep(20 * time.Millisecond)
+		h.kv.ActiveExpireCycle()
+
+		assert.NotContains(t, contents(h.kv), "hash")
+	})
+}
@@ -0,0 +1,239 @@
+package store
+
+import (
+	"errors"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/config"
+	"github.com/codecrafters-io/redis-starter-go/internal/notify"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/hll"
+)
+
+var (
+	ErrHLLNotFound  = errors.New("ERR The specified key does not exist")
+	ErrHLLNotSparse = errors.New("ERR HLL encoding is not sparse")
+)
+
+// HyperLogLog stores HyperLogLogs as string values, with the layout of
+// Redis.
+type HyperLogLog struct {
+	kv  *KVStore
+	cfg *config.Config
+}
+
+func NewHyperLogLog(kv *KVStore, cfg *config.Config) *HyperLogLog {
+	return &HyperLogLog{
+		kv:  kv,
+		cfg: cfg,
+	}
+}
+
+// Add adds the elements to the HyperLogLog, creating it if needed, and
+// returns whether it changed.
+func (h *HyperLogLog) Add(key string, elements []string) (bool, error) {
+	h.kv.mu.Lock()
+	defer h.kv.mu.Unlock()
+
+	val, err := h.get(key)
+	if err != nil {
+		return false, err
+	}
+
+	created := val == nil
+	if created {
+		val = &Value{str: hll.New(), perm: true}
+		h.kv.store.Set(key, val)
+	}
+
+	values := make([][]byte, len(elements))
+	for i, element := range elements {
+		values[i] = []byte(element)
+	}
+
+	str, updated, err := hll.Add(val.str, values, h.cfg.Int(config.HLLSparseMaxBytes))
+	if err != nil {
+		return false, err
+	}
+
+	val.str = str
+
+	if created || updated {
+		h.kv.notify(notify.String, "pfadd", key)
+	}
+
+	return created || updated, nil
+}
+
+// Count returns the estimated cardinality of the HyperLogLog, or of the
+// union of several of them. The cardinality of a single HyperLogLog is
+// cached in it.
+func (h *HyperLogLog) Count(keys []string) (uint64, error) {
+	h.kv.mu.Lock()
+	defer h.kv.mu.Unlock()
+
+	if len(keys) == 1 {
+		val, err := h.get(keys[0])
+		if err != nil || val == nil {
+			return 0, err
+		}
+
+		return hll.Count(val.str)
+	}
+
+	raw, _, err := h.merge(keys)
+	if err != nil {
+		return 0, err
+	}
+
+	return raw.Count(), nil
+}
+
+// Merge stores the union of the HyperLogLogs, dest included, into dest. The
+// result is dense if any of them is.
+func (h *HyperLogLog) Merge(dest string, keys []string) error {
+	h.kv.mu.Lock()
+	defer h.kv.mu.Unlock()
+
+	raw, dense, err := h.merge(append([]string{dest}, keys...))
+	if err != nil {
+		return err
+	}
+
+	str := raw.Encode(dense, h.cfg.Int(config.HLLSparseMaxBytes))
+
+	// dest has been checked by merge already
+	val, _ := h.kv.getString(dest)
+	h.kv.setString(dest, val, str)
+	h.kv.notify(notify.String, "pfadd", dest)
+
+	return nil
+}
+
+// Registers returns the registers of the HyperLogLog, which is turned into
+// the dense encoding.
+func (h *HyperLogLog) Registers(key string) (hll.Raw, error) {
+	h.kv.mu.Lock()
+	defer h.kv.mu.Unlock()
+
+	val, err := h.getExisting(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if val.str, err = hll.ToDense(val.str); err != nil {
+		return nil, err
+	}
+
+	return hll.Decode(val.str)
+}
+
+// Encoding returns "sparse" or "dense".
+func (h *HyperLogLog) Encoding(key string) (string, error) {
+	h.kv.mu.Lock()
+	defer h.kv.mu.Unlock()
+
+	val, err := h.getExisting(key)
+	if err != nil {
+		return "", err
+	}
+
+	if hll.IsSparse(val.str) {
+		return "sparse", nil
+	}
+
+	return "dense", nil
+}
+
+// ToDense turns the HyperLogLog into the dense encoding, and returns false
+// if it already was.
+func (h *HyperLogLog) ToDense(key string) (bool, error) {
+	h.kv.mu.Lock()
+	defer h.kv.mu.Unlock()
+
+	val, err := h.getExisting(key)
+	if err != nil {
+		return false, err
+	}
+
+	if !hll.IsSparse(val.str) {
+		return false, nil
+	}
+
+	val.str, err = hll.ToDense(val.str)
+
+	return err == nil, err
+}
+
+// Decode returns the opcodes of a sparse HyperLogLog.
+func (h *HyperLogLog) Decode(key string) (string, error) {
+	h.kv.mu.Lock()
+	defer h.kv.mu.Unlock()
+
+	val, err := h.getExisting(key)
+	if err != nil {
+		return "", err
+	}
+
+	if !hll.IsSparse(val.str) {
+		return "", ErrHLLNotSparse
+	}
+
+	return hll.DescribeSparse(val.str)
+}
+
+// get returns the value holding the HyperLogLog stored at key, or nil if
+// the key doesn't exist. The caller should hold the lock.
+func (h *HyperLogLog) get(key string) (*Value, error) {
+	val, err := h.kv.getString(key)
+	if err != nil || val == nil {
+		return nil, err
+	}
+
+	if err := hll.Validate(val.str); err != nil {
+		return nil, err
+	}
+
+	return val, nil
+}
+
+// getExisting returns the HyperLogLog stored at key for the PFDEBUG
+// subcommands, failing if it doesn't exist. The caller should hold the lock.
+func (h *HyperLogLog) getExisting(key string) (*Value, error) {
+	val, err := h.get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if val == nil {
+		return nil, ErrHLLNotFound
+	}
+
+	return val, nil
+}
+
+// merge returns the union of the HyperLogLogs stored at the keys, and
+// whether any of them is dense. The caller should hold the lock.
+func (h *HyperLogLog) merge(keys []string) (hll.Raw, bool, error) {
+	raw := hll.NewRaw()
+	dense := false
+
+	for _, key := range keys {
+		val, err := h.get(key)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if val == nil {
+			continue
+		}
+
+		if !hll.IsSparse(val.str) {
+			dense = true
+		}
+
+		if err := raw.Merge(val.str); err != nil {
+			return nil, false, err
+		}
+	}
+
+	return raw, dense, nil
+}
@@ -0,0 +1,115 @@
+package store
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/config"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/hll"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHyperLogLog_AddCount(t *testing.T) {
+	kv := NewKVStore()
+	h := NewHyperLogLog(kv, config.New())
+
+	changed, err := h.Add("hll", nil)
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	changed, err = h.Add("hll", []string{"a", "b", "c", "d", "e", "f", "g"})
+	require.NoError(t, err)
+	assert.True(t, changed)
+
+	changed, err = h.Add("hll", []string{"a"})
+	require.NoError(t, err)
+	assert.False(t, changed)
+
+	count, err := h.Count([]string{"hll"})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(7), count)
+
+	_, err = h.Add("other", []string{"g", "h"})
+	require.NoError(t, err)
+
+	count, err = h.Count([]string{"hll", "other", "missing"})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(8), count)
+
+	// the HyperLogLog is a plain string
+	assert.Equal(t, "string", kv.Type("hll"))
+}
+
+func TestHyperLogLog_Merge(t *testing.T) {
+	kv := NewKVStore()
+	cfg := config.New()
+	h := NewHyperLogLog(kv, cfg)
+
+	_, err := h.Add("hll1", []string{"foo", "bar", "zap", "a"})
+	require.NoError(t, err)
+
+	_, err = h.Add("hll2", []string{"a", "b", "c", "foo"})
+	require.NoError(t, err)
+
+	require.NoError(t, h.Merge("hll3", []string{"hll1", "hll2"}))
+
+	count, err := h.Count([]string{"hll3"})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(6), count)
+
+	encoding, err := h.Encoding("hll3")
+	require.NoError(t, err)
+	assert.Equal(t, "sparse", encoding)
+
+	// a dense source makes the result dense
+	converted, err := h.ToDense("hll1")
+	require.NoError(t, err)
+	assert.True(t, converted)
+
+	require.NoError(t, h.Merge("hll3", []string{"hll1"}))
+
+	encoding, err = h.Encoding("hll3")
+	require.NoError(t, err)
+	assert.Equal(t, "dense", encoding)
+}
+
+func TestHyperLogLog_Promotion(t *testing.T) {
+	cfg := config.New()
+	require.NoError(t, cfg.Set(config.HLLSparseMaxBytes, "100"))
+
+	h := NewHyperLogLog(NewKVStore(), cfg)
+
+	for i := 0; i < 100; i++ {
+		_, err := h.Add("hll", []string{strconv.Itoa(i)})
+		require.NoError(t, err)
+	}
+
+	encoding, err := h.Encoding("hll")
+	require.NoError(t, err)
+	assert.Equal(t, "dense", encoding)
+}
+
+func TestHyperLogLog_Errors(t *testing.T) {
+	kv := NewKVStore()
+	h := NewHyperLogLog(kv, config.New())
+
+	kv.Set("string", []byte("not a HyperLogLog"), 0)
+	_, err := h.Add("string", []string{"a"})
+	assert.ErrorIs(t, err, hll.ErrInvalid)
+
+	kv.setObject("list", "not a string")
+	_, err = h.Count([]string{"list"})
+	assert.ErrorIs(t, err, ErrWrongType)
+
+	// a sparse HyperLogLog with a register too many, and no cached
+	// cardinality
+	corrupted := append(hll.New(), 0x00)
+	corrupted[15] |= 0x80
+	kv.Set("corrupted", corrupted, 0)
+	_, err = h.Count([]string{"corrupted"})
+	assert.ErrorIs(t, err, hll.ErrCorrupted)
+
+	_, err = h.Encoding("missing")
+	assert.ErrorIs(t, err, ErrHLLNotFound)
+}
@@ -0,0 +1,202 @@
+package store
+
+import (
+	"errors"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/glob"
+	"github.com/codecrafters-io/redis-starter-go/internal/notify"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/hash"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/quicklist"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/set"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/stream"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/zset"
+)
+
+var ErrSameSourceAndDst = errors.New("ERR source and destination objects are the same")
+
+// Del deletes the keys and returns the number of keys deleted.
+func (s *KVStore) Del(keys []string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deleted := 0
+	for _, key := range keys {
+		if _, exists := s.lookup(key); exists {
+			s.store.Delete(key)
+			s.notify(notify.Generic, "del", key)
+			deleted++
+		}
+	}
+
+	return deleted
+}
+
+// Exists returns the number of keys which exist, a key given several times
+// being counted as many times.
+func (s *KVStore) Exists(keys []string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for _, key := range keys {
+		if _, exists := s.lookup(key); exists {
+			count++
+		}
+	}
+
+	return count
+}
+
+// Keys returns the keys matching the glob-style pattern.
+func (s *KVStore) Keys(pattern string) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := []string{}
+	matchAll := pattern == "*"
+
+	s.store.ForEach(func(key string, val *Value) bool {
+		if !matchAll && !glob.Match(pattern, key, false) {
+			return true
+		}
+
+		if _, exists := s.lookup(key); exists {
+			keys = append(keys, key)
+		}
+
+		return true
+	})
+
+	return keys
+}
+
+// Rename moves the value stored at src to dst, overwriting dst. The value
+// keeps its expiration time.
+func (s *KVStore) Rename(src, dst string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, exists := s.lookup(src)
+	if !exists {
+		return ErrNoSuchKey
+	}
+
+	s.store.Delete(src)
+	s.store.Set(dst, val)
+	s.notifyRename(src, dst)
+
+	return nil
+}
+
+// RenameNX moves the value stored at src to dst only if dst doesn't exist,
+// and returns whether it was moved.
+func (s *KVStore) RenameNX(src, dst string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, exists := s.lookup(src)
+	if !exists {
+		return false, ErrNoSuchKey
+	}
+
+	if _, exists := s.lookup(dst); exists {
+		return false, nil
+	}
+
+	s.store.Delete(src)
+	s.store.Set(dst, val)
+	s.notifyRename(src, dst)
+
+	return true, nil
+}
+
+// Copy stores a copy of the value stored at src, with its expiration time, at
+// dst in the database db, which must share its lock with s (see
+// NewDatabases). dst is only overwritten when replace is set. It returns
+// whether the value was copied.
+func (s *KVStore) Copy(src string, db *KVStore, dst string, replace bool) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s == db && src == dst {
+		return false, ErrSameSourceAndDst
+	}
+
+	val, exists := s.lookup(src)
+	if !exists {
+		return false, nil
+	}
+
+	if _, exists := db.lookup(dst); exists && !replace {
+		return false, nil
+	}
+
+	db.store.Set(dst, val.clone())
+	db.notify(notify.Generic, "copy_to", dst)
+
+	return true, nil
+}
+
+// notifyRename notifies that src was renamed to dst. The caller should hold
+// the lock.
+func (s *KVStore) notifyRename(src, dst string) {
+	s.notify(notify.Generic, "rename_from", src)
+	s.notify(notify.Generic, "rename_to", dst)
+}
+
+// Touch returns the number of keys which exist. There is no eviction, so
+// there is no access time to update.
+func (s *KVStore) Touch(keys []string) int {
+	return s.Exists(keys)
+}
+
+// RandomKey returns a random key, and false if the keyspace is empty.
+func (s *KVStore) RandomKey() (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// expired keys are deleted when picked, so this ends even if every key
+	// has expired
+	for {
+		key, _, found := s.store.Random()
+		if !found {
+			return "", false
+		}
+
+		if _, exists := s.lookup(key); exists {
+			return key, true
+		}
+	}
+}
+
+// DBSize returns the number of keys, including the expired keys which haven't
+// been removed yet.
+func (s *KVStore) DBSize() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.store.Len()
+}
+
+// clone returns a deep copy of the value, as strings are modified in place by
+// APPEND, SETRANGE, SETBIT, ...
+func (v *Value) clone() *Value {
+	res := &Value{exp: v.exp, perm: v.perm}
+
+	switch obj := v.obj.(type) {
+	case nil:
+		res.str = append([]byte{}, v.str...)
+	case *quicklist.Quicklist:
+		res.obj = obj.Clone()
+	case *stream.Stream:
+		res.obj = obj.Clone()
+	case *hash.Hash:
+		res.obj = obj.Clone()
+	case *set.Set:
+		res.obj = obj.Clone()
+	case *zset.ZSet:
+		res.obj = obj.Clone()
+	}
+
+	return res
+}
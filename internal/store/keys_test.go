@@ -0,0 +1,164 @@
+package store
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKVStore_Del(t *testing.T) {
+	s := NewKVStore()
+	s.Set("a", []byte("1"), 0)
+	s.Set("expired", []byte("1"), -1)
+
+	_, err := NewList(s, config.New()).Push("list", []string{"a", "b"}, Right, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, s.Del([]string{"a", "list", "a", "expired", "missing"}))
+	assert.Equal(t, 0, s.DBSize())
+}
+
+func TestKVStore_Exists(t *testing.T) {
+	s := NewKVStore()
+	s.Set("a", []byte("1"), 0)
+	s.Set("expired", []byte("1"), -1)
+
+	assert.Equal(t, 3, s.Exists([]string{"a", "a", "expired", "missing", "a"}))
+	assert.Equal(t, 1, s.Touch([]string{"a", "expired"}))
+}
+
+func TestKVStore_Keys(t *testing.T) {
+	s := NewKVStore()
+	for _, key := range []string{"hello", "hallo", "hxllo", "heeeello", "world"} {
+		s.Set(key, []byte("1"), 0)
+	}
+
+	s.Set("hillo", []byte("1"), -1)
+
+	testCases := map[string]struct {
+		pattern  string
+		expected []string
+	}{
+		"when all keys":        {pattern: "*", expected: []string{"hallo", "heeeello", "hello", "hxllo", "world"}},
+		"when single char":     {pattern: "h?llo", expected: []string{"hallo", "hello", "hxllo"}},
+		"when any chars":       {pattern: "h*llo", expected: []string{"hallo", "heeeello", "hello", "hxllo"}},
+		"when char class":      {pattern: "h[ae]llo", expected: []string{"hallo", "hello"}},
+		"when negated class":   {pattern: "h[^e]llo", expected: []string{"hallo", "hxllo"}},
+		"when char range":      {pattern: "h[a-b]llo", expected: []string{"hallo"}},
+		"when nothing matches": {pattern: "x*", expected: []string{}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			keys := s.Keys(tc.pattern)
+			sort.Strings(keys)
+
+			assert.Equal(t, tc.expected, keys)
+		})
+	}
+}
+
+func TestKVStore_Rename(t *testing.T) {
+	s := NewKVStore()
+	s.Set("src", []byte("1"), 10000)
+	s.Set("dst", []byte("2"), 0)
+
+	require.NoError(t, s.Rename("src", "dst"))
+
+	value, found := s.Get("dst")
+	assert.True(t, found)
+	assert.Equal(t, "1", string(value))
+
+	// the expiration time moves with the value
+	assert.False(t, contents(s)["dst"].IsPermanent())
+	assert.Equal(t, 0, s.Exists([]string{"src"}))
+
+	assert.ErrorIs(t, s.Rename("src", "dst"), ErrNoSuchKey)
+	require.NoError(t, s.Rename("dst", "dst"))
+}
+
+func TestKVStore_RenameNX(t *testing.T) {
+	s := NewKVStore()
+	s.Set("src", []byte("1"), 0)
+	s.Set("dst", []byte("2"), 0)
+
+	renamed, err := s.RenameNX("src", "dst")
+	require.NoError(t, err)
+	assert.False(t, renamed)
+
+	renamed, err = s.RenameNX("src", "other")
+	require.NoError(t, err)
+	assert.True(t, renamed)
+
+	_, err = s.RenameNX("src", "dst")
+	assert.ErrorIs(t, err, ErrNoSuchKey)
+}
+
+func TestKVStore_Copy(t *testing.T) {
+	s := NewKVStore()
+	s.Set("src", []byte("hello"), 10000)
+	s.Set("dst", []byte("2"), 0)
+
+	copied, err := s.Copy("src", s, "dst", false)
+	require.NoError(t, err)
+	assert.False(t, copied)
+
+	copied, err = s.Copy("src", s, "dst", true)
+	require.NoError(t, err)
+	assert.True(t, copied)
+	assert.False(t, contents(s)["dst"].IsPermanent())
+
+	// the copy doesn't share its bytes with the source
+	_, err = s.SetRange("src", 0, []byte("j"))
+	require.NoError(t, err)
+
+	value, _ := s.Get("dst")
+	assert.Equal(t, "hello", string(value))
+
+	copied, err = s.Copy("missing", s, "dst", true)
+	require.NoError(t, err)
+	assert.False(t, copied)
+
+	_, err = s.Copy("src", s, "src", true)
+	assert.ErrorIs(t, err, ErrSameSourceAndDst)
+}
+
+func TestKVStore_CopyObject(t *testing.T) {
+	s := NewKVStore()
+	sets := NewSet(s, config.New())
+
+	_, err := sets.Add("src", []string{"a"})
+	require.NoError(t, err)
+
+	copied, err := s.Copy("src", s, "dst", false)
+	require.NoError(t, err)
+	assert.True(t, copied)
+
+	_, err = sets.Add("src", []string{"b"})
+	require.NoError(t, err)
+
+	count, err := sets.Card("dst")
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestKVStore_RandomKey(t *testing.T) {
+	s := NewKVStore()
+
+	_, found := s.RandomKey()
+	assert.False(t, found)
+
+	s.Set("expired", []byte("1"), -1)
+	s.Set("a", []byte("1"), 0)
+
+	time.Sleep(time.Millisecond)
+
+	key, found := s.RandomKey()
+	assert.True(t, found)
+	assert.Equal(t, "a", key)
+}
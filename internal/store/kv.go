@@ -1,19 +1,51 @@
 package store
 
 import (
+	"errors"
 	"sync"
 	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/notify"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/dict"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/hash"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/quicklist"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/set"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/stream"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/zset"
 )
 
+var ErrWrongType = errors.New("WRONGTYPE Operation against a key holding the wrong kind of value")
+
+// activeExpireLookups is the number of keys looked at by every active expire
+// cycle.
+const activeExpireLookups = 200
+
+// activeRehashSteps is the number of buckets of the keyspace moved by every
+// active expire cycle while the keyspace is being resized.
+const activeRehashSteps = 100
+
+// KVStore is the keyspace. It holds the keys of every type, the type specific
+// stores (Stream, List, ...) keep their values in it as well.
 type KVStore struct {
-	store map[string]*Value
+	store *dict.Dict[*Value]
 	mu    *sync.Mutex
+
+	// expireCursor is where the next active expire cycle resumes scanning the
+	// keyspace.
+	expireCursor uint64
+
+	// notifier is told about the changes to the keys, nil if keyspace
+	// notifications aren't published. db is the index of the database in
+	// the notifications.
+	notifier *notify.Notifier
+	db       int
 }
 
 type Value struct {
-	str  string
-	exp  int64 // unix milliseconds
-	perm bool  // is permanent
+	str  []byte
+	obj  interface{} // holds the value of every type but strings
+	exp  int64       // unix milliseconds
+	perm bool        // is permanent
 }
 
 func (v *Value) IsExpired() bool {
@@ -32,35 +64,153 @@ func (v *Value) IsPermanent() bool {
 	return v.perm
 }
 
+// Type is the name of the value's type, as reported by the TYPE command.
+func (v *Value) Type() string {
+	switch v.obj.(type) {
+	case nil:
+		return "string"
+	case *quicklist.Quicklist:
+		return "list"
+	case *stream.Stream:
+		return "stream"
+	case *hash.Hash:
+		return "hash"
+	case *set.Set:
+		return "set"
+	case *zset.ZSet:
+		return "zset"
+	}
+
+	return "none"
+}
+
 func NewKVStore() *KVStore {
 	return &KVStore{
-		store: map[string]*Value{},
+		store: dict.New[*Value](),
 		mu:    &sync.Mutex{},
 	}
 }
 
-func (s *KVStore) Get(key string) (string, bool) {
+func (s *KVStore) Get(key string) ([]byte, bool) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	val, exists := s.store[key]
 
+	val, exists := s.lookup(key)
 	if !exists {
-		return "", false
+		s.keyMiss(key)
+		return nil, false
 	}
 
-	if !val.IsPermanent() && val.IsExpired() {
-		return "", false
+	if val.obj != nil {
+		return nil, false
 	}
 
 	return val.str, exists
 }
 
-func (s *KVStore) Set(key, value string, exp int64) {
+func (s *KVStore) Set(key string, value []byte, exp int64) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.store[key] = &Value{
+	s.store.Set(key, &Value{
 		str:  value,
 		exp:  time.Now().UnixMilli() + exp,
 		perm: exp == 0,
+	})
+
+	s.notify(notify.String, "set", key)
+	if exp != 0 {
+		s.notify(notify.Generic, "expire", key)
+	}
+}
+
+// Type returns the type of the value stored at key, or "none" if the key
+// doesn't exist.
+func (s *KVStore) Type(key string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, exists := s.lookup(key)
+	if !exists {
+		return "none"
+	}
+
+	return val.Type()
+}
+
+// lookup returns the value stored at key, deleting it first if it has
+// expired. The caller should hold the lock.
+func (s *KVStore) lookup(key string) (*Value, bool) {
+	val, exists := s.store.Get(key)
+	if !exists {
+		return nil, false
+	}
+
+	if !val.IsPermanent() && val.IsExpired() {
+		s.store.Delete(key)
+		s.notify(notify.Expired, "expired", key)
+
+		return nil, false
+	}
+
+	if fields, ok := val.obj.(*hash.Hash); ok {
+		if expired := fields.DeleteExpired(time.Now().UnixMilli()); len(expired) > 0 {
+			s.notify(notify.Hash, "hexpired", key)
+		}
+
+		if fields.Len() == 0 {
+			s.store.Delete(key)
+			s.notify(notify.Generic, "del", key)
+
+			return nil, false
+		}
 	}
+
+	return val, true
+}
+
+// notify publishes the keyspace notification of the event which happened to
+// the key. The caller should hold the lock.
+func (s *KVStore) notify(class notify.Class, event, key string) {
+	if s.notifier != nil {
+		s.notifier.Notify(class, event, key, s.db)
+	}
+}
+
+// keyMiss notifies that a command reading the key didn't find it. The caller
+// should hold the lock.
+func (s *KVStore) keyMiss(key string) {
+	s.notify(notify.KeyMiss, "keymiss", key)
+}
+
+// ActiveExpireCycle removes some of the expired keys and hash fields, which
+// would otherwise only be removed once accessed. Every call looks up about
+// activeExpireLookups keys, scanning the keyspace from where the previous
+// call stopped, so that repeated calls end up looking at the whole keyspace.
+// It also moves the keyspace along when it's being resized.
+func (s *KVStore) ActiveExpireCycle() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := []string{}
+	for len(keys) < activeExpireLookups {
+		s.expireCursor = s.store.Scan(s.expireCursor, func(key string, val *Value) {
+			keys = append(keys, key)
+		})
+
+		if s.expireCursor == 0 {
+			break
+		}
+	}
+
+	for _, key := range keys {
+		s.lookup(key)
+	}
+
+	s.store.Rehash(activeRehashSteps)
+}
+
+// setObject stores a non string value without expiration. The caller should
+// hold the lock.
+func (s *KVStore) setObject(key string, obj interface{}) {
+	s.store.Set(key, &Value{obj: obj, perm: true})
 }
@@ -1,12 +1,39 @@
 package store
 
 import (
-	"sync"
+	"bytes"
 	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/config"
+	"github.com/codecrafters-io/redis-starter-go/internal/notify"
+	"github.com/codecrafters-io/redis-starter-go/internal/payload"
+	"github.com/codecrafters-io/redis-starter-go/internal/pubsub"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/zset"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+func newKVStoreWith(values map[string]*Value) *KVStore {
+	s := NewKVStore()
+	for key, val := range values {
+		s.store.Set(key, val)
+	}
+
+	return s
+}
+
+func contents(s *KVStore) map[string]*Value {
+	res := map[string]*Value{}
+	s.store.ForEach(func(key string, val *Value) bool {
+		res[key] = val
+		return true
+	})
+
+	return res
+}
+
 func TestKVStore_Get(t *testing.T) {
 	type args struct {
 		key string
@@ -15,28 +42,28 @@ func TestKVStore_Get(t *testing.T) {
 		name  string
 		s     *KVStore
 		args  args
-		want  string
+		want  []byte
 		want1 bool
 	}{
 		{
 			name:  "when kvstore is empty",
 			s:     NewKVStore(),
 			args:  args{key: "key-1"},
-			want:  "",
+			want:  nil,
 			want1: false,
 		},
 		{
 			name:  "when kvstore is not empty",
-			s:     &KVStore{mu: &sync.Mutex{}, store: map[string]*Value{"key-1": {str: "val-1", perm: true}}},
+			s:     newKVStoreWith(map[string]*Value{"key-1": {str: []byte("val-1"), perm: true}}),
 			args:  args{key: "key-1"},
-			want:  "val-1",
+			want:  []byte("val-1"),
 			want1: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got, got1 := tt.s.Get(tt.args.key)
-			if got != tt.want {
+			if !bytes.Equal(got, tt.want) {
 				t.Errorf("KVStore.Get() got = %v, want %v", got, tt.want)
 			}
 			if got1 != tt.want1 {
@@ -49,7 +76,7 @@ func TestKVStore_Get(t *testing.T) {
 func TestKVStore_Set(t *testing.T) {
 	type args struct {
 		key   string
-		value string
+		value []byte
 	}
 	tests := []struct {
 		name     string
@@ -60,19 +87,125 @@ func TestKVStore_Set(t *testing.T) {
 		{
 			name:     "when settings value",
 			s:        NewKVStore(),
-			args:     args{key: "key-1", value: "val-1"},
-			expected: map[string]*Value{"key-1": {str: "val-1", perm: true}},
+			args:     args{key: "key-1", value: []byte("val-1")},
+			expected: map[string]*Value{"key-1": {str: []byte("val-1"), perm: true}},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			tt.s.Set(tt.args.key, tt.args.value, 0)
 
-			for _, v := range tt.s.store {
+			stored := contents(tt.s)
+			for _, v := range stored {
 				v.exp = 0
 			}
 
-			assert.Equal(t, tt.expected, tt.s.store)
+			assert.Equal(t, tt.expected, stored)
+		})
+	}
+}
+
+// eventRecorder records the keyevent notifications of the database 0.
+type eventRecorder struct {
+	received []string
+}
+
+func (r *eventRecorder) Send(msg payload.Push) {
+	r.received = append(r.received, string(payload.Encode(msg, 2)))
+}
+
+func keyevents(events ...string) []string {
+	res := []string{}
+	for i := 0; i < len(events); i += 2 {
+		res = append(res, string(payload.Encode(payload.BulkStrings([]string{
+			"pmessage", "__keyevent@0__:*", "__keyevent@0__:" + events[i], events[i+1],
+		}), 2)))
+	}
+
+	return res
+}
+
+func TestNotifications(t *testing.T) {
+	cfg := config.New()
+
+	testCases := map[string]struct {
+		classes string
+		run     func(s *KVStore)
+		// expected are the event, key pairs notified
+		expected []string
+	}{
+		"when classes are disabled": {
+			classes: "KE",
+			run: func(s *KVStore) {
+				s.Set("key", []byte("value"), 0)
+			},
+			expected: keyevents(),
+		},
+		"when strings are set and deleted": {
+			classes: "E$g",
+			run: func(s *KVStore) {
+				s.Set("key", []byte("value"), 1000)
+				s.Del([]string{"key", "missing"})
+			},
+			expected: keyevents("set", "key", "expire", "key", "del", "key"),
+		},
+		"when a key is renamed": {
+			classes: "Eg",
+			run: func(s *KVStore) {
+				s.Set("key", []byte("value"), 0)
+				s.Rename("key", "other")
+			},
+			expected: keyevents("rename_from", "key", "rename_to", "other"),
+		},
+		"when a list is emptied": {
+			classes: "EA",
+			run: func(s *KVStore) {
+				l := NewList(s, cfg)
+				l.Push("list", []string{"a", "b"}, Left, false)
+				l.Pop("list", 5, Right)
+			},
+			expected: keyevents("lpush", "list", "rpop", "list", "del", "list"),
+		},
+		"when nothing changes": {
+			classes: "EA",
+			run: func(s *KVStore) {
+				NewSet(s, cfg).Rem("set", []string{"a"})
+				NewZSet(s, cfg).Add("zset", []zset.Entry{{Member: "a", Score: 1}}, zset.AddFlags{XX: true})
+			},
+			expected: keyevents(),
+		},
+		"when keys are missed": {
+			classes: "Em",
+			run: func(s *KVStore) {
+				s.GetString("key")
+				NewHash(s, cfg).Len("hash")
+				NewHash(s, cfg).Set("hash", []FieldValue{{Field: "f", Value: "v"}})
+			},
+			expected: keyevents("keymiss", "key", "keymiss", "hash"),
+		},
+		"when a key expires": {
+			classes: "Ex",
+			run: func(s *KVStore) {
+				s.store.Set("key", &Value{str: []byte("value"), exp: time.Now().UnixMilli() - 1})
+				s.ActiveExpireCycle()
+			},
+			expected: keyevents("expired", "key"),
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			hub := pubsub.NewHub()
+			r := &eventRecorder{received: []string{}}
+			hub.PSubscribe(r, "__keyevent@0__:*")
+
+			classes, err := notify.ParseClasses(tc.classes)
+			require.NoError(t, err)
+
+			s := NewDatabases(1, notify.NewNotifier(hub, func() notify.Class { return classes }))[0]
+			tc.run(s)
+
+			assert.Equal(t, tc.expected, r.received)
 		})
 	}
 }
@@ -0,0 +1,437 @@
+package store
+
+import (
+	"errors"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/config"
+	"github.com/codecrafters-io/redis-starter-go/internal/notify"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/quicklist"
+)
+
+var (
+	ErrNoSuchKey       = errors.New("ERR no such key")
+	ErrIndexOutOfRange = errors.New("ERR index out of range")
+)
+
+// ListSide is the end of a list an operation works on
+type ListSide int
+
+const (
+	Left ListSide = iota
+	Right
+)
+
+// pushEvents and popEvents are the keyspace events of the operations on
+// either side of a list.
+var (
+	pushEvents = map[ListSide]string{Left: "lpush", Right: "rpush"}
+	popEvents  = map[ListSide]string{Left: "lpop", Right: "rpop"}
+)
+
+type List struct {
+	kv  *KVStore
+	cfg *config.Config
+}
+
+func NewList(kv *KVStore, cfg *config.Config) *List {
+	return &List{
+		kv:  kv,
+		cfg: cfg,
+	}
+}
+
+// Push adds the values one after the other to the given side of the list and
+// returns the new length. When onlyIfExists is set and the key doesn't exist,
+// nothing is pushed and 0 is returned.
+func (l *List) Push(key string, values []string, side ListSide, onlyIfExists bool) (int, error) {
+	l.kv.mu.Lock()
+	defer l.kv.mu.Unlock()
+
+	list, err := l.get(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if list == nil {
+		if onlyIfExists {
+			return 0, nil
+		}
+
+		list = quicklist.New(l.cfg.Int(config.ListMaxListpackSize))
+		l.kv.setObject(key, list)
+	}
+
+	for _, val := range values {
+		if side == Left {
+			list.PushHead([]byte(val))
+		} else {
+			list.PushTail([]byte(val))
+		}
+	}
+
+	l.kv.notify(notify.List, pushEvents[side], key)
+
+	return list.Len(), nil
+}
+
+// Pop removes up to count elements from the given side of the list. nil is
+// returned if the key doesn't exist.
+func (l *List) Pop(key string, count int, side ListSide) ([]string, error) {
+	l.kv.mu.Lock()
+	defer l.kv.mu.Unlock()
+
+	list, err := l.get(key)
+	if err != nil || list == nil {
+		return nil, err
+	}
+
+	res := make([]string, 0, count)
+
+	for len(res) < count {
+		var val []byte
+		var ok bool
+
+		if side == Left {
+			val, ok = list.PopHead()
+		} else {
+			val, ok = list.PopTail()
+		}
+
+		if !ok {
+			break
+		}
+
+		res = append(res, string(val))
+	}
+
+	if len(res) > 0 {
+		l.kv.notify(notify.List, popEvents[side], key)
+	}
+
+	l.deleteIfEmpty(key, list)
+
+	return res, nil
+}
+
+// Move pops an element from the from side of src and pushes it to the to
+// side of dst, atomically. false is returned if src doesn't exist. src and dst
+// may be the same list, which rotates it.
+func (l *List) Move(src, dst string, from, to ListSide) (string, bool, error) {
+	l.kv.mu.Lock()
+	defer l.kv.mu.Unlock()
+
+	source, err := l.get(src)
+	if err != nil || source == nil {
+		return "", false, err
+	}
+
+	destination, err := l.get(dst)
+	if err != nil {
+		return "", false, err
+	}
+
+	var val []byte
+	if from == Left {
+		val, _ = source.PopHead()
+	} else {
+		val, _ = source.PopTail()
+	}
+
+	if src == dst {
+		destination = source
+	} else if destination == nil {
+		destination = quicklist.New(l.cfg.Int(config.ListMaxListpackSize))
+		l.kv.setObject(dst, destination)
+	}
+
+	if to == Left {
+		destination.PushHead(val)
+	} else {
+		destination.PushTail(val)
+	}
+
+	l.kv.notify(notify.List, popEvents[from], src)
+	l.kv.notify(notify.List, pushEvents[to], dst)
+
+	l.deleteIfEmpty(src, source)
+
+	return string(val), true, nil
+}
+
+func (l *List) Len(key string) (int, error) {
+	l.kv.mu.Lock()
+	defer l.kv.mu.Unlock()
+
+	list, err := readObject[*quicklist.Quicklist](l.kv, key)
+	if err != nil || list == nil {
+		return 0, err
+	}
+
+	return list.Len(), nil
+}
+
+// Range returns the elements between start and stop, both inclusive.
+// Negative indexes count from the tail.
+func (l *List) Range(key string, start, stop int) ([]string, error) {
+	l.kv.mu.Lock()
+	defer l.kv.mu.Unlock()
+
+	res := []string{}
+
+	list, err := readObject[*quicklist.Quicklist](l.kv, key)
+	if err != nil || list == nil {
+		return res, err
+	}
+
+	start, stop, ok := normalizeRange(start, stop, list.Len())
+	if !ok {
+		return res, nil
+	}
+
+	it := list.Iterator(start, false)
+	for i := start; i <= stop && it.Next(); i++ {
+		res = append(res, string(it.Value()))
+	}
+
+	return res, nil
+}
+
+func (l *List) Index(key string, index int) (string, bool, error) {
+	l.kv.mu.Lock()
+	defer l.kv.mu.Unlock()
+
+	list, err := readObject[*quicklist.Quicklist](l.kv, key)
+	if err != nil || list == nil {
+		return "", false, err
+	}
+
+	val, ok := list.Index(index)
+
+	return string(val), ok, nil
+}
+
+func (l *List) Set(key string, index int, value string) error {
+	l.kv.mu.Lock()
+	defer l.kv.mu.Unlock()
+
+	list, err := l.get(key)
+	if err != nil {
+		return err
+	}
+
+	if list == nil {
+		return ErrNoSuchKey
+	}
+
+	if !list.Replace(index, []byte(value)) {
+		return ErrIndexOutOfRange
+	}
+
+	l.kv.notify(notify.List, "lset", key)
+
+	return nil
+}
+
+// Rem removes the elements equal to value and returns how many were removed.
+// A positive count removes up to count elements starting from the head, a
+// negative one starts from the tail and 0 removes all of them.
+func (l *List) Rem(key string, count int, value string) (int, error) {
+	l.kv.mu.Lock()
+	defer l.kv.mu.Unlock()
+
+	list, err := l.get(key)
+	if err != nil || list == nil {
+		return 0, err
+	}
+
+	reverse := count < 0
+	if reverse {
+		count = -count
+	}
+
+	start := 0
+	if reverse {
+		start = -1
+	}
+
+	removed := 0
+
+	it := list.Iterator(start, reverse)
+	for it.Next() {
+		if string(it.Value()) != value {
+			continue
+		}
+
+		it.Delete()
+		removed++
+
+		if count != 0 && removed == count {
+			break
+		}
+	}
+
+	if removed > 0 {
+		l.kv.notify(notify.List, "lrem", key)
+	}
+
+	l.deleteIfEmpty(key, list)
+
+	return removed, nil
+}
+
+// Trim keeps only the elements between start and stop, both inclusive.
+func (l *List) Trim(key string, start, stop int) error {
+	l.kv.mu.Lock()
+	defer l.kv.mu.Unlock()
+
+	list, err := l.get(key)
+	if err != nil || list == nil {
+		return err
+	}
+
+	length := list.Len()
+
+	start, stop, ok := normalizeRange(start, stop, length)
+	if !ok {
+		l.kv.store.Delete(key)
+		l.kv.notify(notify.List, "ltrim", key)
+		l.kv.notify(notify.Generic, "del", key)
+
+		return nil
+	}
+
+	list.DeleteRange(stop+1, length-stop-1)
+	list.DeleteRange(0, start)
+	l.kv.notify(notify.List, "ltrim", key)
+
+	return nil
+}
+
+// Insert adds value before or after the first element equal to pivot. It
+// returns the new length, -1 when the pivot isn't found and 0 when the key
+// doesn't exist.
+func (l *List) Insert(key string, side ListSide, pivot, value string) (int, error) {
+	l.kv.mu.Lock()
+	defer l.kv.mu.Unlock()
+
+	list, err := l.get(key)
+	if err != nil || list == nil {
+		return 0, err
+	}
+
+	index := 0
+
+	it := list.Iterator(0, false)
+	for it.Next() {
+		if string(it.Value()) == pivot {
+			if side == Right {
+				index++
+			}
+
+			list.Insert(index, []byte(value))
+			l.kv.notify(notify.List, "linsert", key)
+
+			return list.Len(), nil
+		}
+
+		index++
+	}
+
+	return -1, nil
+}
+
+// Pos returns the indexes of the elements equal to element. rank is the
+// match to start from, negative ranks search from the tail. Up to count
+// indexes are returned, 0 meaning all of them, and maxLen limits the number
+// of compared elements when it isn't 0.
+func (l *List) Pos(key string, element string, rank, count, maxLen int) ([]int, error) {
+	l.kv.mu.Lock()
+	defer l.kv.mu.Unlock()
+
+	res := []int{}
+
+	list, err := readObject[*quicklist.Quicklist](l.kv, key)
+	if err != nil || list == nil {
+		return res, err
+	}
+
+	reverse := rank < 0
+	if reverse {
+		rank = -rank
+	}
+
+	start, index, step := 0, 0, 1
+	if reverse {
+		start, index, step = -1, list.Len()-1, -1
+	}
+
+	matches := 0
+	compared := 0
+
+	it := list.Iterator(start, reverse)
+	for it.Next() {
+		if maxLen != 0 && compared >= maxLen {
+			break
+		}
+
+		compared++
+
+		if string(it.Value()) == element {
+			matches++
+
+			if matches >= rank {
+				res = append(res, index)
+
+				if count != 0 && len(res) == count {
+					break
+				}
+			}
+		}
+
+		index += step
+	}
+
+	return res, nil
+}
+
+// get returns the list stored at key, or nil if the key doesn't exist. The
+// caller should hold the lock.
+func (l *List) get(key string) (*quicklist.Quicklist, error) {
+	return getObject[*quicklist.Quicklist](l.kv, key)
+}
+
+// deleteIfEmpty removes the key once its list has no elements left, as
+// Redis never keeps empty lists around. The caller should hold the lock.
+func (l *List) deleteIfEmpty(key string, list *quicklist.Quicklist) {
+	if list.Len() == 0 {
+		l.kv.store.Delete(key)
+		l.kv.notify(notify.Generic, "del", key)
+	}
+}
+
+// normalizeRange converts the start and stop indexes of range commands into
+// valid, non negative indexes. The last return value is false if the range
+// is empty.
+func normalizeRange(start, stop, length int) (int, int, bool) {
+	if start < 0 {
+		start = length + start
+	}
+
+	if stop < 0 {
+		stop = length + stop
+	}
+
+	if start < 0 {
+		start = 0
+	}
+
+	if start > stop || start >= length {
+		return 0, 0, false
+	}
+
+	if stop >= length {
+		stop = length - 1
+	}
+
+	return start, stop, true
+}
@@ -0,0 +1,284 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestList(t *testing.T, values ...string) *List {
+	cfg := config.New()
+	require.NoError(t, cfg.Set(config.ListMaxListpackSize, "2"))
+
+	l := NewList(NewKVStore(), cfg)
+
+	if len(values) > 0 {
+		_, err := l.Push("list", values, Right, false)
+		require.NoError(t, err)
+	}
+
+	return l
+}
+
+func TestList_Push(t *testing.T) {
+	testCases := map[string]struct {
+		list           *List
+		values         []string
+		side           ListSide
+		onlyIfExists   bool
+		expectedLength int
+		expected       []string
+	}{
+		"when pushing to the left": {
+			list:           newTestList(t, "c"),
+			values:         []string{"b", "a"},
+			side:           Left,
+			expectedLength: 3,
+			expected:       []string{"a", "b", "c"},
+		},
+		"when pushing to the right": {
+			list:           newTestList(t, "a"),
+			values:         []string{"b", "c"},
+			side:           Right,
+			expectedLength: 3,
+			expected:       []string{"a", "b", "c"},
+		},
+		"when pushing only if the list exists": {
+			list:           newTestList(t),
+			values:         []string{"a"},
+			side:           Right,
+			onlyIfExists:   true,
+			expectedLength: 0,
+			expected:       []string{},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			length, err := tc.list.Push("list", tc.values, tc.side, tc.onlyIfExists)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedLength, length)
+
+			values, err := tc.list.Range("list", 0, -1)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, values)
+		})
+	}
+}
+
+func TestList_WrongType(t *testing.T) {
+	l := newTestList(t)
+	l.kv.Set("string", []byte("value"), 0)
+
+	_, err := l.Push("string", []string{"a"}, Left, false)
+	assert.ErrorIs(t, err, ErrWrongType)
+
+	_, err = l.Len("string")
+	assert.ErrorIs(t, err, ErrWrongType)
+}
+
+func TestList_Pop(t *testing.T) {
+	l := newTestList(t, "a", "b", "c", "d")
+
+	values, err := l.Pop("list", 1, Left)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a"}, values)
+
+	values, err = l.Pop("list", 2, Right)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"d", "c"}, values)
+
+	values, err = l.Pop("list", 10, Right)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b"}, values)
+
+	assert.Equal(t, "none", l.kv.Type("list"))
+
+	values, err = l.Pop("list", 1, Left)
+	require.NoError(t, err)
+	assert.Nil(t, values)
+}
+
+func TestList_Range(t *testing.T) {
+	testCases := map[string]struct {
+		start    int
+		stop     int
+		expected []string
+	}{
+		"when the whole list is requested": {start: 0, stop: -1, expected: []string{"a", "b", "c", "d", "e"}},
+		"when negative indexes given":      {start: -3, stop: -2, expected: []string{"c", "d"}},
+		"when stop is out of range":        {start: 3, stop: 100, expected: []string{"d", "e"}},
+		"when start is out of range":       {start: -100, stop: 0, expected: []string{"a"}},
+		"when start is bigger than stop":   {start: 3, stop: 1, expected: []string{}},
+		"when start is bigger than length": {start: 5, stop: 10, expected: []string{}},
+	}
+
+	l := newTestList(t, "a", "b", "c", "d", "e")
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			values, err := l.Range("list", tc.start, tc.stop)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, values)
+		})
+	}
+}
+
+func TestList_Set(t *testing.T) {
+	l := newTestList(t, "a", "b", "c")
+
+	require.NoError(t, l.Set("list", -1, "z"))
+	assert.ErrorIs(t, l.Set("list", 3, "z"), ErrIndexOutOfRange)
+	assert.ErrorIs(t, l.Set("missing", 0, "z"), ErrNoSuchKey)
+
+	value, found, err := l.Index("list", 2)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "z", value)
+}
+
+func TestList_Rem(t *testing.T) {
+	testCases := map[string]struct {
+		count           int
+		expectedRemoved int
+		expected        []string
+	}{
+		"when removing from the head": {
+			count:           2,
+			expectedRemoved: 2,
+			expected:        []string{"a", "b", "x", "c"},
+		},
+		"when removing from the tail": {
+			count:           -2,
+			expectedRemoved: 2,
+			expected:        []string{"x", "a", "b", "c"},
+		},
+		"when removing every occurrence": {
+			count:           0,
+			expectedRemoved: 3,
+			expected:        []string{"a", "b", "c"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			l := newTestList(t, "x", "a", "x", "b", "x", "c")
+
+			removed, err := l.Rem("list", tc.count, "x")
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedRemoved, removed)
+
+			values, err := l.Range("list", 0, -1)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, values)
+		})
+	}
+}
+
+func TestList_Trim(t *testing.T) {
+	testCases := map[string]struct {
+		start    int
+		stop     int
+		expected []string
+	}{
+		"when keeping the middle":     {start: 1, stop: -2, expected: []string{"b", "c", "d"}},
+		"when keeping everything":     {start: 0, stop: -1, expected: []string{"a", "b", "c", "d", "e"}},
+		"when the range is empty":     {start: 4, stop: 1, expected: []string{}},
+		"when keeping the last items": {start: -2, stop: 100, expected: []string{"d", "e"}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			l := newTestList(t, "a", "b", "c", "d", "e")
+
+			require.NoError(t, l.Trim("list", tc.start, tc.stop))
+
+			values, err := l.Range("list", 0, -1)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, values)
+		})
+	}
+}
+
+func TestList_Insert(t *testing.T) {
+	l := newTestList(t, "a", "c")
+
+	length, err := l.Insert("list", Left, "c", "b")
+	require.NoError(t, err)
+	assert.Equal(t, 3, length)
+
+	length, err = l.Insert("list", Right, "c", "d")
+	require.NoError(t, err)
+	assert.Equal(t, 4, length)
+
+	length, err = l.Insert("list", Right, "missing", "e")
+	require.NoError(t, err)
+	assert.Equal(t, -1, length)
+
+	length, err = l.Insert("missing", Right, "a", "e")
+	require.NoError(t, err)
+	assert.Equal(t, 0, length)
+
+	values, err := l.Range("list", 0, -1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, values)
+}
+
+func TestList_Pos(t *testing.T) {
+	testCases := map[string]struct {
+		rank     int
+		count    int
+		maxLen   int
+		expected []int
+	}{
+		"when first match requested":     {rank: 1, count: 1, expected: []int{0}},
+		"when second match requested":    {rank: 2, count: 1, expected: []int{2}},
+		"when all matches requested":     {rank: 1, count: 0, expected: []int{0, 2, 4}},
+		"when searching from the tail":   {rank: -1, count: 2, expected: []int{4, 2}},
+		"when comparisons are limited":   {rank: 1, count: 0, maxLen: 3, expected: []int{0, 2}},
+		"when rank is bigger than count": {rank: 4, count: 1, expected: []int{}},
+	}
+
+	l := newTestList(t, "x", "a", "x", "b", "x")
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			indexes, err := l.Pos("list", "x", tc.rank, tc.count, tc.maxLen)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, indexes)
+		})
+	}
+}
+
+func TestList_Move(t *testing.T) {
+	l := newTestList(t, "a", "b", "c")
+
+	value, moved, err := l.Move("list", "other", Left, Right)
+	require.NoError(t, err)
+	assert.True(t, moved)
+	assert.Equal(t, "a", value)
+
+	value, moved, err = l.Move("list", "list", Right, Left)
+	require.NoError(t, err)
+	assert.True(t, moved)
+	assert.Equal(t, "c", value)
+
+	values, err := l.Range("list", 0, -1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c", "b"}, values)
+
+	_, moved, err = l.Move("missing", "list", Left, Left)
+	require.NoError(t, err)
+	assert.False(t, moved)
+
+	l.kv.Set("string", []byte("value"), 0)
+	_, _, err = l.Move("list", "string", Left, Left)
+	assert.ErrorIs(t, err, ErrWrongType)
+
+	_, moved, err = l.Move("other", "other", Left, Left)
+	require.NoError(t, err)
+	assert.True(t, moved)
+	assert.Equal(t, "list", l.kv.Type("other"))
+}
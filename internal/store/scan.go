@@ -0,0 +1,61 @@
+package store
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/internal/glob"
+)
+
+// scanIterationsPerCount bounds the number of steps of a scan to this many
+// times the count asked for, so that a sparse table doesn't make a single
+// call visit most of its buckets.
+const scanIterationsPerCount = 10
+
+// scanUntil calls step with the cursor it returns, until res holds at least
+// count elements or the iteration is over, and returns the cursor to
+// continue from.
+func scanUntil[T any](cursor uint64, count int, res *[]T, step func(cursor uint64) uint64) uint64 {
+	for iterations := count * scanIterationsPerCount; ; iterations-- {
+		cursor = step(cursor)
+
+		if cursor == 0 || iterations <= 1 || len(*res) >= count {
+			return cursor
+		}
+	}
+}
+
+// Scan returns some of the keys from the cursor, 0 to start the iteration,
+// visiting about count of them, and the cursor to continue from, 0 once
+// every key has been visited. Only the keys matching the pattern, if any,
+// and holding a value of the type, if any, are returned.
+//
+// Every key present for the whole iteration is returned at least once, even
+// if the keyspace is resized in between calls; a key may be returned several
+// times.
+func (s *KVStore) Scan(cursor uint64, match string, count int, typ string) (uint64, []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := []string{}
+	cursor = scanUntil(cursor, count, &keys, func(cursor uint64) uint64 {
+		return s.store.Scan(cursor, func(key string, val *Value) {
+			if match != "" && !glob.Match(match, key, false) {
+				return
+			}
+
+			if typ != "" && val.Type() != typ {
+				return
+			}
+
+			keys = append(keys, key)
+		})
+	})
+
+	// the expired keys are only deleted once the scan of the table is done
+	res := keys[:0]
+	for _, key := range keys {
+		if _, exists := s.lookup(key); exists {
+			res = append(res, key)
+		}
+	}
+
+	return cursor, res
+}
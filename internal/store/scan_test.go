@@ -0,0 +1,138 @@
+package store
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/config"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/zset"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scanAll runs a whole scan with the given step, and returns the elements
+// returned by every call.
+func scanAll[T any](t *testing.T, step func(cursor uint64) (uint64, []T, error)) []T {
+	res := []T{}
+
+	cursor := uint64(0)
+	for {
+		var elements []T
+		var err error
+
+		cursor, elements, err = step(cursor)
+		require.NoError(t, err)
+
+		res = append(res, elements...)
+		if cursor == 0 {
+			return res
+		}
+	}
+}
+
+func TestKVStore_Scan(t *testing.T) {
+	s := NewKVStore()
+	for i := 0; i < 100; i++ {
+		s.Set("key:"+strconv.Itoa(i), []byte("1"), 0)
+	}
+
+	s.Set("expired", []byte("1"), -1)
+
+	_, err := NewList(s, config.New()).Push("list", []string{"a"}, Right, false)
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		match    string
+		typ      string
+		expected int
+	}{
+		"when no filter":    {expected: 101},
+		"when match given":  {match: "key:1*", expected: 11},
+		"when type given":   {typ: "list", expected: 1},
+		"when both given":   {match: "key:*", typ: "list", expected: 0},
+		"when type is none": {typ: "hash", expected: 0},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			keys := scanAll(t, func(cursor uint64) (uint64, []string, error) {
+				cursor, keys := s.Scan(cursor, tc.match, 10, tc.typ)
+				return cursor, keys, nil
+			})
+
+			assert.Len(t, keys, tc.expected)
+			assert.NotContains(t, keys, "expired")
+		})
+	}
+}
+
+func TestKVStore_ScanWhileGrowing(t *testing.T) {
+	s := NewKVStore()
+	for i := 0; i < 100; i++ {
+		s.Set(strconv.Itoa(i), []byte("1"), 0)
+	}
+
+	added := 0
+	keys := scanAll(t, func(cursor uint64) (uint64, []string, error) {
+		cursor, keys := s.Scan(cursor, "", 5, "")
+
+		// the keyspace gets resized several times during the scan
+		for i := 0; i < 50 && added < 1000; i++ {
+			s.Set("new:"+strconv.Itoa(added), []byte("1"), 0)
+			added++
+		}
+
+		return cursor, keys, nil
+	})
+
+	for i := 0; i < 100; i++ {
+		assert.Contains(t, keys, strconv.Itoa(i))
+	}
+}
+
+func TestSet_Scan(t *testing.T) {
+	members := []string{}
+	for i := 0; i < 200; i++ {
+		members = append(members, "member:"+strconv.Itoa(i))
+	}
+
+	s := newTestSet(t, map[string][]string{"set": members, "intset": {"1", "2", "3"}})
+
+	t.Run("when set is a table", func(t *testing.T) {
+		res := scanAll(t, func(cursor uint64) (uint64, []string, error) {
+			return s.Scan("set", cursor, "member:1?", 10)
+		})
+
+		assert.ElementsMatch(t, members[10:20], res)
+	})
+
+	t.Run("when set is an intset", func(t *testing.T) {
+		cursor, res, err := s.Scan("intset", 0, "", 1)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0), cursor)
+		assert.Equal(t, []string{"1", "2", "3"}, res)
+	})
+
+	t.Run("when key doesn't exist", func(t *testing.T) {
+		cursor, res, err := s.Scan("missing", 0, "", 10)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0), cursor)
+		assert.Empty(t, res)
+	})
+}
+
+func TestZSet_Scan(t *testing.T) {
+	entries := []zset.Entry{}
+	for i := 0; i < 200; i++ {
+		entries = append(entries, zset.Entry{Member: "member:" + strconv.Itoa(i), Score: float64(i)})
+	}
+
+	z := newTestZSet(t, map[string][]zset.Entry{"zset": entries})
+
+	res := scanAll(t, func(cursor uint64) (uint64, []zset.Entry, error) {
+		return z.Scan("zset", cursor, "", 10)
+	})
+
+	assert.ElementsMatch(t, entries, res)
+}
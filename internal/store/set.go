@@ -0,0 +1,410 @@
+package store
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/config"
+	"github.com/codecrafters-io/redis-starter-go/internal/glob"
+	"github.com/codecrafters-io/redis-starter-go/internal/notify"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/set"
+)
+
+// SetOperation is the way SINTER, SUNION and SDIFF combine sets
+type SetOperation int
+
+const (
+	SetInter SetOperation = iota
+	SetUnion
+	SetDiff
+)
+
+// storeEvents are the keyspace events of SINTERSTORE, SUNIONSTORE and
+// SDIFFSTORE.
+var storeEvents = map[SetOperation]string{
+	SetInter: "sinterstore",
+	SetUnion: "sunionstore",
+	SetDiff:  "sdiffstore",
+}
+
+type Set struct {
+	kv  *KVStore
+	cfg *config.Config
+}
+
+func NewSet(kv *KVStore, cfg *config.Config) *Set {
+	return &Set{
+		kv:  kv,
+		cfg: cfg,
+	}
+}
+
+// Add adds the members and returns how many of them weren't in the set yet.
+func (s *Set) Add(key string, members []string) (int, error) {
+	s.kv.mu.Lock()
+	defer s.kv.mu.Unlock()
+
+	entries, err := s.get(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if entries == nil {
+		entries = set.New()
+		s.kv.setObject(key, entries)
+	}
+
+	added := 0
+	for _, member := range members {
+		if entries.Add([]byte(member), s.cfg.Int(config.SetMaxIntsetEntries)) {
+			added++
+		}
+	}
+
+	if added > 0 {
+		s.kv.notify(notify.Set, "sadd", key)
+	}
+
+	return added, nil
+}
+
+// Rem removes the members and returns how many of them were in the set.
+func (s *Set) Rem(key string, members []string) (int, error) {
+	s.kv.mu.Lock()
+	defer s.kv.mu.Unlock()
+
+	entries, err := s.get(key)
+	if err != nil || entries == nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, member := range members {
+		if entries.Remove([]byte(member)) {
+			removed++
+		}
+	}
+
+	if removed > 0 {
+		s.kv.notify(notify.Set, "srem", key)
+	}
+
+	s.deleteIfEmpty(key, entries)
+
+	return removed, nil
+}
+
+// IsMember reports for every given member whether it's in the set.
+func (s *Set) IsMember(key string, members []string) ([]bool, error) {
+	s.kv.mu.Lock()
+	defer s.kv.mu.Unlock()
+
+	res := make([]bool, len(members))
+
+	entries, err := readObject[*set.Set](s.kv, key)
+	if err != nil || entries == nil {
+		return res, err
+	}
+
+	for i, member := range members {
+		res[i] = entries.Contains([]byte(member))
+	}
+
+	return res, nil
+}
+
+func (s *Set) Members(key string) ([]string, error) {
+	s.kv.mu.Lock()
+	defer s.kv.mu.Unlock()
+
+	entries, err := readObject[*set.Set](s.kv, key)
+	if err != nil || entries == nil {
+		return []string{}, err
+	}
+
+	return allMembers(entries), nil
+}
+
+func (s *Set) Card(key string) (int, error) {
+	s.kv.mu.Lock()
+	defer s.kv.mu.Unlock()
+
+	entries, err := readObject[*set.Set](s.kv, key)
+	if err != nil || entries == nil {
+		return 0, err
+	}
+
+	return entries.Len(), nil
+}
+
+// Scan returns some of the members from the cursor, visiting about count of
+// them, and the cursor to continue from, 0 once every member has been
+// visited. Only the members matching the pattern, if any, are returned.
+// Intsets are returned in a single call.
+func (s *Set) Scan(key string, cursor uint64, match string, count int) (uint64, []string, error) {
+	s.kv.mu.Lock()
+	defer s.kv.mu.Unlock()
+
+	entries, err := readObject[*set.Set](s.kv, key)
+	if err != nil || entries == nil {
+		return 0, []string{}, err
+	}
+
+	res := []string{}
+	cursor = scanUntil(cursor, count, &res, func(cursor uint64) uint64 {
+		return entries.Scan(cursor, func(member []byte) {
+			if match == "" || glob.Match(match, string(member), false) {
+				res = append(res, string(member))
+			}
+		})
+	})
+
+	return cursor, res, nil
+}
+
+// Pop removes and returns up to count random members. nil is returned if the
+// key doesn't exist.
+func (s *Set) Pop(key string, count int) ([]string, error) {
+	s.kv.mu.Lock()
+	defer s.kv.mu.Unlock()
+
+	entries, err := s.get(key)
+	if err != nil || entries == nil {
+		return nil, err
+	}
+
+	res := make([]string, 0, count)
+	for len(res) < count && entries.Len() > 0 {
+		member := entries.Random()
+		entries.Remove(member)
+		res = append(res, string(member))
+	}
+
+	if len(res) > 0 {
+		s.kv.notify(notify.Set, "spop", key)
+	}
+
+	s.deleteIfEmpty(key, entries)
+
+	return res, nil
+}
+
+// RandMembers returns up to count distinct random members. When allowRepeat
+// is set exactly count members are returned, possibly the same ones many
+// times.
+func (s *Set) RandMembers(key string, count int, allowRepeat bool) ([]string, error) {
+	s.kv.mu.Lock()
+	defer s.kv.mu.Unlock()
+
+	entries, err := readObject[*set.Set](s.kv, key)
+	if err != nil || entries == nil {
+		return []string{}, err
+	}
+
+	if allowRepeat {
+		res := make([]string, count)
+		for i := range res {
+			res[i] = string(entries.Random())
+		}
+
+		return res, nil
+	}
+
+	all := allMembers(entries)
+	rand.Shuffle(len(all), func(i, j int) {
+		all[i], all[j] = all[j], all[i]
+	})
+
+	if count < len(all) {
+		all = all[:count]
+	}
+
+	return all, nil
+}
+
+// Combine returns the intersection, union or difference of the sets. Missing
+// keys are treated as empty sets.
+func (s *Set) Combine(op SetOperation, keys []string) ([]string, error) {
+	s.kv.mu.Lock()
+	defer s.kv.mu.Unlock()
+
+	res, err := s.combine(op, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	return allMembers(res), nil
+}
+
+// CombineStore stores the result of Combine at dst, overwriting any value,
+// and returns its size. dst is deleted when the result is empty.
+func (s *Set) CombineStore(op SetOperation, dst string, keys []string) (int, error) {
+	s.kv.mu.Lock()
+	defer s.kv.mu.Unlock()
+
+	res, err := s.combine(op, keys)
+	if err != nil {
+		return 0, err
+	}
+
+	if res.Len() == 0 {
+		if s.kv.store.Delete(dst) {
+			s.kv.notify(notify.Generic, "del", dst)
+		}
+
+		return 0, nil
+	}
+
+	s.kv.setObject(dst, res)
+	s.kv.notify(notify.Set, storeEvents[op], dst)
+
+	return res.Len(), nil
+}
+
+// InterCard returns the size of the intersection of the sets, stopping once
+// limit is reached if it's positive.
+func (s *Set) InterCard(keys []string, limit int) (int, error) {
+	s.kv.mu.Lock()
+	defer s.kv.mu.Unlock()
+
+	sets, err := s.getAll(keys)
+	if err != nil || sets == nil {
+		return 0, err
+	}
+
+	count := 0
+	sets[0].ForEach(func(member []byte) bool {
+		if inAll(member, sets[1:]) {
+			count++
+		}
+
+		return limit <= 0 || count < limit
+	})
+
+	return count, nil
+}
+
+// combine computes the result of the set operation. The caller should hold
+// the lock.
+func (s *Set) combine(op SetOperation, keys []string) (*set.Set, error) {
+	res := set.New()
+	maxIntsetEntries := s.cfg.Int(config.SetMaxIntsetEntries)
+
+	if op == SetInter {
+		sets, err := s.getAll(keys)
+		if err != nil || sets == nil {
+			return res, err
+		}
+
+		sets[0].ForEach(func(member []byte) bool {
+			if inAll(member, sets[1:]) {
+				res.Add(member, maxIntsetEntries)
+			}
+
+			return true
+		})
+
+		return res, nil
+	}
+
+	sets := make([]*set.Set, 0, len(keys))
+	for _, key := range keys {
+		entries, err := s.get(key)
+		if err != nil {
+			return nil, err
+		}
+
+		sets = append(sets, entries)
+	}
+
+	if op == SetUnion {
+		for _, entries := range sets {
+			if entries == nil {
+				continue
+			}
+
+			entries.ForEach(func(member []byte) bool {
+				res.Add(member, maxIntsetEntries)
+				return true
+			})
+		}
+
+		return res, nil
+	}
+
+	if sets[0] == nil {
+		return res, nil
+	}
+
+	sets[0].ForEach(func(member []byte) bool {
+		for _, other := range sets[1:] {
+			if other != nil && other.Contains(member) {
+				return true
+			}
+		}
+
+		res.Add(member, maxIntsetEntries)
+
+		return true
+	})
+
+	return res, nil
+}
+
+// getAll returns the sets stored at the keys, smallest first so that
+// intersecting them visits the fewest members. nil is returned if any of the
+// keys doesn't exist, as the intersection is empty then.
+func (s *Set) getAll(keys []string) ([]*set.Set, error) {
+	sets := make([]*set.Set, 0, len(keys))
+
+	for _, key := range keys {
+		entries, err := s.get(key)
+		if err != nil || entries == nil {
+			return nil, err
+		}
+
+		sets = append(sets, entries)
+	}
+
+	sort.SliceStable(sets, func(i, j int) bool {
+		return sets[i].Len() < sets[j].Len()
+	})
+
+	return sets, nil
+}
+
+func inAll(member []byte, sets []*set.Set) bool {
+	for _, other := range sets {
+		if !other.Contains(member) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func allMembers(entries *set.Set) []string {
+	res := make([]string, 0, entries.Len())
+
+	entries.ForEach(func(member []byte) bool {
+		res = append(res, string(member))
+		return true
+	})
+
+	return res
+}
+
+// get returns the set stored at key, or nil if the key doesn't exist. The
+// caller should hold the lock.
+func (s *Set) get(key string) (*set.Set, error) {
+	return getObject[*set.Set](s.kv, key)
+}
+
+// deleteIfEmpty removes the key once its set has no members left. The caller
+// should hold the lock.
+func (s *Set) deleteIfEmpty(key string, entries *set.Set) {
+	if entries.Len() == 0 {
+		s.kv.store.Delete(key)
+		s.kv.notify(notify.Generic, "del", key)
+	}
+}
@@ -0,0 +1,144 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSet(t *testing.T, sets map[string][]string) *Set {
+	s := NewSet(NewKVStore(), config.New())
+
+	for key, members := range sets {
+		_, err := s.Add(key, members)
+		require.NoError(t, err)
+	}
+
+	return s
+}
+
+func TestSet_Add(t *testing.T) {
+	s := newTestSet(t, map[string][]string{"set": {"a", "b"}})
+
+	added, err := s.Add("set", []string{"b", "c", "c"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, added)
+
+	isMember, err := s.IsMember("set", []string{"a", "d"})
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, false}, isMember)
+
+	removed, err := s.Rem("set", []string{"a", "b", "c", "d"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, removed)
+	assert.Equal(t, "none", s.kv.Type("set"))
+
+	s.kv.Set("string", []byte("value"), 0)
+	_, err = s.Add("string", []string{"a"})
+	assert.ErrorIs(t, err, ErrWrongType)
+}
+
+func TestSet_Pop(t *testing.T) {
+	s := newTestSet(t, map[string][]string{"set": {"1", "2", "3"}})
+
+	members, err := s.Pop("set", 2)
+	require.NoError(t, err)
+	assert.Len(t, members, 2)
+
+	remaining, err := s.Members("set")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"1", "2", "3"}, append(members, remaining...))
+
+	members, err = s.Pop("set", 5)
+	require.NoError(t, err)
+	assert.Equal(t, remaining, members)
+	assert.Equal(t, "none", s.kv.Type("set"))
+
+	members, err = s.Pop("set", 1)
+	require.NoError(t, err)
+	assert.Nil(t, members)
+}
+
+func TestSet_RandMembers(t *testing.T) {
+	s := newTestSet(t, map[string][]string{"set": {"a", "b", "c"}})
+
+	members, err := s.RandMembers("set", 2, false)
+	require.NoError(t, err)
+	assert.Len(t, members, 2)
+	assert.NotEqual(t, members[0], members[1])
+
+	members, err = s.RandMembers("set", 5, false)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a", "b", "c"}, members)
+
+	members, err = s.RandMembers("set", 5, true)
+	require.NoError(t, err)
+	assert.Len(t, members, 5)
+}
+
+func TestSet_Combine(t *testing.T) {
+	sets := map[string][]string{
+		"set-1": {"a", "b", "c", "d"},
+		"set-2": {"c"},
+		"set-3": {"a", "c", "e"},
+	}
+
+	testCases := map[string]struct {
+		op       SetOperation
+		keys     []string
+		expected []string
+	}{
+		"when intersecting":               {op: SetInter, keys: []string{"set-1", "set-2", "set-3"}, expected: []string{"c"}},
+		"when intersecting a missing key": {op: SetInter, keys: []string{"set-1", "missing"}, expected: []string{}},
+		"when uniting":                    {op: SetUnion, keys: []string{"set-1", "set-3", "missing"}, expected: []string{"a", "b", "c", "d", "e"}},
+		"when diffing":                    {op: SetDiff, keys: []string{"set-1", "set-2", "set-3", "missing"}, expected: []string{"b", "d"}},
+		"when diffing a missing key":      {op: SetDiff, keys: []string{"missing", "set-1"}, expected: []string{}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			s := newTestSet(t, sets)
+
+			members, err := s.Combine(tc.op, tc.keys)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.expected, members)
+
+			size, err := s.CombineStore(tc.op, "set-1", tc.keys)
+			require.NoError(t, err)
+			assert.Equal(t, len(tc.expected), size)
+
+			stored, err := s.Members("set-1")
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.expected, stored)
+		})
+	}
+
+	t.Run("when a key holds another type", func(t *testing.T) {
+		s := newTestSet(t, sets)
+		s.kv.Set("string", []byte("value"), 0)
+
+		_, err := s.Combine(SetUnion, []string{"set-1", "string"})
+		assert.ErrorIs(t, err, ErrWrongType)
+	})
+}
+
+func TestSet_InterCard(t *testing.T) {
+	s := newTestSet(t, map[string][]string{
+		"set-1": {"1", "2", "3", "4"},
+		"set-2": {"2", "3", "4", "5"},
+	})
+
+	count, err := s.InterCard([]string{"set-1", "set-2"}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	count, err = s.InterCard([]string{"set-1", "set-2"}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	count, err = s.InterCard([]string{"set-1", "missing"}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
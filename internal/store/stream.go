@@ -2,132 +2,214 @@ package store
 
 import (
 	"fmt"
-	"math"
-	"sort"
-	"strconv"
-	"sync"
 	"time"
 
-	"github.com/codecrafters-io/redis-starter-go/internal/payload"
-	"github.com/codecrafters-io/redis-starter-go/internal/streamfn"
+	"github.com/codecrafters-io/redis-starter-go/internal/config"
+	"github.com/codecrafters-io/redis-starter-go/internal/notify"
 	"github.com/codecrafters-io/redis-starter-go/internal/structures/stream"
 )
 
 type Stream struct {
-	store map[string]*stream.NumericTrie
-	mu    *sync.Mutex
+	kv  *KVStore
+	cfg *config.Config
 }
 
-func NewStream() *Stream {
+func NewStream(kv *KVStore, cfg *config.Config) *Stream {
 	return &Stream{
-		store: map[string]*stream.NumericTrie{},
-		mu:    &sync.Mutex{},
+		kv:  kv,
+		cfg: cfg,
 	}
 }
 
 func (s *Stream) Exists(key string) bool {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	_, exists := s.store[key]
-
-	return exists
+	return s.kv.Type(key) == "stream"
 }
 
 func (s *Stream) XAdd(key string, givenId string, values []string) (string, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
-
-	_, exists := s.store[key]
-	if !exists {
-		s.store[key] = stream.NewNumericTrie(time.Now)
-	}
+	s.kv.mu.Lock()
+	defer s.kv.mu.Unlock()
 
-	insertedId, err := s.store[key].Insert(givenId, values)
+	entries, err := s.get(key)
 	if err != nil {
 		return "", err
 	}
 
-	return insertedId, nil
-}
+	created := entries == nil
+	if created {
+		entries = stream.New(time.Now)
+	}
 
-func (s *Stream) XRange(key, begin, end string) (string, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+	limits := stream.NodeLimits{
+		MaxBytes:   s.cfg.Int(config.StreamNodeMaxBytes),
+		MaxEntries: s.cfg.Int(config.StreamNodeMaxEntries),
+	}
 
-	values, err := s.xRange(key, begin, end)
+	insertedId, err := entries.Insert(givenId, values, limits)
 	if err != nil {
-		return "", fmt.Errorf("Failed to search by range: %w", err)
+		return "", err
 	}
 
-	result, err := payload.GenerateNestedListToString(values)
-	if err != nil {
-		return "", fmt.Errorf("Failed to convert to Nested Redis List: %w", err)
+	if created {
+		s.kv.setObject(key, entries)
 	}
 
-	return result, nil
+	s.kv.notify(notify.Stream, "xadd", key)
+
+	return insertedId, nil
 }
 
-func (s *Stream) xRange(key, begin, end string) ([]interface{}, error) {
-	trie, exists := s.store[key]
-	if !exists {
-		return nil, fmt.Errorf("Key doesn't exist")
-	}
+// XRange returns the entries with IDs between begin and end, both
+// inclusive.
+func (s *Stream) XRange(key, begin, end string) ([]*stream.Data, error) {
+	s.kv.mu.Lock()
+	defer s.kv.mu.Unlock()
 
-	if begin == "-" {
-		begin = "0-1"
+	entries, err := s.get(key)
+	if err != nil {
+		return nil, err
 	}
 
-	if end == "+" {
-		end = strconv.Itoa(math.MaxInt64)
+	if entries == nil {
+		return []*stream.Data{}, nil
 	}
 
-	foundValues, err := trie.Range(begin, end)
+	beginID, err := stream.ParseRangeID(begin, false)
 	if err != nil {
-		return nil, fmt.Errorf("Failed to get range: %w", err)
+		return nil, err
 	}
 
-	sort.Slice(foundValues, func(i, j int) bool {
-		return foundValues[i].ID < foundValues[j].ID
-	})
-
-	values := make([]interface{}, 0)
+	endID, err := stream.ParseRangeID(end, true)
+	if err != nil {
+		return nil, err
+	}
 
-	for _, foundValue := range foundValues {
-		values = append(values, foundValue.ToInterface())
+	foundValues, err := entries.Range(beginID, endID)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to get range: %w", err)
 	}
 
-	return values, nil
+	return foundValues, nil
+}
+
+// StreamEntries are the entries read from the stream at Key.
+type StreamEntries struct {
+	Key     string
+	Entries []*stream.Data
 }
 
-func (s *Stream) XRead(keys []string, ids []string) (string, error) {
-	s.mu.Lock()
-	defer s.mu.Unlock()
+// XRead returns, for every stream, the entries with IDs greater than the
+// given one, at most count of them when count is positive. Streams without
+// such entries are left out.
+func (s *Stream) XRead(keys []string, ids []string, count int) ([]StreamEntries, error) {
+	s.kv.mu.Lock()
+	defer s.kv.mu.Unlock()
 
-	res := make([]interface{}, 0)
+	res := make([]StreamEntries, 0)
 
 	for i, key := range keys {
-		if len(ids) < i+1 {
-			continue
+		id, err := stream.ParseID(ids[i], 0)
+		if err != nil {
+			return nil, err
 		}
 
-		id, err := streamfn.IncrementID(ids[i])
+		entries, err := s.get(key)
 		if err != nil {
-			return "", fmt.Errorf("Failed to increment ID: %w", err)
+			return nil, err
 		}
 
-		foundValues, err := s.xRange(key, id, "+")
+		begin, ok := id.Next()
+		if entries == nil || !ok {
+			continue
+		}
+
+		foundValues, err := entries.Range(begin, stream.MaxID)
 		if err != nil {
-			return "", fmt.Errorf("Failed to find values by range: %w", err)
+			return nil, fmt.Errorf("Failed to get range: %w", err)
 		}
 
-		res = append(res, []interface{}{key, foundValues})
+		if count > 0 && len(foundValues) > count {
+			foundValues = foundValues[:count]
+		}
+
+		if len(foundValues) == 0 {
+			continue
+		}
+
+		res = append(res, StreamEntries{Key: key, Entries: foundValues})
+	}
+
+	return res, nil
+}
+
+// Len returns the number of entries of the stream, 0 if the key doesn't
+// exist.
+func (s *Stream) Len(key string) (int, error) {
+	s.kv.mu.Lock()
+	defer s.kv.mu.Unlock()
+
+	entries, err := s.get(key)
+	if err != nil || entries == nil {
+		return 0, err
 	}
 
-	result, err := payload.GenerateNestedListToString(res)
+	return entries.Len(), nil
+}
+
+// StreamInfo describes a stream, as XINFO STREAM.
+type StreamInfo struct {
+	Length int
+	// Blocks is the number of listpacks storing the entries.
+	Blocks int
+	LastID string
+	// FirstEntry and LastEntry are nil when the stream is empty.
+	FirstEntry *stream.Data
+	LastEntry  *stream.Data
+}
+
+// Info describes the stream stored at key, ErrNoSuchKey if the key doesn't
+// exist.
+func (s *Stream) Info(key string) (*StreamInfo, error) {
+	s.kv.mu.Lock()
+	defer s.kv.mu.Unlock()
+
+	entries, err := s.get(key)
 	if err != nil {
-		return "", fmt.Errorf("Failed to convert to Nested Redis List: %w", err)
+		return nil, err
 	}
 
-	return result, nil
+	if entries == nil {
+		return nil, ErrNoSuchKey
+	}
+
+	return &StreamInfo{
+		Length:     entries.Len(),
+		Blocks:     entries.Blocks(),
+		LastID:     entries.LastID().String(),
+		FirstEntry: entries.First(),
+		LastEntry:  entries.Last(),
+	}, nil
+}
+
+// LastID returns the ID of the last entry added to the stream, 0-0 if the
+// key doesn't exist.
+func (s *Stream) LastID(key string) (string, error) {
+	s.kv.mu.Lock()
+	defer s.kv.mu.Unlock()
+
+	entries, err := s.get(key)
+	if err != nil {
+		return "", err
+	}
+
+	if entries == nil {
+		return stream.MinID.String(), nil
+	}
+
+	return entries.LastID().String(), nil
+}
+
+// get returns the stream stored at key, or nil if the key doesn't exist. The
+// caller should hold the lock.
+func (s *Stream) get(key string) (*stream.Stream, error) {
+	return getObject[*stream.Stream](s.kv, key)
 }
@@ -0,0 +1,417 @@
+package store
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/floatfn"
+	"github.com/codecrafters-io/redis-starter-go/internal/notify"
+)
+
+// maxStringSize is the maximum length of a string value, 512MB.
+const maxStringSize = 512 * 1024 * 1024
+
+var (
+	ErrNotInteger     = errors.New("ERR value is not an integer or out of range")
+	ErrNotFloat       = errors.New("ERR value is not a valid float")
+	ErrStringTooLarge = errors.New("ERR string exceeds maximum allowed size (proto-max-bulk-len)")
+)
+
+// SetOptions are the conditions and expiration of SET.
+type SetOptions struct {
+	NX       bool  // only set if the key doesn't exist
+	XX       bool  // only set if the key exists
+	Get      bool  // the previous value is needed, so it must be a string
+	KeepTTL  bool  // keep the expiration time of the previous value
+	ExpireAt int64 // unix milliseconds, 0 for no expiration
+}
+
+// GetString returns the string value stored at key, and false if the key
+// doesn't exist.
+func (s *KVStore) GetString(key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, err := s.readString(key)
+	if err != nil || val == nil {
+		return nil, false, err
+	}
+
+	return val.str, true, nil
+}
+
+// SetWithOptions stores the value according to the options, overwriting a
+// value of any type. It returns the previous string value, nil if there was
+// none, and whether the value was set.
+func (s *KVStore) SetWithOptions(key string, value []byte, opts SetOptions) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	old, exists := s.lookup(key)
+
+	var previous []byte
+	if exists && opts.Get {
+		if old.obj != nil {
+			return nil, false, ErrWrongType
+		}
+
+		previous = old.str
+	}
+
+	if (opts.NX && exists) || (opts.XX && !exists) {
+		return previous, false, nil
+	}
+
+	val := &Value{str: value, exp: opts.ExpireAt, perm: opts.ExpireAt == 0}
+	if opts.KeepTTL && exists {
+		val.exp, val.perm = old.exp, old.perm
+	}
+
+	s.store.Set(key, val)
+
+	s.notify(notify.String, "set", key)
+	if opts.ExpireAt != 0 {
+		s.notify(notify.Generic, "expire", key)
+	}
+
+	return previous, true, nil
+}
+
+// MGet returns the values stored at the keys, nil for the keys which don't
+// exist or don't hold a string.
+func (s *KVStore) MGet(keys []string) [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make([][]byte, len(keys))
+	for i, key := range keys {
+		if val, err := s.readString(key); err == nil && val != nil {
+			res[i] = val.str
+		}
+	}
+
+	return res
+}
+
+// MSet stores the values without expiration. When nx is set nothing is
+// stored if any of the keys exists, and false is returned.
+func (s *KVStore) MSet(keys []string, values [][]byte, nx bool) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if nx {
+		for _, key := range keys {
+			if _, exists := s.lookup(key); exists {
+				return false
+			}
+		}
+	}
+
+	for i, key := range keys {
+		s.store.Set(key, &Value{str: values[i], perm: true})
+		s.notify(notify.String, "set", key)
+	}
+
+	return true
+}
+
+// GetDel returns the string value stored at key and deletes the key.
+func (s *KVStore) GetDel(key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, err := s.readString(key)
+	if err != nil || val == nil {
+		return nil, false, err
+	}
+
+	s.store.Delete(key)
+	s.notify(notify.Generic, "del", key)
+
+	return val.str, true, nil
+}
+
+// GetEx returns the string value stored at key, and changes its expiration
+// time to expireAt if it's not 0 or removes it when persist is set.
+func (s *KVStore) GetEx(key string, expireAt int64, persist bool) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, err := s.readString(key)
+	if err != nil || val == nil {
+		return nil, false, err
+	}
+
+	switch {
+	case persist:
+		if !val.perm {
+			s.notify(notify.Generic, "persist", key)
+		}
+
+		val.exp, val.perm = 0, true
+	case expireAt != 0 && expireAt <= time.Now().UnixMilli():
+		// an expiration time in the past deletes the key right away
+		s.store.Delete(key)
+		s.notify(notify.Generic, "del", key)
+	case expireAt != 0:
+		val.exp, val.perm = expireAt, false
+		s.notify(notify.Generic, "expire", key)
+	}
+
+	return val.str, true, nil
+}
+
+// Append appends the value to the string stored at key, creating it if
+// needed, and returns the new length.
+func (s *KVStore) Append(key string, value []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, err := s.getString(key)
+	if err != nil {
+		return 0, err
+	}
+
+	if val == nil {
+		s.setString(key, nil, append([]byte{}, value...))
+		s.notify(notify.String, "append", key)
+
+		return len(value), nil
+	}
+
+	if len(val.str)+len(value) > maxStringSize {
+		return 0, ErrStringTooLarge
+	}
+
+	s.setString(key, val, append(val.str, value...))
+	s.notify(notify.String, "append", key)
+
+	return len(val.str), nil
+}
+
+// StrLen returns the length of the string stored at key, 0 if it doesn't
+// exist.
+func (s *KVStore) StrLen(key string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, err := s.readString(key)
+	if err != nil || val == nil {
+		return 0, err
+	}
+
+	return len(val.str), nil
+}
+
+// GetRange returns the substring between start and end, both inclusive.
+// Negative offsets count from the end of the string.
+func (s *KVStore) GetRange(key string, start, end int) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, err := s.readString(key)
+	if err != nil || val == nil {
+		return []byte{}, err
+	}
+
+	length := len(val.str)
+	if start < 0 && end < 0 && start > end {
+		return []byte{}, nil
+	}
+
+	if start < 0 {
+		start += length
+	}
+
+	if end < 0 {
+		end += length
+	}
+
+	if start < 0 {
+		start = 0
+	}
+
+	if end < 0 {
+		end = 0
+	}
+
+	if end >= length {
+		end = length - 1
+	}
+
+	if start > end || length == 0 {
+		return []byte{}, nil
+	}
+
+	return val.str[start : end+1], nil
+}
+
+// SetRange overwrites the string stored at key from offset with the value,
+// padding it with zero bytes if it's shorter than offset, and returns the
+// new length. The key is created if needed, unless the value is empty.
+func (s *KVStore) SetRange(key string, offset int, value []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, err := s.getString(key)
+	if err != nil {
+		return 0, err
+	}
+
+	var str []byte
+	if val != nil {
+		str = val.str
+	}
+
+	if len(value) == 0 {
+		return len(str), nil
+	}
+
+	if offset+len(value) > maxStringSize {
+		return 0, ErrStringTooLarge
+	}
+
+	if end := offset + len(value); end > len(str) {
+		str = append(str, make([]byte, end-len(str))...)
+	}
+
+	copy(str[offset:], value)
+	s.setString(key, val, str)
+	s.notify(notify.String, "setrange", key)
+
+	return len(str), nil
+}
+
+// IncrBy increments the integer value of the key, which is set to 0 before
+// the operation if it doesn't exist, and returns the new value. The
+// expiration time of the key is kept.
+func (s *KVStore) IncrBy(key string, increment int64) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, err := s.getString(key)
+	if err != nil {
+		return 0, err
+	}
+
+	var current int64
+	if val != nil {
+		current, err = strconv.ParseInt(string(val.str), 10, 64)
+		if err != nil {
+			return 0, ErrNotInteger
+		}
+	}
+
+	if (increment > 0 && current > math.MaxInt64-increment) || (increment < 0 && current < math.MinInt64-increment) {
+		return 0, ErrIncrOverflow
+	}
+
+	current += increment
+	s.setString(key, val, strconv.AppendInt(nil, current, 10))
+	s.notify(notify.String, "incrby", key)
+
+	return current, nil
+}
+
+// IncrByFloat increments the float value of the key, which is set to 0
+// before the operation if it doesn't exist, and returns the new value as
+// stored. The expiration time of the key is kept.
+func (s *KVStore) IncrByFloat(key string, increment float64) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	val, err := s.getString(key)
+	if err != nil {
+		return "", err
+	}
+
+	var current float64
+	if val != nil {
+		current, err = strconv.ParseFloat(string(val.str), 64)
+		if err != nil || math.IsNaN(current) || math.IsInf(current, 0) {
+			return "", ErrNotFloat
+		}
+	}
+
+	current += increment
+	if math.IsNaN(current) || math.IsInf(current, 0) {
+		return "", ErrIncrNaNOrInfinity
+	}
+
+	formatted := floatfn.FormatHuman(current)
+	s.setString(key, val, []byte(formatted))
+	s.notify(notify.String, "incrbyfloat", key)
+
+	return formatted, nil
+}
+
+// getString returns the string value stored at key, or nil if the key
+// doesn't exist. The caller should hold the lock.
+func (s *KVStore) getString(key string) (*Value, error) {
+	val, exists := s.lookup(key)
+	if !exists {
+		return nil, nil
+	}
+
+	if val.obj != nil {
+		return nil, ErrWrongType
+	}
+
+	return val, nil
+}
+
+// readString is getString for the commands only reading the key, a missing
+// key being notified as a key miss. The caller should hold the lock.
+func (s *KVStore) readString(key string) (*Value, error) {
+	val, err := s.getString(key)
+	if err == nil && val == nil {
+		s.keyMiss(key)
+	}
+
+	return val, err
+}
+
+// getObject returns the object of type T stored at key, or the zero value
+// of T if the key doesn't exist. The caller should hold the lock.
+func getObject[T comparable](s *KVStore, key string) (T, error) {
+	var obj T
+
+	val, exists := s.lookup(key)
+	if !exists {
+		return obj, nil
+	}
+
+	obj, ok := val.obj.(T)
+	if !ok {
+		return obj, ErrWrongType
+	}
+
+	return obj, nil
+}
+
+// readObject is getObject for the commands only reading the key, a missing
+// key being notified as a key miss. The caller should hold the lock.
+func readObject[T comparable](s *KVStore, key string) (T, error) {
+	var missing T
+
+	obj, err := getObject[T](s, key)
+	if err == nil && obj == missing {
+		s.keyMiss(key)
+	}
+
+	return obj, err
+}
+
+// setString replaces the string of val, keeping its expiration time, or
+// stores a new permanent value if val is nil. The caller should hold the
+// lock.
+func (s *KVStore) setString(key string, val *Value, str []byte) {
+	if val == nil {
+		s.store.Set(key, &Value{str: str, perm: true})
+		return
+	}
+
+	val.str = str
+}
@@ -0,0 +1,184 @@
+package store
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKVStore_IncrBy(t *testing.T) {
+	testCases := map[string]struct {
+		value         string
+		exists        bool
+		increment     int64
+		expected      int64
+		expectedError error
+	}{
+		"when key doesn't exist":      {increment: 5, expected: 5},
+		"when value is an integer":    {value: "10", exists: true, increment: -3, expected: 7},
+		"when value isn't an integer": {value: "1.5", exists: true, increment: 1, expectedError: ErrNotInteger},
+		"when value has spaces":       {value: " 1", exists: true, increment: 1, expectedError: ErrNotInteger},
+		"when increment overflows":    {value: strconv.FormatInt(math.MaxInt64, 10), exists: true, increment: 1, expectedError: ErrIncrOverflow},
+		"when decrement overflows":    {value: "-2", exists: true, increment: math.MinInt64, expectedError: ErrIncrOverflow},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			s := NewKVStore()
+			if tc.exists {
+				s.Set("key", []byte(tc.value), 0)
+			}
+
+			value, err := s.IncrBy("key", tc.increment)
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, value)
+
+			stored, _ := s.Get("key")
+			assert.Equal(t, strconv.FormatInt(tc.expected, 10), string(stored))
+		})
+	}
+}
+
+func TestKVStore_IncrByFloat(t *testing.T) {
+	s := NewKVStore()
+	s.Set("key", []byte("10.5"), 10000)
+
+	value, err := s.IncrByFloat("key", 0.1)
+	require.NoError(t, err)
+	assert.Equal(t, "10.6", value)
+
+	// the expiration time is kept
+	assert.False(t, contents(s)["key"].IsPermanent())
+
+	value, err = s.IncrByFloat("key", 5.0e3)
+	require.NoError(t, err)
+	assert.Equal(t, "5010.6", value)
+
+	_, err = s.IncrByFloat("key", math.MaxFloat64)
+	require.NoError(t, err)
+
+	_, err = s.IncrByFloat("key", math.MaxFloat64)
+	assert.ErrorIs(t, err, ErrIncrNaNOrInfinity)
+
+	s.Set("key", []byte("abc"), 0)
+	_, err = s.IncrByFloat("key", 1)
+	assert.ErrorIs(t, err, ErrNotFloat)
+
+	s.setObject("list", "not a string")
+	_, err = s.IncrByFloat("list", 1)
+	assert.ErrorIs(t, err, ErrWrongType)
+}
+
+func TestKVStore_SetWithOptions(t *testing.T) {
+	s := NewKVStore()
+
+	previous, set, err := s.SetWithOptions("key", []byte("a"), SetOptions{XX: true})
+	require.NoError(t, err)
+	assert.False(t, set)
+	assert.Nil(t, previous)
+
+	previous, set, err = s.SetWithOptions("key", []byte("a"), SetOptions{NX: true, Get: true, ExpireAt: math.MaxInt64})
+	require.NoError(t, err)
+	assert.True(t, set)
+	assert.Nil(t, previous)
+
+	previous, set, err = s.SetWithOptions("key", []byte("b"), SetOptions{Get: true, KeepTTL: true})
+	require.NoError(t, err)
+	assert.True(t, set)
+	assert.Equal(t, "a", string(previous))
+	assert.False(t, contents(s)["key"].IsPermanent())
+
+	s.setObject("list", "not a string")
+	_, _, err = s.SetWithOptions("list", []byte("a"), SetOptions{Get: true})
+	assert.ErrorIs(t, err, ErrWrongType)
+
+	// without GET any value is overwritten
+	_, set, err = s.SetWithOptions("list", []byte("a"), SetOptions{})
+	require.NoError(t, err)
+	assert.True(t, set)
+}
+
+func TestKVStore_GetRange(t *testing.T) {
+	testCases := map[string]struct {
+		start, end int
+		expected   string
+	}{
+		"when range is inside the string": {start: 0, end: 3, expected: "This"},
+		"when offsets are negative":       {start: -3, end: -1, expected: "ing"},
+		"when end is past the string":     {start: 10, end: 100, expected: "string"},
+		"when start is after end":         {start: 5, end: 2, expected: ""},
+		"when both are negative and out":  {start: -1, end: -5, expected: ""},
+		"when start is before the string": {start: -100, end: 3, expected: "This"},
+	}
+
+	s := NewKVStore()
+	s.Set("key", []byte("This is a string"), 0)
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			value, err := s.GetRange("key", tc.start, tc.end)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(value))
+		})
+	}
+}
+
+func TestKVStore_SetRange(t *testing.T) {
+	s := NewKVStore()
+
+	length, err := s.SetRange("key", 5, []byte(""))
+	require.NoError(t, err)
+	assert.Equal(t, 0, length)
+	assert.Equal(t, "none", s.Type("key"))
+
+	length, err = s.SetRange("key", 2, []byte("ab"))
+	require.NoError(t, err)
+	assert.Equal(t, 4, length)
+
+	length, err = s.SetRange("key", 0, []byte("x"))
+	require.NoError(t, err)
+	assert.Equal(t, 4, length)
+
+	stored, _ := s.Get("key")
+	assert.Equal(t, "x\x00ab", string(stored))
+}
+
+func TestKVStore_Append(t *testing.T) {
+	s := NewKVStore()
+
+	length, err := s.Append("key", []byte("Hello"))
+	require.NoError(t, err)
+	assert.Equal(t, 5, length)
+
+	length, err = s.Append("key", []byte(" World"))
+	require.NoError(t, err)
+	assert.Equal(t, 11, length)
+
+	stored, _ := s.Get("key")
+	assert.Equal(t, "Hello World", string(stored))
+}
+
+func TestKVStore_GetEx(t *testing.T) {
+	s := NewKVStore()
+	s.Set("key", []byte("value"), 10000)
+
+	value, found, err := s.GetEx("key", 0, true)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "value", string(value))
+	assert.True(t, contents(s)["key"].IsPermanent())
+
+	// an expiration time in the past deletes the key
+	_, found, err = s.GetEx("key", 1, false)
+	require.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "none", s.Type("key"))
+}
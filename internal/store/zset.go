@@ -0,0 +1,588 @@
+package store
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/internal/config"
+	"github.com/codecrafters-io/redis-starter-go/internal/glob"
+	"github.com/codecrafters-io/redis-starter-go/internal/notify"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/set"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/zset"
+)
+
+// zstoreEvents are the keyspace events of ZUNIONSTORE, ZINTERSTORE and
+// ZDIFFSTORE.
+var zstoreEvents = map[SetOperation]string{
+	SetUnion: "zunionstore",
+	SetInter: "zinterstore",
+	SetDiff:  "zdiffstore",
+}
+
+type ZSet struct {
+	kv  *KVStore
+	cfg *config.Config
+}
+
+func NewZSet(kv *KVStore, cfg *config.Config) *ZSet {
+	return &ZSet{
+		kv:  kv,
+		cfg: cfg,
+	}
+}
+
+// Add adds the members or updates their scores according to the flags, and
+// returns how many members were added and how many had their score updated.
+func (z *ZSet) Add(key string, entries []zset.Entry, flags zset.AddFlags) (int, int, error) {
+	z.kv.mu.Lock()
+	defer z.kv.mu.Unlock()
+
+	zs, err := z.getOrCreate(key, flags)
+	if err != nil || zs == nil {
+		return 0, 0, err
+	}
+
+	added, updated := 0, 0
+	for _, entry := range entries {
+		result, _, err := zs.Add(entry.Member, entry.Score, flags, z.limits())
+		if err != nil {
+			z.discardIfEmpty(key, zs)
+			return 0, 0, err
+		}
+
+		switch result {
+		case zset.AddAdded:
+			added++
+		case zset.AddUpdated:
+			updated++
+		}
+	}
+
+	if added+updated > 0 {
+		z.kv.notify(notify.ZSet, "zadd", key)
+	}
+
+	z.discardIfEmpty(key, zs)
+
+	return added, updated, nil
+}
+
+// IncrBy increments the score of the member, adding it if needed, and
+// returns the new score. false is returned when the flags prevented it.
+func (z *ZSet) IncrBy(key, member string, increment float64, flags zset.AddFlags) (float64, bool, error) {
+	z.kv.mu.Lock()
+	defer z.kv.mu.Unlock()
+
+	zs, err := z.getOrCreate(key, flags)
+	if err != nil || zs == nil {
+		return 0, false, err
+	}
+
+	flags.Incr = true
+	result, score, err := zs.Add(member, increment, flags, z.limits())
+
+	z.discardIfEmpty(key, zs)
+
+	if err != nil {
+		return 0, false, err
+	}
+
+	if result != zset.AddNop {
+		z.kv.notify(notify.ZSet, "zincr", key)
+	}
+
+	return score, result != zset.AddNop, nil
+}
+
+// Score returns the score of the member, and false if it doesn't exist.
+func (z *ZSet) Score(key, member string) (float64, bool, error) {
+	z.kv.mu.Lock()
+	defer z.kv.mu.Unlock()
+
+	zs, err := readObject[*zset.ZSet](z.kv, key)
+	if err != nil || zs == nil {
+		return 0, false, err
+	}
+
+	score, exists := zs.Score(member)
+
+	return score, exists, nil
+}
+
+// MScore returns the scores of the members, together with whether each of
+// them exists.
+func (z *ZSet) MScore(key string, members []string) ([]float64, []bool, error) {
+	z.kv.mu.Lock()
+	defer z.kv.mu.Unlock()
+
+	scores := make([]float64, len(members))
+	found := make([]bool, len(members))
+
+	zs, err := readObject[*zset.ZSet](z.kv, key)
+	if err != nil || zs == nil {
+		return scores, found, err
+	}
+
+	for i, member := range members {
+		scores[i], found[i] = zs.Score(member)
+	}
+
+	return scores, found, nil
+}
+
+func (z *ZSet) Card(key string) (int, error) {
+	z.kv.mu.Lock()
+	defer z.kv.mu.Unlock()
+
+	zs, err := readObject[*zset.ZSet](z.kv, key)
+	if err != nil || zs == nil {
+		return 0, err
+	}
+
+	return zs.Len(), nil
+}
+
+// Scan returns some of the members from the cursor, visiting about count of
+// them, and the cursor to continue from, 0 once every member has been
+// visited. Only the members matching the pattern, if any, are returned.
+// Compact sorted sets are returned in a single call.
+func (z *ZSet) Scan(key string, cursor uint64, match string, count int) (uint64, []zset.Entry, error) {
+	z.kv.mu.Lock()
+	defer z.kv.mu.Unlock()
+
+	zs, err := readObject[*zset.ZSet](z.kv, key)
+	if err != nil || zs == nil {
+		return 0, []zset.Entry{}, err
+	}
+
+	res := []zset.Entry{}
+	cursor = scanUntil(cursor, count, &res, func(cursor uint64) uint64 {
+		return zs.Scan(cursor, func(entry zset.Entry) {
+			if match == "" || glob.Match(match, entry.Member, false) {
+				res = append(res, entry)
+			}
+		})
+	})
+
+	return cursor, res, nil
+}
+
+// Rem removes the members and returns how many of them were in the set.
+func (z *ZSet) Rem(key string, members []string) (int, error) {
+	z.kv.mu.Lock()
+	defer z.kv.mu.Unlock()
+
+	zs, err := z.get(key)
+	if err != nil || zs == nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, member := range members {
+		if zs.Remove(member) {
+			removed++
+		}
+	}
+
+	if removed > 0 {
+		z.kv.notify(notify.ZSet, "zrem", key)
+	}
+
+	z.deleteIfEmpty(key, zs)
+
+	return removed, nil
+}
+
+// Rank returns the 0-based rank of the member and its score, counting from
+// the highest score when reverse is set. false is returned if the member
+// doesn't exist.
+func (z *ZSet) Rank(key, member string, reverse bool) (int, float64, bool, error) {
+	z.kv.mu.Lock()
+	defer z.kv.mu.Unlock()
+
+	zs, err := readObject[*zset.ZSet](z.kv, key)
+	if err != nil || zs == nil {
+		return 0, 0, false, err
+	}
+
+	rank, score, found := zs.Rank(member, reverse)
+
+	return rank, score, found, nil
+}
+
+// RangeByRank returns the members between the ranks start and stop, both
+// inclusive. Negative ranks count from the end.
+func (z *ZSet) RangeByRank(key string, start, stop int, reverse bool) ([]zset.Entry, error) {
+	z.kv.mu.Lock()
+	defer z.kv.mu.Unlock()
+
+	zs, err := readObject[*zset.ZSet](z.kv, key)
+	if err != nil || zs == nil {
+		return []zset.Entry{}, err
+	}
+
+	start, stop, ok := normalizeRange(start, stop, zs.Len())
+	if !ok {
+		return []zset.Entry{}, nil
+	}
+
+	return zs.RangeByRank(start, stop, reverse), nil
+}
+
+// RangeByScore returns the members whose score is in the range. offset
+// members are skipped and at most count are returned, unless it's negative.
+func (z *ZSet) RangeByScore(key string, r zset.ScoreRange, reverse bool, offset, count int) ([]zset.Entry, error) {
+	z.kv.mu.Lock()
+	defer z.kv.mu.Unlock()
+
+	zs, err := readObject[*zset.ZSet](z.kv, key)
+	if err != nil || zs == nil {
+		return []zset.Entry{}, err
+	}
+
+	return zs.RangeByScore(r, reverse, offset, count), nil
+}
+
+// RangeByLex is like RangeByScore for a range of members.
+func (z *ZSet) RangeByLex(key string, r zset.LexRange, reverse bool, offset, count int) ([]zset.Entry, error) {
+	z.kv.mu.Lock()
+	defer z.kv.mu.Unlock()
+
+	zs, err := readObject[*zset.ZSet](z.kv, key)
+	if err != nil || zs == nil {
+		return []zset.Entry{}, err
+	}
+
+	return zs.RangeByLex(r, reverse, offset, count), nil
+}
+
+// Count returns the number of members whose score is in the range.
+func (z *ZSet) Count(key string, r zset.ScoreRange) (int, error) {
+	z.kv.mu.Lock()
+	defer z.kv.mu.Unlock()
+
+	zs, err := readObject[*zset.ZSet](z.kv, key)
+	if err != nil || zs == nil {
+		return 0, err
+	}
+
+	return zs.Count(r), nil
+}
+
+// LexCount returns the number of members in the range.
+func (z *ZSet) LexCount(key string, r zset.LexRange) (int, error) {
+	z.kv.mu.Lock()
+	defer z.kv.mu.Unlock()
+
+	zs, err := readObject[*zset.ZSet](z.kv, key)
+	if err != nil || zs == nil {
+		return 0, err
+	}
+
+	return zs.LexCount(r), nil
+}
+
+// RemRangeByRank removes the members between the ranks start and stop and
+// returns how many were removed.
+func (z *ZSet) RemRangeByRank(key string, start, stop int) (int, error) {
+	z.kv.mu.Lock()
+	defer z.kv.mu.Unlock()
+
+	zs, err := z.get(key)
+	if err != nil || zs == nil {
+		return 0, err
+	}
+
+	start, stop, ok := normalizeRange(start, stop, zs.Len())
+	if !ok {
+		return 0, nil
+	}
+
+	return z.remove(key, zs, zs.RangeByRank(start, stop, false), "zremrangebyrank"), nil
+}
+
+// RemRangeByScore removes the members whose score is in the range and
+// returns how many were removed.
+func (z *ZSet) RemRangeByScore(key string, r zset.ScoreRange) (int, error) {
+	z.kv.mu.Lock()
+	defer z.kv.mu.Unlock()
+
+	zs, err := z.get(key)
+	if err != nil || zs == nil {
+		return 0, err
+	}
+
+	return z.remove(key, zs, zs.RangeByScore(r, false, 0, -1), "zremrangebyscore"), nil
+}
+
+// RemRangeByLex removes the members in the range and returns how many were
+// removed.
+func (z *ZSet) RemRangeByLex(key string, r zset.LexRange) (int, error) {
+	z.kv.mu.Lock()
+	defer z.kv.mu.Unlock()
+
+	zs, err := z.get(key)
+	if err != nil || zs == nil {
+		return 0, err
+	}
+
+	return z.remove(key, zs, zs.RangeByLex(r, false, 0, -1), "zremrangebylex"), nil
+}
+
+// Pop removes and returns up to count members with the lowest scores, or the
+// highest ones when max is set.
+func (z *ZSet) Pop(key string, count int, max bool) ([]zset.Entry, error) {
+	z.kv.mu.Lock()
+	defer z.kv.mu.Unlock()
+
+	zs, err := z.get(key)
+	if err != nil || zs == nil {
+		return []zset.Entry{}, err
+	}
+
+	stop := count - 1
+	if stop >= zs.Len() {
+		stop = zs.Len() - 1
+	}
+
+	event := "zpopmin"
+	if max {
+		event = "zpopmax"
+	}
+
+	entries := zs.RangeByRank(0, stop, max)
+	z.remove(key, zs, entries, event)
+
+	return entries, nil
+}
+
+// Combine returns the union, intersection or difference of the sorted sets,
+// ordered by score. The scores of every set are multiplied by its weight,
+// and combined with aggregate when a member is in many sets. Sets are
+// accepted as well, with a score of 1 for every member, and missing keys are
+// treated as empty sets.
+func (z *ZSet) Combine(op SetOperation, keys []string, weights []float64, aggregate zset.Aggregate) ([]zset.Entry, error) {
+	z.kv.mu.Lock()
+	defer z.kv.mu.Unlock()
+
+	res, err := z.combine(op, keys, weights, aggregate)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.RangeByRank(0, res.Len()-1, false), nil
+}
+
+// CombineStore stores the result of Combine at dst, overwriting any value,
+// and returns its size. dst is deleted when the result is empty.
+func (z *ZSet) CombineStore(op SetOperation, dst string, keys []string, weights []float64, aggregate zset.Aggregate) (int, error) {
+	z.kv.mu.Lock()
+	defer z.kv.mu.Unlock()
+
+	res, err := z.combine(op, keys, weights, aggregate)
+	if err != nil {
+		return 0, err
+	}
+
+	z.store(dst, res, zstoreEvents[op])
+
+	return res.Len(), nil
+}
+
+// Store stores a sorted set made of the entries at dst, overwriting any
+// value, and returns its size. dst is deleted when there are no entries.
+// event is the keyspace event of the command storing the entries.
+func (z *ZSet) Store(dst string, entries []zset.Entry, event string) int {
+	z.kv.mu.Lock()
+	defer z.kv.mu.Unlock()
+
+	res := zset.New()
+	for _, entry := range entries {
+		res.Add(entry.Member, entry.Score, zset.AddFlags{}, z.limits())
+	}
+
+	z.store(dst, res, event)
+
+	return res.Len()
+}
+
+// combine computes the result of the set operation. The caller should hold
+// the lock.
+func (z *ZSet) combine(op SetOperation, keys []string, weights []float64, aggregate zset.Aggregate) (*zset.ZSet, error) {
+	inputs := make([]map[string]float64, len(keys))
+	for i, key := range keys {
+		scores, err := z.scores(key)
+		if err != nil {
+			return nil, err
+		}
+
+		inputs[i] = scores
+	}
+
+	combined := map[string]float64{}
+
+	switch op {
+	case SetUnion:
+		for i, scores := range inputs {
+			for member, score := range scores {
+				score = weighted(score, weights[i])
+
+				if current, exists := combined[member]; exists {
+					score = aggregate.Apply(current, score)
+				}
+
+				combined[member] = score
+			}
+		}
+	case SetInter:
+	members:
+		for member, score := range inputs[0] {
+			score = weighted(score, weights[0])
+
+			for i, other := range inputs[1:] {
+				otherScore, exists := other[member]
+				if !exists {
+					continue members
+				}
+
+				score = aggregate.Apply(score, weighted(otherScore, weights[i+1]))
+			}
+
+			combined[member] = score
+		}
+	case SetDiff:
+	diff:
+		for member, score := range inputs[0] {
+			for _, other := range inputs[1:] {
+				if _, exists := other[member]; exists {
+					continue diff
+				}
+			}
+
+			combined[member] = score
+		}
+	}
+
+	res := zset.New()
+	for member, score := range combined {
+		res.Add(member, score, zset.AddFlags{}, z.limits())
+	}
+
+	return res, nil
+}
+
+// weighted multiplies the score by the weight, where 0 times infinity is 0.
+func weighted(score, weight float64) float64 {
+	if weight == 1 {
+		return score
+	}
+
+	res := score * weight
+	if res != res {
+		return 0
+	}
+
+	return res
+}
+
+// scores returns the scores of the members of the sorted set or set stored
+// at key. The caller should hold the lock.
+func (z *ZSet) scores(key string) (map[string]float64, error) {
+	res := map[string]float64{}
+
+	val, exists := z.kv.lookup(key)
+	if !exists {
+		return res, nil
+	}
+
+	switch obj := val.obj.(type) {
+	case *zset.ZSet:
+		obj.ForEach(func(entry zset.Entry) bool {
+			res[entry.Member] = entry.Score
+			return true
+		})
+	case *set.Set:
+		obj.ForEach(func(member []byte) bool {
+			res[string(member)] = 1
+			return true
+		})
+	default:
+		return nil, ErrWrongType
+	}
+
+	return res, nil
+}
+
+// store sets the sorted set at dst, or deletes dst if it's empty. The caller
+// should hold the lock.
+func (z *ZSet) store(dst string, res *zset.ZSet, event string) {
+	if res.Len() == 0 {
+		if z.kv.store.Delete(dst) {
+			z.kv.notify(notify.Generic, "del", dst)
+		}
+
+		return
+	}
+
+	z.kv.setObject(dst, res)
+	z.kv.notify(notify.ZSet, event, dst)
+}
+
+// remove removes the entries from the set, notifying the event if there are
+// any. The caller should hold the lock.
+func (z *ZSet) remove(key string, zs *zset.ZSet, entries []zset.Entry, event string) int {
+	for _, entry := range entries {
+		zs.Remove(entry.Member)
+	}
+
+	if len(entries) > 0 {
+		z.kv.notify(notify.ZSet, event, key)
+	}
+
+	z.deleteIfEmpty(key, zs)
+
+	return len(entries)
+}
+
+func (z *ZSet) limits() zset.Limits {
+	return zset.Limits{
+		MaxEntries: z.cfg.Int(config.ZSetMaxListpackEntries),
+		MaxValue:   z.cfg.Int(config.ZSetMaxListpackValue),
+	}
+}
+
+// get returns the sorted set stored at key, or nil if the key doesn't exist.
+// The caller should hold the lock.
+func (z *ZSet) get(key string) (*zset.ZSet, error) {
+	return getObject[*zset.ZSet](z.kv, key)
+}
+
+// getOrCreate is like get but creates the sorted set if it doesn't exist,
+// unless only existing members may be updated. The caller should hold the
+// lock, and delete the key again if nothing was added.
+func (z *ZSet) getOrCreate(key string, flags zset.AddFlags) (*zset.ZSet, error) {
+	zs, err := z.get(key)
+	if err != nil || zs != nil || flags.XX {
+		return zs, err
+	}
+
+	zs = zset.New()
+	z.kv.setObject(key, zs)
+
+	return zs, nil
+}
+
+// deleteIfEmpty removes the key once its sorted set has no members left. The
+// caller should hold the lock.
+func (z *ZSet) deleteIfEmpty(key string, zs *zset.ZSet) {
+	if zs.Len() == 0 {
+		z.kv.store.Delete(key)
+		z.kv.notify(notify.Generic, "del", key)
+	}
+}
+
+// discardIfEmpty removes the sorted set created by getOrCreate if nothing
+// was added to it. Clients never saw the key, so its removal isn't notified.
+// The caller should hold the lock.
+func (z *ZSet) discardIfEmpty(key string, zs *zset.ZSet) {
+	if zs.Len() == 0 {
+		z.kv.store.Delete(key)
+	}
+}
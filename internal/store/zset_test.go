@@ -0,0 +1,207 @@
+package store
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/config"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/geo"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/zset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestZSet(t *testing.T, sets map[string][]zset.Entry) *ZSet {
+	z := NewZSet(NewKVStore(), config.New())
+
+	for key, entries := range sets {
+		_, _, err := z.Add(key, entries, zset.AddFlags{})
+		require.NoError(t, err)
+	}
+
+	return z
+}
+
+func zsetMembers(entries []zset.Entry) []string {
+	res := []string{}
+	for _, entry := range entries {
+		res = append(res, entry.Member)
+	}
+
+	return res
+}
+
+func TestZSet_Add(t *testing.T) {
+	z := newTestZSet(t, map[string][]zset.Entry{"zset": {{Member: "a", Score: 1}, {Member: "b", Score: 2}}})
+
+	added, updated, err := z.Add("zset", []zset.Entry{{Member: "b", Score: 3}, {Member: "c", Score: 3}}, zset.AddFlags{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, added)
+	assert.Equal(t, 1, updated)
+
+	added, _, err = z.Add("missing", []zset.Entry{{Member: "a", Score: 1}}, zset.AddFlags{XX: true})
+	require.NoError(t, err)
+	assert.Equal(t, 0, added)
+	assert.Equal(t, "none", z.kv.Type("missing"))
+
+	score, ok, err := z.IncrBy("zset", "a", 2.5, zset.AddFlags{})
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 3.5, score)
+
+	_, ok, err = z.IncrBy("zset", "a", 1, zset.AddFlags{NX: true})
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	removed, err := z.Rem("zset", []string{"a", "b", "c", "d"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, removed)
+	assert.Equal(t, "none", z.kv.Type("zset"))
+
+	z.kv.Set("string", []byte("value"), 0)
+	_, _, err = z.Add("string", []zset.Entry{{Member: "a"}}, zset.AddFlags{})
+	assert.ErrorIs(t, err, ErrWrongType)
+}
+
+func TestZSet_RangeByRank(t *testing.T) {
+	z := newTestZSet(t, map[string][]zset.Entry{"zset": {{Member: "a", Score: 1}, {Member: "b", Score: 2}, {Member: "c", Score: 3}}})
+
+	testCases := map[string]struct {
+		start, stop int
+		reverse     bool
+		expected    []string
+	}{
+		"when range is whole set":      {start: 0, stop: -1, expected: []string{"a", "b", "c"}},
+		"when range is reversed":       {start: 0, stop: 1, reverse: true, expected: []string{"c", "b"}},
+		"when range exceeds the set":   {start: -10, stop: 10, expected: []string{"a", "b", "c"}},
+		"when range is out of the set": {start: 5, stop: 10, expected: []string{}},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			entries, err := z.RangeByRank("zset", tc.start, tc.stop, tc.reverse)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, zsetMembers(entries))
+		})
+	}
+}
+
+func TestZSet_RemRange(t *testing.T) {
+	entries := []zset.Entry{{Member: "a", Score: 1}, {Member: "b", Score: 2}, {Member: "c", Score: 3}, {Member: "d", Score: 4}}
+	z := newTestZSet(t, map[string][]zset.Entry{"zset": entries})
+
+	removed, err := z.RemRangeByRank("zset", 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+
+	r, err := zset.ParseScoreRange("(2", "+inf")
+	require.NoError(t, err)
+
+	removed, err = z.RemRangeByScore("zset", r)
+	require.NoError(t, err)
+	assert.Equal(t, 2, removed)
+
+	lex, err := zset.ParseLexRange("-", "+")
+	require.NoError(t, err)
+
+	removed, err = z.RemRangeByLex("zset", lex)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.Equal(t, "none", z.kv.Type("zset"))
+}
+
+func TestZSet_Pop(t *testing.T) {
+	z := newTestZSet(t, map[string][]zset.Entry{"zset": {{Member: "a", Score: 1}, {Member: "b", Score: 2}, {Member: "c", Score: 3}}})
+
+	entries, err := z.Pop("zset", 2, true)
+	require.NoError(t, err)
+	assert.Equal(t, []zset.Entry{{Member: "c", Score: 3}, {Member: "b", Score: 2}}, entries)
+
+	entries, err = z.Pop("zset", 5, false)
+	require.NoError(t, err)
+	assert.Equal(t, []zset.Entry{{Member: "a", Score: 1}}, entries)
+	assert.Equal(t, "none", z.kv.Type("zset"))
+}
+
+func TestZSet_Combine(t *testing.T) {
+	z := newTestZSet(t, map[string][]zset.Entry{
+		"zset-1": {{Member: "a", Score: 1}, {Member: "b", Score: 2}},
+		"zset-2": {{Member: "b", Score: 3}, {Member: "c", Score: 4}},
+	})
+
+	s := NewSet(z.kv, z.cfg)
+	_, err := s.Add("set", []string{"a", "c"})
+	require.NoError(t, err)
+
+	testCases := map[string]struct {
+		op        SetOperation
+		keys      []string
+		weights   []float64
+		aggregate zset.Aggregate
+		expected  []zset.Entry
+	}{
+		"when union": {
+			op:       SetUnion,
+			keys:     []string{"zset-1", "zset-2"},
+			weights:  []float64{1, 1},
+			expected: []zset.Entry{{Member: "a", Score: 1}, {Member: "c", Score: 4}, {Member: "b", Score: 5}},
+		},
+		"when weighted union with max": {
+			op:        SetUnion,
+			keys:      []string{"zset-1", "zset-2"},
+			weights:   []float64{10, 1},
+			aggregate: zset.AggregateMax,
+			expected:  []zset.Entry{{Member: "c", Score: 4}, {Member: "a", Score: 10}, {Member: "b", Score: 20}},
+		},
+		"when intersection with a set": {
+			op:        SetInter,
+			keys:      []string{"zset-2", "set"},
+			weights:   []float64{1, 1},
+			aggregate: zset.AggregateMin,
+			expected:  []zset.Entry{{Member: "c", Score: 1}},
+		},
+		"when difference": {
+			op:       SetDiff,
+			keys:     []string{"zset-1", "zset-2", "missing"},
+			weights:  []float64{1, 1, 1},
+			expected: []zset.Entry{{Member: "a", Score: 1}},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			entries, err := z.Combine(tc.op, tc.keys, tc.weights, tc.aggregate)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, entries)
+		})
+	}
+
+	count, err := z.CombineStore(SetInter, "dst", []string{"zset-1", "missing"}, []float64{1, 1}, zset.AggregateSum)
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+	assert.Equal(t, "none", z.kv.Type("dst"))
+
+	z.kv.Set("string", []byte("value"), 0)
+	_, err = z.Combine(SetUnion, []string{"zset-1", "string"}, []float64{1, 1}, zset.AggregateSum)
+	assert.ErrorIs(t, err, ErrWrongType)
+}
+
+func TestZSet_GeoSearch(t *testing.T) {
+	z := newTestZSet(t, map[string][]zset.Entry{"sicily": {
+		{Member: "Palermo", Score: float64(geo.Encode(13.361389, 38.115556))},
+		{Member: "Catania", Score: float64(geo.Encode(15.087269, 37.502669))},
+	}})
+
+	matches, err := z.GeoSearch("sicily", &geo.Shape{Lon: 15, Lat: 37, Radius: 100000}, 0)
+	require.NoError(t, err)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "Catania", matches[0].Member)
+	assert.InDelta(t, 56441.3, matches[0].Distance, 1)
+
+	matches, err = z.GeoSearch("sicily", &geo.Shape{Lon: 15, Lat: 37, Radius: 200000}, 1)
+	require.NoError(t, err)
+	assert.Len(t, matches, 1)
+
+	matches, err = z.GeoSearch("missing", &geo.Shape{Lon: 15, Lat: 37, Radius: 200000}, 0)
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+}
@@ -0,0 +1,82 @@
+package stringfn
+
+// Match is a range of the longest common subsequence found in both strings,
+// with the offsets of its first and last bytes in each of them.
+type Match struct {
+	AStart, AEnd int
+	BStart, BEnd int
+}
+
+func (m Match) Len() int {
+	return m.AEnd - m.AStart + 1
+}
+
+// LCS returns the longest common subsequence of a and b, together with its
+// ranges found in both strings, from the last one to the first one as LCS
+// IDX replies.
+func LCS(a, b []byte) ([]byte, []Match) {
+	// lengths[i][j] is the length of the LCS of a[:i] and b[:j]
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			switch {
+			case a[i-1] == b[j-1]:
+				lengths[i][j] = lengths[i-1][j-1] + 1
+			case lengths[i-1][j] > lengths[i][j-1]:
+				lengths[i][j] = lengths[i-1][j]
+			default:
+				lengths[i][j] = lengths[i][j-1]
+			}
+		}
+	}
+
+	lcs := make([]byte, lengths[len(a)][len(b)])
+	matches := []Match{}
+
+	// walk back from the end, growing the current range while the matching
+	// bytes are contiguous in both strings
+	var current *Match
+	for i, j, idx := len(a), len(b), len(lcs); i > 0 && j > 0; {
+		if a[i-1] == b[j-1] {
+			lcs[idx-1] = a[i-1]
+			idx--
+
+			if current != nil && current.AStart == i && current.BStart == j {
+				current.AStart--
+				current.BStart--
+			} else {
+				if current != nil {
+					matches = append(matches, *current)
+				}
+
+				current = &Match{AStart: i - 1, AEnd: i - 1, BStart: j - 1, BEnd: j - 1}
+			}
+
+			i--
+			j--
+
+			continue
+		}
+
+		if lengths[i-1][j] > lengths[i][j-1] {
+			i--
+		} else {
+			j--
+		}
+
+		if current != nil {
+			matches = append(matches, *current)
+			current = nil
+		}
+	}
+
+	if current != nil {
+		matches = append(matches, *current)
+	}
+
+	return lcs, matches
+}
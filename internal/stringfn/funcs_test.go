@@ -0,0 +1,52 @@
+package stringfn_test
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/stringfn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLCS(t *testing.T) {
+	testCases := map[string]struct {
+		a, b            string
+		expected        string
+		expectedMatches []stringfn.Match
+	}{
+		"when strings share subsequences": {
+			a:        "ohmytext",
+			b:        "mynewtext",
+			expected: "mytext",
+			expectedMatches: []stringfn.Match{
+				{AStart: 4, AEnd: 7, BStart: 5, BEnd: 8},
+				{AStart: 2, AEnd: 3, BStart: 0, BEnd: 1},
+			},
+		},
+		"when strings are equal": {
+			a:               "abc",
+			b:               "abc",
+			expected:        "abc",
+			expectedMatches: []stringfn.Match{{AStart: 0, AEnd: 2, BStart: 0, BEnd: 2}},
+		},
+		"when nothing is shared": {
+			a:               "abc",
+			b:               "xyz",
+			expected:        "",
+			expectedMatches: []stringfn.Match{},
+		},
+		"when a string is empty": {
+			a:               "",
+			b:               "xyz",
+			expected:        "",
+			expectedMatches: []stringfn.Match{},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			lcs, matches := stringfn.LCS([]byte(tc.a), []byte(tc.b))
+			assert.Equal(t, tc.expected, string(lcs))
+			assert.Equal(t, tc.expectedMatches, matches)
+		})
+	}
+}
@@ -0,0 +1,403 @@
+package dict
+
+import (
+	"hash/maphash"
+	"math/bits"
+	"math/rand"
+)
+
+const (
+	// initialSize is the number of buckets of a new table.
+	initialSize = 4
+	// rehashEmptyVisits bounds the number of empty buckets a rehash step
+	// skips, so that a step takes a bounded time.
+	rehashEmptyVisits = 10
+	// minFill is the inverse of the fill ratio under which the table shrinks.
+	minFill = 8
+)
+
+type entry[V any] struct {
+	key   string
+	value V
+	next  *entry[V]
+}
+
+type table[V any] struct {
+	buckets []*entry[V]
+	used    int
+}
+
+func (t *table[V]) mask() uint64 {
+	return uint64(len(t.buckets) - 1)
+}
+
+// Dict is a hash table with string keys, laid out as the dicts of Redis: the
+// buckets are chained, their number is a power of two, and resizing is
+// incremental. A resize allocates a second table, and every operation moves
+// a bucket from the first table to the second one, until the first table is
+// empty and the second one takes its place.
+//
+// Unlike a Go map, it can be iterated with a cursor across calls while it's
+// modified and resized, see Scan.
+type Dict[V any] struct {
+	tables [2]table[V]
+	// rehashIdx is the next bucket of the first table to move, -1 when not
+	// rehashing.
+	rehashIdx int
+	// pauseRehash is positive while rehashing must not move entries, as
+	// during ForEach.
+	pauseRehash int
+	seed        maphash.Seed
+}
+
+func New[V any]() *Dict[V] {
+	return &Dict[V]{rehashIdx: -1, seed: maphash.MakeSeed()}
+}
+
+// Len is the number of entries.
+func (d *Dict[V]) Len() int {
+	return d.tables[0].used + d.tables[1].used
+}
+
+func (d *Dict[V]) isRehashing() bool {
+	return d.rehashIdx != -1
+}
+
+func (d *Dict[V]) hash(key string) uint64 {
+	return maphash.String(d.seed, key)
+}
+
+// Get returns the value of the key, and false if it doesn't exist.
+func (d *Dict[V]) Get(key string) (V, bool) {
+	if e := d.find(key); e != nil {
+		return e.value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Set adds the key or replaces its value, and returns true if it was added.
+func (d *Dict[V]) Set(key string, value V) bool {
+	if e := d.find(key); e != nil {
+		e.value = value
+		return false
+	}
+
+	d.expandIfNeeded()
+
+	// while rehashing new entries go to the new table, so that the old one
+	// only ever shrinks
+	t := &d.tables[0]
+	if d.isRehashing() {
+		t = &d.tables[1]
+	}
+
+	idx := d.hash(key) & t.mask()
+	t.buckets[idx] = &entry[V]{key: key, value: value, next: t.buckets[idx]}
+	t.used++
+
+	return true
+}
+
+// Delete removes the key and returns true if it existed.
+func (d *Dict[V]) Delete(key string) bool {
+	if d.Len() == 0 {
+		return false
+	}
+
+	d.rehashStep()
+
+	h := d.hash(key)
+	for i := 0; i < 2; i++ {
+		t := &d.tables[i]
+		if t.buckets == nil {
+			break
+		}
+
+		idx := h & t.mask()
+		for prev, e := (*entry[V])(nil), t.buckets[idx]; e != nil; prev, e = e, e.next {
+			if e.key != key {
+				continue
+			}
+
+			if prev == nil {
+				t.buckets[idx] = e.next
+			} else {
+				prev.next = e.next
+			}
+
+			t.used--
+			d.shrinkIfNeeded()
+
+			return true
+		}
+
+		if !d.isRehashing() {
+			break
+		}
+	}
+
+	return false
+}
+
+// Clear removes every entry.
+func (d *Dict[V]) Clear() {
+	d.tables = [2]table[V]{}
+	d.rehashIdx = -1
+}
+
+// ForEach calls fn for every entry until it returns false. fn may delete the
+// entry it's given; entries added meanwhile may or may not be visited.
+func (d *Dict[V]) ForEach(fn func(key string, value V) bool) {
+	d.pauseRehash++
+	defer func() { d.pauseRehash-- }()
+
+	for i := 0; i < 2; i++ {
+		t := &d.tables[i]
+
+		for idx := 0; idx < len(t.buckets); idx++ {
+			for e := t.buckets[idx]; e != nil; {
+				next := e.next
+				if !fn(e.key, e.value) {
+					return
+				}
+
+				e = next
+			}
+		}
+
+		if !d.isRehashing() {
+			return
+		}
+	}
+}
+
+// Random returns a random entry, and false if the dict is empty. Entries in
+// long chains are less likely to be picked than with a uniform distribution.
+func (d *Dict[V]) Random() (string, V, bool) {
+	var zero V
+	if d.Len() == 0 {
+		return "", zero, false
+	}
+
+	d.rehashStep()
+
+	var head *entry[V]
+	for head == nil {
+		if d.isRehashing() {
+			// the buckets of the old table before rehashIdx are empty
+			size0 := len(d.tables[0].buckets)
+			idx := d.rehashIdx + rand.Intn(size0+len(d.tables[1].buckets)-d.rehashIdx)
+			if idx >= size0 {
+				head = d.tables[1].buckets[idx-size0]
+			} else {
+				head = d.tables[0].buckets[idx]
+			}
+		} else {
+			head = d.tables[0].buckets[rand.Intn(len(d.tables[0].buckets))]
+		}
+	}
+
+	length := 0
+	for e := head; e != nil; e = e.next {
+		length++
+	}
+
+	e := head
+	for i := rand.Intn(length); i > 0; i-- {
+		e = e.next
+	}
+
+	return e.key, e.value, true
+}
+
+// Scan calls fn for the entries of one bucket from the cursor, 0 to start
+// the iteration, and returns the cursor to continue from, 0 once the
+// iteration is over.
+//
+// Every entry present for the whole iteration is returned at least once,
+// even if the dict is resized in between calls. To achieve that the cursor
+// is incremented from its most significant bit down: the buckets an entry
+// moves to when the table grows or shrinks come right after the buckets
+// already visited, as the index of a bucket is the lower bits of the hash.
+// Entries may be returned several times when the table shrinks.
+func (d *Dict[V]) Scan(cursor uint64, fn func(key string, value V)) uint64 {
+	if d.Len() == 0 {
+		return 0
+	}
+
+	d.pauseRehash++
+	defer func() { d.pauseRehash-- }()
+
+	if !d.isRehashing() {
+		t := &d.tables[0]
+		m := t.mask()
+
+		emit(t.buckets[cursor&m], fn)
+
+		return nextCursor(cursor, m)
+	}
+
+	small, large := &d.tables[0], &d.tables[1]
+	if len(small.buckets) > len(large.buckets) {
+		small, large = large, small
+	}
+
+	m0, m1 := small.mask(), large.mask()
+
+	emit(small.buckets[cursor&m0], fn)
+
+	// visit the buckets of the larger table the bucket of the smaller one
+	// expands to
+	for {
+		emit(large.buckets[cursor&m1], fn)
+
+		cursor = nextCursor(cursor, m1)
+		if cursor&(m0^m1) == 0 {
+			break
+		}
+	}
+
+	return cursor
+}
+
+// Rehash moves up to n buckets to the new table if a resize is in progress,
+// and returns false once there is nothing left to move.
+func (d *Dict[V]) Rehash(n int) bool {
+	if !d.isRehashing() {
+		return false
+	}
+
+	if d.pauseRehash > 0 {
+		return true
+	}
+
+	emptyVisits := n * rehashEmptyVisits
+	old, t := &d.tables[0], &d.tables[1]
+
+	for ; n > 0 && old.used > 0; n-- {
+		for old.buckets[d.rehashIdx] == nil {
+			d.rehashIdx++
+			emptyVisits--
+			if emptyVisits == 0 {
+				return true
+			}
+		}
+
+		for e := old.buckets[d.rehashIdx]; e != nil; {
+			next := e.next
+			idx := d.hash(e.key) & t.mask()
+			e.next = t.buckets[idx]
+			t.buckets[idx] = e
+			old.used--
+			t.used++
+			e = next
+		}
+
+		old.buckets[d.rehashIdx] = nil
+		d.rehashIdx++
+	}
+
+	if old.used == 0 {
+		d.tables[0] = d.tables[1]
+		d.tables[1] = table[V]{}
+		d.rehashIdx = -1
+
+		return false
+	}
+
+	return true
+}
+
+func (d *Dict[V]) rehashStep() {
+	if d.pauseRehash == 0 {
+		d.Rehash(1)
+	}
+}
+
+func (d *Dict[V]) find(key string) *entry[V] {
+	if d.Len() == 0 {
+		return nil
+	}
+
+	d.rehashStep()
+
+	h := d.hash(key)
+	for i := 0; i < 2; i++ {
+		t := &d.tables[i]
+		if t.buckets == nil {
+			break
+		}
+
+		for e := t.buckets[h&t.mask()]; e != nil; e = e.next {
+			if e.key == key {
+				return e
+			}
+		}
+
+		if !d.isRehashing() {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (d *Dict[V]) expandIfNeeded() {
+	if d.isRehashing() {
+		return
+	}
+
+	if d.tables[0].buckets == nil {
+		d.tables[0].buckets = make([]*entry[V], initialSize)
+		return
+	}
+
+	if d.tables[0].used >= len(d.tables[0].buckets) {
+		d.resize(d.tables[0].used + 1)
+	}
+}
+
+func (d *Dict[V]) shrinkIfNeeded() {
+	if d.isRehashing() {
+		return
+	}
+
+	size := len(d.tables[0].buckets)
+	if size > initialSize && d.tables[0].used*minFill < size {
+		d.resize(d.tables[0].used)
+	}
+}
+
+// resize starts rehashing to a table with the smallest power of two buckets
+// holding size entries.
+func (d *Dict[V]) resize(size int) {
+	n := initialSize
+	for n < size {
+		n *= 2
+	}
+
+	if n == len(d.tables[0].buckets) {
+		return
+	}
+
+	d.tables[1] = table[V]{buckets: make([]*entry[V], n)}
+	d.rehashIdx = 0
+}
+
+func emit[V any](e *entry[V], fn func(key string, value V)) {
+	for ; e != nil; e = e.next {
+		fn(e.key, e.value)
+	}
+}
+
+// nextCursor increments the bits of the cursor covered by the mask in
+// reverse order.
+func nextCursor(cursor, mask uint64) uint64 {
+	cursor |= ^mask
+	cursor = bits.Reverse64(cursor)
+	cursor++
+
+	return bits.Reverse64(cursor)
+}
@@ -0,0 +1,189 @@
+package dict_test
+
+import (
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/dict"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDict(n int) *dict.Dict[int] {
+	d := dict.New[int]()
+	for i := 0; i < n; i++ {
+		d.Set(strconv.Itoa(i), i)
+	}
+
+	return d
+}
+
+func keys(n int) []string {
+	res := make([]string, n)
+	for i := range res {
+		res[i] = strconv.Itoa(i)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+// scan runs a whole scan, calling between for every cursor returned.
+func scan(d *dict.Dict[int], between func()) map[string]int {
+	seen := map[string]int{}
+
+	cursor := uint64(0)
+	for {
+		cursor = d.Scan(cursor, func(key string, value int) {
+			seen[key]++
+		})
+
+		if cursor == 0 {
+			return seen
+		}
+
+		between()
+	}
+}
+
+func TestSetGetDelete(t *testing.T) {
+	d := newDict(1000)
+	assert.Equal(t, 1000, d.Len())
+
+	for i := 0; i < 1000; i++ {
+		value, found := d.Get(strconv.Itoa(i))
+		require.True(t, found)
+		assert.Equal(t, i, value)
+	}
+
+	assert.False(t, d.Set("10", -10))
+
+	value, _ := d.Get("10")
+	assert.Equal(t, -10, value)
+
+	for i := 0; i < 1000; i += 2 {
+		assert.True(t, d.Delete(strconv.Itoa(i)))
+	}
+
+	assert.False(t, d.Delete("0"))
+	assert.Equal(t, 500, d.Len())
+
+	_, found := d.Get("0")
+	assert.False(t, found)
+
+	_, found = d.Get("1")
+	assert.True(t, found)
+
+	d.Clear()
+	assert.Equal(t, 0, d.Len())
+}
+
+func TestForEach(t *testing.T) {
+	d := newDict(100)
+
+	visited := []string{}
+	d.ForEach(func(key string, value int) bool {
+		visited = append(visited, key)
+
+		// deleting the visited entry is allowed
+		d.Delete(key)
+
+		return true
+	})
+
+	sort.Strings(visited)
+	assert.Equal(t, keys(100), visited)
+	assert.Equal(t, 0, d.Len())
+}
+
+func TestRandom(t *testing.T) {
+	_, _, found := dict.New[int]().Random()
+	assert.False(t, found)
+
+	d := newDict(10)
+
+	seen := map[string]bool{}
+	for i := 0; i < 1000; i++ {
+		key, value, found := d.Random()
+		require.True(t, found)
+		assert.Equal(t, key, strconv.Itoa(value))
+
+		seen[key] = true
+	}
+
+	assert.Len(t, seen, 10)
+}
+
+func TestScan(t *testing.T) {
+	testCases := map[string]struct {
+		size    int
+		between func(d *dict.Dict[int])
+		// added are the keys added during the scan, which may or may not be
+		// returned
+		added int
+	}{
+		"when dict is empty": {
+			between: func(d *dict.Dict[int]) {},
+		},
+		"when dict doesn't change": {
+			size:    1000,
+			between: func(d *dict.Dict[int]) {},
+		},
+		"when dict grows during the scan": {
+			size:  100,
+			added: 2000,
+			between: func(d *dict.Dict[int]) {
+				for i := 0; i < 20 && d.Len() < 2100; i++ {
+					d.Set("new"+strconv.Itoa(d.Len()), 0)
+				}
+			},
+		},
+		"when dict is rehashing during the scan": {
+			size:  1000,
+			added: 1,
+			between: func(d *dict.Dict[int]) {
+				// a single step, so the scan sees both tables
+				d.Rehash(1)
+				d.Set("new", 0)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			d := newDict(tc.size)
+
+			seen := scan(d, func() { tc.between(d) })
+
+			for _, key := range keys(tc.size) {
+				assert.Contains(t, seen, key)
+			}
+
+			assert.LessOrEqual(t, len(seen), tc.size+tc.added)
+		})
+	}
+}
+
+func TestScanWhileShrinking(t *testing.T) {
+	d := newDict(1000)
+
+	// the keys not multiple of 50 are deleted little by little, shrinking
+	// the table during the scan
+	next := 0
+	seen := scan(d, func() {
+		for end := next + 20; next < end && next < 1000; next++ {
+			if next%50 != 0 {
+				d.Delete(strconv.Itoa(next))
+			}
+		}
+	})
+
+	assert.Equal(t, 20, d.Len())
+
+	for i := 0; i < 1000; i += 50 {
+		assert.Contains(t, seen, strconv.Itoa(i))
+	}
+}
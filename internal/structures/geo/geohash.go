@@ -0,0 +1,184 @@
+// Package geo encodes coordinates into the 52-bit geohashes used as the
+// scores of the sorted sets behind the GEO commands, and finds the areas to
+// look at when searching around a point.
+package geo
+
+import (
+	"math"
+)
+
+const (
+	// Step is the precision of the stored geohashes, 26 bits per coordinate.
+	Step = 26
+
+	LonMin = -180.0
+	LonMax = 180.0
+	// the latitudes are limited to the range of the web mercator projection
+	LatMin = -85.05112878
+	LatMax = 85.05112878
+
+	// EarthRadius is the radius used for distances, in meters.
+	EarthRadius = 6372797.560856
+
+	// mercatorMax is half the circumference of the earth in the web mercator
+	// projection, in meters.
+	mercatorMax = 20037726.37
+
+	alphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+)
+
+// hashBits is a geohash with step bits per coordinate, the bits of the
+// longitude being interleaved with the ones of the latitude.
+type hashBits struct {
+	bits uint64
+	step uint
+}
+
+type coordRange struct {
+	min, max float64
+}
+
+type area struct {
+	lon, lat coordRange
+}
+
+var (
+	lonRange = coordRange{min: LonMin, max: LonMax}
+	latRange = coordRange{min: LatMin, max: LatMax}
+)
+
+// Valid reports whether the coordinates can be encoded.
+func Valid(lon, lat float64) bool {
+	return lon >= LonMin && lon <= LonMax && lat >= LatMin && lat <= LatMax
+}
+
+// Encode returns the 52-bit geohash of the coordinates, which must be valid.
+func Encode(lon, lat float64) uint64 {
+	return encode(lonRange, latRange, lon, lat, Step).bits
+}
+
+// Decode returns the coordinates of the center of the area of the geohash.
+func Decode(hash uint64) (float64, float64) {
+	a := decode(lonRange, latRange, hashBits{bits: hash, step: Step})
+
+	lon := math.Max(LonMin, math.Min(LonMax, (a.lon.min+a.lon.max)/2))
+	lat := math.Max(LatMin, math.Min(LatMax, (a.lat.min+a.lat.max)/2))
+
+	return lon, lat
+}
+
+// String returns the standard 11 characters geohash of the geohash, which
+// unlike ours covers latitudes from -90 to 90.
+func String(hash uint64) string {
+	lon, lat := Decode(hash)
+	bits := encode(coordRange{min: -180, max: 180}, coordRange{min: -90, max: 90}, lon, lat, Step).bits
+
+	res := make([]byte, 11)
+	for i := range res {
+		idx := 0
+		// the 52 bits only make 10 characters and a half
+		if i < 10 {
+			idx = int(bits>>(52-(i+1)*5)) & 0x1f
+		}
+
+		res[i] = alphabet[idx]
+	}
+
+	return string(res)
+}
+
+// Distance returns the distance in meters between the two points, using the
+// haversine formula.
+func Distance(lon1, lat1, lon2, lat2 float64) float64 {
+	lat1r, lon1r := degToRad(lat1), degToRad(lon1)
+	lat2r, lon2r := degToRad(lat2), degToRad(lon2)
+
+	v := math.Sin((lon2r - lon1r) / 2)
+	if v == 0 {
+		// the points are on the same meridian
+		return latDistance(lat1, lat2)
+	}
+
+	u := math.Sin((lat2r - lat1r) / 2)
+	a := u*u + math.Cos(lat1r)*math.Cos(lat2r)*v*v
+
+	return 2 * EarthRadius * math.Asin(math.Sqrt(a))
+}
+
+func latDistance(lat1, lat2 float64) float64 {
+	return EarthRadius * math.Abs(degToRad(lat2)-degToRad(lat1))
+}
+
+func encode(lonRange, latRange coordRange, lon, lat float64, step uint) hashBits {
+	latOffset := (lat - latRange.min) / (latRange.max - latRange.min)
+	lonOffset := (lon - lonRange.min) / (lonRange.max - lonRange.min)
+
+	latOffset *= float64(uint64(1) << step)
+	lonOffset *= float64(uint64(1) << step)
+
+	return hashBits{
+		bits: interleave(uint32(latOffset), uint32(lonOffset)),
+		step: step,
+	}
+}
+
+func decode(lonRange, latRange coordRange, hash hashBits) area {
+	lat, lon := deinterleave(hash.bits)
+	cells := float64(uint64(1) << hash.step)
+
+	latScale := latRange.max - latRange.min
+	lonScale := lonRange.max - lonRange.min
+
+	return area{
+		lat: coordRange{
+			min: latRange.min + float64(lat)/cells*latScale,
+			max: latRange.min + float64(lat+1)/cells*latScale,
+		},
+		lon: coordRange{
+			min: lonRange.min + float64(lon)/cells*lonScale,
+			max: lonRange.min + float64(lon+1)/cells*lonScale,
+		},
+	}
+}
+
+// interleave puts the bits of x at the even positions and the ones of y at
+// the odd positions.
+func interleave(x, y uint32) uint64 {
+	return spread(x) | spread(y)<<1
+}
+
+func deinterleave(bits uint64) (uint32, uint32) {
+	return squash(bits), squash(bits >> 1)
+}
+
+// spread moves the bits of v to the even positions.
+func spread(v uint32) uint64 {
+	x := uint64(v)
+	x = (x | x<<16) & 0x0000FFFF0000FFFF
+	x = (x | x<<8) & 0x00FF00FF00FF00FF
+	x = (x | x<<4) & 0x0F0F0F0F0F0F0F0F
+	x = (x | x<<2) & 0x3333333333333333
+	x = (x | x<<1) & 0x5555555555555555
+
+	return x
+}
+
+// squash is the reverse of spread, ignoring the odd bits.
+func squash(x uint64) uint32 {
+	x &= 0x5555555555555555
+	x = (x | x>>1) & 0x3333333333333333
+	x = (x | x>>2) & 0x0F0F0F0F0F0F0F0F
+	x = (x | x>>4) & 0x00FF00FF00FF00FF
+	x = (x | x>>8) & 0x0000FFFF0000FFFF
+	x = (x | x>>16) & 0x00000000FFFFFFFF
+
+	return uint32(x)
+}
+
+func degToRad(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func radToDeg(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
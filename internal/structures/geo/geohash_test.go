@@ -0,0 +1,34 @@
+package geo_test
+
+import (
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/geo"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode(t *testing.T) {
+	hash := geo.Encode(13.361389, 38.115556)
+	assert.Equal(t, uint64(3479099956230698), hash)
+
+	lon, lat := geo.Decode(hash)
+	assert.InDelta(t, 13.36138933897018433, lon, 1e-12)
+	assert.InDelta(t, 38.11555639549629859, lat, 1e-12)
+
+	assert.Equal(t, "sqc8b49rny0", geo.String(hash))
+	assert.Equal(t, "sqdtr74hyu0", geo.String(geo.Encode(15.087269, 37.502669)))
+}
+
+func TestValid(t *testing.T) {
+	assert.True(t, geo.Valid(180, 85.05112878))
+	assert.False(t, geo.Valid(180.1, 0))
+	assert.False(t, geo.Valid(0, 86))
+}
+
+func TestDistance(t *testing.T) {
+	palermoLon, palermoLat := geo.Decode(geo.Encode(13.361389, 38.115556))
+	cataniaLon, cataniaLat := geo.Decode(geo.Encode(15.087269, 37.502669))
+
+	assert.InDelta(t, 166274.1516, geo.Distance(palermoLon, palermoLat, cataniaLon, cataniaLat), 1e-4)
+	assert.InDelta(t, 0, geo.Distance(1, 2, 1, 2), 1e-9)
+}
@@ -0,0 +1,231 @@
+package geo
+
+import (
+	"math"
+)
+
+// Shape is the area of a GEOSEARCH, a circle or a box centered on a point.
+// The sizes are in meters.
+type Shape struct {
+	Lon, Lat float64
+
+	Box           bool
+	Radius        float64
+	Width, Height float64
+}
+
+// ScoreRange is a range of geohashes, Min inclusive and Max exclusive.
+type ScoreRange struct {
+	Min, Max uint64
+}
+
+// Contains reports whether the point is in the shape, together with its
+// distance to the center.
+func (s *Shape) Contains(lon, lat float64) (float64, bool) {
+	if !s.Box {
+		distance := Distance(s.Lon, s.Lat, lon, lat)
+		return distance, distance <= s.Radius
+	}
+
+	// the latitude distance is cheaper, so it's checked first
+	if latDistance(lat, s.Lat) > s.Height/2 {
+		return 0, false
+	}
+
+	if Distance(lon, lat, s.Lon, lat) > s.Width/2 {
+		return 0, false
+	}
+
+	return Distance(s.Lon, s.Lat, lon, lat), true
+}
+
+// Ranges returns the ranges of geohashes covering the shape: the area
+// containing the center and its neighbors, at a precision such that they
+// cover the whole shape. Members in these ranges still have to be checked
+// with Contains.
+func (s *Shape) Ranges() []ScoreRange {
+	minLon, minLat, maxLon, maxLat := s.boundingBox()
+
+	radius := s.Radius
+	if s.Box {
+		radius = math.Sqrt((s.Width/2)*(s.Width/2) + (s.Height/2)*(s.Height/2))
+	}
+
+	step := estimateStep(radius, s.Lat)
+
+	hash := encode(lonRange, latRange, s.Lon, s.Lat, step)
+	neighbors := hash.neighbors()
+	center := decode(lonRange, latRange, hash)
+
+	// the neighbors may still be too small to reach the bounding box
+	northArea := decode(lonRange, latRange, neighbors[north])
+	southArea := decode(lonRange, latRange, neighbors[south])
+	eastArea := decode(lonRange, latRange, neighbors[east])
+	westArea := decode(lonRange, latRange, neighbors[west])
+
+	if step > 1 && (northArea.lat.max < maxLat || southArea.lat.min > minLat || eastArea.lon.max < maxLon || westArea.lon.min > minLon) {
+		step--
+		hash = encode(lonRange, latRange, s.Lon, s.Lat, step)
+		neighbors = hash.neighbors()
+		center = decode(lonRange, latRange, hash)
+	}
+
+	cells := append([]hashBits{hash}, neighbors[:]...)
+
+	// exclude the neighbors the shape doesn't reach
+	if step >= 2 {
+		if center.lat.min < minLat {
+			exclude(cells, south, southWest, southEast)
+		}
+
+		if center.lat.max > maxLat {
+			exclude(cells, north, northEast, northWest)
+		}
+
+		if center.lon.min < minLon {
+			exclude(cells, west, southWest, northWest)
+		}
+
+		if center.lon.max > maxLon {
+			exclude(cells, east, southEast, northEast)
+		}
+	}
+
+	ranges := []ScoreRange{}
+	for i, cell := range cells {
+		if cell.step == 0 {
+			continue
+		}
+
+		// with a low precision the neighbors can wrap around to the same cell
+		if i > 0 && cell == cells[i-1] {
+			continue
+		}
+
+		shift := 2 * (Step - cell.step)
+		ranges = append(ranges, ScoreRange{
+			Min: cell.bits << shift,
+			Max: (cell.bits + 1) << shift,
+		})
+	}
+
+	return ranges
+}
+
+// boundingBox returns the minimum and maximum coordinates of the shape.
+func (s *Shape) boundingBox() (float64, float64, float64, float64) {
+	width, height := s.Radius, s.Radius
+	if s.Box {
+		width, height = s.Width/2, s.Height/2
+	}
+
+	latDelta := radToDeg(height / EarthRadius)
+	lonDeltaTop := radToDeg(width / EarthRadius / math.Cos(degToRad(s.Lat+latDelta)))
+	lonDeltaBottom := radToDeg(width / EarthRadius / math.Cos(degToRad(s.Lat-latDelta)))
+
+	// the box is wider on the side closer to the equator
+	lonDelta := lonDeltaTop
+	if s.Lat < 0 {
+		lonDelta = lonDeltaBottom
+	}
+
+	return s.Lon - lonDelta, s.Lat - latDelta, s.Lon + lonDelta, s.Lat + latDelta
+}
+
+// estimateStep returns the precision at which an area is about the size of
+// the radius.
+func estimateStep(radius, lat float64) uint {
+	if radius == 0 {
+		return Step
+	}
+
+	step := 1
+	for radius < mercatorMax {
+		radius *= 2
+		step++
+	}
+
+	// the areas shrink towards the poles
+	step -= 2
+	if lat > 66 || lat < -66 {
+		step--
+		if lat > 80 || lat < -80 {
+			step--
+		}
+	}
+
+	if step < 1 {
+		return 1
+	}
+
+	if step > Step {
+		return Step
+	}
+
+	return uint(step)
+}
+
+// the neighbors of an area, by direction
+const (
+	north = iota
+	east
+	west
+	south
+	northEast
+	southEast
+	northWest
+	southWest
+)
+
+func (h hashBits) neighbors() [8]hashBits {
+	return [8]hashBits{
+		north:     h.move(0, 1),
+		east:      h.move(1, 0),
+		west:      h.move(-1, 0),
+		south:     h.move(0, -1),
+		northEast: h.move(1, 1),
+		southEast: h.move(1, -1),
+		northWest: h.move(-1, 1),
+		southWest: h.move(-1, -1),
+	}
+}
+
+// move returns the area next to this one, in the direction of dx and dy,
+// wrapping around the edges.
+func (h hashBits) move(dx, dy int) hashBits {
+	const (
+		oddBits  = 0xaaaaaaaaaaaaaaaa
+		evenBits = 0x5555555555555555
+	)
+
+	x := h.bits & oddBits
+	y := h.bits & evenBits
+
+	x = moveBits(x, dx, evenBits>>(64-h.step*2), oddBits>>(64-h.step*2))
+	y = moveBits(y, dy, oddBits>>(64-h.step*2), evenBits>>(64-h.step*2))
+
+	return hashBits{bits: x | y, step: h.step}
+}
+
+// moveBits adds d to the coordinate spread over mask. The other bits, zz,
+// are set so that the carries go through them.
+func moveBits(v uint64, d int, zz, mask uint64) uint64 {
+	switch {
+	case d > 0:
+		v += zz + 1
+	case d < 0:
+		v |= zz
+		v -= zz + 1
+	default:
+		return v
+	}
+
+	return v & mask
+}
+
+func exclude(cells []hashBits, directions ...int) {
+	for _, direction := range directions {
+		// the first cell is the center
+		cells[direction+1] = hashBits{}
+	}
+}
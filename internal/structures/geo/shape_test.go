@@ -0,0 +1,80 @@
+package geo_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/geo"
+	"github.com/stretchr/testify/assert"
+)
+
+func inRanges(ranges []geo.ScoreRange, hash uint64) bool {
+	for _, r := range ranges {
+		if hash >= r.Min && hash < r.Max {
+			return true
+		}
+	}
+
+	return false
+}
+
+func TestShape_Contains(t *testing.T) {
+	circle := &geo.Shape{Lon: 15, Lat: 37, Radius: 200000}
+
+	distance, ok := circle.Contains(15.087269, 37.502669)
+	assert.True(t, ok)
+	assert.InDelta(t, 56441.3, distance, 1)
+
+	_, ok = circle.Contains(13.361389, 38.115556)
+	assert.True(t, ok)
+
+	circle.Radius = 100000
+	_, ok = circle.Contains(13.361389, 38.115556)
+	assert.False(t, ok)
+
+	box := &geo.Shape{Lon: 15, Lat: 37, Box: true, Width: 400000, Height: 100000}
+	_, ok = box.Contains(13.361389, 37.2)
+	assert.True(t, ok)
+
+	_, ok = box.Contains(15, 38)
+	assert.False(t, ok)
+}
+
+// every point in the shape must be in the ranges to look at
+func TestShape_Ranges(t *testing.T) {
+	testCases := map[string]*geo.Shape{
+		"when shape is a small circle":   {Lon: 2.35, Lat: 48.85, Radius: 500},
+		"when shape is a big circle":     {Lon: -70, Lat: -30, Radius: 2000000},
+		"when shape is near a pole":      {Lon: 10, Lat: 82, Radius: 50000},
+		"when shape is a box":            {Lon: 179.9, Lat: 0, Box: true, Width: 30000, Height: 10000},
+		"when shape has a zero radius":   {Lon: 0, Lat: 0},
+		"when shape is a very large box": {Lon: 0, Lat: 0, Box: true, Width: 40000000, Height: 40000000},
+	}
+
+	for name, shape := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ranges := shape.Ranges()
+
+			for i := 0; i < 20000; i++ {
+				lon := geo.LonMin + rand.Float64()*(geo.LonMax-geo.LonMin)
+				lat := geo.LatMin + rand.Float64()*(geo.LatMax-geo.LatMin)
+
+				// most random points are far away, so look around the center too
+				if i%2 == 0 {
+					lon = shape.Lon + (rand.Float64()-0.5)*2*shape.Radius/50000
+					lat = shape.Lat + (rand.Float64()-0.5)*2*shape.Radius/100000
+					if !geo.Valid(lon, lat) {
+						continue
+					}
+				}
+
+				hash := geo.Encode(lon, lat)
+				lon, lat = geo.Decode(hash)
+
+				if _, ok := shape.Contains(lon, lat); ok {
+					assert.True(t, inRanges(ranges, hash), "point %v,%v is missed", lon, lat)
+				}
+			}
+		})
+	}
+}
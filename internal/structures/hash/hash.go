@@ -0,0 +1,273 @@
+package hash
+
+import (
+	"bytes"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/dict"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/listpack"
+)
+
+const (
+	EncodingListpack  = "listpack"
+	EncodingHashtable = "hashtable"
+)
+
+// Limits decide when a hash outgrows its compact encoding, with the semantics
+// of hash-max-listpack-entries and hash-max-listpack-value.
+type Limits struct {
+	MaxEntries int
+	MaxValue   int
+}
+
+// Hash maps fields to values. Small hashes are stored as a listpack of
+// field, value pairs, which is compact but takes O(n) to look a field up.
+// Once the hash gets past its limits it's converted to a hash table, and it's
+// never converted back.
+//
+// Fields may have an expiration time. Expired fields aren't removed on their
+// own, DeleteExpired has to be called before accessing the hash.
+type Hash struct {
+	lp   *listpack.Listpack
+	dict *dict.Dict[[]byte]
+
+	// expires holds the expiration times, in unix milliseconds, of the
+	// fields having one. nextExpire is the earliest of them, so checking for
+	// expired fields is cheap until then.
+	expires    map[string]int64
+	nextExpire int64
+}
+
+func New() *Hash {
+	return &Hash{lp: listpack.New()}
+}
+
+// Len is the number of fields in the hash.
+func (h *Hash) Len() int {
+	if h.dict != nil {
+		return h.dict.Len()
+	}
+
+	return h.lp.Len() / 2
+}
+
+// Encoding is the name of the encoding in use, as reported by OBJECT ENCODING.
+func (h *Hash) Encoding() string {
+	if h.dict != nil {
+		return EncodingHashtable
+	}
+
+	return EncodingListpack
+}
+
+// Clone returns a deep copy of the hash, expiration times included.
+func (h *Hash) Clone() *Hash {
+	clone := &Hash{nextExpire: h.nextExpire}
+
+	if h.dict != nil {
+		clone.dict = dict.New[[]byte]()
+		h.dict.ForEach(func(field string, value []byte) bool {
+			clone.dict.Set(field, append([]byte{}, value...))
+			return true
+		})
+	} else {
+		clone.lp = h.lp.Clone()
+	}
+
+	if h.expires != nil {
+		clone.expires = make(map[string]int64, len(h.expires))
+		for field, at := range h.expires {
+			clone.expires[field] = at
+		}
+	}
+
+	return clone
+}
+
+func (h *Hash) Get(field []byte) ([]byte, bool) {
+	if h.dict != nil {
+		return h.dict.Get(string(field))
+	}
+
+	p := h.find(field)
+	if p == -1 {
+		return nil, false
+	}
+
+	return h.lp.Get(h.lp.Next(p)), true
+}
+
+// Set adds or updates a field and returns true if the field is new. Updating
+// a field removes its expiration time.
+func (h *Hash) Set(field, value []byte, limits Limits) bool {
+	h.Persist(field)
+
+	if h.dict == nil && (len(field) > limits.MaxValue || len(value) > limits.MaxValue) {
+		h.convert()
+	}
+
+	if h.dict != nil {
+		return h.dict.Set(string(field), append([]byte{}, value...))
+	}
+
+	if p := h.find(field); p != -1 {
+		h.lp.Replace(h.lp.Next(p), value)
+		return false
+	}
+
+	h.lp.Append(field)
+	h.lp.Append(value)
+
+	if h.Len() > limits.MaxEntries {
+		h.convert()
+	}
+
+	return true
+}
+
+// Delete removes a field and returns true if it existed.
+func (h *Hash) Delete(field []byte) bool {
+	h.Persist(field)
+
+	if h.dict != nil {
+		return h.dict.Delete(string(field))
+	}
+
+	p := h.find(field)
+	if p == -1 {
+		return false
+	}
+
+	h.lp.DeleteRange(p, 2)
+
+	return true
+}
+
+// ForEach calls fn for every field until it returns false. The hash must not
+// be modified meanwhile.
+func (h *Hash) ForEach(fn func(field, value []byte) bool) {
+	if h.dict != nil {
+		h.dict.ForEach(func(field string, value []byte) bool {
+			return fn([]byte(field), value)
+		})
+
+		return
+	}
+
+	for p := h.lp.First(); p != -1; p = h.lp.Next(h.lp.Next(p)) {
+		if !fn(h.lp.Get(p), h.lp.Get(h.lp.Next(p))) {
+			return
+		}
+	}
+}
+
+// Scan calls fn for some of the fields from the cursor, 0 to start the
+// iteration, and returns the cursor to continue from, 0 once every field has
+// been visited. The fields of a listpack are all visited at once. See
+// dict.Dict.Scan for the guarantees.
+func (h *Hash) Scan(cursor uint64, fn func(field, value []byte)) uint64 {
+	if h.dict != nil {
+		return h.dict.Scan(cursor, func(field string, value []byte) {
+			fn([]byte(field), value)
+		})
+	}
+
+	for p := h.lp.First(); p != -1; p = h.lp.Next(h.lp.Next(p)) {
+		fn(h.lp.Get(p), h.lp.Get(h.lp.Next(p)))
+	}
+
+	return 0
+}
+
+// SetExpire sets the expiration time of an existing field, in unix
+// milliseconds. It returns false if the field doesn't exist.
+func (h *Hash) SetExpire(field []byte, at int64) bool {
+	if _, exists := h.Get(field); !exists {
+		return false
+	}
+
+	if h.expires == nil {
+		h.expires = map[string]int64{}
+	}
+
+	h.expires[string(field)] = at
+
+	if len(h.expires) == 1 || at < h.nextExpire {
+		h.nextExpire = at
+	}
+
+	return true
+}
+
+// Expire returns the expiration time of the field, in unix milliseconds. The
+// second return value is false if the field has none.
+func (h *Hash) Expire(field []byte) (int64, bool) {
+	at, exists := h.expires[string(field)]
+	return at, exists
+}
+
+// Persist removes the expiration time of the field, and returns true if it
+// had one.
+func (h *Hash) Persist(field []byte) bool {
+	if _, exists := h.expires[string(field)]; !exists {
+		return false
+	}
+
+	delete(h.expires, string(field))
+
+	if len(h.expires) == 0 {
+		h.expires = nil
+	}
+
+	return true
+}
+
+// DeleteExpired removes the fields which expired by now, given in unix
+// milliseconds, and returns them.
+func (h *Hash) DeleteExpired(now int64) [][]byte {
+	if h.expires == nil || now < h.nextExpire {
+		return nil
+	}
+
+	expired := [][]byte{}
+	next := int64(0)
+
+	for field, at := range h.expires {
+		if at <= now {
+			expired = append(expired, []byte(field))
+		} else if next == 0 || at < next {
+			next = at
+		}
+	}
+
+	for _, field := range expired {
+		h.Delete(field)
+	}
+
+	h.nextExpire = next
+
+	return expired
+}
+
+// find returns the offset of the field in the listpack, or -1 if it doesn't
+// exist.
+func (h *Hash) find(field []byte) int {
+	for p := h.lp.First(); p != -1; p = h.lp.Next(h.lp.Next(p)) {
+		if bytes.Equal(h.lp.Get(p), field) {
+			return p
+		}
+	}
+
+	return -1
+}
+
+func (h *Hash) convert() {
+	fields := dict.New[[]byte]()
+
+	h.ForEach(func(field, value []byte) bool {
+		fields.Set(string(field), value)
+		return true
+	})
+
+	h.dict = fields
+	h.lp = nil
+}
@@ -0,0 +1,140 @@
+package hash_test
+
+import (
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/hash"
+	"github.com/stretchr/testify/assert"
+)
+
+var limits = hash.Limits{MaxEntries: 4, MaxValue: 8}
+
+func fields(h *hash.Hash) []string {
+	res := []string{}
+
+	h.ForEach(func(field, value []byte) bool {
+		res = append(res, string(field)+"="+string(value))
+		return true
+	})
+	sort.Strings(res)
+
+	return res
+}
+
+func TestSet(t *testing.T) {
+	h := hash.New()
+
+	assert.True(t, h.Set([]byte("a"), []byte("1"), limits))
+	assert.True(t, h.Set([]byte("b"), []byte("2"), limits))
+	assert.False(t, h.Set([]byte("a"), []byte("10"), limits))
+
+	value, found := h.Get([]byte("a"))
+	assert.True(t, found)
+	assert.Equal(t, "10", string(value))
+
+	_, found = h.Get([]byte("c"))
+	assert.False(t, found)
+
+	assert.Equal(t, 2, h.Len())
+	assert.Equal(t, hash.EncodingListpack, h.Encoding())
+	assert.Equal(t, []string{"a=10", "b=2"}, fields(h))
+}
+
+func TestDelete(t *testing.T) {
+	h := hash.New()
+	h.Set([]byte("a"), []byte("1"), limits)
+	h.Set([]byte("b"), []byte("2"), limits)
+
+	assert.True(t, h.Delete([]byte("a")))
+	assert.False(t, h.Delete([]byte("a")))
+	assert.Equal(t, []string{"b=2"}, fields(h))
+}
+
+func TestConversion(t *testing.T) {
+	t.Run("when there are too many entries", func(t *testing.T) {
+		h := hash.New()
+
+		for _, field := range []string{"a", "b", "c", "d"} {
+			h.Set([]byte(field), []byte("1"), limits)
+		}
+		assert.Equal(t, hash.EncodingListpack, h.Encoding())
+
+		h.Set([]byte("e"), []byte("1"), limits)
+		assert.Equal(t, hash.EncodingHashtable, h.Encoding())
+		assert.Equal(t, []string{"a=1", "b=1", "c=1", "d=1", "e=1"}, fields(h))
+	})
+
+	t.Run("when a value is too long", func(t *testing.T) {
+		h := hash.New()
+		h.Set([]byte("a"), []byte("1"), limits)
+		h.Set([]byte("b"), []byte(strings.Repeat("x", 9)), limits)
+
+		assert.Equal(t, hash.EncodingHashtable, h.Encoding())
+		assert.Equal(t, 2, h.Len())
+
+		assert.True(t, h.Delete([]byte("a")))
+		assert.Equal(t, []string{"b=xxxxxxxxx"}, fields(h))
+	})
+}
+
+func TestExpire(t *testing.T) {
+	h := hash.New()
+	h.Set([]byte("a"), []byte("1"), limits)
+	h.Set([]byte("b"), []byte("2"), limits)
+	h.Set([]byte("c"), []byte("3"), limits)
+
+	assert.True(t, h.SetExpire([]byte("a"), 100))
+	assert.True(t, h.SetExpire([]byte("b"), 200))
+	assert.False(t, h.SetExpire([]byte("missing"), 100))
+
+	at, found := h.Expire([]byte("b"))
+	assert.True(t, found)
+	assert.Equal(t, int64(200), at)
+
+	assert.Empty(t, h.DeleteExpired(99))
+
+	expired := h.DeleteExpired(150)
+	assert.Equal(t, [][]byte{[]byte("a")}, expired)
+	assert.Equal(t, []string{"b=2", "c=3"}, fields(h))
+
+	// updating a field removes its expiration time
+	h.Set([]byte("b"), []byte("20"), limits)
+	_, found = h.Expire([]byte("b"))
+	assert.False(t, found)
+
+	assert.Empty(t, h.DeleteExpired(300))
+	assert.Equal(t, []string{"b=20", "c=3"}, fields(h))
+
+	assert.True(t, h.SetExpire([]byte("c"), 100))
+	assert.True(t, h.Persist([]byte("c")))
+	assert.False(t, h.Persist([]byte("c")))
+	assert.Empty(t, h.DeleteExpired(300))
+}
+
+func TestClone(t *testing.T) {
+	for name, count := range map[string]int{"when listpack": 2, "when hashtable": 6} {
+		t.Run(name, func(t *testing.T) {
+			h := hash.New()
+			for i := 0; i < count; i++ {
+				h.Set([]byte{'a' + byte(i)}, []byte("1"), limits)
+			}
+
+			h.SetExpire([]byte("a"), 1000)
+
+			clone := h.Clone()
+			h.Set([]byte("a"), []byte("2"), limits)
+			h.Persist([]byte("a"))
+
+			assert.Equal(t, count, clone.Len())
+
+			value, _ := clone.Get([]byte("a"))
+			assert.Equal(t, "1", string(value))
+
+			at, found := clone.Expire([]byte("a"))
+			assert.True(t, found)
+			assert.Equal(t, int64(1000), at)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package hll
+
+// The dense encoding packs the 6 bits registers, the least significant bits
+// first.
+
+func denseGet(registers []byte, index int) uint8 {
+	pos := index * registerBits
+	b, fb := pos/8, uint(pos&7)
+
+	value := uint(registers[b]) >> fb
+	if b+1 < len(registers) {
+		value |= uint(registers[b+1]) << (8 - fb)
+	}
+
+	return uint8(value & registerMax)
+}
+
+func denseSet(registers []byte, index int, value uint8) {
+	pos := index * registerBits
+	b, fb := pos/8, uint(pos&7)
+
+	registers[b] &^= byte(registerMax << fb)
+	registers[b] |= byte(uint(value) << fb)
+
+	if b+1 < len(registers) {
+		registers[b+1] &^= byte(registerMax >> (8 - fb))
+		registers[b+1] |= byte(uint(value) >> (8 - fb))
+	}
+}
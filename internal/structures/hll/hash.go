@@ -0,0 +1,58 @@
+package hll
+
+import "encoding/binary"
+
+const hashSeed = 0xadc83b19
+
+// hashElement returns the register of the element and the value it counts
+// for, the position of the first set bit of the rest of its hash.
+func hashElement(element []byte) (int, uint8) {
+	hash := murmurHash64A(element, hashSeed)
+	index := int(hash & (NumRegisters - 1))
+
+	// the bit q makes sure the loop ends
+	hash >>= p
+	hash |= 1 << q
+
+	count := uint8(1)
+	for bit := uint64(1); hash&bit == 0; bit <<= 1 {
+		count++
+	}
+
+	return index, count
+}
+
+// murmurHash64A is the MurmurHash2 variant used by Redis, reading the input
+// as little endian words whatever the platform.
+func murmurHash64A(key []byte, seed uint64) uint64 {
+	const m = 0xc6a4a7935bd1e995
+	const r = 47
+
+	h := seed ^ (uint64(len(key)) * m)
+
+	for len(key) >= 8 {
+		k := binary.LittleEndian.Uint64(key)
+		k *= m
+		k ^= k >> r
+		k *= m
+
+		h ^= k
+		h *= m
+
+		key = key[8:]
+	}
+
+	if len(key) > 0 {
+		for i := len(key) - 1; i >= 0; i-- {
+			h ^= uint64(key[i]) << (8 * i)
+		}
+
+		h *= m
+	}
+
+	h ^= h >> r
+	h *= m
+	h ^= h >> r
+
+	return h
+}
@@ -0,0 +1,146 @@
+package hll
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+// The layout is the one of Redis, so that HyperLogLogs can be exchanged with
+// it as plain strings: a 16 bytes header followed by the registers, in the
+// dense or sparse encoding.
+//
+// The header holds the "HYLL" magic, the encoding, 3 unused bytes and the
+// cached cardinality as a little endian integer, whose most significant bit
+// is set when the cache is invalid.
+const (
+	p = 14 // the number of bits of the hash used to select a register
+	q = 64 - p
+
+	NumRegisters = 1 << p
+
+	registerBits = 6
+	registerMax  = 1<<registerBits - 1
+
+	headerSize = 16
+	denseSize  = headerSize + (NumRegisters*registerBits+7)/8
+
+	encDense  = 0
+	encSparse = 1
+)
+
+var magic = []byte("HYLL")
+
+var (
+	ErrInvalid   = errors.New("WRONGTYPE Key is not a valid HyperLogLog string value.")
+	ErrCorrupted = errors.New("INVALIDOBJ Corrupted HLL object detected")
+)
+
+// New returns an empty HyperLogLog, in the sparse encoding.
+func New() []byte {
+	b := make([]byte, headerSize, headerSize+2)
+	copy(b, magic)
+	b[4] = encSparse
+
+	return appendXZero(b, NumRegisters)
+}
+
+// Validate checks that b looks like a HyperLogLog. A sparse one may still
+// turn out to be corrupted when its registers are read.
+func Validate(b []byte) error {
+	if len(b) < headerSize || !bytes.Equal(b[:4], magic) {
+		return ErrInvalid
+	}
+
+	switch b[4] {
+	case encSparse:
+		return nil
+	case encDense:
+		if len(b) != denseSize {
+			return ErrInvalid
+		}
+
+		return nil
+	}
+
+	return ErrInvalid
+}
+
+// IsSparse reports whether the HyperLogLog uses the sparse encoding.
+func IsSparse(b []byte) bool {
+	return b[4] == encSparse
+}
+
+// Add adds the elements to the HyperLogLog and returns it, since the sparse
+// encoding is rewritten, along with whether a register was updated. The
+// sparse encoding is turned into the dense one when a register doesn't fit
+// in it anymore or when it grows over sparseMaxBytes.
+func Add(b []byte, elements [][]byte, sparseMaxBytes int) ([]byte, bool, error) {
+	if !IsSparse(b) {
+		updated := false
+		for _, element := range elements {
+			index, count := hashElement(element)
+			if denseGet(b[headerSize:], index) < count {
+				denseSet(b[headerSize:], index, count)
+				updated = true
+			}
+		}
+
+		if updated {
+			invalidateCache(b)
+		}
+
+		return b, updated, nil
+	}
+
+	raw, err := Decode(b)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !raw.Add(elements) {
+		return b, false, nil
+	}
+
+	return raw.Encode(false, sparseMaxBytes), true, nil
+}
+
+// Count returns the estimated cardinality of the HyperLogLog, caching it in
+// its header.
+func Count(b []byte) (uint64, error) {
+	if card := binary.LittleEndian.Uint64(b[8:headerSize]); card&(1<<63) == 0 {
+		return card, nil
+	}
+
+	raw, err := Decode(b)
+	if err != nil {
+		return 0, err
+	}
+
+	card := raw.Count()
+	binary.LittleEndian.PutUint64(b[8:headerSize], card)
+
+	return card, nil
+}
+
+// ToDense returns the HyperLogLog in the dense encoding, keeping its cached
+// cardinality.
+func ToDense(b []byte) ([]byte, error) {
+	if !IsSparse(b) {
+		return b, nil
+	}
+
+	raw, err := Decode(b)
+	if err != nil {
+		return nil, err
+	}
+
+	dense := raw.Encode(true, 0)
+	copy(dense[8:headerSize], b[8:headerSize])
+
+	return dense, nil
+}
+
+func invalidateCache(b []byte) {
+	b[15] |= 1 << 7
+}
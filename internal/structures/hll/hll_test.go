@@ -0,0 +1,177 @@
+package hll
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func elements(from, to int) [][]byte {
+	res := make([][]byte, 0, to-from)
+	for i := from; i < to; i++ {
+		res = append(res, []byte(strconv.Itoa(i)))
+	}
+
+	return res
+}
+
+func TestNew(t *testing.T) {
+	b := New()
+
+	require.NoError(t, Validate(b))
+	assert.True(t, IsSparse(b))
+
+	decoded, err := DescribeSparse(b)
+	require.NoError(t, err)
+	assert.Equal(t, "Z:16384", decoded)
+
+	count, err := Count(b)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), count)
+}
+
+func TestValidate(t *testing.T) {
+	testCases := map[string]struct {
+		b []byte
+	}{
+		"when too short":           {b: []byte("HYLL")},
+		"when magic is wrong":      {b: append([]byte("HYLX"), New()[4:]...)},
+		"when encoding is unknown": {b: append([]byte("HYLL\x02"), New()[5:]...)},
+		"when dense size is wrong": {b: append([]byte("HYLL\x00"), New()[5:]...)},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, Validate(tc.b), ErrInvalid)
+		})
+	}
+}
+
+func TestAdd(t *testing.T) {
+	b, updated, err := Add(New(), [][]byte{[]byte("a"), []byte("b"), []byte("c")}, 3000)
+	require.NoError(t, err)
+	assert.True(t, updated)
+	assert.True(t, IsSparse(b))
+
+	b, updated, err = Add(b, [][]byte{[]byte("a")}, 3000)
+	require.NoError(t, err)
+	assert.False(t, updated)
+
+	count, err := Count(b)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(3), count)
+
+	// the sparse encoding is left once it's too long
+	b, _, err = Add(b, elements(0, 5000), 3000)
+	require.NoError(t, err)
+	assert.False(t, IsSparse(b))
+	assert.Equal(t, denseSize, len(b))
+}
+
+func TestCount_SparseAndDenseAgree(t *testing.T) {
+	sparse, dense := New(), New()
+
+	dense, err := ToDense(dense)
+	require.NoError(t, err)
+
+	for _, n := range []int{10, 100, 1000, 10000, 100000} {
+		var err error
+
+		sparse, _, err = Add(sparse, elements(0, n), math.MaxInt32)
+		require.NoError(t, err)
+
+		dense, _, err = Add(dense, elements(0, n), 0)
+		require.NoError(t, err)
+
+		sparseCount, err := Count(sparse)
+		require.NoError(t, err)
+
+		denseCount, err := Count(dense)
+		require.NoError(t, err)
+
+		assert.Equal(t, denseCount, sparseCount)
+
+		maxErr := math.Ceil(1.04 / math.Sqrt(NumRegisters) * 6 * float64(n))
+		assert.LessOrEqual(t, math.Abs(float64(denseCount)-float64(n)), maxErr, "cardinality %d", n)
+	}
+}
+
+func TestCount_Cache(t *testing.T) {
+	b, _, err := Add(New(), elements(0, 10), 3000)
+	require.NoError(t, err)
+	assert.NotZero(t, b[15]&0x80)
+
+	count, err := Count(b)
+	require.NoError(t, err)
+	assert.Zero(t, b[15]&0x80)
+
+	// the cached value is trusted
+	b[8]++
+	cached, err := Count(b)
+	require.NoError(t, err)
+	assert.Equal(t, count+1, cached)
+}
+
+func TestDense_Registers(t *testing.T) {
+	registers := make([]byte, denseSize-headerSize)
+	values := make([]uint8, NumRegisters)
+
+	for i := range values {
+		values[i] = uint8(rand.Intn(registerMax + 1))
+		denseSet(registers, i, values[i])
+	}
+
+	for i, value := range values {
+		assert.Equal(t, value, denseGet(registers, i), "register %d", i)
+	}
+}
+
+func TestMerge_Corrupted(t *testing.T) {
+	testCases := map[string]struct {
+		b []byte
+	}{
+		"when registers are missing":     {b: append(New()[:headerSize], 0x7f, 0xfe)},
+		"when there are extra registers": {b: append(New(), 0x00)},
+		"when an opcode is truncated":    {b: append(New()[:headerSize], 0x7f)},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.ErrorIs(t, NewRaw().Merge(tc.b), ErrCorrupted)
+		})
+	}
+}
+
+func TestToDense(t *testing.T) {
+	sparse, _, err := Add(New(), elements(0, 100), 3000)
+	require.NoError(t, err)
+
+	_, err = Count(sparse)
+	require.NoError(t, err)
+
+	dense, err := ToDense(sparse)
+	require.NoError(t, err)
+	assert.False(t, IsSparse(dense))
+	// the cached cardinality is kept
+	assert.Equal(t, sparse[8:headerSize], dense[8:headerSize])
+
+	sparseRaw, err := Decode(sparse)
+	require.NoError(t, err)
+
+	denseRaw, err := Decode(dense)
+	require.NoError(t, err)
+
+	assert.Equal(t, sparseRaw, denseRaw)
+}
+
+func TestSelfTest(t *testing.T) {
+	if testing.Short() {
+		t.Skip("adds millions of elements")
+	}
+
+	assert.NoError(t, SelfTest())
+}
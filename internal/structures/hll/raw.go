@@ -0,0 +1,145 @@
+package hll
+
+import "math"
+
+// alphaInf is the bias correction constant of the estimator, 0.5/ln(2).
+const alphaInf = 0.721347520444481703680
+
+// Raw is a HyperLogLog with a byte per register, used to add elements to a
+// sparse HyperLogLog and to merge several of them.
+type Raw []uint8
+
+func NewRaw() Raw {
+	return make(Raw, NumRegisters)
+}
+
+// Decode returns the registers of the HyperLogLog, whatever its encoding.
+func Decode(b []byte) (Raw, error) {
+	raw := NewRaw()
+
+	return raw, raw.Merge(b)
+}
+
+// Add adds the elements and returns whether a register was updated.
+func (r Raw) Add(elements [][]byte) bool {
+	updated := false
+	for _, element := range elements {
+		index, count := hashElement(element)
+		if r[index] < count {
+			r[index] = count
+			updated = true
+		}
+	}
+
+	return updated
+}
+
+// Merge sets every register to the maximum of its value and the one of the
+// HyperLogLog b.
+func (r Raw) Merge(b []byte) error {
+	if !IsSparse(b) {
+		for i := range r {
+			if value := denseGet(b[headerSize:], i); value > r[i] {
+				r[i] = value
+			}
+		}
+
+		return nil
+	}
+
+	i := 0
+
+	return sparseOps(b[headerSize:], func(op opcode, value uint8, runLen int) {
+		for end := i + runLen; i < end; i++ {
+			if value > r[i] {
+				r[i] = value
+			}
+		}
+	})
+}
+
+// Encode returns the registers as a HyperLogLog, with an invalid cached
+// cardinality. The sparse encoding is used unless dense is set, a register
+// doesn't fit in it or it would be longer than sparseMaxBytes.
+func (r Raw) Encode(dense bool, sparseMaxBytes int) []byte {
+	header := make([]byte, headerSize)
+	copy(header, magic)
+	invalidateCache(header)
+
+	if !dense {
+		header[4] = encSparse
+
+		if b, ok := appendSparse(header, r); ok && len(b) <= sparseMaxBytes {
+			return b
+		}
+	}
+
+	b := make([]byte, denseSize)
+	copy(b, header)
+	b[4] = encDense
+
+	for i, value := range r {
+		denseSet(b[headerSize:], i, value)
+	}
+
+	return b
+}
+
+// Count returns the estimated cardinality, with the estimator of Otmar Ertl
+// used by Redis.
+func (r Raw) Count() uint64 {
+	// registers of a dense HyperLogLog can hold values up to 63, even though
+	// values over q+1 can't be produced by adding elements
+	var histogram [registerMax + 1]int
+	for _, value := range r {
+		histogram[value]++
+	}
+
+	m := float64(NumRegisters)
+
+	z := m * tau((m-float64(histogram[q+1]))/m)
+	for j := q; j >= 1; j-- {
+		z += float64(histogram[j])
+		z *= 0.5
+	}
+
+	z += m * sigma(float64(histogram[0])/m)
+
+	return uint64(math.Round(alphaInf * m * m / z))
+}
+
+func sigma(x float64) float64 {
+	if x == 1 {
+		return math.Inf(1)
+	}
+
+	y, z := 1.0, x
+	for {
+		x *= x
+		previous := z
+		z += x * y
+		y += y
+
+		if previous == z {
+			return z
+		}
+	}
+}
+
+func tau(x float64) float64 {
+	if x == 0 || x == 1 {
+		return 0
+	}
+
+	y, z := 1.0, 1-x
+	for {
+		x = math.Sqrt(x)
+		previous := z
+		y *= 0.5
+		z -= math.Pow(1-x, 2) * y
+
+		if previous == z {
+			return z / 3
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package hll
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+const (
+	selfTestMaxCardinality = 10000000
+	selfTestSparseMaxBytes = 3000
+	selfTestBatchSize      = 10000
+)
+
+// SelfTest checks the access to the registers of the dense encoding, and
+// that the sparse and dense encodings agree on cardinalities which are
+// within the expected error, as PFSELFTEST does.
+func SelfTest() error {
+	registers := make([]byte, denseSize-headerSize)
+	values := make([]uint8, NumRegisters)
+
+	for round := 0; round < 1000; round++ {
+		for i := range values {
+			values[i] = uint8(rand.Intn(registerMax + 1))
+			denseSet(registers, i, values[i])
+		}
+
+		for i, value := range values {
+			if got := denseGet(registers, i); got != value {
+				return fmt.Errorf("TESTFAILED Register error at %d: %d != %d", i, got, value)
+			}
+		}
+	}
+
+	sparse := New()
+	dense, _ := ToDense(New())
+
+	relErr := 1.04 / math.Sqrt(NumRegisters)
+	batch := make([][]byte, 0, selfTestBatchSize)
+
+	for checkpoint, next := 1, 1; checkpoint <= selfTestMaxCardinality; checkpoint *= 10 {
+		// the elements are added in batches, since every addition rewrites
+		// the sparse encoding
+		for ; next <= checkpoint; next++ {
+			element := make([]byte, 8)
+			binary.LittleEndian.PutUint64(element, uint64(next))
+			batch = append(batch, element)
+
+			if len(batch) == cap(batch) || next == checkpoint {
+				sparse, _, _ = Add(sparse, batch, selfTestSparseMaxBytes)
+				dense, _, _ = Add(dense, batch, 0)
+				batch = batch[:0]
+			}
+		}
+
+		if checkpoint < selfTestSparseMaxBytes/2 && !IsSparse(sparse) {
+			return fmt.Errorf("TESTFAILED sparse encoding not used")
+		}
+
+		denseCount, _ := Count(dense)
+		sparseCount, _ := Count(sparse)
+
+		if denseCount != sparseCount {
+			return fmt.Errorf("TESTFAILED dense/sparse disagree: %d != %d", denseCount, sparseCount)
+		}
+
+		maxErr := uint64(math.Ceil(relErr * 6 * float64(checkpoint)))
+		if checkpoint == 10 {
+			maxErr = 1
+		}
+
+		absErr := int64(denseCount) - int64(checkpoint)
+		if absErr < 0 {
+			absErr = -absErr
+		}
+
+		if uint64(absErr) > maxErr {
+			return fmt.Errorf("TESTFAILED Too big error. card:%d abserr:%d", checkpoint, absErr)
+		}
+	}
+
+	return nil
+}
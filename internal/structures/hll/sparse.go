@@ -0,0 +1,136 @@
+package hll
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The sparse encoding is a sequence of opcodes describing runs of registers:
+//
+//	00xxxxxx           ZERO: xxxxxx+1 registers set to 0, up to 64
+//	01xxxxxx yyyyyyyy  XZERO: xxxxxxyyyyyyyy+1 registers set to 0, up to 16384
+//	1vvvvvxx           VAL: xx+1 registers set to vvvvv+1, up to 4 registers
+//	                   and a value of 32
+const (
+	sparseZeroMaxLen  = 64
+	sparseXZeroMaxLen = NumRegisters
+	sparseValMaxValue = 32
+	sparseValMaxLen   = 4
+)
+
+type opcode int
+
+const (
+	opZero opcode = iota
+	opXZero
+	opVal
+)
+
+// sparseOps calls fn for every opcode of the sparse registers, with the
+// value and number of the registers it covers. It fails if the opcodes
+// don't cover exactly all the registers.
+func sparseOps(registers []byte, fn func(op opcode, value uint8, runLen int)) error {
+	total := 0
+
+	for i := 0; i < len(registers); {
+		c := registers[i]
+
+		var op opcode
+		var value uint8
+		var runLen int
+
+		switch {
+		case c&0xc0 == 0x00:
+			op, runLen = opZero, int(c&0x3f)+1
+			i++
+		case c&0xc0 == 0x40:
+			if i+1 >= len(registers) {
+				return ErrCorrupted
+			}
+
+			op, runLen = opXZero, (int(c&0x3f)<<8|int(registers[i+1]))+1
+			i += 2
+		default:
+			op, value, runLen = opVal, (c>>2)&0x1f+1, int(c&0x03)+1
+			i++
+		}
+
+		if total += runLen; total > NumRegisters {
+			return ErrCorrupted
+		}
+
+		fn(op, value, runLen)
+	}
+
+	if total != NumRegisters {
+		return ErrCorrupted
+	}
+
+	return nil
+}
+
+// DescribeSparse returns the opcodes of a sparse HyperLogLog as PFDEBUG
+// DECODE shows them.
+func DescribeSparse(b []byte) (string, error) {
+	ops := []string{}
+
+	err := sparseOps(b[headerSize:], func(op opcode, value uint8, runLen int) {
+		switch op {
+		case opZero:
+			ops = append(ops, fmt.Sprintf("z:%d", runLen))
+		case opXZero:
+			ops = append(ops, fmt.Sprintf("Z:%d", runLen))
+		case opVal:
+			ops = append(ops, fmt.Sprintf("v:%d,%d", value, runLen))
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join(ops, " "), nil
+}
+
+// appendSparse appends the sparse encoding of the registers to b, or returns
+// false if a register doesn't fit in it.
+func appendSparse(b []byte, registers []uint8) ([]byte, bool) {
+	for i := 0; i < len(registers); {
+		value := registers[i]
+
+		runLen := 1
+		for i+runLen < len(registers) && registers[i+runLen] == value {
+			runLen++
+		}
+
+		i += runLen
+
+		if value == 0 {
+			b = appendXZero(b, runLen)
+			continue
+		}
+
+		if value > sparseValMaxValue {
+			return nil, false
+		}
+
+		for ; runLen > 0; runLen -= sparseValMaxLen {
+			n := runLen
+			if n > sparseValMaxLen {
+				n = sparseValMaxLen
+			}
+
+			b = append(b, 0x80|(value-1)<<2|byte(n-1))
+		}
+	}
+
+	return b, true
+}
+
+// appendXZero appends a run of zero registers, with the shortest opcode.
+func appendXZero(b []byte, runLen int) []byte {
+	if runLen <= sparseZeroMaxLen {
+		return append(b, byte(runLen-1))
+	}
+
+	return append(b, 0x40|byte((runLen-1)>>8), byte(runLen-1))
+}
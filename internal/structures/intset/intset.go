@@ -0,0 +1,158 @@
+package intset
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+)
+
+// The encodings are the number of bytes used by every integer
+const (
+	encInt16 = 2
+	encInt32 = 4
+	encInt64 = 8
+)
+
+// Intset is a sorted set of integers stored in a byte slice, with the same
+// layout as the intsets of Redis: all the integers use the smallest width able
+// to hold every one of them, and the whole set is upgraded to a wider
+// encoding when an integer doesn't fit anymore.
+type Intset struct {
+	encoding int
+	contents []byte
+}
+
+func New() *Intset {
+	return &Intset{encoding: encInt16}
+}
+
+// Len is the number of integers in the set.
+func (is *Intset) Len() int {
+	return len(is.contents) / is.encoding
+}
+
+// Size is the number of bytes used by the integers.
+func (is *Intset) Size() int {
+	return len(is.contents)
+}
+
+// Clone returns a deep copy of the intset.
+func (is *Intset) Clone() *Intset {
+	contents := make([]byte, len(is.contents))
+	copy(contents, is.contents)
+
+	return &Intset{encoding: is.encoding, contents: contents}
+}
+
+// Get returns the integer at the given position, integers being sorted in
+// ascending order.
+func (is *Intset) Get(pos int) int64 {
+	return is.get(pos, is.encoding)
+}
+
+// Find reports whether the value is in the set.
+func (is *Intset) Find(value int64) bool {
+	if valueEncoding(value) > is.encoding {
+		return false
+	}
+
+	_, found := is.search(value)
+
+	return found
+}
+
+// Add adds the value and returns false if it was already in the set.
+func (is *Intset) Add(value int64) bool {
+	if enc := valueEncoding(value); enc > is.encoding {
+		is.upgrade(enc)
+	}
+
+	pos, found := is.search(value)
+	if found {
+		return false
+	}
+
+	is.contents = append(is.contents, make([]byte, is.encoding)...)
+	copy(is.contents[(pos+1)*is.encoding:], is.contents[pos*is.encoding:])
+	is.set(pos, value)
+
+	return true
+}
+
+// Remove removes the value and returns false if it wasn't in the set.
+func (is *Intset) Remove(value int64) bool {
+	if valueEncoding(value) > is.encoding {
+		return false
+	}
+
+	pos, found := is.search(value)
+	if !found {
+		return false
+	}
+
+	is.contents = append(is.contents[:pos*is.encoding], is.contents[(pos+1)*is.encoding:]...)
+
+	return true
+}
+
+// search returns the position of the value, or the position where it would
+// be inserted if it's not in the set.
+func (is *Intset) search(value int64) (int, bool) {
+	pos := sort.Search(is.Len(), func(i int) bool {
+		return is.Get(i) >= value
+	})
+
+	return pos, pos < is.Len() && is.Get(pos) == value
+}
+
+// upgrade rewrites the set with a wider encoding.
+func (is *Intset) upgrade(encoding int) {
+	length := is.Len()
+	previous := is.encoding
+
+	is.encoding = encoding
+	is.contents = append(is.contents, make([]byte, length*(encoding-previous))...)
+
+	// going backwards so that the values which aren't moved yet aren't
+	// overwritten
+	for i := length - 1; i >= 0; i-- {
+		is.set(i, is.get(i, previous))
+	}
+}
+
+func (is *Intset) get(pos int, encoding int) int64 {
+	buf := is.contents[pos*encoding:]
+
+	switch encoding {
+	case encInt16:
+		return int64(int16(binary.LittleEndian.Uint16(buf)))
+	case encInt32:
+		return int64(int32(binary.LittleEndian.Uint32(buf)))
+	}
+
+	return int64(binary.LittleEndian.Uint64(buf))
+}
+
+func (is *Intset) set(pos int, value int64) {
+	buf := is.contents[pos*is.encoding:]
+
+	switch is.encoding {
+	case encInt16:
+		binary.LittleEndian.PutUint16(buf, uint16(value))
+	case encInt32:
+		binary.LittleEndian.PutUint32(buf, uint32(value))
+	default:
+		binary.LittleEndian.PutUint64(buf, uint64(value))
+	}
+}
+
+func valueEncoding(value int64) int {
+	switch {
+	case value < math.MinInt32 || value > math.MaxInt32:
+		return encInt64
+	case value < math.MinInt16 || value > math.MaxInt16:
+		return encInt32
+	}
+
+	return encInt16
+}
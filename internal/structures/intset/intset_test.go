@@ -0,0 +1,71 @@
+package intset_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/intset"
+	"github.com/stretchr/testify/assert"
+)
+
+func values(is *intset.Intset) []int64 {
+	res := []int64{}
+	for i := 0; i < is.Len(); i++ {
+		res = append(res, is.Get(i))
+	}
+
+	return res
+}
+
+func TestAdd(t *testing.T) {
+	is := intset.New()
+
+	assert.True(t, is.Add(5))
+	assert.True(t, is.Add(-3))
+	assert.True(t, is.Add(10))
+	assert.False(t, is.Add(5))
+
+	assert.Equal(t, []int64{-3, 5, 10}, values(is))
+	assert.Equal(t, 6, is.Size())
+}
+
+func TestUpgrade(t *testing.T) {
+	testCases := map[string]struct {
+		value        int64
+		expectedSize int
+	}{
+		"when upgrading to 32 bits":     {value: math.MaxInt16 + 1, expectedSize: 4 * 4},
+		"when upgrading to 64 bits":     {value: math.MinInt32 - 1, expectedSize: 4 * 8},
+		"when upgrading with min int64": {value: math.MinInt64, expectedSize: 4 * 8},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			is := intset.New()
+			is.Add(-1)
+			is.Add(1)
+			is.Add(math.MaxInt16)
+
+			assert.True(t, is.Add(tc.value))
+			assert.Equal(t, tc.expectedSize, is.Size())
+			assert.ElementsMatch(t, []int64{-1, 1, math.MaxInt16, tc.value}, values(is))
+			assert.True(t, is.Find(tc.value))
+			assert.True(t, is.Find(math.MaxInt16))
+		})
+	}
+}
+
+func TestRemove(t *testing.T) {
+	is := intset.New()
+	is.Add(1)
+	is.Add(2)
+	is.Add(3)
+
+	assert.True(t, is.Remove(2))
+	assert.False(t, is.Remove(2))
+	assert.False(t, is.Remove(math.MaxInt64))
+
+	assert.Equal(t, []int64{1, 3}, values(is))
+	assert.False(t, is.Find(2))
+	assert.True(t, is.Find(3))
+}
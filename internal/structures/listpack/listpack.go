@@ -0,0 +1,492 @@
+package listpack
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// Listpack is a compact, contiguous list of strings and integers that uses the
+// same byte layout as Redis' listpack:
+//
+//	<total-bytes:uint32> <num-elements:uint16> <entry> ... <entry> <0xFF>
+//
+// Every entry is its encoding, its data and a backwards-readable length
+// ("backlen") so the list can be walked in both directions. Since the layout
+// is self-contained, Bytes() can be written as is to disk and read back with
+// FromBytes.
+//
+// Elements are addressed by their byte offset in the buffer. Offsets are only
+// valid until the next modification of the listpack.
+type Listpack struct {
+	buf   []byte
+	count int
+}
+
+const (
+	headerSize = 6
+	eofByte    = 0xFF
+
+	// unknownCount is stored in the header once the number of elements can't
+	// be represented with 16 bits anymore
+	unknownCount = 65535
+)
+
+// Before and After are used by Insert to decide where the new element goes
+// relative to the given offset.
+const (
+	Before = iota
+	After
+)
+
+var ErrInvalidListpack = errors.New("Invalid listpack")
+
+func New() *Listpack {
+	buf := make([]byte, headerSize+1, 64)
+	buf[headerSize] = eofByte
+
+	lp := &Listpack{buf: buf}
+	lp.updateHeader()
+
+	return lp
+}
+
+// FromBytes validates the given serialized listpack and wraps it. The buffer
+// is not copied.
+func FromBytes(buf []byte) (*Listpack, error) {
+	if len(buf) < headerSize+1 {
+		return nil, fmt.Errorf("%w: too short", ErrInvalidListpack)
+	}
+
+	if int(binary.LittleEndian.Uint32(buf)) != len(buf) {
+		return nil, fmt.Errorf("%w: header length mismatch", ErrInvalidListpack)
+	}
+
+	if buf[len(buf)-1] != eofByte {
+		return nil, fmt.Errorf("%w: missing terminator", ErrInvalidListpack)
+	}
+
+	lp := &Listpack{buf: buf}
+
+	for p := lp.First(); p != -1; p = lp.Next(p) {
+		if p+lp.entrySize(p) > len(buf)-1 {
+			return nil, fmt.Errorf("%w: entry overflows the buffer", ErrInvalidListpack)
+		}
+
+		lp.count++
+	}
+
+	header := int(binary.LittleEndian.Uint16(buf[4:]))
+	if header != unknownCount && header != lp.count {
+		return nil, fmt.Errorf("%w: element count mismatch", ErrInvalidListpack)
+	}
+
+	return lp, nil
+}
+
+// Bytes returns the underlying serialized listpack.
+func (lp *Listpack) Bytes() []byte {
+	return lp.buf
+}
+
+// Size is the total number of bytes used by the listpack.
+func (lp *Listpack) Size() int {
+	return len(lp.buf)
+}
+
+// Len is the number of elements in the listpack.
+func (lp *Listpack) Len() int {
+	return lp.count
+}
+
+// First returns the offset of the first element, or -1 if the listpack is
+// empty.
+func (lp *Listpack) First() int {
+	if lp.buf[headerSize] == eofByte {
+		return -1
+	}
+
+	return headerSize
+}
+
+// Last returns the offset of the last element, or -1 if the listpack is empty.
+func (lp *Listpack) Last() int {
+	return lp.Prev(len(lp.buf) - 1)
+}
+
+// Next returns the offset of the element after p, or -1 if p is the last one.
+func (lp *Listpack) Next(p int) int {
+	p += lp.entrySize(p)
+
+	if lp.buf[p] == eofByte {
+		return -1
+	}
+
+	return p
+}
+
+// Prev returns the offset of the element before p, or -1 if p is the first
+// one. p may also point to the terminator.
+func (lp *Listpack) Prev(p int) int {
+	if p <= headerSize {
+		return -1
+	}
+
+	encodedLen, backlenSize := decodeBacklen(lp.buf, p-1)
+
+	return p - backlenSize - encodedLen
+}
+
+// Seek returns the offset of the element at the given index, where negative
+// indexes count from the tail. -1 is returned when the index is out of range.
+func (lp *Listpack) Seek(index int) int {
+	if index < 0 {
+		index = lp.count + index
+	}
+
+	if index < 0 || index >= lp.count {
+		return -1
+	}
+
+	if index > lp.count/2 {
+		p := lp.Last()
+		for i := lp.count - 1; i > index; i-- {
+			p = lp.Prev(p)
+		}
+
+		return p
+	}
+
+	p := lp.First()
+	for i := 0; i < index; i++ {
+		p = lp.Next(p)
+	}
+
+	return p
+}
+
+// Get returns a copy of the element at offset p. Integer encoded elements are
+// returned in their decimal representation.
+func (lp *Listpack) Get(p int) []byte {
+	value, intValue, isInt := lp.decode(p)
+	if isInt {
+		return strconv.AppendInt(nil, intValue, 10)
+	}
+
+	return append([]byte{}, value...)
+}
+
+// GetInt returns the element at offset p as an integer. The second return
+// value is false if the element isn't a valid integer.
+func (lp *Listpack) GetInt(p int) (int64, bool) {
+	value, intValue, isInt := lp.decode(p)
+	if isInt {
+		return intValue, true
+	}
+
+	intValue, ok := stringToInt64(value)
+
+	return intValue, ok
+}
+
+func (lp *Listpack) Append(value []byte) {
+	lp.insertEncoded(len(lp.buf)-1, encodeString(value))
+}
+
+func (lp *Listpack) AppendInt(value int64) {
+	lp.insertEncoded(len(lp.buf)-1, encodeInt(value))
+}
+
+func (lp *Listpack) Prepend(value []byte) {
+	lp.insertEncoded(headerSize, encodeString(value))
+}
+
+// Insert adds the value Before or After the element at offset p and returns
+// the offset of the inserted element.
+func (lp *Listpack) Insert(p int, value []byte, where int) int {
+	if where == After {
+		p += lp.entrySize(p)
+	}
+
+	lp.insertEncoded(p, encodeString(value))
+
+	return p
+}
+
+// Replace overwrites the element at offset p.
+func (lp *Listpack) Replace(p int, value []byte) {
+	lp.replaceEncoded(p, encodeString(value))
+}
+
+// ReplaceInt overwrites the element at offset p with an integer.
+func (lp *Listpack) ReplaceInt(p int, value int64) {
+	lp.replaceEncoded(p, encodeInt(value))
+}
+
+// Delete removes the element at offset p and returns the offset of the
+// element which took its place, or -1 if p was the last element.
+func (lp *Listpack) Delete(p int) int {
+	return lp.DeleteRange(p, 1)
+}
+
+// DeleteRange removes up to count elements starting from offset p and returns
+// the offset of the element following the removed ones, or -1 if there is none.
+func (lp *Listpack) DeleteRange(p int, count int) int {
+	end := p
+	removed := 0
+
+	for removed < count && lp.buf[end] != eofByte {
+		end += lp.entrySize(end)
+		removed++
+	}
+
+	lp.buf = append(lp.buf[:p], lp.buf[end:]...)
+	lp.count -= removed
+	lp.updateHeader()
+
+	if lp.buf[p] == eofByte {
+		return -1
+	}
+
+	return p
+}
+
+// Clone returns a deep copy of the listpack.
+func (lp *Listpack) Clone() *Listpack {
+	buf := make([]byte, len(lp.buf))
+	copy(buf, lp.buf)
+
+	return &Listpack{buf: buf, count: lp.count}
+}
+
+func (lp *Listpack) insertEncoded(p int, encoded []byte) {
+	entry := appendBacklen(encoded, len(encoded))
+
+	lp.buf = append(lp.buf, entry...)
+	copy(lp.buf[p+len(entry):], lp.buf[p:len(lp.buf)-len(entry)])
+	copy(lp.buf[p:], entry)
+
+	lp.count++
+	lp.updateHeader()
+}
+
+func (lp *Listpack) replaceEncoded(p int, encoded []byte) {
+	entry := appendBacklen(encoded, len(encoded))
+	end := p + lp.entrySize(p)
+
+	rest := append(entry, lp.buf[end:]...)
+	lp.buf = append(lp.buf[:p], rest...)
+	lp.updateHeader()
+}
+
+func (lp *Listpack) updateHeader() {
+	binary.LittleEndian.PutUint32(lp.buf, uint32(len(lp.buf)))
+
+	count := lp.count
+	if count > unknownCount {
+		count = unknownCount
+	}
+
+	binary.LittleEndian.PutUint16(lp.buf[4:], uint16(count))
+}
+
+// entrySize is the number of bytes used by the element at p, including the
+// backlen.
+func (lp *Listpack) entrySize(p int) int {
+	encodedLen := encodedSize(lp.buf[p:])
+
+	return encodedLen + backlenSize(encodedLen)
+}
+
+func (lp *Listpack) decode(p int) ([]byte, int64, bool) {
+	buf := lp.buf[p:]
+	enc := buf[0]
+
+	switch {
+	case enc&0x80 == 0: // 0xxxxxxx
+		return nil, int64(enc & 0x7F), true
+	case enc&0xC0 == 0x80: // 10xxxxxx
+		length := int(enc & 0x3F)
+		return buf[1 : 1+length], 0, false
+	case enc&0xE0 == 0xC0: // 110xxxxx yyyyyyyy
+		return nil, signExtend(uint64(enc&0x1F)<<8|uint64(buf[1]), 13), true
+	case enc&0xF0 == 0xE0: // 1110xxxx yyyyyyyy
+		length := int(enc&0x0F)<<8 | int(buf[1])
+		return buf[2 : 2+length], 0, false
+	case enc == 0xF0:
+		length := int(binary.LittleEndian.Uint32(buf[1:]))
+		return buf[5 : 5+length], 0, false
+	case enc == 0xF1:
+		return nil, signExtend(uint64(binary.LittleEndian.Uint16(buf[1:])), 16), true
+	case enc == 0xF2:
+		return nil, signExtend(uint64(buf[1])|uint64(buf[2])<<8|uint64(buf[3])<<16, 24), true
+	case enc == 0xF3:
+		return nil, signExtend(uint64(binary.LittleEndian.Uint32(buf[1:])), 32), true
+	case enc == 0xF4:
+		return nil, int64(binary.LittleEndian.Uint64(buf[1:])), true
+	}
+
+	panic(fmt.Sprintf("listpack: invalid encoding byte %#x at offset %d", enc, p))
+}
+
+// encodedSize is the length of the encoding and data of the element starting
+// at buf, without the backlen.
+func encodedSize(buf []byte) int {
+	enc := buf[0]
+
+	switch {
+	case enc&0x80 == 0:
+		return 1
+	case enc&0xC0 == 0x80:
+		return 1 + int(enc&0x3F)
+	case enc&0xE0 == 0xC0:
+		return 2
+	case enc&0xF0 == 0xE0:
+		return 2 + (int(enc&0x0F)<<8 | int(buf[1]))
+	case enc == 0xF0:
+		return 5 + int(binary.LittleEndian.Uint32(buf[1:]))
+	case enc == 0xF1:
+		return 3
+	case enc == 0xF2:
+		return 4
+	case enc == 0xF3:
+		return 5
+	case enc == 0xF4:
+		return 9
+	}
+
+	panic(fmt.Sprintf("listpack: invalid encoding byte %#x", enc))
+}
+
+func encodeString(value []byte) []byte {
+	if intValue, ok := stringToInt64(value); ok {
+		return encodeInt(intValue)
+	}
+
+	length := len(value)
+
+	var encoded []byte
+
+	switch {
+	case length < 64:
+		encoded = make([]byte, 0, 1+length)
+		encoded = append(encoded, 0x80|byte(length))
+	case length < 4096:
+		encoded = make([]byte, 0, 2+length)
+		encoded = append(encoded, 0xE0|byte(length>>8), byte(length))
+	default:
+		encoded = make([]byte, 5, 5+length)
+		encoded[0] = 0xF0
+		binary.LittleEndian.PutUint32(encoded[1:], uint32(length))
+	}
+
+	return append(encoded, value...)
+}
+
+func encodeInt(value int64) []byte {
+	switch {
+	case value >= 0 && value <= 127:
+		return []byte{byte(value)}
+	case value >= -4096 && value <= 4095:
+		u := uint64(value) & 0x1FFF
+		return []byte{0xC0 | byte(u>>8), byte(u)}
+	case value >= -32768 && value <= 32767:
+		u := uint16(value)
+		return []byte{0xF1, byte(u), byte(u >> 8)}
+	case value >= -8388608 && value <= 8388607:
+		u := uint32(value)
+		return []byte{0xF2, byte(u), byte(u >> 8), byte(u >> 16)}
+	case value >= -2147483648 && value <= 2147483647:
+		encoded := make([]byte, 5)
+		encoded[0] = 0xF3
+		binary.LittleEndian.PutUint32(encoded[1:], uint32(value))
+		return encoded
+	}
+
+	encoded := make([]byte, 9)
+	encoded[0] = 0xF4
+	binary.LittleEndian.PutUint64(encoded[1:], uint64(value))
+
+	return encoded
+}
+
+func signExtend(value uint64, bits uint) int64 {
+	shift := 64 - bits
+
+	return int64(value<<shift) >> shift
+}
+
+// stringToInt64 only accepts the canonical decimal form of an integer, so that
+// converting it back gives exactly the same bytes.
+func stringToInt64(value []byte) (int64, bool) {
+	if len(value) == 0 || len(value) > 20 {
+		return 0, false
+	}
+
+	intValue, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	if string(strconv.AppendInt(nil, intValue, 10)) != string(value) {
+		return 0, false
+	}
+
+	return intValue, true
+}
+
+func backlenSize(length int) int {
+	switch {
+	case length <= 127:
+		return 1
+	case length < 16383:
+		return 2
+	case length < 2097151:
+		return 3
+	case length < 268435455:
+		return 4
+	}
+
+	return 5
+}
+
+// appendBacklen writes the length so it can be read from right to left: the
+// last byte holds the 7 least significant bits and every byte but the first
+// one has its high bit set.
+func appendBacklen(buf []byte, length int) []byte {
+	size := backlenSize(length)
+
+	for i := size - 1; i >= 0; i-- {
+		b := byte(length>>(7*uint(i))) & 0x7F
+		if i != size-1 {
+			b |= 0x80
+		}
+
+		buf = append(buf, b)
+	}
+
+	return buf
+}
+
+// decodeBacklen reads the backlen ending at offset p and returns the encoded
+// length together with the number of bytes the backlen takes.
+func decodeBacklen(buf []byte, p int) (int, int) {
+	length := 0
+	shift := uint(0)
+	size := 0
+
+	for {
+		b := buf[p-size]
+		length |= int(b&0x7F) << shift
+		size++
+
+		if b&0x80 == 0 {
+			break
+		}
+
+		shift += 7
+	}
+
+	return length, size
+}
@@ -0,0 +1,215 @@
+package listpack_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/listpack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func elements(lp *listpack.Listpack) []string {
+	res := []string{}
+
+	for p := lp.First(); p != -1; p = lp.Next(p) {
+		res = append(res, string(lp.Get(p)))
+	}
+
+	return res
+}
+
+func reversedElements(lp *listpack.Listpack) []string {
+	res := []string{}
+
+	for p := lp.Last(); p != -1; p = lp.Prev(p) {
+		res = append(res, string(lp.Get(p)))
+	}
+
+	return res
+}
+
+func TestAppend(t *testing.T) {
+	testCases := map[string]struct {
+		values       []string
+		expectedSize int
+	}{
+		"when listpack is empty": {
+			values:       []string{},
+			expectedSize: 7,
+		},
+		"when small integers given": {
+			values:       []string{"0", "1", "127"},
+			expectedSize: 7 + 3*2,
+		},
+		"when integers with different widths given": {
+			values: []string{
+				"-1", "4095", "-4096", "32767", "-32768", "8388607", "-8388608",
+				"2147483647", "-2147483648", "9223372036854775807", "-9223372036854775808",
+			},
+		},
+		"when non canonical integers given": {
+			values:       []string{"01", "+1", "-0", "1 "},
+			expectedSize: 7 + 4*4,
+		},
+		"when strings with different lengths given": {
+			values: []string{
+				"",
+				"hello",
+				strings.Repeat("a", 63),
+				strings.Repeat("b", 64),
+				strings.Repeat("c", 4095),
+				strings.Repeat("d", 4096),
+				strings.Repeat("e", 70000),
+			},
+		},
+		"when binary data given": {
+			values: []string{"\x00\xff\r\n", "\xff"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			lp := listpack.New()
+
+			for _, val := range tc.values {
+				lp.Append([]byte(val))
+			}
+
+			assert.Equal(t, tc.values, elements(lp))
+			assert.Equal(t, len(tc.values), lp.Len())
+
+			reversed := make([]string, 0, len(tc.values))
+			for i := len(tc.values) - 1; i >= 0; i-- {
+				reversed = append(reversed, tc.values[i])
+			}
+			assert.Equal(t, reversed, reversedElements(lp))
+
+			if tc.expectedSize != 0 {
+				assert.Equal(t, tc.expectedSize, lp.Size())
+			}
+
+			decoded, err := listpack.FromBytes(lp.Bytes())
+			require.NoError(t, err)
+			assert.Equal(t, tc.values, elements(decoded))
+		})
+	}
+}
+
+func TestGetInt(t *testing.T) {
+	lp := listpack.New()
+	lp.AppendInt(-123456789012)
+	lp.Append([]byte("42"))
+	lp.Append([]byte("abc"))
+
+	p := lp.First()
+	val, ok := lp.GetInt(p)
+	assert.True(t, ok)
+	assert.Equal(t, int64(-123456789012), val)
+
+	p = lp.Next(p)
+	val, ok = lp.GetInt(p)
+	assert.True(t, ok)
+	assert.Equal(t, int64(42), val)
+
+	p = lp.Next(p)
+	_, ok = lp.GetInt(p)
+	assert.False(t, ok)
+}
+
+func TestSeek(t *testing.T) {
+	lp := listpack.New()
+	for i := 0; i < 10; i++ {
+		lp.Append([]byte(strconv.Itoa(i)))
+	}
+
+	testCases := map[string]struct {
+		index    int
+		expected string
+		notFound bool
+	}{
+		"when first index given":    {index: 0, expected: "0"},
+		"when middle index given":   {index: 7, expected: "7"},
+		"when negative index given": {index: -3, expected: "7"},
+		"when last index given":     {index: -1, expected: "9"},
+		"when index is too big":     {index: 10, notFound: true},
+		"when index is too small":   {index: -11, notFound: true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			p := lp.Seek(tc.index)
+
+			if tc.notFound {
+				assert.Equal(t, -1, p)
+				return
+			}
+
+			assert.Equal(t, tc.expected, string(lp.Get(p)))
+		})
+	}
+}
+
+func TestModifications(t *testing.T) {
+	lp := listpack.New()
+	lp.Append([]byte("b"))
+	lp.Append([]byte("d"))
+	lp.Prepend([]byte("a"))
+
+	p := lp.Seek(1)
+	lp.Insert(p, []byte("c"), listpack.After)
+	assert.Equal(t, []string{"a", "b", "c", "d"}, elements(lp))
+
+	p = lp.Seek(0)
+	lp.Insert(p, []byte("start"), listpack.Before)
+	assert.Equal(t, []string{"start", "a", "b", "c", "d"}, elements(lp))
+
+	p = lp.Seek(2)
+	lp.Replace(p, []byte(strings.Repeat("x", 200)))
+	assert.Equal(t, []string{"start", "a", strings.Repeat("x", 200), "c", "d"}, elements(lp))
+
+	lp.ReplaceInt(lp.Seek(2), 1000)
+	assert.Equal(t, []string{"start", "a", "1000", "c", "d"}, elements(lp))
+
+	next := lp.Delete(lp.Seek(0))
+	assert.Equal(t, "a", string(lp.Get(next)))
+	assert.Equal(t, []string{"a", "1000", "c", "d"}, elements(lp))
+
+	next = lp.DeleteRange(lp.Seek(2), 5)
+	assert.Equal(t, -1, next)
+	assert.Equal(t, []string{"a", "1000"}, elements(lp))
+	assert.Equal(t, []string{"1000", "a"}, reversedElements(lp))
+	assert.Equal(t, 2, lp.Len())
+
+	decoded, err := listpack.FromBytes(lp.Bytes())
+	require.NoError(t, err)
+	assert.Equal(t, 2, decoded.Len())
+}
+
+func TestFromBytes(t *testing.T) {
+	testCases := map[string]struct {
+		buf []byte
+	}{
+		"when buffer is too short": {
+			buf: []byte{7, 0, 0, 0},
+		},
+		"when total bytes don't match": {
+			buf: []byte{8, 0, 0, 0, 0, 0, 0xFF},
+		},
+		"when terminator is missing": {
+			buf: []byte{7, 0, 0, 0, 0, 0, 0},
+		},
+		"when element count doesn't match": {
+			buf: []byte{7, 0, 0, 0, 1, 0, 0xFF},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := listpack.FromBytes(tc.buf)
+
+			require.ErrorIs(t, err, listpack.ErrInvalidListpack)
+		})
+	}
+}
@@ -0,0 +1,399 @@
+package quicklist
+
+import (
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/listpack"
+)
+
+// sizeLimits are the node sizes in bytes for the negative fill values, -1 to -5
+var sizeLimits = [...]int{4096, 8192, 16384, 32768, 65536}
+
+// entryOverhead is the worst case number of bytes, besides the value itself,
+// that a listpack needs to store an element
+const entryOverhead = 11
+
+type node struct {
+	prev *node
+	next *node
+	lp   *listpack.Listpack
+}
+
+// Quicklist is a doubly linked list of listpacks. Both ends can be modified
+// in O(1) while elements are still stored in compact blocks.
+//
+// fill decides how big nodes can get, with the semantics of
+// list-max-listpack-size: a positive value is the maximum number of elements
+// of a node, and -1 to -5 limit a node to 4, 8, 16, 32 or 64 kb.
+type Quicklist struct {
+	head  *node
+	tail  *node
+	count int
+	fill  int
+}
+
+func New(fill int) *Quicklist {
+	if fill == 0 || fill < -len(sizeLimits) {
+		fill = -2
+	}
+
+	return &Quicklist{fill: fill}
+}
+
+// Len is the number of elements in the list.
+func (q *Quicklist) Len() int {
+	return q.count
+}
+
+// Clone returns a deep copy of the list.
+func (q *Quicklist) Clone() *Quicklist {
+	clone := &Quicklist{count: q.count, fill: q.fill}
+
+	for n := q.head; n != nil; n = n.next {
+		clone.linkAfter(clone.tail, &node{lp: n.lp.Clone()})
+	}
+
+	return clone
+}
+
+func (q *Quicklist) PushHead(value []byte) {
+	if !q.allowsInsert(q.head, value) {
+		q.linkBefore(q.head, &node{lp: listpack.New()})
+	}
+
+	q.head.lp.Prepend(value)
+	q.count++
+}
+
+func (q *Quicklist) PushTail(value []byte) {
+	if !q.allowsInsert(q.tail, value) {
+		q.linkAfter(q.tail, &node{lp: listpack.New()})
+	}
+
+	q.tail.lp.Append(value)
+	q.count++
+}
+
+func (q *Quicklist) PopHead() ([]byte, bool) {
+	if q.head == nil {
+		return nil, false
+	}
+
+	p := q.head.lp.First()
+	value := q.head.lp.Get(p)
+	q.deleteAt(q.head, p)
+
+	return value, true
+}
+
+func (q *Quicklist) PopTail() ([]byte, bool) {
+	if q.tail == nil {
+		return nil, false
+	}
+
+	p := q.tail.lp.Last()
+	value := q.tail.lp.Get(p)
+	q.deleteAt(q.tail, p)
+
+	return value, true
+}
+
+// Index returns the element at the given index. Negative indexes count from
+// the tail, -1 being the last element.
+func (q *Quicklist) Index(index int) ([]byte, bool) {
+	n, p := q.locate(index)
+	if n == nil {
+		return nil, false
+	}
+
+	return n.lp.Get(p), true
+}
+
+// Replace overwrites the element at the given index, and returns false if the
+// index is out of range.
+func (q *Quicklist) Replace(index int, value []byte) bool {
+	n, p := q.locate(index)
+	if n == nil {
+		return false
+	}
+
+	n.lp.Replace(p, value)
+
+	return true
+}
+
+// Insert adds the value so it ends up at the given index, shifting the
+// following elements. An index equal to Len() appends to the tail.
+func (q *Quicklist) Insert(index int, value []byte) bool {
+	if index == q.count {
+		q.PushTail(value)
+		return true
+	}
+
+	n, p := q.locate(index)
+	if n == nil {
+		return false
+	}
+
+	switch {
+	case q.allowsInsert(n, value):
+		n.lp.Insert(p, value, listpack.Before)
+	case p == n.lp.First() && q.allowsInsert(n.prev, value):
+		n.prev.lp.Append(value)
+	case p == n.lp.First():
+		q.linkBefore(n, &node{lp: listpack.New()})
+		n.prev.lp.Append(value)
+	default:
+		// split the node so the new value starts the second half
+		q.split(n, p)
+		n.next.lp.Prepend(value)
+	}
+
+	q.count++
+
+	return true
+}
+
+// DeleteRange removes count elements starting from the given index.
+func (q *Quicklist) DeleteRange(index int, count int) {
+	n, p := q.locate(index)
+
+	for n != nil && count > 0 {
+		available := 0
+		for i := p; i != -1; i = n.lp.Next(i) {
+			available++
+		}
+
+		if available > count {
+			available = count
+		}
+
+		next := n.next
+
+		if p == n.lp.First() && available == n.lp.Len() {
+			q.unlink(n)
+		} else {
+			n.lp.DeleteRange(p, available)
+		}
+
+		q.count -= available
+		count -= available
+
+		n = next
+		if n != nil {
+			p = n.lp.First()
+		}
+	}
+}
+
+// Iterator walks the list from the given index, towards the tail or towards
+// the head if reverse is set.
+func (q *Quicklist) Iterator(index int, reverse bool) *Iterator {
+	n, p := q.locate(index)
+
+	return &Iterator{
+		q:       q,
+		nextN:   n,
+		nextP:   p,
+		reverse: reverse,
+	}
+}
+
+// locate finds the node and the offset inside the node for an index.
+func (q *Quicklist) locate(index int) (*node, int) {
+	if index < 0 {
+		index = q.count + index
+	}
+
+	if index < 0 || index >= q.count {
+		return nil, -1
+	}
+
+	if index < q.count/2 {
+		for n := q.head; n != nil; n = n.next {
+			if index < n.lp.Len() {
+				return n, n.lp.Seek(index)
+			}
+
+			index -= n.lp.Len()
+		}
+	} else {
+		index = q.count - 1 - index
+
+		for n := q.tail; n != nil; n = n.prev {
+			if index < n.lp.Len() {
+				return n, n.lp.Seek(-1 - index)
+			}
+
+			index -= n.lp.Len()
+		}
+	}
+
+	return nil, -1
+}
+
+func (q *Quicklist) allowsInsert(n *node, value []byte) bool {
+	if n == nil {
+		return false
+	}
+
+	if n.lp.Len() == 0 {
+		return true
+	}
+
+	if q.fill > 0 {
+		return n.lp.Len() < q.fill
+	}
+
+	return n.lp.Size()+len(value)+entryOverhead <= sizeLimits[-q.fill-1]
+}
+
+// split moves the elements of n starting from offset p to a new node linked
+// right after n.
+func (q *Quicklist) split(n *node, p int) {
+	second := &node{lp: listpack.New()}
+
+	for i := p; i != -1; i = n.lp.Next(i) {
+		second.lp.Append(n.lp.Get(i))
+	}
+
+	n.lp.DeleteRange(p, second.lp.Len())
+	q.linkAfter(n, second)
+}
+
+// deleteAt removes the element at offset p of n, dropping the node if it
+// becomes empty. It returns the offset of the following element in n, or -1.
+func (q *Quicklist) deleteAt(n *node, p int) int {
+	q.count--
+
+	if n.lp.Len() == 1 {
+		q.unlink(n)
+		return -1
+	}
+
+	return n.lp.Delete(p)
+}
+
+func (q *Quicklist) linkBefore(at *node, n *node) {
+	if at == nil {
+		q.head, q.tail = n, n
+		return
+	}
+
+	n.next = at
+	n.prev = at.prev
+
+	if at.prev != nil {
+		at.prev.next = n
+	} else {
+		q.head = n
+	}
+
+	at.prev = n
+}
+
+func (q *Quicklist) linkAfter(at *node, n *node) {
+	if at == nil {
+		q.head, q.tail = n, n
+		return
+	}
+
+	n.prev = at
+	n.next = at.next
+
+	if at.next != nil {
+		at.next.prev = n
+	} else {
+		q.tail = n
+	}
+
+	at.next = n
+}
+
+func (q *Quicklist) unlink(n *node) {
+	if n.prev != nil {
+		n.prev.next = n.next
+	} else {
+		q.head = n.next
+	}
+
+	if n.next != nil {
+		n.next.prev = n.prev
+	} else {
+		q.tail = n.prev
+	}
+
+	n.prev, n.next = nil, nil
+}
+
+// Iterator is a cursor over the quicklist. The current element can be deleted
+// while iterating, any other modification of the list invalidates it.
+type Iterator struct {
+	q       *Quicklist
+	reverse bool
+
+	n *node
+	p int
+
+	nextN *node
+	nextP int
+}
+
+// Next moves to the next element and returns false once the iteration is
+// over.
+func (it *Iterator) Next() bool {
+	if it.nextN == nil {
+		it.n = nil
+		return false
+	}
+
+	it.n, it.p = it.nextN, it.nextP
+	it.nextN, it.nextP = it.successor(it.n, it.p)
+
+	return true
+}
+
+// Value returns the current element.
+func (it *Iterator) Value() []byte {
+	return it.n.lp.Get(it.p)
+}
+
+// Delete removes the current element. Next continues with the element which
+// followed it.
+func (it *Iterator) Delete() {
+	n, p := it.n, it.p
+	it.n = nil
+
+	if it.reverse {
+		// offsets before p are not affected by the deletion
+		it.q.deleteAt(n, p)
+		return
+	}
+
+	next := it.q.deleteAt(n, p)
+	if next != -1 {
+		it.nextN, it.nextP = n, next
+	}
+}
+
+func (it *Iterator) successor(n *node, p int) (*node, int) {
+	if it.reverse {
+		if prev := n.lp.Prev(p); prev != -1 {
+			return n, prev
+		}
+
+		if n.prev == nil {
+			return nil, -1
+		}
+
+		return n.prev, n.prev.lp.Last()
+	}
+
+	if next := n.lp.Next(p); next != -1 {
+		return n, next
+	}
+
+	if n.next == nil {
+		return nil, -1
+	}
+
+	return n.next, n.next.lp.First()
+}
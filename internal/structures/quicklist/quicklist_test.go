@@ -0,0 +1,235 @@
+package quicklist_test
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/quicklist"
+	"github.com/stretchr/testify/assert"
+)
+
+func elements(q *quicklist.Quicklist) []string {
+	res := []string{}
+
+	it := q.Iterator(0, false)
+	for it.Next() {
+		res = append(res, string(it.Value()))
+	}
+
+	return res
+}
+
+func newList(fill int, values ...string) *quicklist.Quicklist {
+	q := quicklist.New(fill)
+
+	for _, val := range values {
+		q.PushTail([]byte(val))
+	}
+
+	return q
+}
+
+func numbers(from, to int) []string {
+	res := []string{}
+	for i := from; i < to; i++ {
+		res = append(res, strconv.Itoa(i))
+	}
+
+	return res
+}
+
+func TestPushAndPop(t *testing.T) {
+	testCases := map[string]struct {
+		fill int
+	}{
+		"when nodes are limited by count": {fill: 3},
+		"when nodes are limited by size":  {fill: -1},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			q := quicklist.New(tc.fill)
+			big := strings.Repeat("x", 1500)
+
+			q.PushTail([]byte("b"))
+			q.PushHead([]byte("a"))
+			q.PushTail([]byte(big))
+			q.PushTail([]byte("c"))
+			q.PushHead([]byte(big))
+			q.PushTail([]byte(big))
+
+			assert.Equal(t, 6, q.Len())
+			assert.Equal(t, []string{big, "a", "b", big, "c", big}, elements(q))
+
+			val, ok := q.PopHead()
+			assert.True(t, ok)
+			assert.Equal(t, big, string(val))
+
+			val, ok = q.PopTail()
+			assert.True(t, ok)
+			assert.Equal(t, big, string(val))
+
+			assert.Equal(t, []string{"a", "b", big, "c"}, elements(q))
+
+			for i := 0; i < 4; i++ {
+				_, ok = q.PopTail()
+				assert.True(t, ok)
+			}
+
+			_, ok = q.PopHead()
+			assert.False(t, ok)
+			assert.Equal(t, 0, q.Len())
+			assert.Equal(t, []string{}, elements(q))
+		})
+	}
+}
+
+func TestIndexAndReplace(t *testing.T) {
+	q := newList(4, numbers(0, 10)...)
+
+	val, ok := q.Index(5)
+	assert.True(t, ok)
+	assert.Equal(t, "5", string(val))
+
+	val, ok = q.Index(-2)
+	assert.True(t, ok)
+	assert.Equal(t, "8", string(val))
+
+	_, ok = q.Index(10)
+	assert.False(t, ok)
+
+	assert.True(t, q.Replace(-1, []byte("last")))
+	assert.False(t, q.Replace(-11, []byte("nope")))
+
+	val, _ = q.Index(9)
+	assert.Equal(t, "last", string(val))
+}
+
+func TestInsert(t *testing.T) {
+	testCases := map[string]struct {
+		index    int
+		expected []string
+	}{
+		"when inserting to the head": {
+			index:    0,
+			expected: []string{"new", "0", "1", "2", "3", "4", "5"},
+		},
+		"when inserting in a full node": {
+			index:    2,
+			expected: []string{"0", "1", "new", "2", "3", "4", "5"},
+		},
+		"when inserting at a node boundary": {
+			index:    3,
+			expected: []string{"0", "1", "2", "new", "3", "4", "5"},
+		},
+		"when inserting to the tail": {
+			index:    6,
+			expected: []string{"0", "1", "2", "3", "4", "5", "new"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			q := newList(3, numbers(0, 6)...)
+
+			assert.True(t, q.Insert(tc.index, []byte("new")))
+			assert.Equal(t, tc.expected, elements(q))
+			assert.Equal(t, 7, q.Len())
+		})
+	}
+}
+
+func TestDeleteRange(t *testing.T) {
+	testCases := map[string]struct {
+		index    int
+		count    int
+		expected []string
+	}{
+		"when deleting from the head": {
+			index:    0,
+			count:    4,
+			expected: numbers(4, 10),
+		},
+		"when deleting in the middle": {
+			index:    2,
+			count:    5,
+			expected: []string{"0", "1", "7", "8", "9"},
+		},
+		"when deleting more than available": {
+			index:    8,
+			count:    10,
+			expected: numbers(0, 8),
+		},
+		"when deleting everything": {
+			index:    0,
+			count:    10,
+			expected: []string{},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			q := newList(3, numbers(0, 10)...)
+
+			q.DeleteRange(tc.index, tc.count)
+
+			assert.Equal(t, tc.expected, elements(q))
+			assert.Equal(t, len(tc.expected), q.Len())
+		})
+	}
+}
+
+func TestIterator(t *testing.T) {
+	t.Run("when iterating backwards", func(t *testing.T) {
+		q := newList(3, numbers(0, 7)...)
+
+		res := []string{}
+		it := q.Iterator(-2, true)
+		for it.Next() {
+			res = append(res, string(it.Value()))
+		}
+
+		assert.Equal(t, []string{"5", "4", "3", "2", "1", "0"}, res)
+	})
+
+	t.Run("when deleting while iterating forward", func(t *testing.T) {
+		q := newList(3, "a", "x", "x", "x", "b", "x", "c")
+
+		it := q.Iterator(0, false)
+		for it.Next() {
+			if string(it.Value()) == "x" {
+				it.Delete()
+			}
+		}
+
+		assert.Equal(t, []string{"a", "b", "c"}, elements(q))
+		assert.Equal(t, 3, q.Len())
+	})
+
+	t.Run("when deleting while iterating backwards", func(t *testing.T) {
+		q := newList(3, "x", "a", "x", "x", "b", "x", "x")
+
+		it := q.Iterator(-1, true)
+		for it.Next() {
+			if string(it.Value()) == "x" {
+				it.Delete()
+			}
+		}
+
+		assert.Equal(t, []string{"a", "b"}, elements(q))
+		assert.Equal(t, 2, q.Len())
+	})
+}
+
+func TestClone(t *testing.T) {
+	q := newList(2, numbers(0, 5)...)
+	clone := q.Clone()
+
+	q.PushTail([]byte("5"))
+	clone.PopHead()
+
+	assert.Equal(t, numbers(0, 6), elements(q))
+	assert.Equal(t, numbers(1, 5), elements(clone))
+	assert.Equal(t, 4, clone.Len())
+}
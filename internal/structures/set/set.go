@@ -0,0 +1,176 @@
+package set
+
+import (
+	"math/rand"
+	"strconv"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/dict"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/intset"
+)
+
+const (
+	EncodingIntset    = "intset"
+	EncodingHashtable = "hashtable"
+)
+
+// Set is an unordered collection of unique members. Sets whose members are
+// all integers are stored as an intset while they have at most
+// set-max-intset-entries members. They're converted to a hash table
+// otherwise, and never converted back.
+type Set struct {
+	is   *intset.Intset
+	dict *dict.Dict[struct{}]
+}
+
+func New() *Set {
+	return &Set{is: intset.New()}
+}
+
+// Len is the number of members in the set.
+func (s *Set) Len() int {
+	if s.dict != nil {
+		return s.dict.Len()
+	}
+
+	return s.is.Len()
+}
+
+// Encoding is the name of the encoding in use, as reported by OBJECT ENCODING.
+func (s *Set) Encoding() string {
+	if s.dict != nil {
+		return EncodingHashtable
+	}
+
+	return EncodingIntset
+}
+
+// Clone returns a deep copy of the set.
+func (s *Set) Clone() *Set {
+	if s.dict == nil {
+		return &Set{is: s.is.Clone()}
+	}
+
+	clone := dict.New[struct{}]()
+	s.dict.ForEach(func(member string, _ struct{}) bool {
+		clone.Set(member, struct{}{})
+		return true
+	})
+
+	return &Set{dict: clone}
+}
+
+// Add adds the member and returns false if it was already in the set.
+// maxIntsetEntries is the size past which the set can't be an intset anymore.
+func (s *Set) Add(member []byte, maxIntsetEntries int) bool {
+	if s.dict == nil {
+		value, isInt := toInt64(member)
+		if !isInt {
+			s.convert()
+		} else {
+			added := s.is.Add(value)
+			if s.is.Len() > maxIntsetEntries {
+				s.convert()
+			}
+
+			return added
+		}
+	}
+
+	return s.dict.Set(string(member), struct{}{})
+}
+
+// Remove removes the member and returns false if it wasn't in the set.
+func (s *Set) Remove(member []byte) bool {
+	if s.dict == nil {
+		value, isInt := toInt64(member)
+		return isInt && s.is.Remove(value)
+	}
+
+	return s.dict.Delete(string(member))
+}
+
+func (s *Set) Contains(member []byte) bool {
+	if s.dict == nil {
+		value, isInt := toInt64(member)
+		return isInt && s.is.Find(value)
+	}
+
+	_, exists := s.dict.Get(string(member))
+
+	return exists
+}
+
+// ForEach calls fn for every member until it returns false. The members of
+// an intset are visited in ascending order. The set must not be modified
+// meanwhile.
+func (s *Set) ForEach(fn func(member []byte) bool) {
+	if s.dict != nil {
+		s.dict.ForEach(func(member string, _ struct{}) bool {
+			return fn([]byte(member))
+		})
+
+		return
+	}
+
+	for i := 0; i < s.is.Len(); i++ {
+		if !fn(strconv.AppendInt(nil, s.is.Get(i), 10)) {
+			return
+		}
+	}
+}
+
+// Scan calls fn for some of the members from the cursor, 0 to start the
+// iteration, and returns the cursor to continue from, 0 once every member
+// has been visited. The members of an intset are all visited at once. See
+// dict.Dict.Scan for the guarantees.
+func (s *Set) Scan(cursor uint64, fn func(member []byte)) uint64 {
+	if s.dict != nil {
+		return s.dict.Scan(cursor, func(member string, _ struct{}) {
+			fn([]byte(member))
+		})
+	}
+
+	for i := 0; i < s.is.Len(); i++ {
+		fn(strconv.AppendInt(nil, s.is.Get(i), 10))
+	}
+
+	return 0
+}
+
+// Random returns a random member, nil if the set is empty.
+func (s *Set) Random() []byte {
+	if s.Len() == 0 {
+		return nil
+	}
+
+	if s.dict == nil {
+		return strconv.AppendInt(nil, s.is.Get(rand.Intn(s.is.Len())), 10)
+	}
+
+	member, _, _ := s.dict.Random()
+
+	return []byte(member)
+}
+
+func (s *Set) convert() {
+	members := dict.New[struct{}]()
+
+	s.ForEach(func(member []byte) bool {
+		members.Set(string(member), struct{}{})
+		return true
+	})
+
+	s.dict = members
+	s.is = nil
+}
+
+// toInt64 converts the member to an integer if it's the canonical
+// representation of one, as "01" or "+1" must stay strings.
+func toInt64(member []byte) (int64, bool) {
+	value, err := strconv.ParseInt(string(member), 10, 64)
+	if err != nil || strconv.FormatInt(value, 10) != string(member) {
+		return 0, false
+	}
+
+	return value, true
+}
@@ -0,0 +1,116 @@
+package set_test
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/set"
+	"github.com/stretchr/testify/assert"
+)
+
+const maxIntsetEntries = 4
+
+func members(s *set.Set) []string {
+	res := []string{}
+
+	s.ForEach(func(member []byte) bool {
+		res = append(res, string(member))
+		return true
+	})
+	sort.Strings(res)
+
+	return res
+}
+
+func newSet(values ...string) *set.Set {
+	s := set.New()
+	for _, value := range values {
+		s.Add([]byte(value), maxIntsetEntries)
+	}
+
+	return s
+}
+
+func TestAdd(t *testing.T) {
+	testCases := map[string]struct {
+		values           []string
+		expectedEncoding string
+		expected         []string
+	}{
+		"when every member is an integer": {
+			values:           []string{"3", "-1", "2", "3"},
+			expectedEncoding: set.EncodingIntset,
+			expected:         []string{"-1", "2", "3"},
+		},
+		"when a member isn't an integer": {
+			values:           []string{"1", "a", "1"},
+			expectedEncoding: set.EncodingHashtable,
+			expected:         []string{"1", "a"},
+		},
+		"when an integer isn't canonical": {
+			values:           []string{"1", "01"},
+			expectedEncoding: set.EncodingHashtable,
+			expected:         []string{"01", "1"},
+		},
+		"when there are too many integers": {
+			values:           []string{"1", "2", "3", "4", "5"},
+			expectedEncoding: set.EncodingHashtable,
+			expected:         []string{"1", "2", "3", "4", "5"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			s := newSet(tc.values...)
+
+			assert.Equal(t, tc.expectedEncoding, s.Encoding())
+			assert.Equal(t, tc.expected, members(s))
+			assert.Equal(t, len(tc.expected), s.Len())
+		})
+	}
+}
+
+func TestRemove(t *testing.T) {
+	testCases := map[string][]string{
+		"when set is an intset": {"1", "2"},
+		"when set is a table":   {"1", "2", "a"},
+	}
+
+	for name, values := range testCases {
+		t.Run(name, func(t *testing.T) {
+			s := newSet(values...)
+
+			assert.True(t, s.Contains([]byte("1")))
+			assert.True(t, s.Remove([]byte("1")))
+			assert.False(t, s.Remove([]byte("1")))
+			assert.False(t, s.Remove([]byte("b")))
+			assert.False(t, s.Contains([]byte("1")))
+			assert.True(t, s.Contains([]byte("2")))
+		})
+	}
+}
+
+func TestRandom(t *testing.T) {
+	assert.Nil(t, set.New().Random())
+
+	s := newSet("1", "2", "3")
+	assert.Contains(t, []string{"1", "2", "3"}, string(s.Random()))
+
+	s = newSet("a", "b")
+	assert.Contains(t, []string{"a", "b"}, string(s.Random()))
+}
+
+func TestClone(t *testing.T) {
+	for name, values := range map[string][]string{"when intset": {"1", "2"}, "when hashtable": {"a", "b"}} {
+		t.Run(name, func(t *testing.T) {
+			s := newSet(values...)
+			clone := s.Clone()
+
+			s.Remove([]byte(values[0]))
+			clone.Add([]byte("3"), maxIntsetEntries)
+
+			assert.Equal(t, values[1:], members(s))
+			assert.ElementsMatch(t, append([]string{"3"}, values...), members(clone))
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package stream
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidID = errors.New("ERR Invalid stream ID specified as stream command argument")
+
+// ID is a stream entry ID in the {milliseconds}-{sequence} format.
+type ID struct {
+	Ms  uint64
+	Seq uint64
+}
+
+var (
+	MinID = ID{}
+	MaxID = ID{Ms: math.MaxUint64, Seq: math.MaxUint64}
+)
+
+func (id ID) String() string {
+	return fmt.Sprintf("%d-%d", id.Ms, id.Seq)
+}
+
+// Compare returns -1, 0 or 1 depending on id being smaller, equal or bigger
+// than other.
+func (id ID) Compare(other ID) int {
+	switch {
+	case id.Ms < other.Ms:
+		return -1
+	case id.Ms > other.Ms:
+		return 1
+	case id.Seq < other.Seq:
+		return -1
+	case id.Seq > other.Seq:
+		return 1
+	}
+
+	return 0
+}
+
+// Next returns the smallest ID bigger than id. The second return value is
+// false if id is already the biggest possible ID.
+func (id ID) Next() (ID, bool) {
+	if id.Seq < math.MaxUint64 {
+		return ID{Ms: id.Ms, Seq: id.Seq + 1}, true
+	}
+
+	if id.Ms < math.MaxUint64 {
+		return ID{Ms: id.Ms + 1}, true
+	}
+
+	return id, false
+}
+
+// ParseID parses a complete {ms}-{seq} ID. The sequence part may be omitted,
+// in which case it's set to defaultSeq.
+func ParseID(s string, defaultSeq uint64) (ID, error) {
+	ms, seq, found := strings.Cut(s, "-")
+
+	msInt, err := strconv.ParseUint(ms, 10, 64)
+	if err != nil {
+		return ID{}, ErrInvalidID
+	}
+
+	if !found {
+		return ID{Ms: msInt, Seq: defaultSeq}, nil
+	}
+
+	seqInt, err := strconv.ParseUint(seq, 10, 64)
+	if err != nil {
+		return ID{}, ErrInvalidID
+	}
+
+	return ID{Ms: msInt, Seq: seqInt}, nil
+}
+
+// ParseRangeID parses the boundaries of XRANGE-like commands, where "-" and
+// "+" are the smallest and biggest IDs and a missing sequence covers the
+// whole millisecond.
+func ParseRangeID(s string, isEnd bool) (ID, error) {
+	switch s {
+	case "-":
+		return MinID, nil
+	case "+":
+		return MaxID, nil
+	}
+
+	if isEnd {
+		return ParseID(s, math.MaxUint64)
+	}
+
+	return ParseID(s, 0)
+}
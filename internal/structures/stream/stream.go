@@ -0,0 +1,450 @@
+package stream
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/listpack"
+)
+
+var (
+	ErrIDTooSmall = errors.New("ERR The ID specified in XADD is equal or smaller than the target stream top item")
+	ErrIDZero     = errors.New("ERR The ID specified in XADD must be greater than 0-0")
+)
+
+// Entry flags, stored as the first element of every entry in a block
+const (
+	flagNone      = 0
+	flagDeleted   = 1
+	flagSameField = 2
+)
+
+type Data struct {
+	ID     string
+	Values []string // Key Value Pairs
+}
+
+func (d *Data) AsMap() map[string]string {
+	res := make(map[string]string)
+
+	for i := 0; i < len(d.Values); i++ {
+		if i%2 == 0 {
+			continue
+		}
+
+		res[d.Values[i-1]] = d.Values[i]
+	}
+
+	return res
+}
+
+// NodeLimits decide when a block is full and a new one has to be started. A
+// zero value disables the corresponding limit.
+type NodeLimits struct {
+	MaxBytes   int
+	MaxEntries int
+}
+
+// block groups consecutive entries into a single listpack. The first elements
+// of the listpack are the "master entry":
+//
+//	count | deleted | num-fields | field_1 | ... | field_N | 0
+//
+// followed by the entries themselves:
+//
+//	flags | ms-diff | seq-diff | [num-fields | field_1 | value_1 | ...] | lp-count
+//
+// IDs are stored as the difference to the master ID. When an entry has the
+// same fields as the master entry, the flagSameField flag is set and only the
+// values are stored. lp-count is the number of listpack elements the entry
+// takes, so blocks can be walked backwards as well.
+type block struct {
+	master ID
+	lp     *listpack.Listpack
+}
+
+// Stream stores its entries in blocks ordered by their master ID. As IDs only
+// grow, new entries always go to the last block.
+type Stream struct {
+	blocks []*block
+	length int
+	lastID ID
+
+	nowFn func() time.Time
+}
+
+func New(nowFn func() time.Time) *Stream {
+	return &Stream{
+		nowFn: nowFn,
+	}
+}
+
+// Len is the number of entries in the stream.
+func (s *Stream) Len() int {
+	return s.length
+}
+
+func (s *Stream) LastID() ID {
+	return s.lastID
+}
+
+// Blocks is the number of blocks the entries are stored in.
+func (s *Stream) Blocks() int {
+	return len(s.blocks)
+}
+
+// First returns the entry with the smallest ID, nil if the stream is empty.
+func (s *Stream) First() *Data {
+	for _, b := range s.blocks {
+		var first *Data
+
+		b.forEach(func(id ID, values []string) bool {
+			first = &Data{ID: id.String(), Values: values}
+			return false
+		})
+
+		if first != nil {
+			return first
+		}
+	}
+
+	return nil
+}
+
+// Last returns the entry with the biggest ID, nil if the stream is empty.
+// Blocks are bounded, so walking the last one is cheap.
+func (s *Stream) Last() *Data {
+	for i := len(s.blocks) - 1; i >= 0; i-- {
+		var last *Data
+
+		s.blocks[i].forEach(func(id ID, values []string) bool {
+			last = &Data{ID: id.String(), Values: values}
+			return true
+		})
+
+		if last != nil {
+			return last
+		}
+	}
+
+	return nil
+}
+
+// Clone returns a deep copy of the stream.
+func (s *Stream) Clone() *Stream {
+	clone := &Stream{
+		blocks: make([]*block, len(s.blocks)),
+		length: s.length,
+		lastID: s.lastID,
+		nowFn:  s.nowFn,
+	}
+
+	for i, b := range s.blocks {
+		clone.blocks[i] = &block{master: b.master, lp: b.lp.Clone()}
+	}
+
+	return clone
+}
+
+// Insert adds an entry to the stream and returns its ID. The key can be a
+// complete ID, {ms}-* to generate the sequence or * to also use the current
+// time. IDs should always be incremental and 0-0 is not accepted.
+func (s *Stream) Insert(key string, values []string, limits NodeLimits) (string, error) {
+	if s == nil {
+		return "", fmt.Errorf("Invalid stream")
+	}
+
+	if len(values)%2 != 0 {
+		return "", fmt.Errorf("ERR wrong number of arguments for 'xadd' command")
+	}
+
+	id, err := s.nextID(key)
+	if err != nil {
+		return "", err
+	}
+
+	fields := make([]string, 0, len(values)/2)
+	for i := 0; i < len(values); i += 2 {
+		fields = append(fields, values[i])
+	}
+
+	var last *block
+	if len(s.blocks) > 0 {
+		last = s.blocks[len(s.blocks)-1]
+	}
+
+	if last == nil || last.isFull(values, limits) {
+		last = newBlock(id, fields)
+		s.blocks = append(s.blocks, last)
+	}
+
+	last.append(id, fields, values)
+
+	s.length++
+	s.lastID = id
+
+	return id.String(), nil
+}
+
+// Range returns the entries between begin and end, both inclusive, ordered by
+// their IDs.
+func (s *Stream) Range(begin, end ID) ([]*Data, error) {
+	if s == nil {
+		return nil, fmt.Errorf("Invalid stream")
+	}
+
+	if begin.Compare(end) > 0 {
+		return []*Data{}, nil
+	}
+
+	// The first block which can contain begin is the last one whose master
+	// ID isn't bigger than begin
+	first := sort.Search(len(s.blocks), func(i int) bool {
+		return s.blocks[i].master.Compare(begin) > 0
+	}) - 1
+
+	if first < 0 {
+		first = 0
+	}
+
+	foundData := make([]*Data, 0)
+
+	for _, b := range s.blocks[first:] {
+		if b.master.Compare(end) > 0 {
+			break
+		}
+
+		done := false
+
+		b.forEach(func(id ID, values []string) bool {
+			if id.Compare(end) > 0 {
+				done = true
+				return false
+			}
+
+			if id.Compare(begin) >= 0 {
+				foundData = append(foundData, &Data{ID: id.String(), Values: values})
+			}
+
+			return true
+		})
+
+		if done {
+			break
+		}
+	}
+
+	return foundData, nil
+}
+
+func (s *Stream) nextID(key string) (ID, error) {
+	if key == "*" {
+		ms := uint64(s.nowFn().UnixMilli())
+		if ms > s.lastID.Ms {
+			return ID{Ms: ms}, nil
+		}
+
+		id, ok := s.lastID.Next()
+		if !ok {
+			return ID{}, fmt.Errorf("ERR The stream has exhausted the last possible ID, unable to add more items")
+		}
+
+		return id, nil
+	}
+
+	ms, seq, found := strings.Cut(key, "-")
+
+	if found && seq == "*" {
+		msInt, err := strconv.ParseUint(ms, 10, 64)
+		if err != nil {
+			return ID{}, ErrInvalidID
+		}
+
+		switch {
+		case msInt < s.lastID.Ms:
+			return ID{}, ErrIDTooSmall
+		case msInt > s.lastID.Ms:
+			return ID{Ms: msInt}, nil
+		case s.lastID.Seq == math.MaxUint64:
+			return ID{}, ErrIDTooSmall
+		}
+
+		return ID{Ms: msInt, Seq: s.lastID.Seq + 1}, nil
+	}
+
+	id, err := ParseID(key, 0)
+	if err != nil {
+		return ID{}, err
+	}
+
+	if id == MinID {
+		return ID{}, ErrIDZero
+	}
+
+	if id.Compare(s.lastID) <= 0 {
+		return ID{}, ErrIDTooSmall
+	}
+
+	return id, nil
+}
+
+func newBlock(master ID, fields []string) *block {
+	lp := listpack.New()
+
+	lp.AppendInt(0) // count
+	lp.AppendInt(0) // deleted
+	lp.AppendInt(int64(len(fields)))
+
+	for _, field := range fields {
+		lp.Append([]byte(field))
+	}
+
+	lp.AppendInt(0) // master entry terminator
+
+	return &block{master: master, lp: lp}
+}
+
+func (b *block) isFull(values []string, limits NodeLimits) bool {
+	if limits.MaxBytes > 0 {
+		size := b.lp.Size()
+		for _, val := range values {
+			size += len(val)
+		}
+
+		if size >= limits.MaxBytes {
+			return true
+		}
+	}
+
+	if limits.MaxEntries > 0 {
+		count, _ := b.lp.GetInt(b.lp.First())
+		deleted, _ := b.lp.GetInt(b.lp.Next(b.lp.First()))
+
+		if int(count+deleted) >= limits.MaxEntries {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (b *block) masterFields() []string {
+	p := b.lp.Next(b.lp.Next(b.lp.First()))
+	numFields, _ := b.lp.GetInt(p)
+
+	fields := make([]string, 0, numFields)
+	for i := int64(0); i < numFields; i++ {
+		p = b.lp.Next(p)
+		fields = append(fields, string(b.lp.Get(p)))
+	}
+
+	return fields
+}
+
+func (b *block) append(id ID, fields []string, values []string) {
+	lp := b.lp
+
+	flags := int64(flagNone)
+
+	masterFields := b.masterFields()
+	if equalFields(masterFields, fields) {
+		flags |= flagSameField
+	}
+
+	lp.AppendInt(flags)
+	lp.AppendInt(int64(id.Ms - b.master.Ms))
+	lp.AppendInt(int64(id.Seq - b.master.Seq))
+
+	lpCount := len(fields) + 3
+
+	if flags&flagSameField != 0 {
+		for i := 1; i < len(values); i += 2 {
+			lp.Append([]byte(values[i]))
+		}
+	} else {
+		lp.AppendInt(int64(len(fields)))
+
+		for _, val := range values {
+			lp.Append([]byte(val))
+		}
+
+		lpCount += len(fields) + 1
+	}
+
+	lp.AppendInt(int64(lpCount))
+
+	count, _ := lp.GetInt(lp.First())
+	lp.ReplaceInt(lp.First(), count+1)
+}
+
+// forEach walks the non deleted entries of the block in order, until fn
+// returns false.
+func (b *block) forEach(fn func(id ID, values []string) bool) {
+	lp := b.lp
+	masterFields := b.masterFields()
+
+	// skip count, deleted, num-fields, the fields and the terminator
+	p := lp.Seek(3 + len(masterFields) + 1)
+
+	for p != -1 {
+		flags, _ := lp.GetInt(p)
+		p = lp.Next(p)
+		msDiff, _ := lp.GetInt(p)
+		p = lp.Next(p)
+		seqDiff, _ := lp.GetInt(p)
+		p = lp.Next(p)
+
+		id := ID{Ms: b.master.Ms + uint64(msDiff), Seq: b.master.Seq + uint64(seqDiff)}
+
+		var values []string
+
+		if flags&flagSameField != 0 {
+			values = make([]string, 0, len(masterFields)*2)
+
+			for _, field := range masterFields {
+				values = append(values, field, string(lp.Get(p)))
+				p = lp.Next(p)
+			}
+		} else {
+			numFields, _ := lp.GetInt(p)
+			p = lp.Next(p)
+
+			values = make([]string, 0, numFields*2)
+
+			for i := int64(0); i < numFields*2; i++ {
+				values = append(values, string(lp.Get(p)))
+				p = lp.Next(p)
+			}
+		}
+
+		// skip lp-count
+		p = lp.Next(p)
+
+		if flags&flagDeleted != 0 {
+			continue
+		}
+
+		if !fn(id, values) {
+			return
+		}
+	}
+}
+
+func equalFields(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,291 @@
+package stream_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/stream"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fixedNow(ms int64) func() time.Time {
+	return func() time.Time {
+		return time.UnixMilli(ms)
+	}
+}
+
+func newStream(t *testing.T, ids ...string) *stream.Stream {
+	s := stream.New(fixedNow(1000))
+
+	for _, id := range ids {
+		_, err := s.Insert(id, []string{"key", id}, stream.NodeLimits{})
+		require.NoError(t, err)
+	}
+
+	return s
+}
+
+func TestInsert(t *testing.T) {
+	testCases := map[string]struct {
+		key           string
+		values        []string
+		stream        *stream.Stream
+		expectedId    string
+		expectedError error
+	}{
+		"when stream is nil": {
+			key:           "1-1",
+			stream:        nil,
+			expectedError: fmt.Errorf("Invalid stream"),
+		},
+		"when invalid input given": {
+			key:           "11-a",
+			stream:        newStream(t),
+			expectedError: stream.ErrInvalidID,
+		},
+		"when odd number of values given": {
+			key:           "1-1",
+			values:        []string{"key-1"},
+			stream:        newStream(t),
+			expectedError: fmt.Errorf("ERR wrong number of arguments for 'xadd' command"),
+		},
+		"when input already exists": {
+			key:           "0-1",
+			stream:        newStream(t, "0-1"),
+			expectedError: stream.ErrIDTooSmall,
+		},
+		"when bigger value already exists": {
+			key:           "0-2",
+			stream:        newStream(t, "1-1"),
+			expectedError: stream.ErrIDTooSmall,
+		},
+		"when bigger value already exists on a different digit": {
+			key:           "1526985054069-0",
+			stream:        newStream(t, "1526985054079-0"),
+			expectedError: stream.ErrIDTooSmall,
+		},
+		"when trying to add 0-0 to empty stream": {
+			key:           "0-0",
+			stream:        newStream(t),
+			expectedError: stream.ErrIDZero,
+		},
+		"when bigger sequence already exists": {
+			key:           "100-1",
+			stream:        newStream(t, "100-5"),
+			expectedError: stream.ErrIDTooSmall,
+		},
+		"when adding value to empty stream": {
+			key:        "100-5151",
+			stream:     newStream(t),
+			expectedId: "100-5151",
+		},
+		"when 99-0 exists and tries to add 100-0": {
+			key:        "100-0",
+			stream:     newStream(t, "99-0"),
+			expectedId: "100-0",
+		},
+		"when only milliseconds given": {
+			key:        "100",
+			stream:     newStream(t, "99-0"),
+			expectedId: "100-0",
+		},
+		"when auto incrementing non existing timestamp": {
+			key:        "100-*",
+			stream:     newStream(t),
+			expectedId: "100-0",
+		},
+		"when auto incrementing existing timestamp": {
+			key:        "100-*",
+			stream:     newStream(t, "100-0", "100-5"),
+			expectedId: "100-6",
+		},
+		"when auto incrementing zero timestamp": {
+			key:        "0-*",
+			stream:     newStream(t),
+			expectedId: "0-1",
+		},
+		"when auto incrementing smaller timestamp": {
+			key:           "99-*",
+			stream:        newStream(t, "100-0"),
+			expectedError: stream.ErrIDTooSmall,
+		},
+		"when generating the whole id": {
+			key:        "*",
+			stream:     newStream(t, "999-3"),
+			expectedId: "1000-0",
+		},
+		"when generating the whole id and the clock is behind": {
+			key:        "*",
+			stream:     newStream(t, "1000-3"),
+			expectedId: "1000-4",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			values := tc.values
+			if values == nil {
+				values = []string{"key-1", "value-1"}
+			}
+
+			id, err := tc.stream.Insert(tc.key, values, stream.NodeLimits{})
+
+			if tc.expectedError != nil {
+				require.Error(t, err)
+				require.ErrorContains(t, err, tc.expectedError.Error())
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedId, id)
+			assert.Equal(t, tc.expectedId, tc.stream.LastID().String())
+		})
+	}
+}
+
+func TestRange(t *testing.T) {
+	testCases := map[string]struct {
+		stream       *stream.Stream
+		begin        string
+		end          string
+		expectedData []*stream.Data
+	}{
+		"when full valid data given": {
+			stream: newStream(t, "20-0", "21-1", "32-8", "30000-5"),
+			begin:  "21-0",
+			end:    "39-9",
+			expectedData: []*stream.Data{
+				{ID: "21-1", Values: []string{"key", "21-1"}},
+				{ID: "32-8", Values: []string{"key", "32-8"}},
+			},
+		},
+		"when only timestamps given": {
+			stream: newStream(t, "19-5", "20-0", "20-35000", "21-0"),
+			begin:  "20",
+			end:    "20",
+			expectedData: []*stream.Data{
+				{ID: "20-0", Values: []string{"key", "20-0"}},
+				{ID: "20-35000", Values: []string{"key", "20-35000"}},
+			},
+		},
+		"when the whole stream is requested": {
+			stream: newStream(t, "1-1", "2-2"),
+			begin:  "-",
+			end:    "+",
+			expectedData: []*stream.Data{
+				{ID: "1-1", Values: []string{"key", "1-1"}},
+				{ID: "2-2", Values: []string{"key", "2-2"}},
+			},
+		},
+		"when begin is bigger than end": {
+			stream:       newStream(t, "1-1", "2-2"),
+			begin:        "2",
+			end:          "1",
+			expectedData: []*stream.Data{},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			begin, err := stream.ParseRangeID(tc.begin, false)
+			require.NoError(t, err)
+
+			end, err := stream.ParseRangeID(tc.end, true)
+			require.NoError(t, err)
+
+			res, err := tc.stream.Range(begin, end)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedData, res)
+		})
+	}
+}
+
+func TestInsertWithNodeLimits(t *testing.T) {
+	testCases := map[string]struct {
+		limits stream.NodeLimits
+		fields func(i int) []string
+	}{
+		"when entries have the master fields": {
+			limits: stream.NodeLimits{MaxEntries: 3},
+			fields: func(i int) []string {
+				return []string{"temperature", fmt.Sprint(i), "humidity", fmt.Sprint(i * 2)}
+			},
+		},
+		"when entries have different fields": {
+			limits: stream.NodeLimits{MaxEntries: 4},
+			fields: func(i int) []string {
+				return []string{fmt.Sprintf("field-%d", i%3), fmt.Sprintf("value-%d", i)}
+			},
+		},
+		"when blocks are limited by size": {
+			limits: stream.NodeLimits{MaxBytes: 64},
+			fields: func(i int) []string {
+				return []string{"name", fmt.Sprintf("a-rather-long-value-%d", i)}
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			s := stream.New(fixedNow(0))
+			expected := []*stream.Data{}
+
+			for i := 1; i <= 20; i++ {
+				// sequences go back and forth to exercise negative diffs
+				id := fmt.Sprintf("%d-%d", i*1000, (i%4)*7)
+
+				insertedID, err := s.Insert(id, tc.fields(i), tc.limits)
+				require.NoError(t, err)
+				require.Equal(t, id, insertedID)
+
+				expected = append(expected, &stream.Data{ID: id, Values: tc.fields(i)})
+			}
+
+			assert.Equal(t, 20, s.Len())
+
+			res, err := s.Range(stream.MinID, stream.MaxID)
+			require.NoError(t, err)
+			assert.Equal(t, expected, res)
+
+			res, err = s.Range(stream.ID{Ms: 5000}, stream.ID{Ms: 12000, Seq: 0})
+			require.NoError(t, err)
+			assert.Equal(t, expected[4:12], res)
+		})
+	}
+}
+
+func TestClone(t *testing.T) {
+	s := newStream(t, "1-1", "1-2")
+	clone := s.Clone()
+
+	_, err := s.Insert("1-3", []string{"key", "1-3"}, stream.NodeLimits{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, clone.Len())
+	assert.Equal(t, "1-2", clone.LastID().String())
+
+	entries, err := clone.Range(stream.MinID, stream.MaxID)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestFirstLast(t *testing.T) {
+	s := stream.New(fixedNow(1000))
+
+	assert.Nil(t, s.First())
+	assert.Nil(t, s.Last())
+
+	for i := 1; i <= 5; i++ {
+		_, err := s.Insert(fmt.Sprintf("1-%d", i), []string{"key", fmt.Sprint(i)}, stream.NodeLimits{MaxEntries: 2})
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 3, s.Blocks())
+	assert.Equal(t, &stream.Data{ID: "1-1", Values: []string{"key", "1"}}, s.First())
+	assert.Equal(t, &stream.Data{ID: "1-5", Values: []string{"key", "5"}}, s.Last())
+}
@@ -0,0 +1,31 @@
+package zset
+
+import "math"
+
+// Aggregate is the way the scores of a member found in many sorted sets are
+// combined by ZUNION and ZINTER.
+type Aggregate int
+
+const (
+	AggregateSum Aggregate = iota
+	AggregateMin
+	AggregateMax
+)
+
+// Apply combines the two scores.
+func (a Aggregate) Apply(x, y float64) float64 {
+	switch a {
+	case AggregateMin:
+		return math.Min(x, y)
+	case AggregateMax:
+		return math.Max(x, y)
+	}
+
+	sum := x + y
+	if math.IsNaN(sum) {
+		// +inf and -inf cancel out
+		return 0
+	}
+
+	return sum
+}
@@ -0,0 +1,16 @@
+package zset_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/zset"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregate(t *testing.T) {
+	assert.Equal(t, float64(3), zset.AggregateSum.Apply(1, 2))
+	assert.Equal(t, float64(0), zset.AggregateSum.Apply(math.Inf(1), math.Inf(-1)))
+	assert.Equal(t, float64(1), zset.AggregateMin.Apply(1, 2))
+	assert.Equal(t, float64(2), zset.AggregateMax.Apply(1, 2))
+}
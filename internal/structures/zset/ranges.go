@@ -0,0 +1,163 @@
+package zset
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidScore      = errors.New("ERR value is not a valid float")
+	ErrInvalidScoreRange = errors.New("ERR min or max is not a float")
+	ErrInvalidLexRange   = errors.New("ERR min or max not valid string range item")
+)
+
+// ParseScore parses a score, which may be -inf or +inf but not NaN.
+func ParseScore(s string) (float64, error) {
+	score, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(score) {
+		return 0, ErrInvalidScore
+	}
+
+	return score, nil
+}
+
+// ScoreRange is a range of scores, each bound being inclusive unless
+// prefixed with ( as in (1.5.
+type ScoreRange struct {
+	Min, Max     float64
+	MinEx, MaxEx bool
+}
+
+func ParseScoreRange(min, max string) (ScoreRange, error) {
+	r := ScoreRange{}
+	var err error
+
+	if r.Min, r.MinEx, err = parseScoreBound(min); err != nil {
+		return r, err
+	}
+
+	if r.Max, r.MaxEx, err = parseScoreBound(max); err != nil {
+		return r, err
+	}
+
+	return r, nil
+}
+
+func parseScoreBound(s string) (float64, bool, error) {
+	exclusive := strings.HasPrefix(s, "(")
+
+	score, err := ParseScore(strings.TrimPrefix(s, "("))
+	if err != nil {
+		return 0, false, ErrInvalidScoreRange
+	}
+
+	return score, exclusive, nil
+}
+
+func (r ScoreRange) isEmpty() bool {
+	return r.Min > r.Max || (r.Min == r.Max && (r.MinEx || r.MaxEx))
+}
+
+func (r ScoreRange) gteMin(score float64) bool {
+	if r.MinEx {
+		return score > r.Min
+	}
+
+	return score >= r.Min
+}
+
+func (r ScoreRange) lteMax(score float64) bool {
+	if r.MaxEx {
+		return score < r.Max
+	}
+
+	return score <= r.Max
+}
+
+type lexBound struct {
+	value     string
+	exclusive bool
+	// inf is -1 for - and 1 for +, the bounds lower and greater than any
+	// member.
+	inf int
+}
+
+// LexRange is a range of members, for sorted sets whose members all have the
+// same score. Bounds are either prefixed with [ or ( for inclusive and
+// exclusive bounds, or are - and + for the lowest and greatest members.
+type LexRange struct {
+	min, max lexBound
+}
+
+func ParseLexRange(min, max string) (LexRange, error) {
+	r := LexRange{}
+	var err error
+
+	if r.min, err = parseLexBound(min); err != nil {
+		return r, err
+	}
+
+	if r.max, err = parseLexBound(max); err != nil {
+		return r, err
+	}
+
+	return r, nil
+}
+
+func parseLexBound(s string) (lexBound, error) {
+	switch {
+	case s == "-":
+		return lexBound{inf: -1}, nil
+	case s == "+":
+		return lexBound{inf: 1}, nil
+	case strings.HasPrefix(s, "["):
+		return lexBound{value: s[1:]}, nil
+	case strings.HasPrefix(s, "("):
+		return lexBound{value: s[1:], exclusive: true}, nil
+	}
+
+	return lexBound{}, ErrInvalidLexRange
+}
+
+// compare compares the member to the bound, like strings.Compare.
+func (b lexBound) compare(member string) int {
+	if b.inf != 0 {
+		return -b.inf
+	}
+
+	return strings.Compare(member, b.value)
+}
+
+func (r LexRange) isEmpty() bool {
+	if r.min.inf == 1 || r.max.inf == -1 {
+		return true
+	}
+
+	if r.min.inf == -1 || r.max.inf == 1 {
+		return false
+	}
+
+	cmp := strings.Compare(r.min.value, r.max.value)
+
+	return cmp > 0 || (cmp == 0 && (r.min.exclusive || r.max.exclusive))
+}
+
+func (r LexRange) gteMin(member string) bool {
+	cmp := r.min.compare(member)
+	if r.min.exclusive {
+		return cmp > 0
+	}
+
+	return cmp >= 0
+}
+
+func (r LexRange) lteMax(member string) bool {
+	cmp := r.max.compare(member)
+	if r.max.exclusive {
+		return cmp < 0
+	}
+
+	return cmp <= 0
+}
@@ -0,0 +1,241 @@
+package zset
+
+import "math/rand"
+
+const (
+	skiplistMaxLevel = 32
+	// skiplistP is the probability of a node having one more level
+	skiplistP = 0.25
+)
+
+type skiplistLevel struct {
+	forward *skiplistNode
+	// span is the number of nodes the forward pointer skips, used to
+	// compute ranks.
+	span int
+}
+
+type skiplistNode struct {
+	Entry
+	backward *skiplistNode
+	levels   []skiplistLevel
+}
+
+func (n *skiplistNode) next() *skiplistNode {
+	return n.levels[0].forward
+}
+
+// skiplist keeps the elements sorted by score then member, with O(log n)
+// insertion, deletion and access by rank. It's the skiplist of Redis: every
+// level also stores the span of its forward pointer, so ranks are computed
+// while walking down the levels.
+type skiplist struct {
+	header *skiplistNode
+	tail   *skiplistNode
+	length int
+	level  int
+}
+
+func newSkiplist() *skiplist {
+	return &skiplist{
+		header: &skiplistNode{levels: make([]skiplistLevel, skiplistMaxLevel)},
+		level:  1,
+	}
+}
+
+func randomLevel() int {
+	level := 1
+	for level < skiplistMaxLevel && rand.Float64() < skiplistP {
+		level++
+	}
+
+	return level
+}
+
+func (sl *skiplist) first() *skiplistNode {
+	return sl.header.levels[0].forward
+}
+
+func (sl *skiplist) last() *skiplistNode {
+	return sl.tail
+}
+
+// insert adds an element which must not be in the list already.
+func (sl *skiplist) insert(score float64, member string) *skiplistNode {
+	var update [skiplistMaxLevel]*skiplistNode
+	var rank [skiplistMaxLevel]int
+
+	x := sl.header
+	for i := sl.level - 1; i >= 0; i-- {
+		if i < sl.level-1 {
+			rank[i] = rank[i+1]
+		}
+
+		for x.levels[i].forward != nil && x.levels[i].forward.less(score, member) {
+			rank[i] += x.levels[i].span
+			x = x.levels[i].forward
+		}
+
+		update[i] = x
+	}
+
+	level := randomLevel()
+	if level > sl.level {
+		for i := sl.level; i < level; i++ {
+			rank[i] = 0
+			update[i] = sl.header
+			update[i].levels[i].span = sl.length
+		}
+
+		sl.level = level
+	}
+
+	x = &skiplistNode{Entry: Entry{Member: member, Score: score}, levels: make([]skiplistLevel, level)}
+	for i := 0; i < level; i++ {
+		x.levels[i].forward = update[i].levels[i].forward
+		update[i].levels[i].forward = x
+
+		x.levels[i].span = update[i].levels[i].span - (rank[0] - rank[i])
+		update[i].levels[i].span = rank[0] - rank[i] + 1
+	}
+
+	// the levels above the new node now skip one more node
+	for i := level; i < sl.level; i++ {
+		update[i].levels[i].span++
+	}
+
+	if update[0] != sl.header {
+		x.backward = update[0]
+	}
+
+	if x.levels[0].forward != nil {
+		x.levels[0].forward.backward = x
+	} else {
+		sl.tail = x
+	}
+
+	sl.length++
+
+	return x
+}
+
+// delete removes the element and returns false if it wasn't found.
+func (sl *skiplist) delete(score float64, member string) bool {
+	var update [skiplistMaxLevel]*skiplistNode
+
+	x := sl.header
+	for i := sl.level - 1; i >= 0; i-- {
+		for x.levels[i].forward != nil && x.levels[i].forward.less(score, member) {
+			x = x.levels[i].forward
+		}
+
+		update[i] = x
+	}
+
+	x = x.levels[0].forward
+	if x == nil || x.Score != score || x.Member != member {
+		return false
+	}
+
+	sl.deleteNode(x, update[:])
+
+	return true
+}
+
+func (sl *skiplist) deleteNode(x *skiplistNode, update []*skiplistNode) {
+	for i := 0; i < sl.level; i++ {
+		if update[i].levels[i].forward == x {
+			update[i].levels[i].span += x.levels[i].span - 1
+			update[i].levels[i].forward = x.levels[i].forward
+		} else {
+			update[i].levels[i].span--
+		}
+	}
+
+	if x.levels[0].forward != nil {
+		x.levels[0].forward.backward = x.backward
+	} else {
+		sl.tail = x.backward
+	}
+
+	for sl.level > 1 && sl.header.levels[sl.level-1].forward == nil {
+		sl.level--
+	}
+
+	sl.length--
+}
+
+// rank returns the 1-based rank of the element, 0 if it isn't found.
+func (sl *skiplist) rank(score float64, member string) int {
+	rank := 0
+
+	x := sl.header
+	for i := sl.level - 1; i >= 0; i-- {
+		for x.levels[i].forward != nil && !x.levels[i].forward.greater(score, member) {
+			rank += x.levels[i].span
+			x = x.levels[i].forward
+		}
+
+		if x != sl.header && x.Score == score && x.Member == member {
+			return rank
+		}
+	}
+
+	return 0
+}
+
+// byRank returns the element with the given 1-based rank, nil if out of
+// range.
+func (sl *skiplist) byRank(rank int) *skiplistNode {
+	traversed := 0
+
+	x := sl.header
+	for i := sl.level - 1; i >= 0; i-- {
+		for x.levels[i].forward != nil && traversed+x.levels[i].span <= rank {
+			traversed += x.levels[i].span
+			x = x.levels[i].forward
+		}
+
+		if traversed == rank {
+			if x == sl.header {
+				return nil
+			}
+
+			return x
+		}
+	}
+
+	return nil
+}
+
+// firstMatch returns the first element for which before is false, before
+// having to be true for a prefix of the list only. nil is returned if before
+// is true for every element.
+func (sl *skiplist) firstMatch(before func(e Entry) bool) *skiplistNode {
+	x := sl.header
+	for i := sl.level - 1; i >= 0; i-- {
+		for x.levels[i].forward != nil && before(x.levels[i].forward.Entry) {
+			x = x.levels[i].forward
+		}
+	}
+
+	return x.levels[0].forward
+}
+
+// lastMatch returns the last element for which notAfter is true, notAfter
+// having to be true for a prefix of the list only. nil is returned if
+// notAfter is false for every element.
+func (sl *skiplist) lastMatch(notAfter func(e Entry) bool) *skiplistNode {
+	x := sl.header
+	for i := sl.level - 1; i >= 0; i-- {
+		for x.levels[i].forward != nil && notAfter(x.levels[i].forward.Entry) {
+			x = x.levels[i].forward
+		}
+	}
+
+	if x == sl.header {
+		return nil
+	}
+
+	return x
+}
@@ -0,0 +1,59 @@
+package zset
+
+import (
+	"math/rand"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSkiplist(t *testing.T) {
+	sl := newSkiplist()
+	expected := []Entry{}
+
+	for i := 0; i < 500; i++ {
+		entry := Entry{Member: "member:" + strconv.Itoa(i), Score: float64(rand.Intn(50))}
+		sl.insert(entry.Score, entry.Member)
+		expected = append(expected, entry)
+	}
+
+	// delete every third element
+	kept := []Entry{}
+	for i, entry := range expected {
+		if i%3 == 0 {
+			require.True(t, sl.delete(entry.Score, entry.Member))
+		} else {
+			kept = append(kept, entry)
+		}
+	}
+	expected = kept
+
+	assert.False(t, sl.delete(1000, "missing"))
+
+	sort.Slice(expected, func(i, j int) bool {
+		return expected[i].less(expected[j].Score, expected[j].Member)
+	})
+
+	require.Equal(t, len(expected), sl.length)
+
+	i := 0
+	for n := sl.first(); n != nil; n = n.next() {
+		assert.Equal(t, expected[i], n.Entry)
+		assert.Equal(t, i+1, sl.rank(n.Score, n.Member))
+		assert.Equal(t, n, sl.byRank(i+1))
+		i++
+	}
+
+	i = len(expected) - 1
+	for n := sl.last(); n != nil; n = n.backward {
+		assert.Equal(t, expected[i], n.Entry)
+		i--
+	}
+
+	assert.Nil(t, sl.byRank(0))
+	assert.Nil(t, sl.byRank(len(expected)+1))
+	assert.Equal(t, 0, sl.rank(1000, "missing"))
+}
@@ -0,0 +1,452 @@
+package zset
+
+import (
+	"errors"
+	"math"
+	"strconv"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/dict"
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/listpack"
+)
+
+const (
+	EncodingListpack = "listpack"
+	EncodingSkiplist = "skiplist"
+)
+
+var ErrScoreNaN = errors.New("ERR resulting score is not a number (NaN)")
+
+// Limits decide when a sorted set outgrows its compact encoding, with the
+// semantics of zset-max-listpack-entries and zset-max-listpack-value.
+type Limits struct {
+	MaxEntries int
+	MaxValue   int
+}
+
+// Entry is a member of a sorted set with its score.
+type Entry struct {
+	Member string
+	Score  float64
+}
+
+// less reports whether the entry sorts before the given score and member.
+func (e Entry) less(score float64, member string) bool {
+	return e.Score < score || (e.Score == score && e.Member < member)
+}
+
+// greater reports whether the entry sorts after the given score and member.
+func (e Entry) greater(score float64, member string) bool {
+	return e.Score > score || (e.Score == score && e.Member > member)
+}
+
+// AddFlags are the conditions of ZADD.
+type AddFlags struct {
+	NX   bool // only add new members
+	XX   bool // only update existing members
+	GT   bool // only update if the new score is greater
+	LT   bool // only update if the new score is lower
+	Incr bool // increment the score instead of setting it
+}
+
+// AddResult is what Add ended up doing.
+type AddResult int
+
+const (
+	AddNop       AddResult = iota // the flags prevented the change
+	AddUnchanged                  // the member already had the score
+	AddAdded
+	AddUpdated
+)
+
+// ZSet is a set of members sorted by score, then by member for equal scores.
+// Small sorted sets are a listpack of member, score pairs kept in order.
+// Bigger ones use a skiplist, for access by rank and score in O(log n), and a
+// hash table to find the score of a member in O(1).
+type ZSet struct {
+	lp *listpack.Listpack
+
+	sl   *skiplist
+	dict *dict.Dict[float64]
+}
+
+func New() *ZSet {
+	return &ZSet{lp: listpack.New()}
+}
+
+// Len is the number of members in the sorted set.
+func (z *ZSet) Len() int {
+	if z.sl != nil {
+		return z.sl.length
+	}
+
+	return z.lp.Len() / 2
+}
+
+// Encoding is the name of the encoding in use, as reported by OBJECT ENCODING.
+func (z *ZSet) Encoding() string {
+	if z.sl != nil {
+		return EncodingSkiplist
+	}
+
+	return EncodingListpack
+}
+
+// Clone returns a deep copy of the sorted set.
+func (z *ZSet) Clone() *ZSet {
+	if z.sl == nil {
+		return &ZSet{lp: z.lp.Clone()}
+	}
+
+	clone := &ZSet{sl: newSkiplist(), dict: dict.New[float64]()}
+	for x := z.sl.first(); x != nil; x = x.next() {
+		clone.sl.insert(x.Score, x.Member)
+		clone.dict.Set(x.Member, x.Score)
+	}
+
+	return clone
+}
+
+func (z *ZSet) Score(member string) (float64, bool) {
+	if z.sl != nil {
+		return z.dict.Get(member)
+	}
+
+	p, score := z.lpFind(member)
+
+	return score, p != -1
+}
+
+// Add adds the member or updates its score, according to the flags, and
+// returns what it did together with the score of the member.
+func (z *ZSet) Add(member string, score float64, flags AddFlags, limits Limits) (AddResult, float64, error) {
+	current, exists := z.Score(member)
+
+	if !exists {
+		if flags.XX {
+			return AddNop, 0, nil
+		}
+
+		z.insert(member, score, limits)
+
+		return AddAdded, score, nil
+	}
+
+	if flags.NX {
+		return AddNop, current, nil
+	}
+
+	if flags.Incr {
+		score += current
+		if math.IsNaN(score) {
+			return AddNop, 0, ErrScoreNaN
+		}
+	}
+
+	if (flags.GT && score <= current) || (flags.LT && score >= current) {
+		return AddNop, current, nil
+	}
+
+	if score == current {
+		return AddUnchanged, current, nil
+	}
+
+	z.Remove(member)
+	z.insert(member, score, limits)
+
+	return AddUpdated, score, nil
+}
+
+// Remove removes the member and returns false if it wasn't in the set.
+func (z *ZSet) Remove(member string) bool {
+	if z.sl != nil {
+		score, exists := z.dict.Get(member)
+		if !exists {
+			return false
+		}
+
+		z.sl.delete(score, member)
+		z.dict.Delete(member)
+
+		return true
+	}
+
+	p, _ := z.lpFind(member)
+	if p == -1 {
+		return false
+	}
+
+	z.lp.DeleteRange(p, 2)
+
+	return true
+}
+
+// Rank returns the 0-based rank of the member with its score, counting from
+// the highest score when reverse is set.
+func (z *ZSet) Rank(member string, reverse bool) (int, float64, bool) {
+	score, exists := z.Score(member)
+	if !exists {
+		return 0, 0, false
+	}
+
+	rank := 0
+
+	if z.sl != nil {
+		rank = z.sl.rank(score, member) - 1
+	} else {
+		for i, entry := range z.lpEntries() {
+			if entry.Member == member {
+				rank = i
+				break
+			}
+		}
+	}
+
+	if reverse {
+		rank = z.Len() - 1 - rank
+	}
+
+	return rank, score, true
+}
+
+// RangeByRank returns the members from rank start to stop, both inclusive
+// and valid 0-based ranks, counting from the highest score when reverse is
+// set.
+func (z *ZSet) RangeByRank(start, stop int, reverse bool) []Entry {
+	res := make([]Entry, 0, stop-start+1)
+
+	if z.sl == nil {
+		entries := z.lpEntries()
+		for i := start; i <= stop; i++ {
+			if reverse {
+				res = append(res, entries[len(entries)-1-i])
+			} else {
+				res = append(res, entries[i])
+			}
+		}
+
+		return res
+	}
+
+	var n *skiplistNode
+	if reverse {
+		n = z.sl.byRank(z.sl.length - start)
+	} else {
+		n = z.sl.byRank(start + 1)
+	}
+
+	for i := start; i <= stop && n != nil; i++ {
+		res = append(res, n.Entry)
+
+		if reverse {
+			n = n.backward
+		} else {
+			n = n.next()
+		}
+	}
+
+	return res
+}
+
+// RangeByScore returns the members whose score is in the range, from the
+// highest score when reverse is set. offset members are skipped, and at most
+// count members are returned unless count is negative.
+func (z *ZSet) RangeByScore(r ScoreRange, reverse bool, offset, count int) []Entry {
+	if r.isEmpty() {
+		return []Entry{}
+	}
+
+	return z.rangeBy(
+		func(e Entry) bool { return r.gteMin(e.Score) },
+		func(e Entry) bool { return r.lteMax(e.Score) },
+		reverse, offset, count,
+	)
+}
+
+// RangeByLex is like RangeByScore for a range of members. It's only
+// meaningful when every member has the same score.
+func (z *ZSet) RangeByLex(r LexRange, reverse bool, offset, count int) []Entry {
+	if r.isEmpty() {
+		return []Entry{}
+	}
+
+	return z.rangeBy(
+		func(e Entry) bool { return r.gteMin(e.Member) },
+		func(e Entry) bool { return r.lteMax(e.Member) },
+		reverse, offset, count,
+	)
+}
+
+// Count returns the number of members whose score is in the range.
+func (z *ZSet) Count(r ScoreRange) int {
+	return len(z.RangeByScore(r, false, 0, -1))
+}
+
+// LexCount returns the number of members in the range.
+func (z *ZSet) LexCount(r LexRange) int {
+	return len(z.RangeByLex(r, false, 0, -1))
+}
+
+// ForEach calls fn for every member, in order, until it returns false. The
+// set must not be modified meanwhile.
+func (z *ZSet) ForEach(fn func(entry Entry) bool) {
+	if z.sl == nil {
+		for _, entry := range z.lpEntries() {
+			if !fn(entry) {
+				return
+			}
+		}
+
+		return
+	}
+
+	for n := z.sl.first(); n != nil; n = n.next() {
+		if !fn(n.Entry) {
+			return
+		}
+	}
+}
+
+// Scan calls fn for some of the members from the cursor, 0 to start the
+// iteration, and returns the cursor to continue from, 0 once every member
+// has been visited. The members of a listpack are all visited at once. See
+// dict.Dict.Scan for the guarantees.
+func (z *ZSet) Scan(cursor uint64, fn func(entry Entry)) uint64 {
+	if z.sl != nil {
+		return z.dict.Scan(cursor, func(member string, score float64) {
+			fn(Entry{Member: member, Score: score})
+		})
+	}
+
+	for _, entry := range z.lpEntries() {
+		fn(entry)
+	}
+
+	return 0
+}
+
+// rangeBy returns the members between the bounds, given as gteMin which
+// holds for a suffix of the set and lteMax which holds for a prefix.
+func (z *ZSet) rangeBy(gteMin, lteMax func(e Entry) bool, reverse bool, offset, count int) []Entry {
+	res := []Entry{}
+
+	if z.sl == nil {
+		entries := z.lpEntries()
+
+		for i := range entries {
+			if reverse {
+				i = len(entries) - 1 - i
+			}
+
+			if !gteMin(entries[i]) || !lteMax(entries[i]) {
+				continue
+			}
+
+			if offset > 0 {
+				offset--
+				continue
+			}
+
+			if count >= 0 && len(res) == count {
+				break
+			}
+
+			res = append(res, entries[i])
+		}
+
+		return res
+	}
+
+	var n *skiplistNode
+	if reverse {
+		n = z.sl.lastMatch(lteMax)
+	} else {
+		n = z.sl.firstMatch(func(e Entry) bool { return !gteMin(e) })
+	}
+
+	for ; n != nil && gteMin(n.Entry) && lteMax(n.Entry); offset-- {
+		if offset <= 0 {
+			if count >= 0 && len(res) == count {
+				break
+			}
+
+			res = append(res, n.Entry)
+		}
+
+		if reverse {
+			n = n.backward
+		} else {
+			n = n.next()
+		}
+	}
+
+	return res
+}
+
+func (z *ZSet) insert(member string, score float64, limits Limits) {
+	if z.sl == nil && (z.Len() >= limits.MaxEntries || len(member) > limits.MaxValue) {
+		z.convert()
+	}
+
+	if z.sl != nil {
+		z.sl.insert(score, member)
+		z.dict.Set(member, score)
+
+		return
+	}
+
+	encodedScore := []byte(strconv.FormatFloat(score, 'g', -1, 64))
+
+	for p := z.lp.First(); p != -1; p = z.lp.Next(z.lp.Next(p)) {
+		current := Entry{Member: string(z.lp.Get(p)), Score: z.lpScore(z.lp.Next(p))}
+		if current.greater(score, member) {
+			p = z.lp.Insert(p, []byte(member), listpack.Before)
+			z.lp.Insert(p, encodedScore, listpack.After)
+
+			return
+		}
+	}
+
+	z.lp.Append([]byte(member))
+	z.lp.Append(encodedScore)
+}
+
+// lpFind returns the offset of the member in the listpack with its score,
+// -1 if it isn't found.
+func (z *ZSet) lpFind(member string) (int, float64) {
+	for p := z.lp.First(); p != -1; p = z.lp.Next(z.lp.Next(p)) {
+		if string(z.lp.Get(p)) == member {
+			return p, z.lpScore(z.lp.Next(p))
+		}
+	}
+
+	return -1, 0
+}
+
+func (z *ZSet) lpScore(p int) float64 {
+	score, _ := strconv.ParseFloat(string(z.lp.Get(p)), 64)
+	return score
+}
+
+func (z *ZSet) lpEntries() []Entry {
+	res := make([]Entry, 0, z.Len())
+
+	for p := z.lp.First(); p != -1; p = z.lp.Next(z.lp.Next(p)) {
+		res = append(res, Entry{Member: string(z.lp.Get(p)), Score: z.lpScore(z.lp.Next(p))})
+	}
+
+	return res
+}
+
+func (z *ZSet) convert() {
+	sl := newSkiplist()
+	scores := dict.New[float64]()
+
+	for _, entry := range z.lpEntries() {
+		sl.insert(entry.Score, entry.Member)
+		scores.Set(entry.Member, entry.Score)
+	}
+
+	z.sl = sl
+	z.dict = scores
+	z.lp = nil
+}
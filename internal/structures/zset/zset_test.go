@@ -0,0 +1,258 @@
+package zset_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/codecrafters-io/redis-starter-go/internal/structures/zset"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// every test runs against both encodings
+var encodings = map[string]zset.Limits{
+	zset.EncodingListpack: {MaxEntries: 128, MaxValue: 64},
+	zset.EncodingSkiplist: {MaxEntries: 0, MaxValue: 64},
+}
+
+func newZSet(t *testing.T, limits zset.Limits, entries ...zset.Entry) *zset.ZSet {
+	z := zset.New()
+
+	for _, entry := range entries {
+		_, _, err := z.Add(entry.Member, entry.Score, zset.AddFlags{}, limits)
+		require.NoError(t, err)
+	}
+
+	return z
+}
+
+func members(entries []zset.Entry) []string {
+	res := []string{}
+	for _, entry := range entries {
+		res = append(res, entry.Member)
+	}
+
+	return res
+}
+
+var sample = []zset.Entry{
+	{Member: "e", Score: 5},
+	{Member: "a", Score: 1},
+	{Member: "c", Score: 3},
+	{Member: "b", Score: 3},
+	{Member: "d", Score: 4},
+}
+
+func TestAdd(t *testing.T) {
+	for encoding, limits := range encodings {
+		t.Run(encoding, func(t *testing.T) {
+			z := newZSet(t, limits, sample...)
+			assert.Equal(t, encoding, z.Encoding())
+
+			testCases := map[string]struct {
+				member         string
+				score          float64
+				flags          zset.AddFlags
+				expectedResult zset.AddResult
+				expectedScore  float64
+			}{
+				"when adding a new member":         {member: "f", score: 6, expectedResult: zset.AddAdded, expectedScore: 6},
+				"when updating a member":           {member: "a", score: 2, expectedResult: zset.AddUpdated, expectedScore: 2},
+				"when score doesn't change":        {member: "a", score: 1, expectedResult: zset.AddUnchanged, expectedScore: 1},
+				"when only adding new members":     {member: "a", score: 2, flags: zset.AddFlags{NX: true}, expectedResult: zset.AddNop, expectedScore: 1},
+				"when only updating members":       {member: "f", score: 2, flags: zset.AddFlags{XX: true}, expectedResult: zset.AddNop},
+				"when score isn't greater":         {member: "e", score: 4, flags: zset.AddFlags{GT: true}, expectedResult: zset.AddNop, expectedScore: 5},
+				"when score is lower":              {member: "e", score: 4, flags: zset.AddFlags{LT: true}, expectedResult: zset.AddUpdated, expectedScore: 4},
+				"when adding a new member with GT": {member: "f", score: 6, flags: zset.AddFlags{GT: true}, expectedResult: zset.AddAdded, expectedScore: 6},
+				"when incrementing":                {member: "e", score: 1.5, flags: zset.AddFlags{Incr: true}, expectedResult: zset.AddUpdated, expectedScore: 6.5},
+			}
+
+			for name, tc := range testCases {
+				t.Run(name, func(t *testing.T) {
+					z := newZSet(t, limits, sample...)
+
+					result, score, err := z.Add(tc.member, tc.score, tc.flags, limits)
+					require.NoError(t, err)
+					assert.Equal(t, tc.expectedResult, result)
+					assert.Equal(t, tc.expectedScore, score)
+
+					all := z.RangeByRank(0, z.Len()-1, false)
+					for i := 1; i < len(all); i++ {
+						assert.True(t, all[i-1].Score < all[i].Score || all[i-1].Member < all[i].Member)
+					}
+				})
+			}
+
+			t.Run("when increment gives NaN", func(t *testing.T) {
+				z := newZSet(t, limits, zset.Entry{Member: "a", Score: math.Inf(1)})
+
+				_, _, err := z.Add("a", math.Inf(-1), zset.AddFlags{Incr: true}, limits)
+				assert.ErrorIs(t, err, zset.ErrScoreNaN)
+			})
+		})
+	}
+}
+
+func TestConversion(t *testing.T) {
+	limits := zset.Limits{MaxEntries: 2, MaxValue: 4}
+
+	z := newZSet(t, limits, sample[:2]...)
+	assert.Equal(t, zset.EncodingListpack, z.Encoding())
+
+	z.Add("c", 3, zset.AddFlags{}, limits)
+	assert.Equal(t, zset.EncodingSkiplist, z.Encoding())
+	assert.Equal(t, []string{"a", "c", "e"}, members(z.RangeByRank(0, 2, false)))
+
+	z = newZSet(t, limits, zset.Entry{Member: "too long", Score: 1})
+	assert.Equal(t, zset.EncodingSkiplist, z.Encoding())
+}
+
+func TestRemove(t *testing.T) {
+	for encoding, limits := range encodings {
+		t.Run(encoding, func(t *testing.T) {
+			z := newZSet(t, limits, sample...)
+
+			assert.True(t, z.Remove("c"))
+			assert.False(t, z.Remove("c"))
+
+			_, found := z.Score("c")
+			assert.False(t, found)
+			assert.Equal(t, []string{"a", "b", "d", "e"}, members(z.RangeByRank(0, 3, false)))
+		})
+	}
+}
+
+func TestRank(t *testing.T) {
+	for encoding, limits := range encodings {
+		t.Run(encoding, func(t *testing.T) {
+			z := newZSet(t, limits, sample...)
+
+			rank, score, found := z.Rank("c", false)
+			assert.True(t, found)
+			assert.Equal(t, 2, rank)
+			assert.Equal(t, float64(3), score)
+
+			rank, _, found = z.Rank("a", true)
+			assert.True(t, found)
+			assert.Equal(t, 4, rank)
+
+			_, _, found = z.Rank("missing", false)
+			assert.False(t, found)
+		})
+	}
+}
+
+func TestRangeByRank(t *testing.T) {
+	for encoding, limits := range encodings {
+		t.Run(encoding, func(t *testing.T) {
+			z := newZSet(t, limits, sample...)
+
+			assert.Equal(t, []string{"b", "c", "d"}, members(z.RangeByRank(1, 3, false)))
+			assert.Equal(t, []string{"e", "d"}, members(z.RangeByRank(0, 1, true)))
+		})
+	}
+}
+
+func TestRangeByScore(t *testing.T) {
+	testCases := map[string]struct {
+		min, max string
+		reverse  bool
+		offset   int
+		count    int
+		expected []string
+	}{
+		"when range is inclusive":    {min: "3", max: "4", count: -1, expected: []string{"b", "c", "d"}},
+		"when range is exclusive":    {min: "(3", max: "(5", count: -1, expected: []string{"d"}},
+		"when range is infinite":     {min: "-inf", max: "+inf", count: -1, expected: []string{"a", "b", "c", "d", "e"}},
+		"when range is reversed":     {min: "2", max: "4", reverse: true, count: -1, expected: []string{"d", "c", "b"}},
+		"when limit given":           {min: "-inf", max: "inf", offset: 1, count: 2, expected: []string{"b", "c"}},
+		"when reversed limit given":  {min: "-inf", max: "inf", reverse: true, offset: 3, count: 5, expected: []string{"b", "a"}},
+		"when range is empty":        {min: "4", max: "3", count: -1, expected: []string{}},
+		"when bounds are equal":      {min: "(3", max: "3", count: -1, expected: []string{}},
+		"when no member is in range": {min: "10", max: "20", count: -1, expected: []string{}},
+	}
+
+	for encoding, limits := range encodings {
+		t.Run(encoding, func(t *testing.T) {
+			z := newZSet(t, limits, sample...)
+
+			for name, tc := range testCases {
+				t.Run(name, func(t *testing.T) {
+					r, err := zset.ParseScoreRange(tc.min, tc.max)
+					require.NoError(t, err)
+
+					assert.Equal(t, tc.expected, members(z.RangeByScore(r, tc.reverse, tc.offset, tc.count)))
+				})
+			}
+
+			r, err := zset.ParseScoreRange("(1", "4")
+			require.NoError(t, err)
+			assert.Equal(t, 3, z.Count(r))
+		})
+	}
+}
+
+func TestRangeByLex(t *testing.T) {
+	testCases := map[string]struct {
+		min, max string
+		reverse  bool
+		expected []string
+	}{
+		"when range is inclusive": {min: "[b", max: "[d", expected: []string{"b", "c", "d"}},
+		"when range is exclusive": {min: "(b", max: "(d", expected: []string{"c"}},
+		"when range is infinite":  {min: "-", max: "+", expected: []string{"a", "b", "c", "d", "e"}},
+		"when range is reversed":  {min: "-", max: "(c", reverse: true, expected: []string{"b", "a"}},
+		"when range is empty":     {min: "+", max: "-", expected: []string{}},
+	}
+
+	entries := []zset.Entry{}
+	for _, entry := range sample {
+		entries = append(entries, zset.Entry{Member: entry.Member})
+	}
+
+	for encoding, limits := range encodings {
+		t.Run(encoding, func(t *testing.T) {
+			z := newZSet(t, limits, entries...)
+
+			for name, tc := range testCases {
+				t.Run(name, func(t *testing.T) {
+					r, err := zset.ParseLexRange(tc.min, tc.max)
+					require.NoError(t, err)
+
+					assert.Equal(t, tc.expected, members(z.RangeByLex(r, tc.reverse, 0, -1)))
+				})
+			}
+		})
+	}
+}
+
+func TestParseRanges(t *testing.T) {
+	_, err := zset.ParseScoreRange("a", "1")
+	assert.ErrorIs(t, err, zset.ErrInvalidScoreRange)
+
+	_, err = zset.ParseScoreRange("1", "nan")
+	assert.ErrorIs(t, err, zset.ErrInvalidScoreRange)
+
+	_, err = zset.ParseLexRange("a", "+")
+	assert.ErrorIs(t, err, zset.ErrInvalidLexRange)
+
+	_, err = zset.ParseScore("1e400")
+	assert.ErrorIs(t, err, zset.ErrInvalidScore)
+}
+
+func TestClone(t *testing.T) {
+	for name, limits := range encodings {
+		t.Run(name, func(t *testing.T) {
+			z := newZSet(t, limits, sample...)
+			clone := z.Clone()
+
+			z.Remove(sample[0].Member)
+
+			assert.Equal(t, z.Encoding(), clone.Encoding())
+			assert.Equal(t, []zset.Entry{sample[1]}, z.RangeByRank(0, 0, false))
+			assert.Equal(t, len(sample), clone.Len())
+			assert.Equal(t, sample[0], clone.RangeByRank(len(sample)-1, len(sample)-1, false)[0])
+			assert.Equal(t, len(sample)-1, z.Len())
+		})
+	}
+}